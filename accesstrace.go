@@ -0,0 +1,147 @@
+package hot
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/DmitriyVTitov/size"
+	"github.com/samber/hot/internal/mrc"
+	"github.com/samber/hot/pkg/accesstrace"
+)
+
+// errAccessTraceRecording is returned by StartAccessTrace when a recording is in progress.
+var errAccessTraceRecording = errors.New("access trace is already recording")
+
+// accessTrace samples the accesses of the cache for a recorder started at runtime.
+// Keys are sampled by hash, so that every access of a sampled key is recorded.
+type accessTrace[K comparable, V any] struct {
+	threshold uint64         // Keys are sampled when hash % mrc.Modulus < threshold
+	rawKey    func(K) string // Nil records the hashes only
+
+	recorder atomic.Pointer[accesstrace.Recorder]
+}
+
+func newAccessTrace[K comparable, V any](sampleRate float64, rawKey func(K) string) *accessTrace[K, V] {
+	return &accessTrace[K, V]{
+		threshold: max(uint64(sampleRate*mrc.Modulus), 1),
+		rawKey:    rawKey,
+	}
+}
+
+// recording returns true when a recorder is started. A nil accessTrace never records.
+func (t *accessTrace[K, V]) recording() bool {
+	return t != nil && t.recorder.Load() != nil
+}
+
+// record queues the access of a sampled key, without blocking.
+// The size of the value is only computed for sampled keys.
+func (t *accessTrace[K, V]) record(op accesstrace.Op, key K, hit bool, value V, hasValue bool) {
+	if t == nil {
+		return
+	}
+
+	recorder := t.recorder.Load()
+	if recorder == nil {
+		return
+	}
+
+	hash := mrc.Hash(key)
+	if hash%mrc.Modulus >= t.threshold {
+		return
+	}
+
+	r := accesstrace.Record{
+		Time:    time.Now(),
+		Op:      op,
+		Hit:     hit,
+		KeyHash: hash,
+	}
+	if t.rawKey != nil {
+		r.Key = t.rawKey(key)
+	}
+	if hasValue {
+		r.ValueSize = int64(size.Of(value))
+	}
+
+	recorder.Record(r)
+}
+
+// recordItem records the access of a key, with the value of a cached or loaded item. The item may be nil.
+func (t *accessTrace[K, V]) recordItem(op accesstrace.Op, key K, hit bool, it *item[V]) {
+	if it == nil {
+		t.record(op, key, hit, zero[V](), false)
+		return
+	}
+
+	t.record(op, key, hit, it.value, it.hasValue)
+}
+
+// traceReads records the reads of a batch: cached keys are hits, missing keys are misses with their loaded value.
+func (c *HotCache[K, V]) traceReads(cached map[K]*item[V], missing []K, loaded map[K]*item[V]) {
+	if !c.accessTrace.recording() {
+		return
+	}
+
+	for key, it := range cached {
+		c.accessTrace.recordItem(accesstrace.OpGet, key, true, it)
+	}
+	for _, key := range missing {
+		c.accessTrace.recordItem(accesstrace.OpGet, key, false, loaded[key])
+	}
+}
+
+// traceWrites records the writes of a batch.
+func (c *HotCache[K, V]) traceWrites(items map[K]V, missing []K) {
+	if !c.accessTrace.recording() {
+		return
+	}
+
+	for key, value := range items {
+		c.accessTrace.record(accesstrace.OpSet, key, false, value, true)
+	}
+	for _, key := range missing {
+		c.accessTrace.record(accesstrace.OpSet, key, false, zero[V](), false)
+	}
+}
+
+// traceDeletes records the deletions of a batch. Hit is true when the key was removed.
+func (c *HotCache[K, V]) traceDeletes(deleted map[K]bool) {
+	if !c.accessTrace.recording() {
+		return
+	}
+
+	for key, ok := range deleted {
+		c.accessTrace.record(accesstrace.OpDelete, key, ok, zero[V](), false)
+	}
+}
+
+// StartAccessTrace starts recording the sampled accesses of the cache (reads, writes and deletions) to recorder,
+// until StopAccessTrace or Close. Recording never blocks the cache: records are dropped when the recorder is late.
+// Returns an error when a recording is in progress. Panics if WithAccessTrace is not enabled.
+func (c *HotCache[K, V]) StartAccessTrace(recorder *accesstrace.Recorder) error {
+	if c.accessTrace == nil {
+		panic("access trace is not enabled")
+	}
+
+	if !c.accessTrace.recorder.CompareAndSwap(nil, recorder) {
+		return errAccessTraceRecording
+	}
+
+	return nil
+}
+
+// StopAccessTrace stops the recording started by StartAccessTrace, and closes the recorder.
+// Returns the error of the recorder, if any. Does nothing when nothing is recorded.
+func (c *HotCache[K, V]) StopAccessTrace() error {
+	if c.accessTrace == nil {
+		return nil
+	}
+
+	recorder := c.accessTrace.recorder.Swap(nil)
+	if recorder == nil {
+		return nil
+	}
+
+	return recorder.Close()
+}
@@ -0,0 +1,139 @@
+package hot
+
+import (
+	"errors"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/samber/hot/pkg/accesstrace"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHotCache_AccessTrace(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewHotCache[int, string](LRU, 100).
+		WithAccessTrace(1, strconv.Itoa).
+		WithMissingSharedCache().
+		WithLoaders(func(keys []int) (map[int]string, error) {
+			values := map[int]string{}
+			for _, key := range keys {
+				if key != 404 {
+					values[key] = "value"
+				}
+			}
+			return values, nil
+		}).
+		Build()
+
+	// Nothing is recorded before the recording starts.
+	cache.Set(0, "ignored")
+
+	path := filepath.Join(t.TempDir(), "trace")
+	recorder, err := accesstrace.NewRecorder(path, 1<<20, 2, 1_000)
+	is.NoError(err)
+	is.NoError(cache.StartAccessTrace(recorder))
+	is.ErrorIs(cache.StartAccessTrace(recorder), errAccessTraceRecording)
+
+	cache.Set(1, "a")
+	_, _, _ = cache.Get(1)
+	_, _, _ = cache.Get(2)
+	_, _, _ = cache.GetMany([]int{2, 3})
+	_, _, _ = cache.Get(404)
+	cache.SetMissing(5)
+	cache.Delete(1)
+	cache.DeleteMany([]int{42})
+
+	is.NoError(cache.StopAccessTrace())
+	is.NoError(cache.StopAccessTrace())
+
+	// Nothing is recorded after the recording stops.
+	cache.Set(6, "ignored")
+
+	records := []accesstrace.Record{}
+	is.NoError(accesstrace.ReadFiles(path, func(r accesstrace.Record) error {
+		records = append(records, r)
+		return nil
+	}))
+
+	type access struct {
+		op   accesstrace.Op
+		key  string
+		hit  bool
+		size bool
+	}
+	accesses := []access{}
+	for _, r := range records {
+		is.NotZero(r.KeyHash)
+		is.False(r.Time.IsZero())
+		accesses = append(accesses, access{r.Op, r.Key, r.Hit, r.ValueSize > 0})
+	}
+
+	is.ElementsMatch([]access{
+		{accesstrace.OpSet, "1", false, true},
+		{accesstrace.OpGet, "1", true, true},
+		{accesstrace.OpGet, "2", false, true},
+		{accesstrace.OpGet, "2", true, true},
+		{accesstrace.OpGet, "3", false, true},
+		{accesstrace.OpGet, "404", false, false},
+		{accesstrace.OpSet, "5", false, false},
+		{accesstrace.OpDelete, "1", true, false},
+		{accesstrace.OpDelete, "42", false, false},
+	}, accesses)
+	is.Equal(accesstrace.OpSet, records[0].Op)
+	is.Equal(accesstrace.OpDelete, records[len(records)-1].Op)
+}
+
+func TestHotCache_AccessTrace_sampling(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewHotCache[int, int](LRU, 100).
+		WithAccessTrace(0.1, nil).
+		Build()
+
+	path := filepath.Join(t.TempDir(), "trace")
+	recorder, err := accesstrace.NewRecorder(path, 1<<20, 1, 100_000)
+	is.NoError(err)
+	is.NoError(cache.StartAccessTrace(recorder))
+
+	// Every access of a sampled key is recorded.
+	for i := 0; i < 3; i++ {
+		for key := 0; key < 10_000; key++ {
+			_, _, _ = cache.Get(key)
+		}
+	}
+	is.NoError(cache.Close())
+
+	counts := map[uint64]int{}
+	is.NoError(accesstrace.ReadFiles(path, func(r accesstrace.Record) error {
+		if r.Key != "" {
+			return errors.New("raw key recorded")
+		}
+		counts[r.KeyHash]++
+		return nil
+	}))
+
+	is.InDelta(1_000, len(counts), 150)
+	for _, count := range counts {
+		is.Equal(3, count)
+	}
+}
+
+func TestWithAccessTrace(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	is.Panics(func() {
+		_ = NewHotCache[int, int](LRU, 100).WithAccessTrace(0, nil)
+	})
+	is.Panics(func() {
+		_ = NewHotCache[int, int](LRU, 100).WithAccessTrace(1.5, nil)
+	})
+	is.Panics(func() {
+		_ = NewHotCache[int, int](LRU, 100).Build().StartAccessTrace(nil)
+	})
+	is.NoError(NewHotCache[int, int](LRU, 100).Build().StopAccessTrace())
+}
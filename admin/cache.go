@@ -0,0 +1,315 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/samber/hot"
+)
+
+var (
+	// ErrWarmUpRunning is returned when a warm-up is triggered while another one is running.
+	ErrWarmUpRunning = errors.New("warm-up already running")
+	// ErrInvalidKey is returned when a key cannot be decoded by the key codec.
+	ErrInvalidKey = errors.New("invalid key")
+)
+
+// NewCache describes a cache to be mounted in the admin handler under the given name.
+func NewCache[K comparable, V any](name string, cache *hot.HotCache[K, V]) CacheConfig[K, V] {
+	return CacheConfig[K, V]{
+		name:              name,
+		cache:             cache,
+		keyCodec:          DefaultKeyCodec[K](),
+		valueEncoder:      identityValueEncoder[V],
+		warmUpConcurrency: 1,
+	}
+}
+
+// CacheConfig holds the configuration of a mounted cache.
+type CacheConfig[K comparable, V any] struct {
+	name              string
+	cache             *hot.HotCache[K, V]
+	keyCodec          KeyCodec[K]
+	valueEncoder      ValueEncoder[V]
+	warmUpConcurrency int
+}
+
+// WithKeyCodec sets the conversion of keys to and from strings. Default is DefaultKeyCodec.
+func (cfg CacheConfig[K, V]) WithKeyCodec(codec KeyCodec[K]) CacheConfig[K, V] {
+	cfg.keyCodec = codec
+	return cfg
+}
+
+// WithValueEncoder sets the conversion of values before being exposed. Default exposes values as is.
+func (cfg CacheConfig[K, V]) WithValueEncoder(encoder ValueEncoder[V]) CacheConfig[K, V] {
+	cfg.valueEncoder = encoder
+	return cfg
+}
+
+// WithWarmUpConcurrency sets the number of chunks loaded in parallel by triggered warm-ups. Default is 1.
+func (cfg CacheConfig[K, V]) WithWarmUpConcurrency(concurrency int) CacheConfig[K, V] {
+	cfg.warmUpConcurrency = concurrency
+	return cfg
+}
+
+// Build returns the cache, to be passed to NewHandler.
+func (cfg CacheConfig[K, V]) Build() Cache {
+	if cfg.name == "" || strings.Contains(cfg.name, "/") {
+		panic("name must be a non-empty path segment")
+	}
+	if cfg.cache == nil {
+		panic("cache is required")
+	}
+	if cfg.keyCodec == nil {
+		panic("key codec is required")
+	}
+	if cfg.valueEncoder == nil {
+		panic("value encoder is required")
+	}
+	if cfg.warmUpConcurrency <= 0 {
+		panic("warm-up concurrency must be a positive value")
+	}
+
+	return &cache[K, V]{cfg: cfg}
+}
+
+// Cache is a type-erased cache mounted in the admin handler.
+type Cache interface {
+	// Name returns the path segment of the cache.
+	Name() string
+
+	config() Config
+	stats() Stats
+	keys(prefix string, after string, limit int) Page
+	entry(rawKey string) (Entry, bool, error)
+	delete(rawKey string) (bool, error)
+	purge()
+	warmUp(rawKeys []string) error
+	warmUpStatus() WarmUpStatus
+}
+
+// Config describes the settings of a cache.
+type Config struct {
+	Name                  string        `json:"name"`
+	Algorithm             string        `json:"algorithm"`
+	Capacity              int           `json:"capacity"`
+	MissingCacheAlgorithm string        `json:"missing_cache_algorithm,omitempty"`
+	MissingCacheCapacity  int           `json:"missing_cache_capacity,omitempty"`
+	TTL                   time.Duration `json:"ttl"`
+	Stale                 time.Duration `json:"stale"`
+	Shards                int           `json:"shards"`
+}
+
+// Stats describes the activity and the current state of a cache.
+type Stats struct {
+	Name string `json:"name"`
+	hot.Stats
+	WarmUp WarmUpStatus `json:"warm_up"`
+}
+
+// WarmUpStatus reports the progress of the warm-up started by Build, or of the last triggered one.
+type WarmUpStatus struct {
+	Running    bool       `json:"running"`
+	Total      int        `json:"total"`
+	Loaded     int        `json:"loaded"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// Page is a page of keys, sorted by their encoded representation.
+type Page struct {
+	Keys []string `json:"keys"`
+	// Next is the cursor of the next page, empty on the last page.
+	Next string `json:"next,omitempty"`
+}
+
+// Entry describes a cached entry.
+type Entry struct {
+	Key        string     `json:"key"`
+	Value      any        `json:"value,omitempty"`
+	Missing    bool       `json:"missing"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	StaleUntil *time.Time `json:"stale_until,omitempty"`
+	Stale      bool       `json:"stale"`
+	Expired    bool       `json:"expired"`
+}
+
+type cache[K comparable, V any] struct {
+	cfg CacheConfig[K, V]
+
+	mu        sync.Mutex
+	triggered *WarmUpStatus // last warm-up triggered from the handler
+}
+
+var _ Cache = (*cache[string, int])(nil)
+
+func (c *cache[K, V]) Name() string {
+	return c.cfg.name
+}
+
+func (c *cache[K, V]) decodeKey(raw string) (K, error) {
+	key, err := c.cfg.keyCodec.DecodeKey(raw)
+	if err != nil {
+		return key, fmt.Errorf("%w: %s", ErrInvalidKey, err.Error())
+	}
+	return key, nil
+}
+
+func (c *cache[K, V]) config() Config {
+	algorithm, missingAlgorithm := c.cfg.cache.Algorithm()
+	capacity, missingCapacity := c.cfg.cache.Capacity()
+	ttl, stale := c.cfg.cache.TTL()
+
+	return Config{
+		Name:                  c.cfg.name,
+		Algorithm:             algorithm,
+		Capacity:              capacity,
+		MissingCacheAlgorithm: missingAlgorithm,
+		MissingCacheCapacity:  missingCapacity,
+		TTL:                   ttl,
+		Stale:                 stale,
+		Shards:                c.cfg.cache.Shards(),
+	}
+}
+
+func (c *cache[K, V]) stats() Stats {
+	return Stats{
+		Name:   c.cfg.name,
+		Stats:  c.cfg.cache.Stats(),
+		WarmUp: c.warmUpStatus(),
+	}
+}
+
+func (c *cache[K, V]) keys(prefix string, after string, limit int) Page {
+	encoded := []string{}
+	for _, key := range c.cfg.cache.Keys() {
+		raw := c.cfg.keyCodec.EncodeKey(key)
+		if strings.HasPrefix(raw, prefix) && raw > after {
+			encoded = append(encoded, raw)
+		}
+	}
+	sort.Strings(encoded)
+
+	page := Page{Keys: encoded}
+	if len(encoded) > limit {
+		page.Keys = encoded[:limit]
+		page.Next = encoded[limit-1]
+	}
+
+	return page
+}
+
+func (c *cache[K, V]) entry(rawKey string) (Entry, bool, error) {
+	key, err := c.decodeKey(rawKey)
+	if err != nil {
+		return Entry{}, false, err
+	}
+
+	info, ok := c.cfg.cache.Inspect(key)
+	if !ok {
+		return Entry{}, false, nil
+	}
+
+	entry := Entry{
+		Key:     rawKey,
+		Missing: info.Missing,
+		Stale:   info.Stale,
+		Expired: info.Expired,
+	}
+	if !info.ExpiresAt.IsZero() {
+		entry.ExpiresAt = &info.ExpiresAt
+		entry.StaleUntil = &info.StaleUntil
+	}
+	if !info.Missing {
+		entry.Value, err = c.cfg.valueEncoder(info.Value)
+		if err != nil {
+			return Entry{}, false, err
+		}
+	}
+
+	return entry, true, nil
+}
+
+func (c *cache[K, V]) delete(rawKey string) (bool, error) {
+	key, err := c.decodeKey(rawKey)
+	if err != nil {
+		return false, err
+	}
+
+	return c.cfg.cache.Delete(key), nil
+}
+
+func (c *cache[K, V]) purge() {
+	c.cfg.cache.Purge()
+}
+
+// warmUp reloads the given keys through the loader chain in the background.
+// When no key is given, the keys currently cached are reloaded.
+func (c *cache[K, V]) warmUp(rawKeys []string) error {
+	var keys []K
+	if len(rawKeys) == 0 {
+		keys = c.cfg.cache.Keys()
+	} else {
+		keys = make([]K, 0, len(rawKeys))
+		for _, raw := range rawKeys {
+			key, err := c.decodeKey(raw)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, key)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.triggered != nil && c.triggered.Running {
+		return ErrWarmUpRunning
+	}
+
+	now := time.Now()
+	c.triggered = &WarmUpStatus{Running: true, Total: len(keys), StartedAt: &now}
+
+	go func() {
+		err := c.cfg.cache.WarmUpKeys(keys, c.cfg.warmUpConcurrency)
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		finished := time.Now()
+		c.triggered.Running = false
+		c.triggered.Loaded = len(keys)
+		c.triggered.FinishedAt = &finished
+		if err != nil {
+			c.triggered.Error = err.Error()
+		}
+	}()
+
+	return nil
+}
+
+func (c *cache[K, V]) warmUpStatus() WarmUpStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.triggered != nil {
+		return *c.triggered
+	}
+
+	status := c.cfg.cache.WarmUpStatus()
+	output := WarmUpStatus{
+		Running: !status.Done,
+		Total:   status.Total,
+		Loaded:  status.Loaded,
+	}
+	if status.Err != nil {
+		output.Error = status.Err.Error()
+	}
+
+	return output
+}
@@ -0,0 +1,57 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// KeyCodec converts cache keys to and from the strings used in URLs and responses.
+type KeyCodec[K comparable] interface {
+	EncodeKey(key K) string
+	DecodeKey(raw string) (K, error)
+}
+
+// ValueEncoder converts a cached value into a JSON-serializable representation.
+// It can be used to redact sensitive fields before exposing entries.
+type ValueEncoder[V any] func(value V) (any, error)
+
+// DefaultKeyCodec returns the codec used when none is configured.
+// String keys are used as is, and other keys are encoded as JSON (eg: `42` for an int key).
+func DefaultKeyCodec[K comparable]() KeyCodec[K] {
+	var key K
+	if _, ok := any(key).(string); ok {
+		return stringKeyCodec[K]{}
+	}
+	return jsonKeyCodec[K]{}
+}
+
+type stringKeyCodec[K comparable] struct{}
+
+func (stringKeyCodec[K]) EncodeKey(key K) string {
+	return any(key).(string)
+}
+
+func (stringKeyCodec[K]) DecodeKey(raw string) (K, error) {
+	return any(raw).(K), nil
+}
+
+type jsonKeyCodec[K comparable] struct{}
+
+func (jsonKeyCodec[K]) EncodeKey(key K) string {
+	b, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Sprint(key)
+	}
+	return string(b)
+}
+
+func (jsonKeyCodec[K]) DecodeKey(raw string) (K, error) {
+	var key K
+	err := json.Unmarshal([]byte(raw), &key)
+	return key, err
+}
+
+// identityValueEncoder exposes values as is.
+func identityValueEncoder[V any](value V) (any, error) {
+	return value, nil
+}
@@ -0,0 +1,40 @@
+package admin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultKeyCodec(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	str := DefaultKeyCodec[string]()
+	is.Equal("a/b", str.EncodeKey("a/b"))
+	s, err := str.DecodeKey("a/b")
+	is.NoError(err)
+	is.Equal("a/b", s)
+
+	type userID string
+	named := DefaultKeyCodec[userID]()
+	is.Equal(`"u1"`, named.EncodeKey("u1"))
+
+	integer := DefaultKeyCodec[int]()
+	is.Equal("42", integer.EncodeKey(42))
+	i, err := integer.DecodeKey("42")
+	is.NoError(err)
+	is.Equal(42, i)
+	_, err = integer.DecodeKey("abc")
+	is.Error(err)
+
+	type compound struct {
+		Tenant string
+		ID     int
+	}
+	c := DefaultKeyCodec[compound]()
+	is.Equal(`{"Tenant":"acme","ID":1}`, c.EncodeKey(compound{"acme", 1}))
+	decoded, err := c.DecodeKey(`{"Tenant":"acme","ID":1}`)
+	is.NoError(err)
+	is.Equal(compound{"acme", 1}, decoded)
+}
@@ -0,0 +1,235 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// AuthFunc authorizes a request. A non-nil error rejects the request with 403 Forbidden.
+type AuthFunc func(r *http.Request) error
+
+// NewHandler creates an http.Handler exposing the given caches. Routes are relative to the
+// mount point, which is usually stripped with http.StripPrefix:
+//
+//	GET    /                        list of the caches with their config
+//	GET    /{cache}/config          algorithm, capacity, ttl, stale and shards
+//	GET    /{cache}/stats           hits, misses, loads, evictions (counted with WithStats), length and warm-up progress
+//	GET    /{cache}/keys            keys, filtered by ?prefix= and paginated with ?after= and ?limit=
+//	GET    /{cache}/keys/{key}      entry with its metadata
+//	DELETE /{cache}/keys/{key}      delete an entry
+//	POST   /{cache}/purge           delete every entry
+//	POST   /{cache}/warmup          reload {"keys": [...]} through the loaders, or the cached keys when empty
+func NewHandler(caches ...Cache) HandlerConfig {
+	return HandlerConfig{
+		caches: caches,
+	}
+}
+
+// HandlerConfig holds the configuration of the admin handler.
+type HandlerConfig struct {
+	caches   []Cache
+	readOnly bool
+	auth     AuthFunc
+}
+
+// WithReadOnly rejects the routes modifying the caches with 405 Method Not Allowed.
+func (cfg HandlerConfig) WithReadOnly() HandlerConfig {
+	cfg.readOnly = true
+	return cfg
+}
+
+// WithAuth sets a hook authorizing every request.
+func (cfg HandlerConfig) WithAuth(auth AuthFunc) HandlerConfig {
+	cfg.auth = auth
+	return cfg
+}
+
+// Build returns the handler.
+func (cfg HandlerConfig) Build() http.Handler {
+	h := &handler{
+		cfg:    cfg,
+		caches: make(map[string]Cache, len(cfg.caches)),
+		mux:    http.NewServeMux(),
+	}
+
+	for _, c := range cfg.caches {
+		if c == nil {
+			panic("cache is required")
+		}
+		if _, ok := h.caches[c.Name()]; ok {
+			panic("duplicate cache name: " + c.Name())
+		}
+		h.caches[c.Name()] = c
+	}
+
+	h.mux.HandleFunc("GET /{$}", h.list)
+	h.mux.HandleFunc("GET /{cache}/config", h.withCache(h.config))
+	h.mux.HandleFunc("GET /{cache}/stats", h.withCache(h.stats))
+	h.mux.HandleFunc("GET /{cache}/keys", h.withCache(h.keys))
+	h.mux.HandleFunc("GET /{cache}/keys/{key...}", h.withCache(h.entry))
+	h.mux.HandleFunc("DELETE /{cache}/keys/{key...}", h.withCache(h.writable(h.delete)))
+	h.mux.HandleFunc("POST /{cache}/purge", h.withCache(h.writable(h.purge)))
+	h.mux.HandleFunc("POST /{cache}/warmup", h.withCache(h.writable(h.warmUp)))
+
+	return h
+}
+
+type handler struct {
+	cfg    HandlerConfig
+	caches map[string]Cache
+	mux    *http.ServeMux
+}
+
+var _ http.Handler = (*handler)(nil)
+
+// ServeHTTP implements http.Handler.
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.cfg.auth != nil {
+		if err := h.cfg.auth(r); err != nil {
+			writeError(w, http.StatusForbidden, err)
+			return
+		}
+	}
+
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *handler) withCache(fn func(http.ResponseWriter, *http.Request, Cache)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c, ok := h.caches[r.PathValue("cache")]
+		if !ok {
+			writeError(w, http.StatusNotFound, errors.New("cache not found"))
+			return
+		}
+
+		fn(w, r, c)
+	}
+}
+
+func (h *handler) writable(fn func(http.ResponseWriter, *http.Request, Cache)) func(http.ResponseWriter, *http.Request, Cache) {
+	return func(w http.ResponseWriter, r *http.Request, c Cache) {
+		if h.cfg.readOnly {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("read-only"))
+			return
+		}
+
+		fn(w, r, c)
+	}
+}
+
+func (h *handler) list(w http.ResponseWriter, _ *http.Request) {
+	configs := make([]Config, 0, len(h.caches))
+	for _, c := range h.caches {
+		configs = append(configs, c.config())
+	}
+	sort.Slice(configs, func(i, j int) bool {
+		return configs[i].Name < configs[j].Name
+	})
+
+	writeJSON(w, http.StatusOK, configs)
+}
+
+func (h *handler) config(w http.ResponseWriter, _ *http.Request, c Cache) {
+	writeJSON(w, http.StatusOK, c.config())
+}
+
+func (h *handler) stats(w http.ResponseWriter, _ *http.Request, c Cache) {
+	writeJSON(w, http.StatusOK, c.stats())
+}
+
+func (h *handler) keys(w http.ResponseWriter, r *http.Request, c Cache) {
+	query := r.URL.Query()
+
+	limit := defaultPageSize
+	if raw := query.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, errors.New("invalid limit"))
+			return
+		}
+		limit = min(n, maxPageSize)
+	}
+
+	writeJSON(w, http.StatusOK, c.keys(query.Get("prefix"), query.Get("after"), limit))
+}
+
+func (h *handler) entry(w http.ResponseWriter, r *http.Request, c Cache) {
+	entry, ok, err := c.entry(r.PathValue("key"))
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("key not found"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, entry)
+}
+
+func (h *handler) delete(w http.ResponseWriter, r *http.Request, c Cache) {
+	ok, err := c.delete(r.PathValue("key"))
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("key not found"))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) purge(w http.ResponseWriter, _ *http.Request, c Cache) {
+	c.purge()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) warmUp(w http.ResponseWriter, r *http.Request, c Cache) {
+	var body struct {
+		Keys []string `json:"keys"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	if err := c.warmUp(body.Keys); err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, c.warmUpStatus())
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrInvalidKey):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrWarmUpRunning):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
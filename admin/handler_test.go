@@ -0,0 +1,259 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samber/hot"
+	"github.com/stretchr/testify/assert"
+)
+
+func do(t *testing.T, h http.Handler, method string, path string, body string) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+
+	var req *http.Request
+	if body != "" {
+		req = httptest.NewRequest(method, path, strings.NewReader(body))
+	} else {
+		req = httptest.NewRequest(method, path, nil)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var output map[string]any
+	_ = json.Unmarshal(rec.Body.Bytes(), &output)
+	return rec, output
+}
+
+func newTestHandler(t *testing.T, cfg func(HandlerConfig) HandlerConfig) (http.Handler, *hot.HotCache[string, string], *hot.HotCache[int, int], *atomic.Int32) {
+	t.Helper()
+
+	var loads atomic.Int32
+	users := hot.NewHotCache[string, string](hot.LRU, 10).
+		WithTTL(time.Minute).
+		WithRevalidation(time.Second).
+		WithMissingCache(hot.LFU, 5).
+		WithLoaders(func(keys []string) (map[string]string, error) {
+			loads.Add(int32(len(keys)))
+			output := map[string]string{}
+			for _, key := range keys {
+				output[key] = strings.ToUpper(key)
+			}
+			return output, nil
+		}).
+		Build()
+	counters := hot.NewHotCache[int, int](hot.ARC, 20).
+		WithSharding(2, func(key int) uint64 { return uint64(key) }).
+		Build()
+
+	config := NewHandler(
+		NewCache("users", users).
+			WithValueEncoder(func(v string) (any, error) {
+				if v == "SECRET" {
+					return nil, errors.New("cannot encode")
+				}
+				return map[string]string{"name": v}, nil
+			}).
+			Build(),
+		NewCache("counters", counters).Build(),
+	)
+	if cfg != nil {
+		config = cfg(config)
+	}
+
+	return config.Build(), users, counters, &loads
+}
+
+func TestHandler_config(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	h, _, _, _ := newTestHandler(t, nil)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	is.Equal(http.StatusOK, rec.Code)
+	var configs []Config
+	is.NoError(json.Unmarshal(rec.Body.Bytes(), &configs))
+	is.Equal([]Config{
+		{Name: "counters", Algorithm: "arc", Capacity: 40, Shards: 2},
+		{Name: "users", Algorithm: "lru", Capacity: 10, MissingCacheAlgorithm: "lfu", MissingCacheCapacity: 5, TTL: time.Minute, Stale: time.Second, Shards: 1},
+	}, configs)
+
+	rec, body := do(t, h, http.MethodGet, "/users/config", "")
+	is.Equal(http.StatusOK, rec.Code)
+	is.Equal("users", body["name"])
+	is.Equal("application/json", rec.Header().Get("Content-Type"))
+
+	rec, body = do(t, h, http.MethodGet, "/unknown/config", "")
+	is.Equal(http.StatusNotFound, rec.Code)
+	is.Equal("cache not found", body["error"])
+}
+
+func TestHandler_keysAndEntries(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	h, users, counters, _ := newTestHandler(t, nil)
+
+	for i := 0; i < 5; i++ {
+		users.Set(fmt.Sprintf("user/%d", i), fmt.Sprintf("USER%d", i))
+		counters.Set(i, i*10)
+	}
+	users.Set("admin", "ADMIN")
+	users.Set("secret", "SECRET")
+	users.SetMissing("ghost")
+
+	// pagination and prefix
+	_, body := do(t, h, http.MethodGet, "/users/keys?prefix=user/&limit=2", "")
+	is.Equal([]any{"user/0", "user/1"}, body["keys"])
+	is.Equal("user/1", body["next"])
+	_, body = do(t, h, http.MethodGet, "/users/keys?prefix=user/&limit=2&after=user/3", "")
+	is.Equal([]any{"user/4"}, body["keys"])
+	is.Nil(body["next"])
+	_, body = do(t, h, http.MethodGet, "/counters/keys", "")
+	is.Equal([]any{"0", "1", "2", "3", "4"}, body["keys"])
+	rec, _ := do(t, h, http.MethodGet, "/users/keys?limit=0", "")
+	is.Equal(http.StatusBadRequest, rec.Code)
+
+	// lookup
+	rec, body = do(t, h, http.MethodGet, "/users/keys/user/2", "")
+	is.Equal(http.StatusOK, rec.Code)
+	is.Equal("user/2", body["key"])
+	is.Equal(map[string]any{"name": "USER2"}, body["value"])
+	is.Equal(false, body["missing"])
+	is.NotEmpty(body["expires_at"])
+	is.NotEmpty(body["stale_until"])
+
+	_, body = do(t, h, http.MethodGet, "/users/keys/ghost", "")
+	is.Equal(true, body["missing"])
+	is.Nil(body["value"])
+
+	_, body = do(t, h, http.MethodGet, "/counters/keys/3", "")
+	is.Equal(float64(30), body["value"])
+	is.Nil(body["expires_at"])
+
+	rec, _ = do(t, h, http.MethodGet, "/users/keys/nobody", "")
+	is.Equal(http.StatusNotFound, rec.Code)
+	rec, _ = do(t, h, http.MethodGet, "/counters/keys/abc", "")
+	is.Equal(http.StatusBadRequest, rec.Code)
+	rec, _ = do(t, h, http.MethodGet, "/users/keys/secret", "")
+	is.Equal(http.StatusInternalServerError, rec.Code)
+
+	// delete and purge
+	rec, _ = do(t, h, http.MethodDelete, "/users/keys/user/2", "")
+	is.Equal(http.StatusNoContent, rec.Code)
+	is.False(users.Has("user/2"))
+	rec, _ = do(t, h, http.MethodDelete, "/users/keys/user/2", "")
+	is.Equal(http.StatusNotFound, rec.Code)
+
+	rec, _ = do(t, h, http.MethodPost, "/counters/purge", "")
+	is.Equal(http.StatusNoContent, rec.Code)
+	is.Equal(0, counters.Len())
+	is.NotZero(users.Len())
+
+	_, body = do(t, h, http.MethodGet, "/users/stats", "")
+	is.Equal(float64(users.Len()), body["length"])
+	is.Equal("users", body["name"])
+	is.Contains(body, "hits")
+}
+
+func TestHandler_warmUp(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	h, users, _, loads := newTestHandler(t, nil)
+
+	rec, body := do(t, h, http.MethodPost, "/users/warmup", `{"keys": ["a", "b", "c"]}`)
+	is.Equal(http.StatusAccepted, rec.Code)
+	is.Equal(float64(3), body["total"])
+
+	is.Eventually(func() bool {
+		_, body := do(t, h, http.MethodGet, "/users/stats", "")
+		warmUp := body["warm_up"].(map[string]any)
+		return warmUp["running"] == false && warmUp["finished_at"] != nil
+	}, time.Second, time.Millisecond)
+	is.Equal(int32(3), loads.Load())
+	v, ok := users.Peek("b")
+	is.True(ok)
+	is.Equal("B", v)
+
+	// reload the cached keys
+	rec, _ = do(t, h, http.MethodPost, "/users/warmup", "")
+	is.Equal(http.StatusAccepted, rec.Code)
+	is.Eventually(func() bool {
+		return loads.Load() == 6
+	}, time.Second, time.Millisecond)
+
+	rec, _ = do(t, h, http.MethodPost, "/users/warmup", `{"keys":`)
+	is.Equal(http.StatusBadRequest, rec.Code)
+	rec, _ = do(t, h, http.MethodPost, "/counters/warmup", `{"keys": ["x"]}`)
+	is.Equal(http.StatusBadRequest, rec.Code)
+}
+
+func TestHandler_readOnlyAndAuth(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	h, users, _, _ := newTestHandler(t, func(cfg HandlerConfig) HandlerConfig {
+		return cfg.
+			WithReadOnly().
+			WithAuth(func(r *http.Request) error {
+				if r.Header.Get("Authorization") != "Bearer token" {
+					return errors.New("invalid token")
+				}
+				return nil
+			})
+	})
+	users.Set("a", "A")
+
+	rec, body := do(t, h, http.MethodGet, "/users/keys/a", "")
+	is.Equal(http.StatusForbidden, rec.Code)
+	is.Equal("invalid token", body["error"])
+
+	authorized := func(method string, path string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer token")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	is.Equal(http.StatusOK, authorized(http.MethodGet, "/users/keys/a"))
+	is.Equal(http.StatusMethodNotAllowed, authorized(http.MethodDelete, "/users/keys/a"))
+	is.Equal(http.StatusMethodNotAllowed, authorized(http.MethodPost, "/users/purge"))
+	is.Equal(http.StatusMethodNotAllowed, authorized(http.MethodPost, "/users/warmup"))
+	is.True(users.Has("a"))
+}
+
+func TestHandler_build(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := hot.NewHotCache[string, int](hot.LRU, 10).Build()
+
+	is.Panics(func() { NewCache("", cache).Build() })
+	is.Panics(func() { NewCache("a/b", cache).Build() })
+	is.Panics(func() { NewCache[string, int]("a", nil).Build() })
+	is.Panics(func() { NewCache("a", cache).WithKeyCodec(nil).Build() })
+	is.Panics(func() { NewCache("a", cache).WithValueEncoder(nil).Build() })
+	is.Panics(func() { NewCache("a", cache).WithWarmUpConcurrency(0).Build() })
+	is.Panics(func() { NewHandler(NewCache("a", cache).Build(), NewCache("a", cache).Build()).Build() })
+	is.Panics(func() { NewHandler(nil).Build() })
+
+	// mounted under a prefix
+	mux := http.NewServeMux()
+	mux.Handle("/admin/cache/", http.StripPrefix("/admin/cache", NewHandler(NewCache("a", cache).Build()).Build()))
+	cache.Set("k", 1)
+	rec, body := do(t, mux, http.MethodGet, "/admin/cache/a/keys", "")
+	is.Equal(http.StatusOK, rec.Code)
+	is.Equal([]any{"k"}, body["keys"])
+}
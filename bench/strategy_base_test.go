@@ -5,7 +5,9 @@ import (
 
 	"github.com/samber/hot/pkg/lfu"
 	"github.com/samber/hot/pkg/lru"
+	"github.com/samber/hot/pkg/tinylfu"
 	"github.com/samber/hot/pkg/twoqueue"
+	"github.com/samber/hot/pkg/wtinylfu"
 )
 
 func BenchmarkSetGetLRU(b *testing.B) {
@@ -32,6 +34,41 @@ func BenchmarkSetGetTwoQueue(b *testing.B) {
 	}
 }
 
+func BenchmarkSetGetTinyLFU(b *testing.B) {
+	cache := tinylfu.NewTinyLFUCache[int, int](100)
+	for n := 0; n < b.N; n++ {
+		cache.Set(n, n)
+		cache.Get(n)
+	}
+}
+
+func BenchmarkSetGetWTinyLFU(b *testing.B) {
+	cache := wtinylfu.NewWTinyLFUCache[int, int](100)
+	for n := 0; n < b.N; n++ {
+		cache.Set(n, n)
+		cache.Get(n)
+	}
+}
+
+// Hits do not allocate: keys are hashed by the frequency sketch without formatting.
+func BenchmarkGetTinyLFU(b *testing.B) {
+	cache := tinylfu.NewTinyLFUCache[string, int](100)
+	cache.Set("key", 42)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		cache.Get("key")
+	}
+}
+
+func BenchmarkGetWTinyLFU(b *testing.B) {
+	cache := wtinylfu.NewWTinyLFUCache[string, int](100)
+	cache.Set("key", 42)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		cache.Get("key")
+	}
+}
+
 // func BenchmarkSetGetARC(b *testing.B) {
 // 	cache := cache.NewARCCache[int, int](100)
 // 	for n := 0; n < b.N; n++ {
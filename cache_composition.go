@@ -3,6 +3,7 @@ package hot
 import (
 	"github.com/samber/hot/pkg/arc"
 	"github.com/samber/hot/pkg/base"
+	"github.com/samber/hot/pkg/disk"
 	"github.com/samber/hot/pkg/fifo"
 	"github.com/samber/hot/pkg/lfu"
 	"github.com/samber/hot/pkg/lru"
@@ -32,6 +33,12 @@ import (
 //                     │    │    │    │    │
 //                     ▼    ▼    ▼    ▼    ▼
 // ┌─────────────────────────────────────────────────────────────┐
+// │              pkg/disk.TieredCache[K, V]                     │
+// │                (Optional disk tier layer)                   │
+// └─────────────────────────────────────────────────────────────┘
+//                               │
+//                               ▼
+// ┌─────────────────────────────────────────────────────────────┐
 // │              pkg/metrics.InstrumentedCache[K, V]            │
 // │                   (Metric collection layer)                 │
 // └─────────────────────────────────────────────────────────────┘
@@ -54,8 +61,15 @@ import (
 // └─────────────────────────────────────────────────────────────┘
 //
 
+// sketchOptions configures the frequency sketch of the TinyLFU and W-TinyLFU algorithms, per shard.
+type sketchOptions struct {
+	width      int // 4-bit counters per row
+	sampleSize int // Increments between two agings
+}
+
 // composeInternalCache creates an internal cache instance based on the provided configuration.
 // It handles sharding, locking, and different eviction algorithms.
+// When diskStoreBuilder is not nil, values evicted for capacity are demoted to a disk tier instead of being dropped.
 func composeInternalCache[K comparable, V any](
 	locking bool,
 	algorithm EvictionAlgorithm,
@@ -65,6 +79,8 @@ func composeInternalCache[K comparable, V any](
 	shardingFn sharded.Hasher[K],
 	onEviction base.EvictionCallback[K, V],
 	collectorBuilder func(shard int) metrics.Collector,
+	diskStoreBuilder func(shard int) *disk.Store[K, *item[V]],
+	sketch *sketchOptions,
 ) base.InMemoryCache[K, *item[V]] {
 	assertValue(capacity >= 0, "capacity must be a positive value")
 	assertValue((shards > 1 && shardingFn != nil) || shards <= 1, "sharded cache requires sharding function")
@@ -73,7 +89,7 @@ func composeInternalCache[K comparable, V any](
 		return sharded.NewShardedInMemoryCache(
 			shards,
 			func(shardIndex int) base.InMemoryCache[K, *item[V]] {
-				return composeInternalCache(false, algorithm, capacity, 0, shardIndex, nil, onEviction, collectorBuilder)
+				return composeInternalCache(false, algorithm, capacity, 0, shardIndex, nil, onEviction, collectorBuilder, diskStoreBuilder, sketch)
 			},
 			shardingFn,
 		)
@@ -81,10 +97,23 @@ func composeInternalCache[K comparable, V any](
 
 	var cache base.InMemoryCache[K, *item[V]]
 
+	var store *disk.Store[K, *item[V]]
+	if diskStoreBuilder != nil {
+		store = diskStoreBuilder(shardIndex)
+	}
+
 	var onItemEviction base.EvictionCallback[K, *item[V]]
-	if onEviction != nil {
+	if onEviction != nil || store != nil {
 		onItemEviction = func(reason base.EvictionReason, key K, value *item[V]) {
-			onEviction(reason, key, value.value)
+			// Values evicted for capacity move to the disk tier. The eviction callback
+			// is called later, when the value leaves the disk tier.
+			if store != nil && reason == base.EvictionReasonCapacity && value.hasValue && store.Set(key, value) == nil {
+				return
+			}
+
+			if onEviction != nil {
+				onEviction(reason, key, value.value)
+			}
 		}
 	}
 
@@ -94,9 +123,17 @@ func composeInternalCache[K comparable, V any](
 	case LFU:
 		cache = lfu.NewLFUCacheWithEvictionCallback(capacity, onItemEviction)
 	case TinyLFU:
-		cache = tinylfu.NewTinyLFUCacheWithEvictionCallback(capacity, onItemEviction)
+		if sketch != nil {
+			cache = tinylfu.NewTinyLFUCacheWithSketchAndEvictionCallback(capacity, sketch.width, sketch.sampleSize, onItemEviction)
+		} else {
+			cache = tinylfu.NewTinyLFUCacheWithEvictionCallback(capacity, onItemEviction)
+		}
 	case WTinyLFU:
-		cache = wtinylfu.NewWTinyLFUCacheWithEvictionCallback(capacity, onItemEviction)
+		if sketch != nil {
+			cache = wtinylfu.NewWTinyLFUCacheWithSketchAndEvictionCallback(capacity, sketch.width, sketch.sampleSize, onItemEviction)
+		} else {
+			cache = wtinylfu.NewWTinyLFUCacheWithEvictionCallback(capacity, onItemEviction)
+		}
 	case TwoQueue:
 		cache = twoqueue.New2QCacheWithEvictionCallback(capacity, onItemEviction)
 	case ARC:
@@ -120,5 +157,9 @@ func composeInternalCache[K comparable, V any](
 		cache = metrics.NewInstrumentedCache(cache, collectorBuilder(shardIndex))
 	}
 
+	if store != nil {
+		cache = disk.NewTieredCache(cache, store)
+	}
+
 	return cache
 }
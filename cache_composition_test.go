@@ -24,35 +24,35 @@ func TestComposeInternalCache(t *testing.T) {
 	t.Parallel()
 
 	// Test LRU with locking
-	cache := composeInternalCache[string, int](true, LRU, 42, 0, -1, nil, nil, nil)
+	cache := composeInternalCache[string, int](true, LRU, 42, 0, -1, nil, nil, nil, nil, nil)
 	is.Equal(42, cache.Capacity())
 	is.Equal("lru", cache.Algorithm())
 	_, ok := cache.(*safe.SafeInMemoryCache[string, *item[int]])
 	is.True(ok)
 
 	// Test LFU with locking
-	cache = composeInternalCache[string, int](true, LFU, 42, 0, -1, nil, nil, nil)
+	cache = composeInternalCache[string, int](true, LFU, 42, 0, -1, nil, nil, nil, nil, nil)
 	is.Equal(42, cache.Capacity())
 	is.Equal("lfu", cache.Algorithm())
 	_, ok = cache.(*safe.SafeInMemoryCache[string, *item[int]])
 	is.True(ok)
 
 	// Test TwoQueue with locking
-	cache = composeInternalCache[string, int](true, TwoQueue, 42, 0, -1, nil, nil, nil)
+	cache = composeInternalCache[string, int](true, TwoQueue, 42, 0, -1, nil, nil, nil, nil, nil)
 	is.Equal(42, cache.Capacity())
 	is.Equal("2q", cache.Algorithm())
 	_, ok = cache.(*safe.SafeInMemoryCache[string, *item[int]])
 	is.True(ok)
 
 	// Test ARC with locking
-	cache = composeInternalCache[string, int](true, ARC, 42, 0, -1, nil, nil, nil)
+	cache = composeInternalCache[string, int](true, ARC, 42, 0, -1, nil, nil, nil, nil, nil)
 	is.Equal(42, cache.Capacity())
 	is.Equal("arc", cache.Algorithm())
 	_, ok = cache.(*safe.SafeInMemoryCache[string, *item[int]])
 	is.True(ok)
 
 	// Test FIFO with locking
-	cache = composeInternalCache[string, int](true, FIFO, 42, 0, -1, nil, nil, nil)
+	cache = composeInternalCache[string, int](true, FIFO, 42, 0, -1, nil, nil, nil, nil, nil)
 	is.Equal(42, cache.Capacity())
 	is.Equal("fifo", cache.Algorithm())
 	_, ok = cache.(*safe.SafeInMemoryCache[string, *item[int]])
@@ -60,11 +60,11 @@ func TestComposeInternalCache(t *testing.T) {
 
 	// Test invalid capacity (should panic)
 	is.Panics(func() {
-		_ = composeInternalCache[string, int](true, ARC, 0, 0, -1, nil, nil, nil)
+		_ = composeInternalCache[string, int](true, ARC, 0, 0, -1, nil, nil, nil, nil, nil)
 	})
 
 	// Test LRU without locking
-	cache = composeInternalCache[string, int](false, LRU, 42, 0, -1, nil, nil, nil)
+	cache = composeInternalCache[string, int](false, LRU, 42, 0, -1, nil, nil, nil, nil, nil)
 	is.Equal(42, cache.Capacity())
 	is.Equal("lru", cache.Algorithm())
 	_, ok = cache.(*safe.SafeInMemoryCache[string, *item[int]])
@@ -73,7 +73,7 @@ func TestComposeInternalCache(t *testing.T) {
 	is.True(ok)
 
 	// Test LFU without locking
-	cache = composeInternalCache[string, int](false, LFU, 42, 0, -1, nil, nil, nil)
+	cache = composeInternalCache[string, int](false, LFU, 42, 0, -1, nil, nil, nil, nil, nil)
 	is.Equal(42, cache.Capacity())
 	is.Equal("lfu", cache.Algorithm())
 	_, ok = cache.(*safe.SafeInMemoryCache[string, *item[int]])
@@ -82,7 +82,7 @@ func TestComposeInternalCache(t *testing.T) {
 	is.True(ok)
 
 	// Test TwoQueue without locking
-	cache = composeInternalCache[string, int](false, TwoQueue, 42, 0, -1, nil, nil, nil)
+	cache = composeInternalCache[string, int](false, TwoQueue, 42, 0, -1, nil, nil, nil, nil, nil)
 	is.Equal(42, cache.Capacity())
 	is.Equal("2q", cache.Algorithm())
 	_, ok = cache.(*safe.SafeInMemoryCache[string, *item[int]])
@@ -91,7 +91,7 @@ func TestComposeInternalCache(t *testing.T) {
 	is.True(ok)
 
 	// Test ARC without locking
-	cache = composeInternalCache[string, int](false, ARC, 42, 0, -1, nil, nil, nil)
+	cache = composeInternalCache[string, int](false, ARC, 42, 0, -1, nil, nil, nil, nil, nil)
 	is.Equal(42, cache.Capacity())
 	is.Equal("arc", cache.Algorithm())
 	_, ok = cache.(*safe.SafeInMemoryCache[string, *item[int]])
@@ -101,7 +101,7 @@ func TestComposeInternalCache(t *testing.T) {
 
 	// Test FIFO without locking
 
-	cache = composeInternalCache[string, int](false, FIFO, 42, 0, -1, nil, nil, nil)
+	cache = composeInternalCache[string, int](false, FIFO, 42, 0, -1, nil, nil, nil, nil, nil)
 	is.Equal(42, cache.Capacity())
 	is.Equal("fifo", cache.Algorithm())
 	_, ok = cache.(*safe.SafeInMemoryCache[string, *item[int]])
@@ -111,7 +111,7 @@ func TestComposeInternalCache(t *testing.T) {
 
 	// Test invalid capacity without locking (should panic)
 	is.Panics(func() {
-		_ = composeInternalCache[string, int](false, ARC, 0, 0, -1, nil, nil, nil)
+		_ = composeInternalCache[string, int](false, ARC, 0, 0, -1, nil, nil, nil, nil, nil)
 	})
 }
 
@@ -124,14 +124,14 @@ func TestComposeInternalCacheWithSharding(t *testing.T) {
 	capacity := 42
 
 	// Test sharded cache with locking
-	cache := composeInternalCache[string, int](true, LRU, capacity, shards, -1, hashFn, nil, nil)
+	cache := composeInternalCache[string, int](true, LRU, capacity, shards, -1, hashFn, nil, nil, nil, nil)
 	is.Equal(capacity*int(shards), cache.Capacity())
 	is.Equal("lru", cache.Algorithm())
 	_, ok := cache.(*sharded.ShardedInMemoryCache[string, *item[int]])
 	is.True(ok)
 
 	// Test sharded cache without locking
-	cache = composeInternalCache[string, int](false, LRU, capacity, shards, -1, hashFn, nil, nil)
+	cache = composeInternalCache[string, int](false, LRU, capacity, shards, -1, hashFn, nil, nil, nil, nil)
 	is.Equal(capacity*int(shards), cache.Capacity())
 	is.Equal("lru", cache.Algorithm())
 	_, ok = cache.(*sharded.ShardedInMemoryCache[string, *item[int]])
@@ -139,7 +139,7 @@ func TestComposeInternalCacheWithSharding(t *testing.T) {
 
 	// Test invalid sharding configuration (should panic)
 	is.Panics(func() {
-		_ = composeInternalCache[string, int](true, LRU, capacity, shards, -1, nil, nil, nil)
+		_ = composeInternalCache[string, int](true, LRU, capacity, shards, -1, nil, nil, nil, nil, nil)
 	})
 }
 
@@ -152,7 +152,7 @@ func TestComposeInternalCacheWithEvictionCallback(t *testing.T) {
 	}
 
 	// Test with eviction callback
-	cache := composeInternalCache[string, int](false, LRU, 42, 0, -1, nil, evictionCallback, nil)
+	cache := composeInternalCache[string, int](false, LRU, 42, 0, -1, nil, evictionCallback, nil, nil, nil)
 	is.Equal(42, cache.Capacity())
 	is.Equal("lru", cache.Algorithm())
 
@@ -170,7 +170,7 @@ func TestComposeInternalCacheWithMetrics(t *testing.T) {
 	t.Parallel()
 
 	// Test with metrics collector
-	cache := composeInternalCache[string, int](false, LRU, 42, 0, 0, nil, nil, mockCollectorBuilder)
+	cache := composeInternalCache[string, int](false, LRU, 42, 0, 0, nil, nil, mockCollectorBuilder, nil, nil)
 	is.Equal(42, cache.Capacity())
 	is.Equal("lru", cache.Algorithm())
 	// Should be an InstrumentedCache
@@ -178,7 +178,7 @@ func TestComposeInternalCacheWithMetrics(t *testing.T) {
 	is.True(isInstrumented)
 
 	// Test with metrics and locking
-	cache = composeInternalCache[string, int](true, LRU, 42, 0, 0, nil, nil, mockCollectorBuilder)
+	cache = composeInternalCache[string, int](true, LRU, 42, 0, 0, nil, nil, mockCollectorBuilder, nil, nil)
 	is.Equal(42, cache.Capacity())
 	is.Equal("lru", cache.Algorithm())
 	_, isSafe := cache.(*safe.SafeInMemoryCache[string, *item[int]])
@@ -196,14 +196,14 @@ func TestComposeInternalCacheWithShardingAndMetrics(t *testing.T) {
 	capacity := 42
 
 	// Test sharded cache with metrics
-	cache := composeInternalCache[string, int](false, LRU, capacity, shards, -1, hashFn, nil, mockCollectorBuilder)
+	cache := composeInternalCache[string, int](false, LRU, capacity, shards, -1, hashFn, nil, mockCollectorBuilder, nil, nil)
 	is.Equal(capacity*int(shards), cache.Capacity())
 	is.Equal("lru", cache.Algorithm())
 	_, ok := cache.(*sharded.ShardedInMemoryCache[string, *item[int]])
 	is.True(ok)
 
 	// Test sharded cache with metrics and locking
-	cache = composeInternalCache[string, int](true, LRU, capacity, shards, -1, hashFn, nil, mockCollectorBuilder)
+	cache = composeInternalCache[string, int](true, LRU, capacity, shards, -1, hashFn, nil, mockCollectorBuilder, nil, nil)
 	is.Equal(capacity*int(shards), cache.Capacity())
 	is.Equal("lru", cache.Algorithm())
 	_, ok = cache.(*sharded.ShardedInMemoryCache[string, *item[int]])
@@ -216,7 +216,7 @@ func TestComposeInternalCacheUnknownAlgorithm(t *testing.T) {
 
 	// Test unknown algorithm (should panic)
 	is.Panics(func() {
-		_ = composeInternalCache[string, int](false, "unknown", 42, 0, -1, nil, nil, nil)
+		_ = composeInternalCache[string, int](false, "unknown", 42, 0, -1, nil, nil, nil, nil, nil)
 	})
 }
 
@@ -226,16 +226,16 @@ func TestComposeInternalCacheEdgeCases(t *testing.T) {
 
 	// Test with negative capacity (should panic)
 	is.Panics(func() {
-		_ = composeInternalCache[string, int](false, LRU, -1, 0, -1, nil, nil, nil)
+		_ = composeInternalCache[string, int](false, LRU, -1, 0, -1, nil, nil, nil, nil, nil)
 	})
 
 	// Test with zero shards (should work)
-	cache := composeInternalCache[string, int](false, LRU, 42, 0, -1, nil, nil, nil)
+	cache := composeInternalCache[string, int](false, LRU, 42, 0, -1, nil, nil, nil, nil, nil)
 	is.Equal(42, cache.Capacity())
 	is.Equal("lru", cache.Algorithm())
 
 	// Test with one shard (should work, treated as no sharding)
-	cache = composeInternalCache[string, int](false, LRU, 42, 1, -1, nil, nil, nil)
+	cache = composeInternalCache[string, int](false, LRU, 42, 1, -1, nil, nil, nil, nil, nil)
 	is.Equal(42, cache.Capacity())
 	is.Equal("lru", cache.Algorithm())
 	_, ok := cache.(*sharded.ShardedInMemoryCache[string, *item[int]])
@@ -243,7 +243,7 @@ func TestComposeInternalCacheEdgeCases(t *testing.T) {
 
 	// Test with shards > 1 and shardingFn provided (should work)
 	hashFn := func(key string) uint64 { return uint64(len(key)) }
-	cache = composeInternalCache[string, int](false, LRU, 10, 3, -1, hashFn, nil, nil)
+	cache = composeInternalCache[string, int](false, LRU, 10, 3, -1, hashFn, nil, nil, nil, nil)
 	is.Equal(30, cache.Capacity())
 	is.Equal("lru", cache.Algorithm())
 }
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"container/heap"
+	"math"
+)
+
+// belady is the name of Belady's optimal policy, in the results.
+const belady = "belady"
+
+// simulateBelady replays the trace with Belady's optimal policy (MIN): on a miss, the key accessed
+// the furthest in the future is evicted, and keys never accessed again are not inserted.
+// No policy that does not know the future hits more often: it is an upper bound of the hit ratio.
+func simulateBelady(t *trace, capacity int) result {
+	// Index of the next access of the key of each request.
+	next := make([]int, len(t.requests))
+	last := make(map[uint64]int, t.keys)
+	for i := len(t.requests) - 1; i >= 0; i-- {
+		key := t.requests[i].key
+		if j, ok := last[key]; ok {
+			next[i] = j
+		} else {
+			next[i] = math.MaxInt
+		}
+		last[key] = i
+	}
+
+	r := result{Policy: belady, Capacity: capacity}
+
+	// Next access of the cached keys. The heap may hold outdated entries, skipped when popped.
+	cached := make(map[uint64]int, capacity)
+	byNext := &nextAccessHeap{}
+
+	for i, req := range t.requests {
+		_, hit := cached[req.key]
+		r.add(req, hit)
+
+		if hit {
+			if next[i] == math.MaxInt {
+				// Never accessed again: the slot is free for another key.
+				delete(cached, req.key)
+			} else {
+				cached[req.key] = next[i]
+				heap.Push(byNext, nextAccess{key: req.key, next: next[i]})
+			}
+			continue
+		}
+
+		if next[i] == math.MaxInt || capacity == 0 {
+			continue
+		}
+
+		if len(cached) >= capacity {
+			furthest := byNext.furthest(cached)
+			if furthest.next <= next[i] {
+				continue
+			}
+			heap.Pop(byNext)
+			delete(cached, furthest.key)
+		}
+
+		cached[req.key] = next[i]
+		heap.Push(byNext, nextAccess{key: req.key, next: next[i]})
+	}
+
+	return r
+}
+
+type nextAccess struct {
+	key  uint64
+	next int
+}
+
+// nextAccessHeap implements heap.Interface, as a max-heap on the next access.
+type nextAccessHeap []nextAccess
+
+func (h nextAccessHeap) Len() int           { return len(h) }
+func (h nextAccessHeap) Less(i, j int) bool { return h[i].next > h[j].next }
+func (h nextAccessHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *nextAccessHeap) Push(x any) {
+	*h = append(*h, x.(nextAccess))
+}
+
+func (h *nextAccessHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// furthest drops the outdated entries, and returns the cached key accessed the furthest in the future.
+func (h *nextAccessHeap) furthest(cached map[uint64]int) nextAccess {
+	for {
+		top := (*h)[0]
+		if next, ok := cached[top.key]; ok && next == top.next {
+			return top
+		}
+		heap.Pop(h)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Synthetic workloads.
+const (
+	workloadZipf    = "zipf"    // Zipf-distributed accesses over the keys
+	workloadScan    = "scan"    // Zipf accesses, interleaved with sequential scans of keys never accessed again
+	workloadLoop    = "loop"    // Cyclic accesses over the keys, in order
+	workloadHotspot = "hotspot" // Zipf accesses whose hottest keys change several times
+)
+
+// hotspotPhases is the number of times the hottest keys change in the hotspot workload.
+const hotspotPhases = 4
+
+// scanLength is the number of keys of each scan in the scan workload.
+const scanLength = 1_000
+
+// generateTrace generates `requests` accesses over `keys` keys.
+// `skew` is the exponent of the Zipf distribution (> 1).
+func generateTrace(workload string, requests int, keys int, skew float64, seed int64) (*trace, error) {
+	if requests <= 0 || keys <= 0 {
+		return nil, fmt.Errorf("requests and keys must be greater than 0")
+	}
+	if skew <= 1 && workload != workloadLoop {
+		return nil, fmt.Errorf("zipf skew must be greater than 1")
+	}
+
+	t := &trace{requests: make([]request, 0, requests)}
+	random := rand.New(rand.NewSource(seed))
+	add := func(key uint64) {
+		t.requests = append(t.requests, request{key: key, size: 1})
+	}
+
+	switch workload {
+	case workloadZipf:
+		zipf := rand.NewZipf(random, skew, 1, uint64(keys-1))
+		for i := 0; i < requests; i++ {
+			add(zipf.Uint64())
+		}
+
+	case workloadScan:
+		// Half of the requests come from the scans. Scanned keys follow the Zipf keys.
+		zipf := rand.NewZipf(random, skew, 1, uint64(keys-1))
+		next := uint64(keys)
+		for len(t.requests) < requests {
+			for i := 0; i < scanLength && len(t.requests) < requests; i++ {
+				add(zipf.Uint64())
+			}
+			for i := 0; i < scanLength && len(t.requests) < requests; i++ {
+				add(next)
+				next++
+			}
+		}
+
+	case workloadLoop:
+		for i := 0; i < requests; i++ {
+			add(uint64(i % keys))
+		}
+
+	case workloadHotspot:
+		// Each phase shifts the hottest keys by half of the keys.
+		zipf := rand.NewZipf(random, skew, 1, uint64(keys-1))
+		phase := max(requests/hotspotPhases, 1)
+		for i := 0; i < requests; i++ {
+			offset := uint64(i/phase) * uint64(keys/2)
+			add(zipf.Uint64() + offset)
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown workload %q", workload)
+	}
+
+	distinct := make(map[uint64]struct{}, keys)
+	for _, r := range t.requests {
+		distinct[r.key] = struct{}{}
+	}
+	t.keys = len(distinct)
+
+	return t, nil
+}
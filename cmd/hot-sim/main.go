@@ -0,0 +1,195 @@
+// Command hot-sim replays access traces through the eviction policies of hot, at several capacities,
+// and prints the hit ratio of each policy. Belady's optimal policy gives an upper bound.
+//
+// Replay a trace:
+//
+//	hot-sim -trace requests.log -format keys -policies lru,arc,sieve,s3fifo,wtinylfu,belady -capacities 1000,10000
+//	hot-sim -trace P1.lis -format arc -capacities 1%,5%,10% -output json
+//	hot-sim -trace /var/log/users-cache.trace -format hot
+//
+// Or generate one:
+//
+//	hot-sim -generate zipf -requests 1000000 -keys 100000 -skew 1.1 -capacities 1000,10000
+//
+// Capacities are numbers of keys, or percentages of the distinct keys of the trace.
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/samber/hot/pkg/accesstrace"
+)
+
+const defaultPolicies = "lru,arc,sieve,s3fifo,wtinylfu,belady"
+
+// report is the JSON output.
+type report struct {
+	Trace    string   `json:"trace"`
+	Requests int      `json:"requests"`
+	Keys     int      `json:"keys"`
+	Bytes    int64    `json:"bytes"` // Total size of the requests
+	Results  []result `json:"results"`
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "hot-sim:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("hot-sim", flag.ContinueOnError)
+
+	tracePath := flags.String("trace", "", "trace file to replay, - for stdin")
+	format := flags.String("format", formatKeys, "trace format: keys, arc, lirs, csv (timestamp,key,size) or hot (pkg/accesstrace)")
+	workload := flags.String("generate", "", "synthetic workload instead of a trace: zipf, scan, loop or hotspot")
+	requests := flags.Int("requests", 1_000_000, "number of generated requests")
+	keys := flags.Int("keys", 100_000, "number of generated keys")
+	skew := flags.Float64("skew", 1.1, "exponent of the Zipf distribution of the generated keys (> 1)")
+	seed := flags.Int64("seed", 42, "seed of the generated trace")
+	policyList := flags.String("policies", defaultPolicies, "comma-separated policies: "+strings.Join(policyNames(), ", "))
+	capacityList := flags.String("capacities", "1%,5%,10%", "comma-separated capacities, in keys or in percent of the distinct keys")
+	output := flags.String("output", "csv", "output format: csv or json")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var t *trace
+	var name string
+	var err error
+
+	switch {
+	case *tracePath != "" && *workload != "":
+		return fmt.Errorf("-trace and -generate are mutually exclusive")
+	case *tracePath == "-":
+		name = "stdin"
+		t, err = readTrace(stdin, *format)
+	case *tracePath != "":
+		name = *tracePath
+		t, err = readTraceFile(*tracePath, *format)
+	case *workload != "":
+		name = *workload
+		t, err = generateTrace(*workload, *requests, *keys, *skew, *seed)
+	default:
+		return fmt.Errorf("-trace or -generate is required")
+	}
+	if err != nil {
+		return err
+	}
+
+	capacities, err := parseCapacities(*capacityList, t.keys)
+	if err != nil {
+		return err
+	}
+
+	policies := strings.Split(*policyList, ",")
+	for i := range policies {
+		policies[i] = strings.TrimSpace(policies[i])
+	}
+
+	results, err := simulateAll(t, policies, capacities)
+	if err != nil {
+		return err
+	}
+
+	switch *output {
+	case "csv":
+		return writeCSV(stdout, results)
+	case "json":
+		return writeJSON(stdout, report{Trace: name, Requests: len(t.requests), Keys: t.keys, Bytes: t.bytes(), Results: results})
+	default:
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+}
+
+// readTraceFile reads a trace file. Access traces include the files rotated by the recorder.
+func readTraceFile(path string, format string) (*trace, error) {
+	if format == formatHot {
+		return readAccessTrace(func(fn func(accesstrace.Record) error) error {
+			return accesstrace.ReadFiles(path, fn)
+		})
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return readTrace(f, format)
+}
+
+// parseCapacities parses a list of capacities, in keys or in percent of the distinct keys.
+func parseCapacities(list string, keys int) ([]int, error) {
+	capacities := []int{}
+
+	for _, field := range strings.Split(list, ",") {
+		field = strings.TrimSpace(field)
+
+		var capacity int
+		if percent, ok := strings.CutSuffix(field, "%"); ok {
+			value, err := strconv.ParseFloat(percent, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid capacity %q", field)
+			}
+			capacity = max(int(value*float64(keys)/100), 1)
+		} else {
+			value, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid capacity %q", field)
+			}
+			capacity = value
+		}
+
+		if capacity <= 0 {
+			return nil, fmt.Errorf("capacity %q must be greater than 0", field)
+		}
+		capacities = append(capacities, capacity)
+	}
+
+	return capacities, nil
+}
+
+func policyNames() []string {
+	names := []string{belady}
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func writeCSV(w io.Writer, results []result) error {
+	out := csv.NewWriter(w)
+
+	_ = out.Write([]string{"policy", "capacity", "requests", "hits", "hit_ratio", "byte_hit_ratio"})
+	for _, r := range results {
+		_ = out.Write([]string{
+			r.Policy,
+			strconv.Itoa(r.Capacity),
+			strconv.FormatInt(r.Requests, 10),
+			strconv.FormatInt(r.Hits, 10),
+			strconv.FormatFloat(r.HitRatio, 'f', 4, 64),
+			strconv.FormatFloat(r.ByteHitRatio, 'f', 4, 64),
+		})
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+func writeJSON(w io.Writer, r report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
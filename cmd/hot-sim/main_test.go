@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samber/hot/pkg/accesstrace"
+	"github.com/stretchr/testify/assert"
+)
+
+func keysOf(t *trace) []uint64 {
+	keys := make([]uint64, 0, len(t.requests))
+	for _, r := range t.requests {
+		keys = append(keys, r.key)
+	}
+	return keys
+}
+
+func TestReadTrace(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	tr, err := readTrace(strings.NewReader("# comment\nuser:1\nuser:2\n\nuser:1\n"), formatKeys)
+	is.NoError(err)
+	is.Equal([]uint64{0, 1, 0}, keysOf(tr))
+	is.Equal(2, tr.keys)
+	is.EqualValues(3, tr.bytes())
+
+	// Blocks 10, 11 and 12, then 11.
+	tr, err = readTrace(strings.NewReader("10 3 0 1\n11 1 0 2\n"), formatARC)
+	is.NoError(err)
+	is.Equal([]uint64{0, 1, 2, 1}, keysOf(tr))
+	is.Equal(3, tr.keys)
+
+	tr, err = readTrace(strings.NewReader("5\n7\n5\n*\n9\n"), formatLIRS)
+	is.NoError(err)
+	is.Equal([]uint64{0, 1, 0}, keysOf(tr))
+
+	tr, err = readTrace(strings.NewReader("timestamp,key,size\n1,a,100\n2,b,50\n3,a,100\n"), formatCSV)
+	is.NoError(err)
+	is.Equal([]uint64{0, 1, 0}, keysOf(tr))
+	is.EqualValues(250, tr.bytes())
+
+	_, err = readTrace(strings.NewReader("1,a,100\n2,b\n"), formatCSV)
+	is.EqualError(err, "line 2: expected 3 fields, got 2")
+	_, err = readTrace(strings.NewReader("x 1 0 1\n"), formatARC)
+	is.Error(err)
+	_, err = readTrace(strings.NewReader("1 2\n"), formatLIRS)
+	is.Error(err)
+	_, err = readTrace(strings.NewReader(""), "unknown")
+	is.EqualError(err, `unknown trace format "unknown"`)
+}
+
+func TestReadTraceFile_accessTrace(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "trace")
+	recorder, err := accesstrace.NewRecorder(path, 100, 3, 100)
+	is.NoError(err)
+
+	// Writes are skipped. Keys are the raw keys when recorded, or their hashes.
+	now := time.Now()
+	recorder.Record(accesstrace.Record{Time: now, Op: accesstrace.OpGet, KeyHash: 1, Key: "a", ValueSize: 10})
+	recorder.Record(accesstrace.Record{Time: now, Op: accesstrace.OpSet, KeyHash: 2, Key: "b", ValueSize: 10})
+	recorder.Record(accesstrace.Record{Time: now, Op: accesstrace.OpGet, KeyHash: 3, ValueSize: 20})
+	for i := 0; i < 10; i++ {
+		recorder.Record(accesstrace.Record{Time: now, Op: accesstrace.OpGet, KeyHash: 1, Key: "a", ValueSize: 10})
+	}
+	is.NoError(recorder.Close())
+
+	// The records span the rotated files.
+	files, _ := accesstrace.Files(path)
+	is.Len(files, 3)
+
+	tr, err := readTraceFile(path, formatHot)
+	is.NoError(err)
+	is.Len(tr.requests, 12)
+	is.Equal(2, tr.keys)
+	is.Equal(request{key: 1, size: 20}, tr.requests[1])
+	is.EqualValues(130, tr.bytes())
+
+	_, err = readTrace(strings.NewReader("a\n"), formatHot)
+	is.ErrorIs(err, accesstrace.ErrInvalidFormat)
+}
+
+func TestGenerateTrace(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	for _, workload := range []string{workloadZipf, workloadScan, workloadLoop, workloadHotspot} {
+		tr, err := generateTrace(workload, 10_000, 1_000, 1.1, 42)
+		is.NoError(err, workload)
+		is.Len(tr.requests, 10_000, workload)
+		is.Positive(tr.keys, workload)
+
+		again, _ := generateTrace(workload, 10_000, 1_000, 1.1, 42)
+		is.Equal(tr.requests, again.requests, workload)
+	}
+
+	tr, _ := generateTrace(workloadLoop, 10, 4, 0, 42)
+	is.Equal([]uint64{0, 1, 2, 3, 0, 1, 2, 3, 0, 1}, keysOf(tr))
+	is.Equal(4, tr.keys)
+
+	// Scanned keys are never accessed again.
+	tr, _ = generateTrace(workloadScan, 10_000, 1_000, 1.1, 42)
+	is.Greater(tr.keys, 5_000)
+
+	_, err := generateTrace("unknown", 10, 10, 1.1, 42)
+	is.Error(err)
+	_, err = generateTrace(workloadZipf, 10, 10, 1, 42)
+	is.Error(err)
+	_, err = generateTrace(workloadZipf, 0, 10, 1.1, 42)
+	is.Error(err)
+}
+
+func TestSimulateBelady(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	// 1 and 2 are kept, 3 is never inserted: it is accessed after them.
+	tr := &trace{requests: []request{{1, 1}, {2, 1}, {3, 1}, {1, 1}, {2, 1}, {3, 1}}, keys: 3}
+	r, err := simulate(tr, belady, 2)
+	is.NoError(err)
+	is.EqualValues(6, r.Requests)
+	is.EqualValues(2, r.Hits)
+	is.InDelta(1.0/3, r.HitRatio, 1e-9)
+
+	// A loop larger than the cache never hits an LRU cache, the optimal policy keeps capacity-1 keys.
+	tr, _ = generateTrace(workloadLoop, 10_000, 100, 0, 42)
+	r, _ = simulate(tr, belady, 50)
+	is.Greater(r.HitRatio, 0.45)
+	r, _ = simulate(tr, "lru", 50)
+	is.Zero(r.Hits)
+
+	// No policy does better than the optimal one.
+	tr, _ = generateTrace(workloadZipf, 50_000, 5_000, 1.1, 42)
+	optimal, _ := simulate(tr, belady, 100)
+	for _, policy := range []string{"lru", "lfu", "2q", "fifo", "sieve", "s3fifo", "wtinylfu"} {
+		r, err := simulate(tr, policy, 100)
+		is.NoError(err)
+		is.Positive(r.Hits, policy)
+		is.LessOrEqual(r.Hits, optimal.Hits, policy)
+	}
+
+	_, err = simulate(tr, "unknown", 100)
+	is.EqualError(err, `unknown policy "unknown"`)
+}
+
+func TestSimulateAll(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	tr, _ := generateTrace(workloadZipf, 10_000, 1_000, 1.1, 42)
+	results, err := simulateAll(tr, []string{"lru", belady}, []int{10, 100})
+	is.NoError(err)
+	is.Len(results, 4)
+	is.Equal("lru", results[0].Policy)
+	is.Equal(10, results[0].Capacity)
+	is.Equal(belady, results[3].Policy)
+	is.Equal(100, results[3].Capacity)
+	is.Less(results[0].HitRatio, results[1].HitRatio)
+	is.Equal(results[1].HitRatio, results[1].ByteHitRatio)
+
+	_, err = simulateAll(tr, []string{"lru", "unknown"}, []int{10})
+	is.Error(err)
+}
+
+func TestParseCapacities(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	capacities, err := parseCapacities("100, 1%,50%", 1_000)
+	is.NoError(err)
+	is.Equal([]int{100, 10, 500}, capacities)
+
+	capacities, err = parseCapacities("0.01%", 1_000)
+	is.NoError(err)
+	is.Equal([]int{1}, capacities)
+
+	_, err = parseCapacities("0", 1_000)
+	is.Error(err)
+	_, err = parseCapacities("abc", 1_000)
+	is.Error(err)
+	_, err = parseCapacities("x%", 1_000)
+	is.Error(err)
+}
+
+func TestRun(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var out bytes.Buffer
+	err := run([]string{"-trace", "-", "-policies", "lru, belady", "-capacities", "1"}, strings.NewReader("a\na\nb\na\n"), &out)
+	is.NoError(err)
+	is.Equal(`policy,capacity,requests,hits,hit_ratio,byte_hit_ratio
+lru,1,4,1,0.2500,0.2500
+belady,1,4,2,0.5000,0.5000
+`, out.String())
+
+	out.Reset()
+	err = run([]string{"-generate", "loop", "-requests", "100", "-keys", "10", "-policies", "fifo", "-capacities", "100%", "-output", "json"}, nil, &out)
+	is.NoError(err)
+
+	var r report
+	is.NoError(json.Unmarshal(out.Bytes(), &r))
+	is.Equal("loop", r.Trace)
+	is.Equal(100, r.Requests)
+	is.Equal(10, r.Keys)
+	is.EqualValues(100, r.Bytes)
+	is.Len(r.Results, 1)
+	is.Equal("fifo", r.Results[0].Policy)
+	is.Equal(10, r.Results[0].Capacity)
+	is.EqualValues(90, r.Results[0].Hits)
+
+	is.Error(run([]string{}, nil, &out))
+	is.Error(run([]string{"-trace", "x", "-generate", "zipf"}, nil, &out))
+	is.Error(run([]string{"-trace", "/does/not/exist"}, nil, &out))
+	is.Error(run([]string{"-generate", "zipf", "-requests", "10", "-output", "xml"}, nil, &out))
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/samber/hot/pkg/arc"
+	"github.com/samber/hot/pkg/base"
+	"github.com/samber/hot/pkg/fifo"
+	"github.com/samber/hot/pkg/lfu"
+	"github.com/samber/hot/pkg/lru"
+	"github.com/samber/hot/pkg/s3fifo"
+	"github.com/samber/hot/pkg/sieve"
+	"github.com/samber/hot/pkg/tinylfu"
+	"github.com/samber/hot/pkg/twoqueue"
+	"github.com/samber/hot/pkg/wtinylfu"
+)
+
+// policies are the eviction policies of pkg/*, by name. Belady's optimal is simulated apart.
+var policies = map[string]func(capacity int) base.InMemoryCache[uint64, struct{}]{
+	"lru": func(capacity int) base.InMemoryCache[uint64, struct{}] {
+		return lru.NewLRUCache[uint64, struct{}](capacity)
+	},
+	"lfu": func(capacity int) base.InMemoryCache[uint64, struct{}] {
+		return lfu.NewLFUCache[uint64, struct{}](capacity)
+	},
+	"tinylfu": func(capacity int) base.InMemoryCache[uint64, struct{}] {
+		return tinylfu.NewTinyLFUCache[uint64, struct{}](capacity)
+	},
+	"wtinylfu": func(capacity int) base.InMemoryCache[uint64, struct{}] {
+		return wtinylfu.NewWTinyLFUCache[uint64, struct{}](capacity)
+	},
+	"2q": func(capacity int) base.InMemoryCache[uint64, struct{}] {
+		return twoqueue.New2QCache[uint64, struct{}](capacity)
+	},
+	"arc": func(capacity int) base.InMemoryCache[uint64, struct{}] {
+		return arc.NewARCCache[uint64, struct{}](capacity)
+	},
+	"fifo": func(capacity int) base.InMemoryCache[uint64, struct{}] {
+		return fifo.NewFIFOCache[uint64, struct{}](capacity)
+	},
+	"sieve": func(capacity int) base.InMemoryCache[uint64, struct{}] {
+		return sieve.NewSIEVECache[uint64, struct{}](capacity)
+	},
+	"s3fifo": func(capacity int) base.InMemoryCache[uint64, struct{}] {
+		return s3fifo.NewS3FIFOCache[uint64, struct{}](capacity)
+	},
+}
+
+// result is the outcome of the replay of a trace with a policy at a capacity.
+type result struct {
+	Policy       string  `json:"policy"`
+	Capacity     int     `json:"capacity"`
+	Requests     int64   `json:"requests"`
+	Hits         int64   `json:"hits"`
+	HitRatio     float64 `json:"hit_ratio"`
+	ByteHitRatio float64 `json:"byte_hit_ratio"`
+
+	bytes    int64
+	hitBytes int64
+}
+
+func (r *result) add(req request, hit bool) {
+	r.Requests++
+	r.bytes += req.size
+	if hit {
+		r.Hits++
+		r.hitBytes += req.size
+	}
+}
+
+// done computes the ratios.
+func (r *result) done() {
+	if r.Requests > 0 {
+		r.HitRatio = float64(r.Hits) / float64(r.Requests)
+	}
+	if r.bytes > 0 {
+		r.ByteHitRatio = float64(r.hitBytes) / float64(r.bytes)
+	}
+}
+
+// simulate replays the trace with a policy: hits are counted, and misses are inserted like a loader would.
+func simulate(t *trace, policy string, capacity int) (result, error) {
+	if policy == belady {
+		r := simulateBelady(t, capacity)
+		r.done()
+		return r, nil
+	}
+
+	newCache, ok := policies[policy]
+	if !ok {
+		return result{}, fmt.Errorf("unknown policy %q", policy)
+	}
+	if capacity <= 0 {
+		return result{}, fmt.Errorf("capacity must be greater than 0")
+	}
+
+	cache := newCache(capacity)
+	r := result{Policy: policy, Capacity: capacity}
+
+	for _, req := range t.requests {
+		_, hit := cache.Get(req.key)
+		r.add(req, hit)
+		if !hit {
+			cache.Set(req.key, struct{}{})
+		}
+	}
+
+	r.done()
+	return r, nil
+}
+
+// simulateAll replays the trace with every policy at every capacity, in parallel.
+// Results are ordered by policy, then by capacity.
+func simulateAll(t *trace, policies []string, capacities []int) ([]result, error) {
+	results := make([]result, len(policies)*len(capacities))
+	errs := make([]error, len(results))
+
+	var wg sync.WaitGroup
+	workers := make(chan struct{}, runtime.GOMAXPROCS(0))
+
+	for i, policy := range policies {
+		for j, capacity := range capacities {
+			index := i*len(capacities) + j
+
+			wg.Add(1)
+			workers <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-workers }()
+
+				results[index], errs[index] = simulate(t, policy, capacity)
+			}()
+		}
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/samber/hot/pkg/accesstrace"
+)
+
+// Trace formats.
+const (
+	formatKeys = "keys" // One key per line
+	formatARC  = "arc"  // "start blocks ignored request" per line (Megiddo & Modha): blocks start to start+blocks-1
+	formatLIRS = "lirs" // One block number per line, optionally terminated by "*" (Jiang & Zhang)
+	formatCSV  = "csv"  // "timestamp,key,size" per line, with an optional header
+	formatHot  = "hot"  // Files written by pkg/accesstrace: the reads are replayed
+)
+
+// request is an access to a key, interned to an integer.
+type request struct {
+	key  uint64
+	size int64
+}
+
+// trace is a sequence of accesses, in order.
+type trace struct {
+	requests []request
+	keys     int // Distinct keys
+}
+
+// bytes returns the total size of the requests.
+func (t *trace) bytes() int64 {
+	total := int64(0)
+	for _, r := range t.requests {
+		total += r.size
+	}
+	return total
+}
+
+// traceBuilder interns the keys of a trace.
+type traceBuilder struct {
+	trace trace
+	ids   map[string]uint64
+}
+
+func newTraceBuilder() *traceBuilder {
+	return &traceBuilder{
+		ids: map[string]uint64{},
+	}
+}
+
+func (b *traceBuilder) add(key string, size int64) {
+	id, ok := b.ids[key]
+	if !ok {
+		id = uint64(len(b.ids))
+		b.ids[key] = id
+	}
+
+	b.trace.requests = append(b.trace.requests, request{key: id, size: size})
+}
+
+func (b *traceBuilder) build() *trace {
+	b.trace.keys = len(b.ids)
+	return &b.trace
+}
+
+// readTrace parses a trace in the given format. Empty lines and lines starting with "#" are skipped.
+func readTrace(r io.Reader, format string) (*trace, error) {
+	var parse func(b *traceBuilder, fields []string) error
+
+	switch format {
+	case formatHot:
+		reader, err := accesstrace.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return readAccessTrace(func(fn func(accesstrace.Record) error) error {
+			for {
+				record, err := reader.Next()
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				if err := fn(record); err != nil {
+					return err
+				}
+			}
+		})
+	case formatKeys:
+		parse = parseKeyLine
+	case formatARC:
+		parse = parseARCLine
+	case formatLIRS:
+		parse = parseLIRSLine
+	case formatCSV:
+		parse = parseCSVLine
+	default:
+		return nil, fmt.Errorf("unknown trace format %q", format)
+	}
+
+	b := newTraceBuilder()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if format == formatLIRS && text == "*" {
+			break
+		}
+
+		var fields []string
+		if format == formatCSV {
+			fields = strings.Split(text, ",")
+		} else {
+			fields = strings.Fields(text)
+		}
+
+		if err := parse(b, fields); err != nil {
+			// A CSV header is not an error.
+			if format == formatCSV && line == 1 {
+				continue
+			}
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return b.build(), nil
+}
+
+func parseKeyLine(b *traceBuilder, fields []string) error {
+	b.add(strings.Join(fields, " "), 1)
+	return nil
+}
+
+func parseARCLine(b *traceBuilder, fields []string) error {
+	if len(fields) < 2 {
+		return fmt.Errorf("expected at least 2 fields, got %d", len(fields))
+	}
+
+	start, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid start block: %w", err)
+	}
+	blocks, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid number of blocks: %w", err)
+	}
+
+	for i := uint64(0); i < blocks; i++ {
+		b.add(strconv.FormatUint(start+i, 10), 1)
+	}
+	return nil
+}
+
+func parseLIRSLine(b *traceBuilder, fields []string) error {
+	if len(fields) != 1 {
+		return fmt.Errorf("expected 1 field, got %d", len(fields))
+	}
+
+	block, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid block: %w", err)
+	}
+
+	b.add(strconv.FormatUint(block, 10), 1)
+	return nil
+}
+
+func parseCSVLine(b *traceBuilder, fields []string) error {
+	if len(fields) != 3 {
+		return fmt.Errorf("expected 3 fields, got %d", len(fields))
+	}
+
+	if _, err := strconv.ParseFloat(strings.TrimSpace(fields[0]), 64); err != nil {
+		return fmt.Errorf("invalid timestamp: %w", err)
+	}
+	size, err := strconv.ParseInt(strings.TrimSpace(fields[2]), 10, 64)
+	if err != nil || size < 0 {
+		return fmt.Errorf("invalid size %q", fields[2])
+	}
+
+	b.add(strings.TrimSpace(fields[1]), size)
+	return nil
+}
+
+// readAccessTrace builds a trace from the reads of an access trace. Keys are the raw keys when recorded, or their hashes.
+func readAccessTrace(read func(fn func(accesstrace.Record) error) error) (*trace, error) {
+	b := newTraceBuilder()
+
+	err := read(func(r accesstrace.Record) error {
+		if r.Op != accesstrace.OpGet {
+			return nil
+		}
+
+		key := r.Key
+		if key == "" {
+			key = strconv.FormatUint(r.KeyHash, 16)
+		}
+		b.add(key, r.ValueSize)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return b.build(), nil
+}
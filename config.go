@@ -1,14 +1,27 @@
 package hot
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/samber/hot/pkg/base"
+	"github.com/samber/hot/pkg/disk"
+	"github.com/samber/hot/pkg/invalidation"
 	"github.com/samber/hot/pkg/metrics"
+	"github.com/samber/hot/pkg/replication"
 	"github.com/samber/hot/pkg/sharded"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// meterName is the instrumentation scope of the OpenTelemetry instruments.
+const meterName = "github.com/samber/hot"
+
 // EvictionAlgorithm represents the cache eviction policy to use.
 type EvictionAlgorithm string
 
@@ -62,13 +75,48 @@ type HotCacheConfig[K comparable, V any] struct {
 	lockingDisabled bool
 	janitorEnabled  bool
 
+	// Disk tier configuration
+	diskTierDir      string
+	diskTierCapacity int
+	diskTierCodec    disk.Codec[V]
+	diskStores       []*disk.Store[K, *item[V]]
+
+	invalidator    invalidation.Invalidator
+	replicationLog *replication.Log[K, V]
+
 	// Metrics configuration
 	prometheusMetricsEnabled bool
+	meterProvider            metric.MeterProvider
 	cacheName                string
 	collectors               []metrics.Collector
+	otelInstruments          *metrics.OpenTelemetryInstruments
+	otelCollectors           []*metrics.OpenTelemetryCollector
+	statsdClient             *metrics.StatsDClient
+	statsdCollectors         []*metrics.StatsDCollector
+	tracerProvider           trace.TracerProvider
+	statsEnabled             bool
 
 	warmUpFn                func() (map[K]V, []K, error)
+	hotKeysPath             string
+	hotKeysLimit            int
+	hotKeysInterval         time.Duration
+	hotKeysConcurrency      int
+	hotKeyCapacity          int
+	hotKeyHalfLife          time.Duration
+	hotKeyRefreshAheadTop   int
+	hotKeyRefreshAhead      time.Duration
+	hotKeyMetricsTop        int
+	hotKeyMetricsLabel      func(K) string
+	ghostCapacity           int
+	mrcSampleRate           float64
+	mrcMaxKeys              int
+	shadowAlgorithms        []EvictionAlgorithm
+	accessTraceSampleRate   float64
+	accessTraceRawKey       func(K) string
+	windowRatio             float64
+	sketch                  *sketchOptions
 	loaderFns               LoaderChain[K, V]
+	loaderNames             []string
 	revalidationLoaderFns   LoaderChain[K, V]
 	revalidationErrorPolicy revalidationErrorPolicy
 	onEviction              base.EvictionCallback[K, V]
@@ -173,6 +221,176 @@ func (cfg HotCacheConfig[K, V]) WithWarmUpWithTimeout(timeout time.Duration, fn
 	return cfg
 }
 
+// WithHotKeysPersistence records the hottest keys to a file, in the order the eviction policy would keep them.
+// Values are never written. The file is written every interval (0 = only on Close) with up to `limit` keys.
+// Close returns the first error of the periodic writes, if any.
+// On Build, keys found in the file are loaded back through the loader chain in the background,
+// with up to `concurrency` parallel loader calls. Use WarmUpDone() and WarmUpStatus() to wait for readiness.
+// Cannot be used together with WithoutLocking().
+func (cfg HotCacheConfig[K, V]) WithHotKeysPersistence(path string, limit int, interval time.Duration, concurrency int) HotCacheConfig[K, V] {
+	assertValue(path != "", "hot keys file path is required")
+	assertValue(interval >= 0, "hot keys persistence interval must be a positive value")
+	assertValue(concurrency >= 1, "hot keys warm-up concurrency must be greater than or equal to 1")
+
+	cfg.hotKeysPath = path
+	cfg.hotKeysLimit = limit
+	cfg.hotKeysInterval = interval
+	cfg.hotKeysConcurrency = concurrency
+	return cfg
+}
+
+// WithHotKeyTracking counts the accesses of the keys read with Get and GetMany (and their variants),
+// hits and misses alike, in a Space-Saving top-K summary monitoring up to `capacity` keys.
+// Counts are halved every `halfLife` (0 = never), so that HotKeys reports the keys accessed recently.
+// Keys are spread over up to 16 summaries by hash, each under its own lock, so that concurrent reads rarely
+// contend. A larger capacity is more accurate, but uses more memory.
+func (cfg HotCacheConfig[K, V]) WithHotKeyTracking(capacity int, halfLife time.Duration) HotCacheConfig[K, V] {
+	assertValue(capacity > 0, "hot key tracking capacity must be greater than 0")
+	assertValue(halfLife >= 0, "hot key tracking half-life must be a positive value")
+
+	cfg.hotKeyCapacity = capacity
+	cfg.hotKeyHalfLife = halfLife
+	return cfg
+}
+
+// WithHotKeyRefreshAhead reloads the values of the `top` most accessed keys in the background,
+// when they are read less than `before` their expiration. Hot keys are then never missing from the cache.
+// Values are reloaded like stale values (see WithRevalidation). The top keys are ranked again every 100ms.
+// Requires WithHotKeyTracking.
+func (cfg HotCacheConfig[K, V]) WithHotKeyRefreshAhead(top int, before time.Duration) HotCacheConfig[K, V] {
+	assertValue(top > 0, "hot key refresh-ahead top must be greater than 0")
+	assertValue(before > 0, "hot key refresh-ahead duration must be a positive value")
+
+	cfg.hotKeyRefreshAheadTop = top
+	cfg.hotKeyRefreshAhead = before
+	return cfg
+}
+
+// WithHotKeyMetrics exports the `top` most accessed keys to Prometheus, as the `hot_key_accesses` gauge.
+// The `key` label is formatted with `label`, or with fmt.Sprint when nil. Keys might contain PII: format them accordingly.
+// Requires WithHotKeyTracking and WithPrometheusMetrics.
+func (cfg HotCacheConfig[K, V]) WithHotKeyMetrics(top int, label func(K) string) HotCacheConfig[K, V] {
+	assertValue(top > 0, "hot key metrics top must be greater than 0")
+
+	cfg.hotKeyMetricsTop = top
+	cfg.hotKeyMetricsLabel = label
+	return cfg
+}
+
+// WithEvictionRegretTracking remembers up to `capacity` keys recently evicted for capacity, without their values.
+// A miss on one of them is counted as a premature eviction, with the time elapsed since the eviction:
+// a high rate of premature evictions means the cache is too small for its working set.
+// Keys written, deleted or purged explicitly since their eviction are forgotten.
+// Reported by Stats (with WithStats), and exported by WithPrometheusMetrics and WithOpenTelemetryMetrics.
+// The cache capacity is a good starting point for `capacity`.
+func (cfg HotCacheConfig[K, V]) WithEvictionRegretTracking(capacity int) HotCacheConfig[K, V] {
+	assertValue(capacity > 0, "eviction regret tracking capacity must be greater than 0")
+
+	cfg.ghostCapacity = capacity
+	return cfg
+}
+
+// WithMissRatioCurve estimates the hit ratio the cache would have at 0.25×, 0.5×, 1×, 2× and 4× its capacity,
+// if it used LRU, with SHARDS spatial sampling of the keys read with Get and GetMany (and their variants).
+// CPU overhead is bounded by `sampleRate` (e.g. 0.01): the accesses of the other keys only cost a hash.
+// Memory is bounded by `maxKeys` (e.g. 8192): beyond, the sample rate is lowered.
+// The curve is returned by MissRatioCurve, and exported by WithPrometheusMetrics.
+func (cfg HotCacheConfig[K, V]) WithMissRatioCurve(sampleRate float64, maxKeys int) HotCacheConfig[K, V] {
+	assertValue(sampleRate > 0 && sampleRate <= 1, "miss-ratio curve sample rate must be in (0, 1]")
+	assertValue(maxKeys > 0, "miss-ratio curve max keys must be greater than 0")
+
+	cfg.mrcSampleRate = sampleRate
+	cfg.mrcMaxKeys = maxKeys
+	return cfg
+}
+
+// WithWindowRatio pins the admission window of the W-TinyLFU algorithm to a fraction of the capacity,
+// in (0, 1). By default, the window starts at 1% of the capacity and adapts to the workload by hill climbing.
+// The window ratio is returned by WindowRatio, and exported by WithPrometheusMetrics.
+func (cfg HotCacheConfig[K, V]) WithWindowRatio(ratio float64) HotCacheConfig[K, V] {
+	assertValue(cfg.cacheAlgo == WTinyLFU, "window ratio requires the wtinylfu eviction algorithm")
+	assertValue(ratio > 0 && ratio < 1, "window ratio must be in (0, 1)")
+
+	cfg.windowRatio = ratio
+	return cfg
+}
+
+// WithFrequencySketch configures the frequency sketch of the main cache, using the TinyLFU or W-TinyLFU algorithm:
+// `width` 4-bit counters per row, halved every `sampleSize` increments. Defaults are the capacity and 10 × the capacity.
+// With sharding, every shard has its own sketch. The missing cache keeps the default sketch.
+func (cfg HotCacheConfig[K, V]) WithFrequencySketch(width int, sampleSize int) HotCacheConfig[K, V] {
+	assertValue(cfg.cacheAlgo == TinyLFU || cfg.cacheAlgo == WTinyLFU, "frequency sketch requires the tinylfu or wtinylfu eviction algorithm")
+	assertValue(width > 0, "frequency sketch width must be greater than 0")
+	assertValue(sampleSize > 0, "frequency sketch sample size must be greater than 0")
+
+	cfg.sketch = &sketchOptions{width: width, sampleSize: sampleSize}
+	return cfg
+}
+
+// WithShadowAlgorithms replays the accesses of the cache in key-only caches using other eviction algorithms,
+// at the same capacity, to compare their hit ratio with the real one. Keys read with Get and GetMany (and their
+// variants) are looked up, while writes, loaded values and missing keys are inserted with their TTL, and deletions
+// are removed. Caches larger than 4096 keys are simulated in miniature: keys are sampled, and the shadow capacity
+// is scaled down by the sampling rate. A shadow of the current algorithm is always included first, as a baseline
+// measuring the error of the simulation.
+// Results are reported by Stats, and exported by WithPrometheusMetrics.
+func (cfg HotCacheConfig[K, V]) WithShadowAlgorithms(algorithms ...EvictionAlgorithm) HotCacheConfig[K, V] {
+	assertValue(len(algorithms) > 0, "at least one shadow algorithm is required")
+
+	cfg.shadowAlgorithms = algorithms
+	return cfg
+}
+
+// WithAccessTrace allows recording the accesses of the cache at runtime, with StartAccessTrace and StopAccessTrace,
+// to replay them offline (see pkg/accesstrace and cmd/hot-sim). Keys are sampled by hash, at `sampleRate`:
+// every access of a sampled key is recorded, so that traces stay representative.
+// Only hashes are recorded when `rawKey` is nil. Otherwise, keys are formatted with `rawKey`: they might contain PII.
+// Until a recording is started, the overhead is an atomic load per access.
+func (cfg HotCacheConfig[K, V]) WithAccessTrace(sampleRate float64, rawKey func(K) string) HotCacheConfig[K, V] {
+	assertValue(sampleRate > 0 && sampleRate <= 1, "access trace sample rate must be in (0, 1]")
+
+	cfg.accessTraceSampleRate = sampleRate
+	cfg.accessTraceRawKey = rawKey
+	return cfg
+}
+
+// WithDiskTier enables a second tier on local disk, in the `dir` directory.
+// Values evicted from memory for capacity are written to disk instead of being dropped,
+// up to `capacity` entries. Values found on disk are promoted back to memory on read.
+// Values are serialized with the provided codec. The disk tier is emptied on Build and removed on Close.
+// When the cache is sharded, each shard has its own segment file.
+func (cfg HotCacheConfig[K, V]) WithDiskTier(dir string, capacity int, codec disk.Codec[V]) HotCacheConfig[K, V] {
+	assertValue(dir != "", "disk tier directory is required")
+	assertValue(capacity > 0, "disk tier capacity must be a positive value")
+	assertValue(codec != nil, "disk tier codec is required")
+
+	cfg.diskTierDir = dir
+	cfg.diskTierCapacity = capacity
+	cfg.diskTierCodec = codec
+	return cfg
+}
+
+// WithInvalidator broadcasts Delete, DeleteMany and Purge to the other instances of the cache through
+// the provided transport, and applies the invalidations received from them locally, without broadcasting them again.
+// Keys must be serializable with encoding/json. The invalidator is closed by Close.
+// Invalidations that cannot be published are counted by Stats and WithPrometheusMetrics.
+func (cfg HotCacheConfig[K, V]) WithInvalidator(invalidator invalidation.Invalidator) HotCacheConfig[K, V] {
+	assertValue(invalidator != nil, "invalidator is required")
+
+	cfg.invalidator = invalidator
+	return cfg
+}
+
+// WithReplicationLog appends every write (Set, Delete, Purge, loaded values and expirations)
+// to the replication log, to be streamed to followers by a replication.Primary.
+// Keys and values must be serializable with encoding/json.
+func (cfg HotCacheConfig[K, V]) WithReplicationLog(log *replication.Log[K, V]) HotCacheConfig[K, V] {
+	assertValue(log != nil, "replication log is required")
+
+	cfg.replicationLog = log
+	return cfg
+}
+
 // WithoutLocking disables mutex for the cache and improves internal performance.
 // This should only be used when the cache is not accessed concurrently.
 // Cannot be used together with WithJanitor().
@@ -192,6 +410,22 @@ func (cfg HotCacheConfig[K, V]) WithJanitor() HotCacheConfig[K, V] {
 // These loaders will be called in sequence when a key is not found in the cache.
 func (cfg HotCacheConfig[K, V]) WithLoaders(loaders ...Loader[K, V]) HotCacheConfig[K, V] {
 	cfg.loaderFns = loaders
+	cfg.loaderNames = nil
+	return cfg
+}
+
+// WithNamedLoaders is like WithLoaders, with a name for each loader.
+// The names label the loader metrics. Loaders set by WithLoaders are labeled with their index in the chain.
+func (cfg HotCacheConfig[K, V]) WithNamedLoaders(loaders ...NamedLoader[K, V]) HotCacheConfig[K, V] {
+	cfg.loaderFns = make(LoaderChain[K, V], 0, len(loaders))
+	cfg.loaderNames = make([]string, 0, len(loaders))
+	for _, loader := range loaders {
+		assertValue(loader.Name != "", "loader name is required")
+		assertValue(loader.Loader != nil, "loader is required")
+
+		cfg.loaderFns = append(cfg.loaderFns, loader.Loader)
+		cfg.loaderNames = append(cfg.loaderNames, loader.Name)
+	}
 	return cfg
 }
 
@@ -228,25 +462,124 @@ func (cfg HotCacheConfig[K, V]) WithPrometheusMetrics(cacheName string) HotCache
 	return cfg
 }
 
+// WithOpenTelemetryMetrics enables metric collection through the OpenTelemetry meter provider.
+// Attributes match the labels of WithPrometheusMetrics: `name`, `mode`, and `shard` when the cache is sharded.
+// Both exporters can be enabled on the same cache, with the same cache name.
+func (cfg HotCacheConfig[K, V]) WithOpenTelemetryMetrics(meterProvider metric.MeterProvider, cacheName string) HotCacheConfig[K, V] {
+	assertValue(meterProvider != nil, "meter provider is required when metrics are enabled")
+	assertValue(cacheName != "", "cache name is required when metrics are enabled")
+	assertValue(cfg.cacheName == "" || cfg.cacheName == cacheName, "prometheus and opentelemetry metrics must share the same cache name")
+
+	cfg.meterProvider = meterProvider
+	cfg.cacheName = cacheName
+	return cfg
+}
+
+// WithStats enables the counters returned by Stats and PublishExpvar: hits, misses, loads, insertions and
+// evictions. Counters are maintained with atomic operations, independently of Prometheus.
+func (cfg HotCacheConfig[K, V]) WithStats() HotCacheConfig[K, V] {
+	cfg.statsEnabled = true
+	return cfg
+}
+
+// WithStatsDMetrics enables metric collection through a StatsD client, in the DogStatsD format.
+// Tags match the labels of WithPrometheusMetrics: `name`, `mode`, and `shard` when the cache is sharded.
+// Values are aggregated in memory and sent by the client every flush interval: cache operations never block on the network.
+// The client is owned by the caller, and can be shared by many caches. Close unregisters the cache from the client.
+func (cfg HotCacheConfig[K, V]) WithStatsDMetrics(client *metrics.StatsDClient, cacheName string) HotCacheConfig[K, V] {
+	assertValue(client != nil, "statsd client is required when metrics are enabled")
+	assertValue(cacheName != "", "cache name is required when metrics are enabled")
+	assertValue(cfg.cacheName == "" || cfg.cacheName == cacheName, "metrics exporters must share the same cache name")
+
+	cfg.statsdClient = client
+	cfg.cacheName = cacheName
+	return cfg
+}
+
+// WithTracing enables OpenTelemetry tracing of loads and revalidations.
+// Each load is recorded in a span, child of the span carried by the context passed to GetContext and
+// GetManyContext. Background revalidations start a new trace, linked to the span of the caller.
+// Cache hits and misses are recorded as events on the span of the caller.
+// The cache name set by WithPrometheusMetrics or WithOpenTelemetryMetrics is added to every span.
+func (cfg HotCacheConfig[K, V]) WithTracing(tracerProvider trace.TracerProvider) HotCacheConfig[K, V] {
+	assertValue(tracerProvider != nil, "tracer provider is required when tracing is enabled")
+
+	cfg.tracerProvider = tracerProvider
+	return cfg
+}
+
 // Build creates and returns a new HotCache instance with the current configuration.
 // This method validates the configuration and creates all necessary internal components.
 // The cache is ready to use immediately after this call.
 func (cfg HotCacheConfig[K, V]) Build() *HotCache[K, V] {
 	assertValue(!cfg.janitorEnabled || !cfg.lockingDisabled, "lockingDisabled and janitorEnabled cannot be used together")
+	assertValue(cfg.hotKeysPath == "" || !cfg.lockingDisabled, "lockingDisabled and hot keys persistence cannot be used together")
+	assertValue(cfg.hotKeyRefreshAheadTop == 0 || cfg.hotKeyCapacity > 0, "hot key refresh-ahead requires hot key tracking")
+	assertValue(cfg.hotKeyMetricsTop == 0 || cfg.hotKeyCapacity > 0, "hot key metrics require hot key tracking")
+	assertValue(cfg.hotKeyMetricsTop == 0 || cfg.prometheusMetricsEnabled, "hot key metrics require prometheus metrics")
+
+	// Every eviction is counted, including the ones of the disk tier and of the janitor.
+	var stats *statsCounters
+	if cfg.statsEnabled {
+		stats = newStatsCounters()
+		cfg.onEviction = evictionCallbackWithStats(stats, cfg.onEviction)
+	}
 
-	var collectorBuilderMain func(shard int) metrics.Collector
-	var collectorBuilderMissing func(shard int) metrics.Collector
+	var ghosts *evictionGhosts[K]
+	var evictionRegretMetrics metrics.MultiEvictionRegretCollector
+	if cfg.ghostCapacity > 0 {
+		ghosts = newEvictionGhosts[K](cfg.ghostCapacity)
+		cfg.onEviction = evictionCallbackWithGhosts(ghosts, cfg.onEviction)
+	}
+
+	var meter metric.Meter
+	if cfg.meterProvider != nil {
+		meter = cfg.meterProvider.Meter(meterName)
+		instruments, err := metrics.NewOpenTelemetryInstruments(meter)
+		assertValue(err == nil, fmt.Sprintf("failed to create opentelemetry instruments: %v", err))
+		cfg.otelInstruments = instruments
+	}
+
+	var loaderMetrics metrics.MultiLoaderCollector
+	var lookupMetrics metrics.MultiLookupCollector
 	if cfg.prometheusMetricsEnabled {
-		collectorBuilderMain = cfg.buildPrometheusCollector(base.CacheModeMain)
-		collectorBuilderMissing = cfg.buildPrometheusCollector(base.CacheModeMissing)
+		loaderMetrics = append(loaderMetrics, metrics.NewPrometheusLoaderCollector(cfg.cacheName))
+		lookupMetrics = append(lookupMetrics, metrics.NewPrometheusLookupCollector(cfg.cacheName))
+		if ghosts != nil {
+			evictionRegretMetrics = append(evictionRegretMetrics, metrics.NewPrometheusEvictionRegretCollector(cfg.cacheName))
+		}
+	}
+	if meter != nil {
+		loaderCollector, err := metrics.NewOpenTelemetryLoaderCollector(meter, cfg.cacheName)
+		assertValue(err == nil, fmt.Sprintf("failed to create opentelemetry instruments: %v", err))
+		loaderMetrics = append(loaderMetrics, loaderCollector)
+
+		lookupCollector, err := metrics.NewOpenTelemetryLookupCollector(meter, cfg.cacheName)
+		assertValue(err == nil, fmt.Sprintf("failed to create opentelemetry instruments: %v", err))
+		lookupMetrics = append(lookupMetrics, lookupCollector)
+
+		if ghosts != nil {
+			evictionRegretCollector, err := metrics.NewOpenTelemetryEvictionRegretCollector(meter, cfg.cacheName)
+			assertValue(err == nil, fmt.Sprintf("failed to create opentelemetry instruments: %v", err))
+			evictionRegretMetrics = append(evictionRegretMetrics, evictionRegretCollector)
+		}
+	}
+
+	collectorBuilderMain := cfg.buildCollector(base.CacheModeMain)
+	collectorBuilderMissing := cfg.buildCollector(base.CacheModeMissing)
+	collectorBuilderDisk := cfg.buildCollector(base.CacheModeDisk)
+
+	var diskStoreBuilder func(shard int) *disk.Store[K, *item[V]]
+	if cfg.diskTierDir != "" {
+		diskStoreBuilder = cfg.buildDiskStore(collectorBuilderDisk)
 	}
 
 	var missingCache base.InMemoryCache[K, *item[V]]
 	if cfg.missingCacheCapacity > 0 {
-		missingCache = composeInternalCache(!cfg.lockingDisabled, cfg.missingCacheAlgo, cfg.missingCacheCapacity, cfg.shards, -1, cfg.shardingFn, cfg.onEviction, collectorBuilderMissing)
+		missingCache = composeInternalCache(!cfg.lockingDisabled, cfg.missingCacheAlgo, cfg.missingCacheCapacity, cfg.shards, -1, cfg.shardingFn, cfg.onEviction, collectorBuilderMissing, nil, nil)
 	}
 
-	cacheInstance := composeInternalCache(!cfg.lockingDisabled, cfg.cacheAlgo, cfg.cacheCapacity, cfg.shards, -1, cfg.shardingFn, cfg.onEviction, collectorBuilderMain)
+	cacheInstance := composeInternalCache(!cfg.lockingDisabled, cfg.cacheAlgo, cfg.cacheCapacity, cfg.shards, -1, cfg.shardingFn, cfg.onEviction, collectorBuilderMain, diskStoreBuilder, cfg.sketch)
 	hot := newHotCache(
 		cacheInstance,
 		cfg.missingSharedCache,
@@ -266,6 +599,74 @@ func (cfg HotCacheConfig[K, V]) Build() *HotCache[K, V] {
 
 		cfg.collectors,
 	)
+	hot.diskStores = cfg.diskStores
+	hot.shards = cfg.shards
+	hot.stats = stats
+	hot.loaderNames = cfg.loaderNames
+	hot.loaderMetrics = loaderMetrics
+	hot.lookupMetrics = lookupMetrics
+	hot.ghosts = ghosts
+	hot.evictionRegretMetrics = evictionRegretMetrics
+
+	if cfg.accessTraceSampleRate > 0 {
+		hot.accessTrace = newAccessTrace[K, V](cfg.accessTraceSampleRate, cfg.accessTraceRawKey)
+	}
+
+	if len(cfg.shadowAlgorithms) > 0 {
+		hot.shadows = newShadowCaches[K](cacheInstance.Capacity(), cfg.stale.Nanoseconds(), cfg.cacheAlgo, cfg.shadowAlgorithms)
+		if cfg.prometheusMetricsEnabled {
+			hot.shadows.prometheus = metrics.NewPrometheusShadowCollector(cfg.cacheName, hot.prometheusShadows)
+		}
+	}
+
+	if cfg.windowRatio > 0 {
+		base.PinWindowRatio(cacheInstance, cfg.windowRatio)
+	}
+	if _, ok := base.WindowRatio(cacheInstance); ok && cfg.prometheusMetricsEnabled {
+		hot.windowRatioMetrics = metrics.NewPrometheusWindowRatioCollector(cfg.cacheName, hot.windowRatio)
+	}
+
+	if cfg.mrcMaxKeys > 0 {
+		hot.missRatioCurve = newMissRatioCurve[K](cacheInstance.Capacity(), cfg.mrcSampleRate, cfg.mrcMaxKeys)
+		if cfg.prometheusMetricsEnabled {
+			hot.missRatioCurve.prometheus = metrics.NewPrometheusMissRatioCurveCollector(cfg.cacheName, hot.prometheusMissRatioCurve)
+		}
+	}
+
+	if cfg.tracerProvider != nil {
+		hot.tracing = newTracing(cfg.tracerProvider, cfg.cacheName)
+	}
+
+	if cfg.hotKeyCapacity > 0 {
+		hot.hotKeyTracker = newHotKeyTracker[K](cfg.hotKeyCapacity, cfg.hotKeyHalfLife.Nanoseconds())
+		hot.hotKeyTracker.refreshAheadTop = cfg.hotKeyRefreshAheadTop
+		hot.hotKeyTracker.refreshAheadNano = cfg.hotKeyRefreshAhead.Nanoseconds()
+		if cfg.hotKeyMetricsTop > 0 {
+			hot.hotKeyTracker.prometheus = metrics.NewPrometheusHotKeysCollector(cfg.cacheName, hot.prometheusHotKeys(cfg.hotKeyMetricsTop, cfg.hotKeyMetricsLabel))
+		}
+	}
+
+	if meter != nil {
+		registration, err := hot.registerOpenTelemetryCallback(meter, cfg.otelInstruments, cfg.otelCollectors)
+		assertValue(err == nil, fmt.Sprintf("failed to register opentelemetry callback: %v", err))
+		hot.otelRegistration = registration
+	}
+
+	if cfg.statsdClient != nil {
+		hot.statsdRegistration = cfg.statsdClient.Register(hot.refreshMetricGauges, cfg.statsdCollectors...)
+	}
+
+	if cfg.invalidator != nil {
+		hot.startInvalidation(cfg.invalidator)
+		if cfg.prometheusMetricsEnabled {
+			hot.invalidation.prometheus = metrics.NewPrometheusInvalidationCollector(cfg.cacheName, hot.invalidationPublishErrors)
+		}
+	}
+
+	if cfg.replicationLog != nil {
+		hot.replicationLog = cfg.replicationLog
+		cfg.replicationLog.SetSnapshot(hot.replicationSnapshot)
+	}
 
 	if cfg.warmUpFn != nil {
 		// @TODO: Check error?
@@ -276,16 +677,108 @@ func (cfg HotCacheConfig[K, V]) Build() *HotCache[K, V] {
 		hot.Janitor()
 	}
 
+	if cfg.hotKeysPath != "" {
+		hot.startWarmUpFromFile(cfg.hotKeysPath, cfg.hotKeysConcurrency)
+		hot.startHotKeysPersistence(cfg.hotKeysPath, cfg.hotKeysLimit, cfg.hotKeysInterval)
+	}
+
 	return hot
 }
 
+// buildCollector returns a builder of the collectors of every enabled exporter, or nil when metrics are disabled.
+func (cfg *HotCacheConfig[K, V]) buildCollector(mode base.CacheMode) func(shard int) metrics.Collector {
+	builders := []func(shard int) metrics.Collector{}
+	if cfg.prometheusMetricsEnabled {
+		builders = append(builders, cfg.buildPrometheusCollector(mode))
+	}
+	if cfg.otelInstruments != nil {
+		builders = append(builders, cfg.buildOpenTelemetryCollector(mode))
+	}
+	if cfg.statsdClient != nil {
+		builders = append(builders, cfg.buildStatsDCollector(mode))
+	}
+
+	switch len(builders) {
+	case 0:
+		return nil
+	case 1:
+		return builders[0]
+	default:
+		return func(shard int) metrics.Collector {
+			collectors := make(metrics.MultiCollector, 0, len(builders))
+			for _, builder := range builders {
+				collectors = append(collectors, builder(shard))
+			}
+			return collectors
+		}
+	}
+}
+
+func (cfg *HotCacheConfig[K, V]) buildOpenTelemetryCollector(mode base.CacheMode) func(shard int) metrics.Collector {
+	capacity := cfg.cacheCapacity
+	if mode == base.CacheModeDisk {
+		capacity = cfg.diskTierCapacity
+	}
+
+	return func(shard int) metrics.Collector {
+		collector := metrics.NewOpenTelemetryCollector(
+			cfg.otelInstruments,
+			cfg.cacheName,
+			shard,
+			mode,
+			capacity,
+			string(cfg.cacheAlgo),
+			emptyableToPtr(cfg.ttl),
+			emptyableToPtr(cfg.jitterLambda),
+			emptyableToPtr(cfg.jitterUpperBound),
+			emptyableToPtr(cfg.stale),
+			emptyableToPtr(cfg.missingCacheCapacity),
+		)
+
+		cfg.otelCollectors = append(cfg.otelCollectors, collector)
+
+		return collector
+	}
+}
+
+func (cfg *HotCacheConfig[K, V]) buildStatsDCollector(mode base.CacheMode) func(shard int) metrics.Collector {
+	capacity := cfg.cacheCapacity
+	if mode == base.CacheModeDisk {
+		capacity = cfg.diskTierCapacity
+	}
+
+	return func(shard int) metrics.Collector {
+		collector := metrics.NewStatsDCollector(
+			cfg.cacheName,
+			shard,
+			mode,
+			capacity,
+			string(cfg.cacheAlgo),
+			emptyableToPtr(cfg.ttl),
+			emptyableToPtr(cfg.jitterLambda),
+			emptyableToPtr(cfg.jitterUpperBound),
+			emptyableToPtr(cfg.stale),
+			emptyableToPtr(cfg.missingCacheCapacity),
+		)
+
+		cfg.statsdCollectors = append(cfg.statsdCollectors, collector)
+
+		return collector
+	}
+}
+
 func (cfg *HotCacheConfig[K, V]) buildPrometheusCollector(mode base.CacheMode) func(shard int) metrics.Collector {
+	capacity := cfg.cacheCapacity
+	if mode == base.CacheModeDisk {
+		capacity = cfg.diskTierCapacity
+	}
+
 	return func(shard int) metrics.Collector {
 		collector := metrics.NewPrometheusCollector(
 			cfg.cacheName,
 			shard,
 			mode,
-			cfg.cacheCapacity,
+			capacity,
 			string(cfg.cacheAlgo),
 			emptyableToPtr(cfg.ttl),
 			emptyableToPtr(cfg.jitterLambda),
@@ -299,3 +792,48 @@ func (cfg *HotCacheConfig[K, V]) buildPrometheusCollector(mode base.CacheMode) f
 		return collector
 	}
 }
+
+func (cfg *HotCacheConfig[K, V]) buildDiskStore(collectorBuilder func(shard int) metrics.Collector) func(shard int) *disk.Store[K, *item[V]] {
+	var onEviction base.EvictionCallback[K, *item[V]]
+	if cfg.onEviction != nil {
+		onEviction = func(reason base.EvictionReason, key K, value *item[V]) {
+			cfg.onEviction(reason, key, value.value)
+		}
+	}
+
+	// Segment files are named after a random build identifier, so that several caches,
+	// or a rebuilt cache, sharing the same directory never open the same files.
+	id := make([]byte, 8)
+	_, err := rand.Read(id)
+	assertValue(err == nil, fmt.Sprintf("failed to generate disk tier identifier: %v", err))
+	prefix := "segment-" + hex.EncodeToString(id)
+
+	return func(shard int) *disk.Store[K, *item[V]] {
+		err := os.MkdirAll(cfg.diskTierDir, 0o700)
+		assertValue(err == nil, fmt.Sprintf("failed to create disk tier directory: %v", err))
+
+		name := prefix + ".seg"
+		if shard >= 0 {
+			name = fmt.Sprintf("%s-%d.seg", prefix, shard)
+		}
+
+		var collector metrics.Collector
+		if collectorBuilder != nil {
+			collector = collectorBuilder(shard)
+		}
+
+		store, err := disk.NewStore(
+			filepath.Join(cfg.diskTierDir, name),
+			cfg.diskTierCapacity,
+			disk.Codec[*item[V]](itemCodec[V]{codec: cfg.diskTierCodec}),
+			itemDiskExpiry[V],
+			onEviction,
+			collector,
+		)
+		assertValue(err == nil, fmt.Sprintf("failed to open disk tier: %v", err))
+
+		cfg.diskStores = append(cfg.diskStores, store)
+
+		return store
+	}
+}
@@ -1,11 +1,23 @@
 package hot
 
 import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/samber/hot/pkg/base"
+	"github.com/samber/hot/pkg/disk"
+	"github.com/samber/hot/pkg/metrics"
 	"github.com/stretchr/testify/assert"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 func TestAssertValue(t *testing.T) {
@@ -242,6 +254,381 @@ func TestBuildWithPrometheusMetrics(t *testing.T) {
 	is.Equal("test-cache", opts.cacheName)
 }
 
+func TestWithOpenTelemetryMetrics(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	provider := sdkmetric.NewMeterProvider()
+	opts := NewHotCache[string, int](LRU, 42).WithOpenTelemetryMetrics(provider, "test-cache")
+	is.Equal(provider, opts.meterProvider)
+	is.Equal("test-cache", opts.cacheName)
+
+	is.Panics(func() {
+		_ = opts.WithOpenTelemetryMetrics(nil, "test-cache")
+	})
+	is.Panics(func() {
+		_ = opts.WithOpenTelemetryMetrics(provider, "")
+	})
+	is.Panics(func() {
+		_ = opts.WithPrometheusMetrics("test-cache").WithOpenTelemetryMetrics(provider, "other")
+	})
+}
+
+func TestBuildWithOpenTelemetryMetrics(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	cache := NewHotCache[string, int](LRU, 42).
+		WithSharding(2, func(key string) uint64 { return uint64(len(key)) }).
+		WithMissingCache(LFU, 10).
+		WithPrometheusMetrics("test-cache").
+		WithOpenTelemetryMetrics(provider, "test-cache").
+		Build()
+
+	cache.Set("a", 1)
+	cache.Set("bb", 2)
+	_, _, _ = cache.Get("a")
+	_, _, _ = cache.Get("ccc")
+
+	collect := func() map[string]int64 {
+		var rm metricdata.ResourceMetrics
+		is.NoError(reader.Collect(context.Background(), &rm))
+
+		output := map[string]int64{}
+		for _, sm := range rm.ScopeMetrics {
+			is.Equal(meterName, sm.Scope.Name)
+			for _, m := range sm.Metrics {
+				var points []metricdata.DataPoint[int64]
+				switch d := m.Data.(type) {
+				case metricdata.Sum[int64]:
+					points = d.DataPoints
+				case metricdata.Gauge[int64]:
+					points = d.DataPoints
+				}
+				for _, point := range points {
+					if mode, _ := point.Attributes.Value("mode"); mode.AsString() == "main" {
+						output[m.Name] += point.Value
+					}
+				}
+			}
+		}
+		return output
+	}
+
+	values := collect()
+	is.Equal(int64(2), values["hot.insertion"])
+	is.Equal(int64(1), values["hot.hit"])
+	is.Equal(int64(1), values["hot.miss"])
+	is.Equal(int64(2), values["hot.length"])
+	is.Positive(values["hot.size"])
+	is.Equal(int64(84), values["hot.settings.capacity"])
+
+	// the prometheus collectors are still fed
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(cache)
+	families, err := registry.Gather()
+	is.NoError(err)
+	hits := 0.0
+	for _, family := range families {
+		if family.GetName() == "hot_hit_total" {
+			for _, m := range family.GetMetric() {
+				hits += m.GetCounter().GetValue()
+			}
+		}
+	}
+	is.Equal(1.0, hits)
+
+	is.NoError(cache.Close())
+	is.Empty(collect())
+}
+
+func TestWithNamedLoaders(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	loader := func(keys []string) (map[string]int, error) { return nil, nil }
+
+	opts := NewHotCache[string, int](LRU, 42).WithNamedLoaders(
+		NamedLoader[string, int]{Name: "db", Loader: loader},
+		NamedLoader[string, int]{Name: "api", Loader: loader},
+	)
+	is.Len(opts.loaderFns, 2)
+	is.Equal([]string{"db", "api"}, opts.loaderNames)
+	is.Equal([]string{"db", "api"}, opts.Build().loaderNames)
+
+	opts = opts.WithLoaders(loader)
+	is.Len(opts.loaderFns, 1)
+	is.Nil(opts.loaderNames)
+
+	is.Panics(func() {
+		_ = opts.WithNamedLoaders(NamedLoader[string, int]{Loader: loader})
+	})
+	is.Panics(func() {
+		_ = opts.WithNamedLoaders(NamedLoader[string, int]{Name: "db"})
+	})
+}
+
+func TestBuildWithLoaderMetrics(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	release := make(chan struct{})
+	cache := NewHotCache[string, int](LRU, 42).
+		WithPrometheusMetrics("test-cache").
+		WithOpenTelemetryMetrics(provider, "test-cache").
+		WithNamedLoaders(
+			NamedLoader[string, int]{Name: "db", Loader: func(keys []string) (map[string]int, error) {
+				<-release
+				return map[string]int{"a": 1}, nil
+			}},
+			NamedLoader[string, int]{Name: "api", Loader: func(keys []string) (map[string]int, error) {
+				return nil, nil
+			}},
+		).
+		Build()
+	is.Len(cache.loaderMetrics, 2)
+
+	// The second call waits for the load of the first one.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _, _ = cache.GetMany([]string{"a", "b"})
+	}()
+	time.Sleep(20 * time.Millisecond)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _, _ = cache.Get("a")
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// Ad-hoc loaders are labeled with their index.
+	_, _, err := cache.GetWithLoaders("c", func(keys []string) (map[string]int, error) {
+		return nil, assert.AnError
+	})
+	is.ErrorIs(err, assert.AnError)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(cache)
+	families, err := registry.Gather()
+	is.NoError(err)
+
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			name := family.GetName()
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "loader" {
+					name += "/" + label.GetValue()
+				}
+			}
+			switch {
+			case m.GetCounter() != nil:
+				values[name] = m.GetCounter().GetValue()
+			case m.GetHistogram() != nil:
+				values[name] = float64(m.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+
+	is.Equal(1.0, values["hot_loader_found_total/db"])
+	is.Equal(1.0, values["hot_loader_missing_total/db"])
+	is.Equal(0.0, values["hot_loader_found_total/api"])
+	is.Equal(1.0, values["hot_loader_missing_total/api"])
+	is.Equal(1.0, values["hot_loader_error_total/0"])
+	is.Equal(1.0, values["hot_loader_duration_seconds/db"])
+	is.Equal(1.0, values["hot_loader_batch_size/api"])
+	is.Equal(1.0, values["hot_loader_deduplicated_total"])
+
+	var rm metricdata.ResourceMetrics
+	is.NoError(reader.Collect(context.Background(), &rm))
+	deduplicated := int64(0)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == "hot.loader.deduplicated" {
+				for _, point := range sum.DataPoints {
+					deduplicated += point.Value
+				}
+			}
+		}
+	}
+	is.Equal(int64(1), deduplicated)
+}
+
+func TestBuildWithLookupMetrics(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	fail := atomic.Bool{}
+	cache := NewHotCache[string, int](LRU, 42).
+		WithTTL(30 * time.Millisecond).
+		WithRevalidation(time.Hour).
+		WithRevalidationErrorPolicy(KeepOnError).
+		WithMissingSharedCache().
+		WithPrometheusMetrics("test-cache").
+		WithLoaders(func(keys []string) (map[string]int, error) {
+			if fail.Load() {
+				return nil, assert.AnError
+			}
+			return map[string]int{"a": 1, "b": 2}, nil
+		}).
+		Build()
+
+	lookups := func() map[string]float64 {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(cache)
+		families, err := registry.Gather()
+		is.NoError(err)
+
+		values := map[string]float64{}
+		for _, family := range families {
+			if family.GetName() != "hot_lookup_total" && family.GetName() != "hot_revalidation_total" && family.GetName() != "hot_expired_on_read_total" {
+				continue
+			}
+			for _, m := range family.GetMetric() {
+				name := family.GetName()
+				for _, label := range m.GetLabel() {
+					if label.GetName() == "result" {
+						name += "/" + label.GetValue()
+					}
+				}
+				if v := m.GetCounter().GetValue(); v > 0 {
+					values[name] = v
+				}
+			}
+		}
+		return values
+	}
+
+	_, _, _ = cache.GetMany([]string{"a", "c"}) // loaded + not found
+	_, _, _ = cache.Get("a")                    // fresh hit
+	_, _, _ = cache.Get("c")                    // missing hit
+	is.Equal(map[string]float64{
+		"hot_lookup_total/loaded":      1,
+		"hot_lookup_total/not_found":   1,
+		"hot_lookup_total/fresh_hit":   1,
+		"hot_lookup_total/missing_hit": 1,
+	}, lookups())
+
+	// stale hits, revalidated in the background
+	time.Sleep(40 * time.Millisecond)
+	_, _, _ = cache.Get("a")
+	is.Eventually(func() bool {
+		return lookups()["hot_revalidation_total/success"] == 1
+	}, time.Second, 5*time.Millisecond)
+	is.Equal(1.0, lookups()["hot_lookup_total/stale_hit"])
+
+	fail.Store(true)
+	time.Sleep(40 * time.Millisecond)
+	_, _, _ = cache.GetMany([]string{"a", "b"}) // "b" was loaded along "a"
+	_, _, err := cache.Get("e")
+	is.ErrorIs(err, assert.AnError)
+	is.Eventually(func() bool {
+		return lookups()["hot_revalidation_total/kept_on_error"] == 2
+	}, time.Second, 5*time.Millisecond)
+	is.Equal(3.0, lookups()["hot_lookup_total/stale_hit"])
+	is.Equal(1.0, lookups()["hot_lookup_total/load_error"])
+
+	// expired on read, without a stale window
+	cache = NewHotCache[string, int](LRU, 42).
+		WithPrometheusMetrics("test-cache").
+		WithLoaders(func(keys []string) (map[string]int, error) {
+			return map[string]int{}, nil
+		}).
+		Build()
+	cache.SetWithTTL("d", 4, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	value, found, err := cache.Get("d")
+	is.Zero(value)
+	is.NoError(err)
+	is.False(found)
+	is.Equal(map[string]float64{
+		"hot_expired_on_read_total":  1,
+		"hot_lookup_total/not_found": 1,
+	}, lookups())
+}
+
+func TestWithStatsDMetrics(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	client, err := metrics.NewStatsDClient("127.0.0.1:8125", "hot.", time.Hour)
+	is.NoError(err)
+	defer client.Close()
+
+	opts := NewHotCache[string, int](LRU, 42).WithStatsDMetrics(client, "test-cache")
+	is.Equal(client, opts.statsdClient)
+	is.Equal("test-cache", opts.cacheName)
+
+	is.Panics(func() {
+		_ = opts.WithStatsDMetrics(nil, "test-cache")
+	})
+	is.Panics(func() {
+		_ = opts.WithStatsDMetrics(client, "")
+	})
+	is.Panics(func() {
+		_ = opts.WithPrometheusMetrics("test-cache").WithStatsDMetrics(client, "other")
+	})
+}
+
+func TestBuildWithStatsDMetrics(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	listener, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	is.NoError(err)
+	defer listener.Close()
+
+	client, err := metrics.NewStatsDClient(listener.LocalAddr().String(), "hot.", time.Hour)
+	is.NoError(err)
+	defer client.Close()
+
+	cache := NewHotCache[string, int](LRU, 42).
+		WithMissingCache(LFU, 10).
+		WithStatsDMetrics(client, "test-cache").
+		Build()
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	_, _, _ = cache.Get("a")
+	_, _, _ = cache.Get("c")
+
+	// Pending values are sent when the cache is closed.
+	is.NoError(cache.Close())
+
+	lines := []string{}
+	buf := make([]byte, 65536)
+	for {
+		_ = listener.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		n, err := listener.Read(buf)
+		if err != nil {
+			break
+		}
+		lines = append(lines, strings.Split(string(buf[:n]), "\n")...)
+	}
+
+	is.Contains(lines, "hot.insertion:2|c|#name:test-cache,mode:main")
+	is.Contains(lines, "hot.hit:1|c|#name:test-cache,mode:main")
+	is.Contains(lines, "hot.miss:1|c|#name:test-cache,mode:main")
+	is.Contains(lines, "hot.length:2|g|#name:test-cache,mode:main")
+	is.Contains(lines, "hot.length:0|g|#name:test-cache,mode:missing")
+
+	// The cache is unregistered from the client.
+	is.NoError(client.Flush())
+	_ = listener.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+	_, err = listener.Read(buf)
+	is.Error(err)
+}
+
 func TestBuildWithSharding(t *testing.T) {
 	is := assert.New(t)
 	t.Parallel()
@@ -341,3 +728,176 @@ func TestBuildWithEvictionCallback(t *testing.T) {
 	cache := opts.Build()
 	is.NotNil(cache)
 }
+
+func TestBuildWithFrequencySketch(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	for _, algorithm := range []EvictionAlgorithm{TinyLFU, WTinyLFU} {
+		cache := NewHotCache[int, int](algorithm, 100).
+			WithSharding(4, func(k int) uint64 { return uint64(k) }).
+			WithFrequencySketch(64, 200).
+			Build()
+
+		for i := 0; i < 1_000; i++ {
+			cache.Set(i%200, i)
+		}
+		is.LessOrEqual(cache.Len(), 400, algorithm)
+	}
+
+	is.PanicsWithValue("frequency sketch requires the tinylfu or wtinylfu eviction algorithm", func() {
+		_ = NewHotCache[int, int](LRU, 100).WithFrequencySketch(64, 200)
+	})
+	is.PanicsWithValue("frequency sketch width must be greater than 0", func() {
+		_ = NewHotCache[int, int](TinyLFU, 100).WithFrequencySketch(0, 200)
+	})
+	is.PanicsWithValue("frequency sketch sample size must be greater than 0", func() {
+		_ = NewHotCache[int, int](TinyLFU, 100).WithFrequencySketch(64, 0)
+	})
+}
+
+func TestBuildWithDiskTier(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "tier")
+
+	var mu sync.Mutex
+	evicted := map[string]int{}
+
+	cache := NewHotCache[string, int](LRU, 2).
+		WithDiskTier(dir, 2, disk.JSONCodec[int]{}).
+		WithEvictionCallback(func(reason base.EvictionReason, key string, value int) {
+			mu.Lock()
+			defer mu.Unlock()
+			is.Equal(base.EvictionReasonCapacity, reason)
+			evicted[key] = value
+		}).
+		Build()
+	segments, err := filepath.Glob(filepath.Join(dir, "segment-*.seg"))
+	is.NoError(err)
+	is.Len(segments, 1)
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+	cache.Set("d", 4)
+	is.Equal(4, cache.Len())
+	is.Empty(evicted)
+
+	// promoted back to memory, "c" is demoted
+	v, ok, err := cache.Get("a")
+	is.NoError(err)
+	is.True(ok)
+	is.Equal(1, v)
+
+	// "b" leaves the disk tier
+	cache.Set("e", 5)
+	is.Equal(map[string]int{"b": 2}, evicted)
+	is.False(cache.Has("b"))
+	is.True(cache.Has("c"))
+
+	is.NoError(cache.Close())
+	is.NoFileExists(segments[0])
+}
+
+func TestBuildWithDiskTier_SharedDirectory(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	cache1 := NewHotCache[string, int](LRU, 1).
+		WithDiskTier(dir, 10, disk.JSONCodec[int]{}).
+		Build()
+	cache2 := NewHotCache[string, int](LRU, 1).
+		WithDiskTier(dir, 10, disk.JSONCodec[int]{}).
+		Build()
+
+	entries, err := os.ReadDir(dir)
+	is.NoError(err)
+	is.Len(entries, 2)
+
+	cache1.Set("a", 1)
+	cache1.Set("b", 2)
+	cache2.Set("a", 10)
+	cache2.Set("b", 20)
+
+	v, ok, err := cache1.Get("a")
+	is.NoError(err)
+	is.True(ok)
+	is.Equal(1, v)
+	v, ok, err = cache2.Get("a")
+	is.NoError(err)
+	is.True(ok)
+	is.Equal(10, v)
+
+	is.NoError(cache1.Close())
+	is.NoError(cache2.Close())
+}
+
+func TestBuildWithDiskTier_Janitor(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+	testWithTimeout(t, 2*time.Second)
+
+	cache := NewHotCache[string, int](LRU, 1).
+		WithDiskTier(t.TempDir(), 10, disk.JSONCodec[int]{}).
+		WithTTL(10 * time.Millisecond).
+		WithJanitor().
+		Build()
+	defer cache.Close()
+
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+	is.Equal(3, cache.Len())
+
+	// expired entries leave the disk tier
+	is.Eventually(func() bool { return cache.Len() == 0 }, time.Second, 5*time.Millisecond)
+}
+
+func TestBuildWithDiskTierAndSharding(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	cache := NewHotCache[string, int](LRU, 2).
+		WithSharding(2, func(key string) uint64 { return uint64(len(key)) }).
+		WithDiskTier(dir, 10, disk.JSONCodec[int]{}).
+		WithTTL(time.Hour).
+		Build()
+
+	entries, err := os.ReadDir(dir)
+	is.NoError(err)
+	is.Len(entries, 2)
+	shard0, err := filepath.Glob(filepath.Join(dir, "segment-*-0.seg"))
+	is.NoError(err)
+	is.Len(shard0, 1)
+	shard1, err := filepath.Glob(filepath.Join(dir, "segment-*-1.seg"))
+	is.NoError(err)
+	is.Len(shard1, 1)
+
+	for _, key := range []string{"a", "b", "c", "aa", "bb", "cc"} {
+		cache.Set(key, len(key))
+	}
+	is.Equal(6, cache.Len())
+
+	v, ok, err := cache.Get("a")
+	is.NoError(err)
+	is.True(ok)
+	is.Equal(1, v)
+
+	is.NoError(cache.Close())
+
+	is.Panics(func() {
+		_ = NewHotCache[string, int](LRU, 2).WithDiskTier("", 10, disk.JSONCodec[int]{})
+	})
+	is.Panics(func() {
+		_ = NewHotCache[string, int](LRU, 2).WithDiskTier(dir, 0, disk.JSONCodec[int]{})
+	})
+	is.Panics(func() {
+		_ = NewHotCache[string, int](LRU, 2).WithDiskTier(dir, 10, nil)
+	})
+}
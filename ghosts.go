@@ -0,0 +1,133 @@
+package hot
+
+import (
+	"sync"
+	"time"
+
+	"github.com/samber/hot/internal"
+	"github.com/samber/hot/pkg/base"
+)
+
+// evictionGhosts remembers the keys recently evicted for capacity, without their values.
+// When the set is full, the oldest ghost is forgotten.
+type evictionGhosts[K comparable] struct {
+	mu      sync.Mutex
+	entries map[K]ghost
+	ring    []ghostSlot[K] // Ghosts in eviction order, indexed by seq % len(ring)
+	seq     uint64         // Sequence number of the next ghost
+}
+
+type ghost struct {
+	evictedAtNano int64
+	seq           uint64
+}
+
+type ghostSlot[K comparable] struct {
+	key K
+	seq uint64
+}
+
+func newEvictionGhosts[K comparable](capacity int) *evictionGhosts[K] {
+	return &evictionGhosts[K]{
+		entries: make(map[K]ghost, capacity),
+		ring:    make([]ghostSlot[K], capacity),
+	}
+}
+
+// add records the eviction of a key.
+func (g *evictionGhosts[K]) add(key K, nowNano int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	slot := &g.ring[g.seq%uint64(len(g.ring))]
+	if g.seq >= uint64(len(g.ring)) {
+		// The key of the overwritten slot may have been forgotten, or evicted again since.
+		if old, ok := g.entries[slot.key]; ok && old.seq == slot.seq {
+			delete(g.entries, slot.key)
+		}
+	}
+
+	slot.key = key
+	slot.seq = g.seq
+	g.entries[key] = ghost{evictedAtNano: nowNano, seq: g.seq}
+	g.seq++
+}
+
+// forget removes keys that left the cache for another reason than capacity, or were written
+// or deleted explicitly since their eviction: a later miss would not be caused by the eviction.
+func (g *evictionGhosts[K]) forget(keys ...K) {
+	g.mu.Lock()
+	for _, key := range keys {
+		delete(g.entries, key)
+	}
+	g.mu.Unlock()
+}
+
+// purge removes every ghost.
+func (g *evictionGhosts[K]) purge() {
+	g.mu.Lock()
+	clear(g.entries)
+	g.mu.Unlock()
+}
+
+// hits removes the ghosts of the missing keys and returns the time elapsed since their eviction.
+func (g *evictionGhosts[K]) hits(keys []K, nowNano int64) []time.Duration {
+	var ages []time.Duration
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, key := range keys {
+		if ghost, ok := g.entries[key]; ok {
+			delete(g.entries, key)
+			ages = append(ages, time.Duration(nowNano-ghost.evictedAtNano))
+		}
+	}
+
+	return ages
+}
+
+// len returns the number of ghosts.
+func (g *evictionGhosts[K]) len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return len(g.entries)
+}
+
+// evictionCallbackWithGhosts wraps the eviction callback to record the keys evicted for capacity.
+func evictionCallbackWithGhosts[K comparable, V any](g *evictionGhosts[K], onEviction base.EvictionCallback[K, V]) base.EvictionCallback[K, V] {
+	return func(reason base.EvictionReason, key K, value V) {
+		if reason == base.EvictionReasonCapacity {
+			g.add(key, internal.NowNano())
+		} else {
+			g.forget(key)
+		}
+
+		if onEviction != nil {
+			onEviction(reason, key, value)
+		}
+	}
+}
+
+// forgetGhosts removes the ghosts of keys written or deleted explicitly.
+func (c *HotCache[K, V]) forgetGhosts(keys ...K) {
+	if c.ghosts == nil || len(keys) == 0 {
+		return
+	}
+
+	c.ghosts.forget(keys...)
+}
+
+// observeGhostMisses counts the misses on keys recently evicted for capacity as premature evictions.
+func (c *HotCache[K, V]) observeGhostMisses(keys ...K) {
+	if c.ghosts == nil || len(keys) == 0 {
+		return
+	}
+
+	ages := c.ghosts.hits(keys, internal.NowNano())
+	for _, age := range ages {
+		c.evictionRegretMetrics.ObservePrematureEviction(age)
+	}
+	c.stats.addPrematureEvictions(int64(len(ages)))
+}
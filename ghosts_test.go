@@ -0,0 +1,160 @@
+package hot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/samber/hot/pkg/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvictionGhosts(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	ghosts := newEvictionGhosts[string](2)
+	ghosts.add("a", 100)
+	ghosts.add("b", 200)
+	is.Equal(2, ghosts.len())
+
+	// The oldest ghost is forgotten when the set is full.
+	ghosts.add("c", 300)
+	is.Equal(2, ghosts.len())
+	is.Equal([]time.Duration{800, 700}, ghosts.hits([]string{"a", "b", "c"}, 1_000))
+	is.Equal(0, ghosts.len())
+
+	// A ghost evicted again is not forgotten with its first slot.
+	ghosts.add("d", 100)
+	ghosts.add("d", 200)
+	ghosts.add("e", 300)
+	is.Equal([]time.Duration{800}, ghosts.hits([]string{"d"}, 1_000))
+
+	ghosts.forget("e")
+	is.Nil(ghosts.hits([]string{"e"}, 1_000))
+
+	ghosts.add("e", 300)
+	ghosts.purge()
+	is.Equal(0, ghosts.len())
+
+	// Keys leaving the cache for another reason than capacity are forgotten.
+	evicted := []base.EvictionReason{}
+	onEviction := evictionCallbackWithGhosts(ghosts, func(reason base.EvictionReason, key string, value int) {
+		evicted = append(evicted, reason)
+	})
+	onEviction(base.EvictionReasonCapacity, "f", 1)
+	onEviction(base.EvictionReasonCapacity, "g", 1)
+	onEviction(base.EvictionReasonTTL, "g", 1)
+	is.Equal([]base.EvictionReason{base.EvictionReasonCapacity, base.EvictionReasonCapacity, base.EvictionReasonTTL}, evicted)
+	is.Equal(1, ghosts.len())
+	is.Len(ghosts.hits([]string{"f", "g"}, time.Now().UnixNano()), 1)
+}
+
+func TestWithEvictionRegretTracking(t *testing.T) {
+	t.Parallel()
+
+	for _, algorithm := range []EvictionAlgorithm{LRU, LFU, TinyLFU, WTinyLFU, TwoQueue, ARC, FIFO, SIEVE} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			is := assert.New(t)
+			t.Parallel()
+
+			cache := NewHotCache[int, int](algorithm, 10).
+				WithStats().
+				WithEvictionRegretTracking(100).
+				Build()
+
+			for i := 0; i < 50; i++ {
+				cache.Set(i, i)
+			}
+
+			evicted := cache.Stats().Evictions[base.EvictionReasonCapacity]
+			is.Positive(evicted)
+
+			keys := make([]int, 50)
+			for i := range keys {
+				keys[i] = i
+			}
+			_, missing, err := cache.GetMany(keys)
+			is.NoError(err)
+			is.Len(missing, int(evicted))
+			is.Equal(evicted, cache.Stats().PrematureEvictions)
+
+			// Ghosts are counted once.
+			_, _, _ = cache.Get(missing[0])
+			is.Equal(evicted, cache.Stats().PrematureEvictions)
+		})
+	}
+}
+
+func TestWithEvictionRegretTracking_ExplicitWrites(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewHotCache[int, int](LRU, 1).
+		WithEvictionRegretTracking(10).
+		Build()
+	ghosted := func(key int) bool {
+		cache.ghosts.mu.Lock()
+		defer cache.ghosts.mu.Unlock()
+		_, ok := cache.ghosts.entries[key]
+		return ok
+	}
+
+	for i := 0; i < 6; i++ {
+		cache.Set(i, i)
+	}
+	is.Equal(5, cache.ghosts.len())
+
+	// keys written or deleted explicitly since their eviction are not premature evictions
+	cache.Set(0, 0)
+	is.False(ghosted(0))
+	cache.SetMany(map[int]int{1: 1})
+	is.False(ghosted(1))
+	cache.Delete(2)
+	is.False(ghosted(2))
+	cache.DeleteMany([]int{3})
+	is.False(ghosted(3))
+	is.True(ghosted(4))
+
+	cache.Purge()
+	is.Zero(cache.ghosts.len())
+}
+
+func TestWithEvictionRegretTracking_Metrics(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewHotCache[int, int](LRU, 1).
+		WithEvictionRegretTracking(10).
+		WithPrometheusMetrics("test-cache").
+		Build()
+
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+	time.Sleep(5 * time.Millisecond)
+	_, found, _ := cache.Get(1)
+	is.False(found)
+
+	registry := prometheus.NewRegistry()
+	is.NoError(registry.Register(cache))
+	families, err := registry.Gather()
+	is.NoError(err)
+
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			switch {
+			case m.GetCounter() != nil:
+				values[family.GetName()] = m.GetCounter().GetValue()
+			case m.GetHistogram() != nil:
+				values[family.GetName()] = m.GetHistogram().GetSampleSum()
+			}
+		}
+	}
+	is.InDelta(1.0, values["hot_premature_eviction_total"], 0)
+	is.GreaterOrEqual(values["hot_premature_eviction_age_seconds"], 0.005)
+
+	is.Panics(func() {
+		_ = NewHotCache[int, int](LRU, 1).WithEvictionRegretTracking(0)
+	})
+}
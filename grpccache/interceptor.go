@@ -0,0 +1,180 @@
+package grpccache
+
+import (
+	"context"
+	"time"
+
+	"github.com/samber/go-singleflightx"
+	"github.com/samber/hot"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultBypassHeader is the outgoing metadata key skipping the cache lookup of a call.
+const DefaultBypassHeader = "x-hot-cache-bypass"
+
+// NewUnaryClientInterceptor creates a grpc.UnaryClientInterceptor caching the responses of
+// idempotent unary RPCs. Only the methods registered with WithMethod, or every method when
+// WithDefaultTTL is set, are cached.
+//
+// Responses are keyed on the full method name and the deterministic proto encoding of the request.
+// Headers and trailers are not cached: grpc.Header and grpc.Trailer call options are left empty on hits.
+func NewUnaryClientInterceptor(cache *hot.HotCache[string, proto.Message]) InterceptorConfig {
+	return InterceptorConfig{
+		cache:        cache,
+		methods:      map[string]time.Duration{},
+		bypassHeader: DefaultBypassHeader,
+	}
+}
+
+// InterceptorConfig holds the configuration of the interceptor.
+type InterceptorConfig struct {
+	cache        *hot.HotCache[string, proto.Message]
+	methods      map[string]time.Duration
+	defaultTTL   time.Duration
+	bypassHeader string
+}
+
+// WithMethod enables caching for a full method name (eg: "/package.Service/Method").
+// A zero TTL uses the TTL of the cache.
+func (cfg InterceptorConfig) WithMethod(method string, ttl time.Duration) InterceptorConfig {
+	methods := make(map[string]time.Duration, len(cfg.methods)+1)
+	for k, v := range cfg.methods {
+		methods[k] = v
+	}
+	methods[method] = ttl
+	cfg.methods = methods
+	return cfg
+}
+
+// WithDefaultTTL enables caching for every unary method not registered with WithMethod.
+func (cfg InterceptorConfig) WithDefaultTTL(ttl time.Duration) InterceptorConfig {
+	cfg.defaultTTL = ttl
+	return cfg
+}
+
+// WithBypassHeader sets the outgoing metadata key skipping the cache lookup. The response of a
+// bypassing call still refreshes the cache. Default is DefaultBypassHeader.
+func (cfg InterceptorConfig) WithBypassHeader(header string) InterceptorConfig {
+	cfg.bypassHeader = header
+	return cfg
+}
+
+// Build returns the interceptor, to be registered with grpc.WithUnaryInterceptor.
+func (cfg InterceptorConfig) Build() grpc.UnaryClientInterceptor {
+	if cfg.cache == nil {
+		panic("cache is required")
+	}
+	if cfg.defaultTTL < 0 {
+		panic("default ttl must be a positive value")
+	}
+	for method, ttl := range cfg.methods {
+		if ttl < 0 {
+			panic("ttl of " + method + " must be a positive value")
+		}
+	}
+
+	i := &interceptor{cfg: cfg}
+	return i.intercept
+}
+
+type interceptor struct {
+	cfg   InterceptorConfig
+	group singleflightx.Group[string, proto.Message]
+}
+
+// ttl returns the ttl of a method, and false when its responses must not be cached.
+func (i *interceptor) ttl(method string) (time.Duration, bool) {
+	if ttl, ok := i.cfg.methods[method]; ok {
+		return ttl, true
+	}
+	return i.cfg.defaultTTL, i.cfg.defaultTTL > 0
+}
+
+func (i *interceptor) bypass(ctx context.Context) bool {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	return ok && len(md.Get(i.cfg.bypassHeader)) > 0
+}
+
+func (i *interceptor) intercept(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ttl, ok := i.ttl(method)
+	if !ok {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	reqMsg, ok1 := req.(proto.Message)
+	replyMsg, ok2 := reply.(proto.Message)
+	if !ok1 || !ok2 {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	payload, err := proto.MarshalOptions{Deterministic: true}.Marshal(reqMsg)
+	if err != nil {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	key := method + "\x00" + string(payload)
+
+	// Bypassing calls are never collapsed with other calls: they always reach the server.
+	if i.bypass(ctx) {
+		return i.invoke(ctx, key, ttl, method, req, replyMsg, cc, invoker, opts...)
+	}
+
+	if cached, found, _ := i.cfg.cache.Get(key); found {
+		proto.Reset(replyMsg)
+		proto.Merge(replyMsg, cached)
+		return nil
+	}
+
+	// Concurrent identical calls share the response of the first one, made with its options. The shared
+	// call does not depend on the cancellation nor the deadline of the first caller: every caller waits
+	// for it until its own context is done.
+	shared := context.WithoutCancel(ctx)
+	ch := i.group.DoChan(key, func() (proto.Message, error) {
+		res := replyMsg.ProtoReflect().New().Interface()
+		if err := invoker(shared, method, req, res, cc, opts...); err != nil {
+			return nil, err
+		}
+
+		i.store(key, res, ttl)
+		return res, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	case result := <-ch:
+		if result.Err != nil {
+			// A cancellation of the shared call is not the failure of the other callers: they retry on their own.
+			if code := status.Code(result.Err); code == codes.Canceled || code == codes.DeadlineExceeded {
+				return i.invoke(ctx, key, ttl, method, req, replyMsg, cc, invoker, opts...)
+			}
+			return result.Err
+		}
+
+		proto.Reset(replyMsg)
+		proto.Merge(replyMsg, result.Value.Value)
+		return nil
+	}
+}
+
+// invoke calls the server with the context and options of the caller, and caches the response.
+func (i *interceptor) invoke(ctx context.Context, key string, ttl time.Duration, method string, req any, reply proto.Message, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if err := invoker(ctx, method, req, reply, cc, opts...); err != nil {
+		return err
+	}
+
+	i.store(key, proto.Clone(reply), ttl)
+	return nil
+}
+
+// store caches a response. A zero ttl uses the TTL of the cache.
+func (i *interceptor) store(key string, res proto.Message, ttl time.Duration) {
+	if ttl > 0 {
+		i.cfg.cache.SetWithTTL(key, res, ttl)
+	} else {
+		i.cfg.cache.Set(key, res)
+	}
+}
@@ -0,0 +1,305 @@
+package grpccache
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samber/hot"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	upperMethod = "/test.Echo/Upper"
+	lowerMethod = "/test.Echo/Lower"
+)
+
+type echoServer struct {
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (s *echoServer) handler(transform func(string) string) func(any, context.Context, func(any) error, grpc.UnaryServerInterceptor) (any, error) {
+	return func(_ any, _ context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+		in := &wrapperspb.StringValue{}
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+
+		s.calls.Add(1)
+		if s.release != nil {
+			<-s.release
+		}
+		if in.Value == "fail" {
+			return nil, status.Error(codes.Unavailable, "unavailable")
+		}
+		if in.Value == "cancel" {
+			return nil, status.Error(codes.Canceled, "canceled")
+		}
+
+		return wrapperspb.String(transform(in.Value)), nil
+	}
+}
+
+func newTestConn(t *testing.T, server *echoServer, cfg InterceptorConfig) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	srv.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "test.Echo",
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{
+			{MethodName: "Upper", Handler: server.handler(strings.ToUpper)},
+			{MethodName: "Lower", Handler: server.handler(strings.ToLower)},
+		},
+	}, server)
+	go func() {
+		_ = srv.Serve(listener)
+	}()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(cfg.Build()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+		srv.Stop()
+	})
+
+	return conn
+}
+
+func call(ctx context.Context, conn *grpc.ClientConn, method string, value string) (string, error) {
+	reply := &wrapperspb.StringValue{}
+	err := conn.Invoke(ctx, method, wrapperspb.String(value), reply)
+	return reply.GetValue(), err
+}
+
+func TestInterceptor(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := hot.NewHotCache[string, proto.Message](hot.LRU, 100).Build()
+	server := &echoServer{}
+	conn := newTestConn(t, server, NewUnaryClientInterceptor(cache).WithMethod(upperMethod, time.Minute))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		res, err := call(ctx, conn, upperMethod, "hello")
+		is.NoError(err)
+		is.Equal("HELLO", res)
+	}
+	is.Equal(int32(1), server.calls.Load())
+
+	// different request, different key
+	res, err := call(ctx, conn, upperMethod, "world")
+	is.NoError(err)
+	is.Equal("WORLD", res)
+	is.Equal(int32(2), server.calls.Load())
+
+	// methods not registered are not cached
+	for i := 0; i < 2; i++ {
+		res, err = call(ctx, conn, lowerMethod, "HELLO")
+		is.NoError(err)
+		is.Equal("hello", res)
+	}
+	is.Equal(int32(4), server.calls.Load())
+
+	// errors are not cached
+	for i := 0; i < 2; i++ {
+		_, err = call(ctx, conn, upperMethod, "fail")
+		is.Equal(codes.Unavailable, status.Code(err))
+	}
+	is.Equal(int32(6), server.calls.Load())
+
+	is.Equal(2, cache.Len())
+	cached, ok := cache.Peek(upperMethod + "\x00" + string(mustMarshal(wrapperspb.String("hello"))))
+	is.True(ok)
+	is.True(proto.Equal(wrapperspb.String("HELLO"), cached))
+}
+
+func TestInterceptor_defaultTTLAndBypass(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := hot.NewHotCache[string, proto.Message](hot.LRU, 100).Build()
+	server := &echoServer{}
+	conn := newTestConn(t, server, NewUnaryClientInterceptor(cache).WithDefaultTTL(time.Minute).WithBypassHeader("x-no-cache"))
+	ctx := context.Background()
+
+	_, _ = call(ctx, conn, lowerMethod, "A")
+	_, _ = call(ctx, conn, lowerMethod, "A")
+	is.Equal(int32(1), server.calls.Load())
+
+	bypass := metadata.AppendToOutgoingContext(ctx, "x-no-cache", "1")
+	res, err := call(bypass, conn, lowerMethod, "A")
+	is.NoError(err)
+	is.Equal("a", res)
+	is.Equal(int32(2), server.calls.Load())
+
+	_, _ = call(ctx, conn, lowerMethod, "A")
+	is.Equal(int32(2), server.calls.Load())
+}
+
+func TestInterceptor_singleflight(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := hot.NewHotCache[string, proto.Message](hot.LRU, 100).Build()
+	server := &echoServer{release: make(chan struct{})}
+	conn := newTestConn(t, server, NewUnaryClientInterceptor(cache).WithMethod(upperMethod, 0))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := call(context.Background(), conn, upperMethod, "hello")
+			is.NoError(err)
+			is.Equal("HELLO", res)
+		}()
+	}
+
+	is.Eventually(func() bool {
+		return server.calls.Load() == 1
+	}, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(server.release)
+	wg.Wait()
+
+	is.LessOrEqual(server.calls.Load(), int32(2))
+}
+
+func TestInterceptor_singleflightDetachedContext(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := hot.NewHotCache[string, proto.Message](hot.LRU, 100).Build()
+	server := &echoServer{release: make(chan struct{})}
+	conn := newTestConn(t, server, NewUnaryClientInterceptor(cache).WithMethod(upperMethod, 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := call(ctx, conn, upperMethod, "hello")
+		first <- err
+	}()
+	is.Eventually(func() bool {
+		return server.calls.Load() == 1
+	}, time.Second, time.Millisecond)
+
+	second := make(chan string)
+	go func() {
+		res, err := call(context.Background(), conn, upperMethod, "hello")
+		is.NoError(err)
+		second <- res
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	// the first caller goes away on its own: the shared call completes for the other one
+	cancel()
+	is.Equal(codes.Canceled, status.Code(<-first))
+
+	// a caller with a short deadline stops waiting at its own deadline
+	short, cancelShort := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelShort()
+	_, err := call(short, conn, upperMethod, "hello")
+	is.Equal(codes.DeadlineExceeded, status.Code(err))
+
+	close(server.release)
+	is.Equal("HELLO", <-second)
+	is.Equal(int32(1), server.calls.Load())
+
+	// a cancellation of the shared call is retried with the context of the caller
+	_, err = call(context.Background(), conn, upperMethod, "cancel")
+	is.Equal(codes.Canceled, status.Code(err))
+	is.Equal(int32(3), server.calls.Load())
+}
+
+func TestInterceptor_bypassNotCollapsed(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := hot.NewHotCache[string, proto.Message](hot.LRU, 100).Build()
+	server := &echoServer{release: make(chan struct{})}
+	conn := newTestConn(t, server, NewUnaryClientInterceptor(cache).WithMethod(upperMethod, 0))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		res, err := call(context.Background(), conn, upperMethod, "hello")
+		is.NoError(err)
+		is.Equal("HELLO", res)
+	}()
+	is.Eventually(func() bool {
+		return server.calls.Load() == 1
+	}, time.Second, time.Millisecond)
+
+	// a bypassing call does not wait for the call in flight
+	bypassed := make(chan struct{})
+	go func() {
+		defer close(bypassed)
+		bypass := metadata.AppendToOutgoingContext(context.Background(), DefaultBypassHeader, "1")
+		res, err := call(bypass, conn, upperMethod, "hello")
+		is.NoError(err)
+		is.Equal("HELLO", res)
+	}()
+	is.Eventually(func() bool {
+		return server.calls.Load() == 2
+	}, time.Second, time.Millisecond)
+
+	close(server.release)
+	<-done
+	<-bypassed
+
+	// the response of the bypassing call refreshed the cache
+	res, err := call(context.Background(), conn, upperMethod, "hello")
+	is.NoError(err)
+	is.Equal("HELLO", res)
+	is.Equal(int32(2), server.calls.Load())
+}
+
+func TestInterceptor_build(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := hot.NewHotCache[string, proto.Message](hot.LRU, 100).Build()
+
+	is.Panics(func() { NewUnaryClientInterceptor(nil).Build() })
+	is.Panics(func() { NewUnaryClientInterceptor(cache).WithDefaultTTL(-time.Second).Build() })
+	is.Panics(func() { NewUnaryClientInterceptor(cache).WithMethod(upperMethod, -time.Second).Build() })
+
+	// configs do not share their method table
+	base := NewUnaryClientInterceptor(cache).WithMethod(upperMethod, time.Second)
+	_ = base.WithMethod(lowerMethod, time.Second)
+	is.Len(base.methods, 1)
+}
+
+func mustMarshal(msg proto.Message) []byte {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -1,14 +1,20 @@
 package hot
 
 import (
+	"context"
 	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/samber/go-singleflightx"
 	"github.com/samber/hot/internal"
+	"github.com/samber/hot/pkg/accesstrace"
 	"github.com/samber/hot/pkg/base"
+	"github.com/samber/hot/pkg/disk"
+	"github.com/samber/hot/pkg/invalidation"
 	"github.com/samber/hot/pkg/metrics"
+	"github.com/samber/hot/pkg/replication"
+	"go.opentelemetry.io/otel/metric"
 )
 
 var _ prometheus.Collector = (*HotCache[any, any])(nil)
@@ -90,8 +96,60 @@ type HotCache[K comparable, V any] struct {
 
 	group singleflightx.Group[K, V]
 
+	// Number of shards, for introspection. 0 when sharding is disabled.
+	shards uint64
+
 	// Prometheus collector for metrics registration
 	prometheusCollectors []metrics.Collector
+
+	// Hot keys persistence and key-based warm-up, started by Build when enabled.
+	hotKeys *hotKeysPersistence
+	warmUp  *warmUpState
+
+	// Disk tier segment files, expired by the janitor and closed by Close.
+	diskStores []*disk.Store[K, *item[V]]
+
+	// Cross-instance invalidation, started by Build when enabled.
+	invalidation *invalidationBus
+
+	// Writes streamed to the followers, when this cache is a replication primary.
+	replicationLog *replication.Log[K, V]
+
+	// Counters behind Stats, set by Build. Nil counts nothing.
+	stats *statsCounters
+
+	// OpenTelemetry callback reporting the collectors, unregistered by Close.
+	otelRegistration metric.Registration
+
+	// StatsD collectors sent by the client, unregistered by Close.
+	statsdRegistration *metrics.StatsDRegistration
+
+	// Tracer of loads and revalidations, set by Build. Nil traces nothing.
+	tracing *tracing
+
+	// Names of loaderFns, labeling the loader metrics. Nil labels the loaders with their index.
+	loaderNames []string
+	// Loader metrics of every enabled exporter, set by Build. Empty when metrics are disabled.
+	loaderMetrics metrics.MultiLoaderCollector
+	// Lookup metrics of every enabled exporter, set by Build. Empty when metrics are disabled.
+	lookupMetrics metrics.MultiLookupCollector
+
+	// Top-K of the accessed keys, set by Build. Nil tracks nothing.
+	hotKeyTracker *hotKeyTracker[K]
+
+	// Keys recently evicted for capacity, set by Build. Nil tracks nothing.
+	ghosts *evictionGhosts[K]
+	// Premature eviction metrics of every enabled exporter, set by Build. Empty when metrics are disabled.
+	evictionRegretMetrics metrics.MultiEvictionRegretCollector
+
+	// Miss-ratio curve analyzer, set by Build. Nil samples nothing.
+	missRatioCurve *missRatioCurve[K]
+	// Key-only caches replaying the reads with other eviction algorithms, set by Build. Nil replays nothing.
+	shadows *shadowCaches[K]
+	// Sampler of the accesses for StartAccessTrace, set by Build. Nil records nothing.
+	accessTrace *accessTrace[K, V]
+	// Gauge of the admission window of the main cache, set by Build. Nil when the cache has no admission window.
+	windowRatioMetrics *metrics.PrometheusWindowRatioCollector
 }
 
 // Set adds a value to the cache. If the key already exists, its value is updated.
@@ -102,6 +160,7 @@ func (c *HotCache[K, V]) Set(key K, v V) {
 	}
 
 	c.setUnsafe(key, true, v, c.ttlNano)
+	c.accessTrace.record(accesstrace.OpSet, key, false, v, true)
 }
 
 // SetMissing adds a key to the missing cache to prevent repeated lookups for non-existent keys.
@@ -113,6 +172,7 @@ func (c *HotCache[K, V]) SetMissing(key K) {
 	}
 
 	c.setUnsafe(key, false, zero[V](), c.ttlNano)
+	c.accessTrace.record(accesstrace.OpSet, key, false, zero[V](), false)
 }
 
 // SetWithTTL adds a value to the cache with a specific TTL duration.
@@ -123,6 +183,7 @@ func (c *HotCache[K, V]) SetWithTTL(key K, v V, ttl time.Duration) {
 	}
 
 	c.setUnsafe(key, true, v, ttl.Nanoseconds())
+	c.accessTrace.record(accesstrace.OpSet, key, false, v, true)
 }
 
 // SetMissingWithTTL adds a key to the missing cache with a specific TTL duration.
@@ -134,6 +195,7 @@ func (c *HotCache[K, V]) SetMissingWithTTL(key K, ttl time.Duration) {
 	}
 
 	c.setUnsafe(key, false, zero[V](), ttl.Nanoseconds())
+	c.accessTrace.record(accesstrace.OpSet, key, false, zero[V](), false)
 }
 
 // SetMany adds multiple values to the cache in a single operation.
@@ -148,6 +210,7 @@ func (c *HotCache[K, V]) SetMany(items map[K]V) {
 	}
 
 	c.setManyUnsafe(items, []K{}, c.ttlNano)
+	c.traceWrites(items, nil)
 }
 
 // SetMissingMany adds multiple keys to the missing cache in a single operation.
@@ -159,6 +222,7 @@ func (c *HotCache[K, V]) SetMissingMany(missingKeys []K) {
 	}
 
 	c.setManyUnsafe(map[K]V{}, missingKeys, c.ttlNano)
+	c.traceWrites(nil, missingKeys)
 }
 
 // SetManyWithTTL adds multiple values to the cache with a specific TTL duration.
@@ -173,6 +237,7 @@ func (c *HotCache[K, V]) SetManyWithTTL(items map[K]V, ttl time.Duration) {
 	}
 
 	c.setManyUnsafe(items, []K{}, ttl.Nanoseconds())
+	c.traceWrites(items, nil)
 }
 
 // SetMissingManyWithTTL adds multiple keys to the missing cache with a specific TTL duration.
@@ -184,6 +249,7 @@ func (c *HotCache[K, V]) SetMissingManyWithTTL(missingKeys []K, ttl time.Duratio
 	}
 
 	c.setManyUnsafe(map[K]V{}, missingKeys, ttl.Nanoseconds())
+	c.traceWrites(nil, missingKeys)
 }
 
 // Has checks if a key exists in the cache and has a valid value.
@@ -213,7 +279,12 @@ func (c *HotCache[K, V]) HasMany(keys []K) map[K]bool {
 // Get returns a value from the cache, a boolean indicating whether the key was found,
 // and an error when loaders fail. Uses the default loaders configured for the cache.
 func (c *HotCache[K, V]) Get(key K) (value V, found bool, err error) {
-	return c.GetWithLoaders(key, c.loaderFns...)
+	return c.getWithLoaders(context.Background(), key, c.loaderFns, c.loaderNames)
+}
+
+// GetContext is like Get. The context carries the parent span of the load, when tracing is enabled.
+func (c *HotCache[K, V]) GetContext(ctx context.Context, key K) (value V, found bool, err error) {
+	return c.getWithLoaders(ctx, key, c.loaderFns, c.loaderNames)
 }
 
 // MustGet returns a value from the cache and a boolean indicating whether the key was found.
@@ -231,12 +302,40 @@ func (c *HotCache[K, V]) MustGet(key K) (value V, found bool) {
 // and an error when loaders fail. Uses the provided loaders for cache misses.
 // Concurrent calls for the same key are deduplicated using singleflight.
 func (c *HotCache[K, V]) GetWithLoaders(key K, loaders ...Loader[K, V]) (value V, found bool, err error) {
+	return c.GetWithLoadersContext(context.Background(), key, loaders...)
+}
+
+// GetWithLoadersContext is like GetWithLoaders. The context carries the parent span of the load, when tracing is enabled.
+func (c *HotCache[K, V]) GetWithLoadersContext(ctx context.Context, key K, loaders ...Loader[K, V]) (value V, found bool, err error) {
+	return c.getWithLoaders(ctx, key, loaders, nil)
+}
+
+// getWithLoaders implements the getters of a single key. The names of the loaders label their metrics,
+// and default to their index in the chain.
+func (c *HotCache[K, V]) getWithLoaders(ctx context.Context, key K, loaders LoaderChain[K, V], names []string) (value V, found bool, err error) {
+	c.hotKeyTracker.inc(key)
+	c.missRatioCurve.access(key)
+	c.shadows.access(key)
+
 	// The item might be found, but without value (missing key)
 	cached, revalidate, found := c.getUnsafe(key)
 
 	if found {
-		if revalidate {
-			go c.revalidate(map[K]*item[V]{key: cached}, loaders)
+		c.stats.addHits(1, boolToInt64(!cached.hasValue), boolToInt64(revalidate))
+		c.tracing.recordLookup(ctx, 1, int(boolToInt64(!cached.hasValue)), int(boolToInt64(revalidate)), 0)
+		switch {
+		case !cached.hasValue:
+			c.lookupMetrics.AddLookups(metrics.LookupResultMissingHit, 1)
+		case revalidate:
+			c.lookupMetrics.AddLookups(metrics.LookupResultStaleHit, 1)
+		default:
+			c.lookupMetrics.AddLookups(metrics.LookupResultFreshHit, 1)
+		}
+
+		c.accessTrace.recordItem(accesstrace.OpGet, key, true, cached)
+
+		if revalidate || (cached.hasValue && c.shouldRefreshAhead(key, cached, loaders)) {
+			go c.revalidate(ctx, map[K]*item[V]{key: cached}, loaders, names)
 		}
 
 		if cached.hasValue && c.copyOnRead != nil {
@@ -246,18 +345,28 @@ func (c *HotCache[K, V]) GetWithLoaders(key K, loaders ...Loader[K, V]) (value V
 		return cached.value, cached.hasValue, nil
 	}
 
-	loaded, err := c.loadAndSetMany([]K{key}, loaders)
+	c.stats.addMisses(1)
+	c.tracing.recordLookup(ctx, 0, 0, 0, 1)
+	c.observeGhostMisses(key)
+
+	loaded, err := c.loadAndSetMany(ctx, []K{key}, loaders, names)
 	if err != nil {
+		c.lookupMetrics.AddLookups(metrics.LookupResultLoadError, 1)
+		c.accessTrace.recordItem(accesstrace.OpGet, key, false, nil)
 		return zero[V](), false, err
 	}
 
 	// `loaded` is expected to contain `key`, even if values was not available
 
 	item, ok := loaded[key]
+	c.accessTrace.recordItem(accesstrace.OpGet, key, false, item)
 	if !ok || !item.hasValue {
+		c.lookupMetrics.AddLookups(metrics.LookupResultNotFound, 1)
 		return zero[V](), false, nil
 	}
 
+	c.lookupMetrics.AddLookups(metrics.LookupResultLoaded, 1)
+
 	if c.copyOnRead != nil {
 		return c.copyOnRead(item.value), true, nil
 	}
@@ -279,7 +388,12 @@ func (c *HotCache[K, V]) MustGetWithLoaders(key K, loaders ...Loader[K, V]) (val
 // GetMany returns multiple values from the cache, a slice of missing keys, and an error when loaders fail.
 // Uses the default loaders configured for the cache.
 func (c *HotCache[K, V]) GetMany(keys []K) (values map[K]V, missing []K, err error) {
-	return c.GetManyWithLoaders(keys, c.loaderFns...)
+	return c.getManyWithLoaders(context.Background(), keys, c.loaderFns, c.loaderNames)
+}
+
+// GetManyContext is like GetMany. The context carries the parent span of the load, when tracing is enabled.
+func (c *HotCache[K, V]) GetManyContext(ctx context.Context, keys []K) (values map[K]V, missing []K, err error) {
+	return c.getManyWithLoaders(ctx, keys, c.loaderFns, c.loaderNames)
 }
 
 // MustGetMany returns multiple values from the cache and a slice of missing keys.
@@ -296,23 +410,101 @@ func (c *HotCache[K, V]) MustGetMany(keys []K) (values map[K]V, missing []K) {
 // GetManyWithLoaders returns multiple values from the cache, a slice of missing keys, and an error when loaders fail.
 // Uses the provided loaders for cache misses. Concurrent calls for the same keys are deduplicated using singleflight.
 func (c *HotCache[K, V]) GetManyWithLoaders(keys []K, loaders ...Loader[K, V]) (values map[K]V, missing []K, err error) {
+	return c.GetManyWithLoadersContext(context.Background(), keys, loaders...)
+}
+
+// GetManyWithLoadersContext is like GetManyWithLoaders. The context carries the parent span of the load, when tracing is enabled.
+func (c *HotCache[K, V]) GetManyWithLoadersContext(ctx context.Context, keys []K, loaders ...Loader[K, V]) (values map[K]V, missing []K, err error) {
+	return c.getManyWithLoaders(ctx, keys, loaders, nil)
+}
+
+// getManyWithLoaders implements the getters of many keys. The names of the loaders label their metrics,
+// and default to their index in the chain.
+func (c *HotCache[K, V]) getManyWithLoaders(ctx context.Context, keys []K, loaders LoaderChain[K, V], names []string) (values map[K]V, missing []K, err error) {
+	c.hotKeyTracker.inc(keys...)
+	c.missRatioCurve.access(keys...)
+	c.shadows.access(keys...)
+
 	// Some items might be found in cache, but without value (missing keys).
 	// Other items will be returned in `missing`.
 	cached, missing, revalidate := c.getManyUnsafe(keys)
 
-	loaded, err := c.loadAndSetMany(missing, loaders)
+	if c.stats != nil || c.tracing != nil || len(c.lookupMetrics) > 0 {
+		missingHits := int64(0)
+		for _, v := range cached {
+			if !v.hasValue {
+				missingHits++
+			}
+		}
+		c.stats.addHits(int64(len(cached)), missingHits, int64(len(revalidate)))
+		c.stats.addMisses(int64(len(missing)))
+		c.tracing.recordLookup(ctx, len(cached), int(missingHits), len(revalidate), len(missing))
+		c.recordHitLookups(cached, missingHits, revalidate)
+	}
+
+	c.observeGhostMisses(missing...)
+
+	if c.hotKeyTracker != nil {
+		for k, v := range cached {
+			if _, ok := revalidate[k]; !ok && v.hasValue && c.shouldRefreshAhead(k, v, loaders) {
+				revalidate[k] = v
+			}
+		}
+	}
+
+	loaded, err := c.loadAndSetMany(ctx, missing, loaders, names)
+	c.traceReads(cached, missing, loaded)
 	if err != nil {
+		c.lookupMetrics.AddLookups(metrics.LookupResultLoadError, int64(len(missing)))
 		return nil, nil, err
 	}
 
+	if len(c.lookupMetrics) > 0 && len(loaded) > 0 {
+		found := int64(0)
+		for _, v := range loaded {
+			if v.hasValue {
+				found++
+			}
+		}
+		c.lookupMetrics.AddLookups(metrics.LookupResultLoaded, found)
+		c.lookupMetrics.AddLookups(metrics.LookupResultNotFound, int64(len(loaded))-found)
+	}
+
 	if len(revalidate) > 0 {
-		go c.revalidate(revalidate, loaders)
+		go c.revalidate(ctx, revalidate, loaders, names)
 	}
 
 	found, missing := itemMapsToValues(c.copyOnRead, cached, loaded)
 	return found, missing, nil
 }
 
+// shouldRefreshAhead returns true when a fresh value of a hot key must be reloaded before its expiration.
+func (c *HotCache[K, V]) shouldRefreshAhead(key K, cached *item[V], loaders LoaderChain[K, V]) bool {
+	if len(loaders) == 0 && len(c.revalidationLoaderFns) == 0 {
+		return false
+	}
+
+	return c.hotKeyTracker.shouldRefreshAhead(key, cached.expiryNano)
+}
+
+// recordHitLookups counts the cached keys of a read: missing keys first, then stale and fresh values.
+func (c *HotCache[K, V]) recordHitLookups(cached map[K]*item[V], missingHits int64, revalidate map[K]*item[V]) {
+	if len(c.lookupMetrics) == 0 || len(cached) == 0 {
+		return
+	}
+
+	staleHits := int64(0)
+	for _, v := range revalidate {
+		if v.hasValue {
+			staleHits++
+		}
+	}
+
+	c.lookupMetrics.AddLookups(metrics.LookupResultMissingHit, missingHits)
+	c.lookupMetrics.AddLookups(metrics.LookupResultStaleHit, staleHits)
+	c.lookupMetrics.AddLookups(metrics.LookupResultFreshHit, int64(len(cached))-missingHits-staleHits)
+}
+
 // MustGetManyWithLoaders returns multiple values from the cache and a slice of missing keys.
 // Panics when loaders fail. Uses the provided loaders for cache misses.
 func (c *HotCache[K, V]) MustGetManyWithLoaders(keys []K, loaders ...Loader[K, V]) (values map[K]V, missing []K) {
@@ -365,6 +557,25 @@ func (c *HotCache[K, V]) PeekMany(keys []K) (map[K]V, []K) {
 	return cached, missing
 }
 
+// Inspect returns a cached entry with its metadata, including keys cached as missing and expired
+// entries not removed yet. Like Peek, it neither calls loaders nor triggers revalidation.
+func (c *HotCache[K, V]) Inspect(key K) (EntryInfo[V], bool) {
+	item, ok := c.cache.Peek(key)
+	if !ok && c.missingCache != nil {
+		item, ok = c.missingCache.Peek(key)
+	}
+	if !ok {
+		return EntryInfo[V]{}, false
+	}
+
+	info := item.entryInfo(internal.NowNano(), time.Now())
+	if item.hasValue && c.copyOnRead != nil {
+		info.Value = c.copyOnRead(info.Value)
+	}
+
+	return info, true
+}
+
 // Keys returns all keys in the cache that have valid values.
 // Missing keys are not included in the result.
 func (c *HotCache[K, V]) Keys() []K {
@@ -443,13 +654,58 @@ func (c *HotCache[K, V]) Range(f func(K, V) bool) {
 
 // Delete removes a key from the cache.
 // Returns true if the key was found and removed, false otherwise.
+// When an invalidator is configured, the deletion is broadcast to the other instances.
 func (c *HotCache[K, V]) Delete(key K) bool {
-	return c.cache.Delete(key) || (c.missingCache != nil && c.missingCache.Delete(key))
+	var ok bool
+	if c.replicationLog != nil {
+		ok = c.deleteReplicated(key)
+	} else {
+		ok = c.deleteLocal(key)
+	}
+	c.accessTrace.record(accesstrace.OpDelete, key, ok, zero[V](), false)
+	c.broadcastInvalidation(invalidation.OpDelete, []K{key})
+	return ok
 }
 
 // DeleteMany removes multiple keys from the cache in a single operation.
 // Returns a map where keys are the input keys and values indicate whether the key was found and removed.
+// When an invalidator is configured, the deletion is broadcast to the other instances.
 func (c *HotCache[K, V]) DeleteMany(keys []K) map[K]bool {
+	var output map[K]bool
+	if c.replicationLog != nil {
+		output = c.deleteManyReplicated(keys)
+	} else {
+		output = c.deleteManyLocal(keys)
+	}
+	c.traceDeletes(output)
+	c.broadcastInvalidation(invalidation.OpDelete, keys)
+	return output
+}
+
+// Purge removes all keys and values from the cache.
+// This operation clears both the main cache and the missing cache if enabled.
+// When an invalidator is configured, the purge is broadcast to the other instances.
+func (c *HotCache[K, V]) Purge() {
+	if c.replicationLog != nil {
+		c.purgeReplicated()
+	} else {
+		c.purgeLocal()
+	}
+	c.broadcastInvalidation(invalidation.OpPurge, nil)
+}
+
+// deleteLocal removes a key from this instance only.
+func (c *HotCache[K, V]) deleteLocal(key K) bool {
+	c.forgetGhosts(key)
+	c.shadows.delete(key)
+	return c.cache.Delete(key) || (c.missingCache != nil && c.missingCache.Delete(key))
+}
+
+// deleteManyLocal removes multiple keys from this instance only.
+func (c *HotCache[K, V]) deleteManyLocal(keys []K) map[K]bool {
+	c.forgetGhosts(keys...)
+	c.shadows.delete(keys...)
+
 	// @TODO: should be done in a single call to avoid multiple locks
 	a := c.cache.DeleteMany(keys)
 	b := map[K]bool{}
@@ -465,9 +721,13 @@ func (c *HotCache[K, V]) DeleteMany(keys []K) map[K]bool {
 	return output
 }
 
-// Purge removes all keys and values from the cache.
-// This operation clears both the main cache and the missing cache if enabled.
-func (c *HotCache[K, V]) Purge() {
+// purgeLocal removes all keys and values from this instance only.
+func (c *HotCache[K, V]) purgeLocal() {
+	if c.ghosts != nil {
+		c.ghosts.purge()
+	}
+	c.shadows.purge()
+
 	c.cache.Purge()
 	if c.missingCache != nil {
 		// @TODO: should be done in a single call to avoid multiple locks
@@ -497,6 +757,32 @@ func (c *HotCache[K, V]) Algorithm() (mainCacheAlgorithm string, missingCacheAlg
 	return c.cache.Algorithm(), ""
 }
 
+// WindowRatio returns the fraction of the capacity of the main cache used by its admission window,
+// or false when the eviction algorithm has no admission window (only W-TinyLFU has one).
+// The window adapts to the workload, unless pinned by WithWindowRatio. Shards are averaged.
+func (c *HotCache[K, V]) WindowRatio() (float64, bool) {
+	return base.WindowRatio(c.cache)
+}
+
+// windowRatio feeds the window ratio gauge.
+func (c *HotCache[K, V]) windowRatio() float64 {
+	ratio, _ := c.WindowRatio()
+	return ratio
+}
+
+// TTL returns the default time-to-live of the entries and the duration of the stale window.
+func (c *HotCache[K, V]) TTL() (ttl time.Duration, stale time.Duration) {
+	return time.Duration(c.ttlNano), time.Duration(c.staleNano)
+}
+
+// Shards returns the number of shards, or 1 when sharding is disabled.
+func (c *HotCache[K, V]) Shards() int {
+	if c.shards > 1 {
+		return int(c.shards)
+	}
+	return 1
+}
+
 // Len returns the number of items in the main cache.
 // This includes both valid values and missing keys if using shared missing cache.
 func (c *HotCache[K, V]) Len() int {
@@ -587,7 +873,8 @@ func (c *HotCache[K, V]) Janitor() {
 				{
 					toDelete := []K{}
 					toDeleteKV := map[K]V{}
-					c.cache.Range(func(k K, v *item[V]) bool {
+					// The disk tier is not decoded: it expires its entries from its own index below.
+					base.RangeMemory(c.cache, func(k K, v *item[V]) bool {
 						if v.isExpired(nowNano) {
 							toDelete = append(toDelete, k)
 							if c.onEviction != nil {
@@ -597,7 +884,7 @@ func (c *HotCache[K, V]) Janitor() {
 						return true
 					})
 
-					deleted := c.cache.DeleteMany(toDelete)
+					deleted := c.deleteExpired(c.cache, toDelete)
 					if c.onEviction != nil {
 						for k, ok := range deleted {
 							if ok {
@@ -605,6 +892,10 @@ func (c *HotCache[K, V]) Janitor() {
 							}
 						}
 					}
+
+					for _, store := range c.diskStores {
+						store.DeleteExpired()
+					}
 				}
 
 				// Clean expired items from missing cache (if separate cache is used)
@@ -621,7 +912,7 @@ func (c *HotCache[K, V]) Janitor() {
 						return true
 					})
 
-					deleted := c.missingCache.DeleteMany(toDelete)
+					deleted := c.deleteExpired(c.missingCache, toDelete)
 					if c.onEviction != nil {
 						for k, ok := range deleted {
 							if ok {
@@ -669,6 +960,47 @@ func (c *HotCache[K, V]) StopJanitor() {
 	})
 }
 
+// Close stops the background goroutines started by the cache (janitor, hot keys persistence).
+// When hot keys persistence is enabled, the hot keys file is written a last time, and the first error
+// of the periodic writes is returned.
+// When the disk tier is enabled, its segment files are closed and removed.
+// When an invalidator is configured, it is closed.
+// When an access trace is recording, its recorder is closed.
+// This method is safe to call multiple times.
+func (c *HotCache[K, V]) Close() error {
+	c.StopJanitor()
+	c.stopWarmUp()
+	err := c.stopHotKeysPersistence()
+
+	if closeErr := c.stopInvalidation(); err == nil {
+		err = closeErr
+	}
+
+	if closeErr := c.StopAccessTrace(); err == nil {
+		err = closeErr
+	}
+
+	for _, store := range c.diskStores {
+		if closeErr := store.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	if c.otelRegistration != nil {
+		if closeErr := c.otelRegistration.Unregister(); err == nil {
+			err = closeErr
+		}
+	}
+
+	if c.statsdRegistration != nil {
+		if closeErr := c.statsdRegistration.Unregister(); err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
 // setUnsafe is an internal method that sets a key-value pair in the cache without thread safety.
 // It handles both regular values and missing keys, applying TTL jitter and managing separate caches.
 func (c *HotCache[K, V]) setUnsafe(key K, hasValue bool, value V, ttlNano int64) {
@@ -678,6 +1010,21 @@ func (c *HotCache[K, V]) setUnsafe(key K, hasValue bool, value V, ttlNano int64)
 
 	ttlNano = applyJitter(ttlNano, c.jitterLambda, c.jitterUpperBound)
 
+	if c.replicationLog != nil {
+		c.setReplicated(key, hasValue, value, ttlNano)
+	} else {
+		c.storeUnsafe(key, hasValue, value, ttlNano)
+	}
+
+	c.shadows.set(ttlNano, key)
+	c.stats.addInsertions(1)
+}
+
+// storeUnsafe stores a key-value pair, or a missing key, in the right cache.
+// The TTL jitter has already been applied.
+func (c *HotCache[K, V]) storeUnsafe(key K, hasValue bool, value V, ttlNano int64) {
+	c.forgetGhosts(key)
+
 	// Since we don't know where the previous key is stored, we need to delete preemptively
 	if c.missingCache != nil {
 		// @TODO: Should be done in a single call to avoid multiple locks
@@ -703,6 +1050,31 @@ func (c *HotCache[K, V]) setManyUnsafe(items map[K]V, missing []K, ttlNano int64
 		missing = []K{}
 	}
 
+	if c.replicationLog != nil {
+		c.setManyReplicated(items, missing, ttlNano)
+	} else {
+		c.storeManyUnsafe(items, missing, ttlNano)
+	}
+
+	if c.shadows != nil {
+		c.shadows.set(ttlNano, missing...)
+		for k := range items {
+			c.shadows.set(ttlNano, k)
+		}
+	}
+	c.stats.addInsertions(int64(len(items) + len(missing)))
+}
+
+// storeManyUnsafe stores multiple key-value pairs and missing keys in the right caches.
+func (c *HotCache[K, V]) storeManyUnsafe(items map[K]V, missing []K, ttlNano int64) {
+	if c.ghosts != nil {
+		keys := append(make([]K, 0, len(items)+len(missing)), missing...)
+		for k := range items {
+			keys = append(keys, k)
+		}
+		c.forgetGhosts(keys...)
+	}
+
 	if c.missingCache != nil {
 		keysHavingValues := make([]K, 0, len(items))
 		for k := range items {
@@ -751,6 +1123,8 @@ func (c *HotCache[K, V]) getUnsafe(key K) (value *item[V], revalidate bool, foun
 			return item, item.shouldRevalidate(nowNano), true
 		}
 
+		c.lookupMetrics.AddExpiredOnRead(1)
+
 		ok := c.cache.Delete(key)
 		if ok && c.onEviction != nil {
 			c.onEviction(base.EvictionReasonTTL, key, item.value)
@@ -764,6 +1138,8 @@ func (c *HotCache[K, V]) getUnsafe(key K) (value *item[V], revalidate bool, foun
 				return item, item.shouldRevalidate(nowNano), true
 			}
 
+			c.lookupMetrics.AddExpiredOnRead(1)
+
 			ok := c.missingCache.Delete(key)
 			if ok && c.onEviction != nil {
 				c.onEviction(base.EvictionReasonTTL, key, item.value)
@@ -805,6 +1181,8 @@ func (c *HotCache[K, V]) getManyUnsafe(keys []K) (cached map[K]*item[V], missing
 	}
 
 	if len(toDeleteCache) > 0 {
+		c.lookupMetrics.AddExpiredOnRead(int64(len(toDeleteCache)))
+
 		// @TODO: Should be done in a single call to avoid multiple locks
 		deleted := c.cache.DeleteMany(toDeleteCache)
 		if c.onEviction != nil {
@@ -836,6 +1214,8 @@ func (c *HotCache[K, V]) getManyUnsafe(keys []K) (cached map[K]*item[V], missing
 		}
 
 		if len(toDeleteMissingCache) > 0 {
+			c.lookupMetrics.AddExpiredOnRead(int64(len(toDeleteMissingCache)))
+
 			// @TODO: Should be done in a single call to avoid multiple locks
 			deleted := c.missingCache.DeleteMany(toDeleteMissingCache)
 			if c.onEviction != nil {
@@ -857,7 +1237,9 @@ func (c *HotCache[K, V]) getManyUnsafe(keys []K) (cached map[K]*item[V], missing
 // It returns a map of keys to items and an error when loaders fail.
 // All requested keys are returned, even if they have no value.
 // Concurrent calls for the same keys are deduplicated using singleflight.
-func (c *HotCache[K, V]) loadAndSetMany(keys []K, loaders LoaderChain[K, V]) (map[K]*item[V], error) {
+// When tracing is enabled, the load is recorded in a span child of the span carried by the context.
+// The names of the loaders label their metrics, and default to their index in the chain.
+func (c *HotCache[K, V]) loadAndSetMany(ctx context.Context, keys []K, loaders LoaderChain[K, V], names []string) (output map[K]*item[V], err error) {
 	if len(keys) == 0 || len(loaders) == 0 {
 		result := map[K]*item[V]{}
 		for _, key := range keys {
@@ -866,12 +1248,23 @@ func (c *HotCache[K, V]) loadAndSetMany(keys []K, loaders LoaderChain[K, V]) (ma
 		return result, nil
 	}
 
+	ctx, span := c.tracing.startLoad(ctx, len(keys))
+	found, deduplicated := 0, 0
+	defer func() {
+		c.tracing.endLoad(span, found, len(keys)-found, deduplicated, err)
+	}()
+
 	// go-singleflightx is used to avoid calling the loaders multiple times for concurrent loads.
 	// go-singleflightx returns all keys, so we don't need to keep track of missing keys.
 	// Instead of looping over every loader, we should return the valid keys as soon as possible (and it will reduce errors).
 	// @TODO: A custom implementation of go-singleflightx could be used to avoid some loops.
+	requested := 0
 	results := c.group.DoX(keys, func(missing []K) (map[K]V, error) {
-		results, stillMissing, err := loaders.run(missing)
+		requested = len(missing)
+
+		start := time.Now()
+		results, stillMissing, err := loaders.runObserved(missing, c.loaderObserver(ctx, names))
+		c.stats.addLoad(time.Since(start), err)
 		if err != nil {
 			return nil, err
 		}
@@ -890,14 +1283,24 @@ func (c *HotCache[K, V]) loadAndSetMany(keys []K, loaders LoaderChain[K, V]) (ma
 		return results, nil
 	})
 
+	// Keys loaded by a concurrent call.
+	deduplicated = len(results) - requested
+	if deduplicated > 0 {
+		c.loaderMetrics.AddDeduplicatedKeys(int64(deduplicated))
+	}
+
 	// Format output
-	output := map[K]*item[V]{}
+	output = map[K]*item[V]{}
 	for _, key := range keys {
 		if v, ok := results[key]; ok {
 			if v.Err != nil {
 				return map[K]*item[V]{}, v.Err
 			}
 
+			if v.Value.Valid {
+				found++
+			}
+
 			output[key] = newItem(v.Value.Value, v.Value.Valid, 0, 0)
 		} else {
 			// Not expected, since go-singleflightx should return all keys
@@ -908,10 +1311,36 @@ func (c *HotCache[K, V]) loadAndSetMany(keys []K, loaders LoaderChain[K, V]) (ma
 	return output, nil
 }
 
+// loaderObserver returns the observer of the loaders of a chain, recording their spans and metrics.
+// Nil when both tracing and metrics are disabled.
+func (c *HotCache[K, V]) loaderObserver(ctx context.Context, names []string) loaderObserver[K] {
+	observeTracing := loaderObserverWithTracing[K](ctx, c.tracing)
+	if len(c.loaderMetrics) == 0 {
+		return observeTracing
+	}
+
+	return func(index int, keys []K) func(found int, err error) {
+		var done func(found int, err error)
+		if observeTracing != nil {
+			done = observeTracing(index, keys)
+		}
+
+		start := time.Now()
+
+		return func(found int, err error) {
+			c.loaderMetrics.ObserveLoad(loaderName(names, index), len(keys), found, time.Since(start), err)
+			if done != nil {
+				done(found, err)
+			}
+		}
+	}
+}
+
 // revalidate revalidates stale items in the background using the provided fallback loaders.
 // If revalidation loaders are configured, they are used instead of fallback loaders.
 // If revalidation fails and the error policy is KeepOnError, the original items are preserved.
-func (c *HotCache[K, V]) revalidate(items map[K]*item[V], fallbackLoaders LoaderChain[K, V]) {
+// When tracing is enabled, the revalidation is recorded in a new trace, linked to the span carried by the context.
+func (c *HotCache[K, V]) revalidate(ctx context.Context, items map[K]*item[V], fallbackLoaders LoaderChain[K, V], fallbackNames []string) {
 	if len(items) == 0 {
 		return
 	}
@@ -921,14 +1350,26 @@ func (c *HotCache[K, V]) revalidate(items map[K]*item[V], fallbackLoaders Loader
 		keys = append(keys, k)
 	}
 
-	loaders := fallbackLoaders
+	loaders, names := fallbackLoaders, fallbackNames
 	if len(c.revalidationLoaderFns) > 0 {
-		loaders = c.revalidationLoaderFns
+		loaders, names = c.revalidationLoaderFns, nil
 	}
 
 	// @TODO: We might be fetching keys one by one, which is not efficient.
 	// We should batch the keys and fetch them after a short delay.
-	_, err := c.loadAndSetMany(keys, loaders)
+	ctx, span := c.tracing.startRevalidation(ctx, len(keys))
+	_, err := c.loadAndSetMany(ctx, keys, loaders, names)
+	endSpan(span, err)
+
+	switch {
+	case err == nil:
+		c.lookupMetrics.AddRevalidations(metrics.RevalidationResultSuccess, int64(len(keys)))
+	case c.revalidationErrorPolicy == KeepOnError:
+		c.lookupMetrics.AddRevalidations(metrics.RevalidationResultKeptOnError, int64(len(keys)))
+	default:
+		c.lookupMetrics.AddRevalidations(metrics.RevalidationResultFailure, int64(len(keys)))
+	}
+
 	if err != nil && c.revalidationErrorPolicy == KeepOnError {
 		valid := map[K]V{}
 		missing := []K{}
@@ -952,22 +1393,100 @@ func (c *HotCache[K, V]) Describe(ch chan<- *prometheus.Desc) {
 			prometheusCollector.Describe(ch)
 		}
 	}
+	for _, collector := range c.loaderMetrics {
+		if prometheusCollector, ok := collector.(prometheus.Collector); ok {
+			prometheusCollector.Describe(ch)
+		}
+	}
+	for _, collector := range c.lookupMetrics {
+		if prometheusCollector, ok := collector.(prometheus.Collector); ok {
+			prometheusCollector.Describe(ch)
+		}
+	}
+	if c.hotKeyTracker != nil && c.hotKeyTracker.prometheus != nil {
+		c.hotKeyTracker.prometheus.Describe(ch)
+	}
+	for _, collector := range c.evictionRegretMetrics {
+		if prometheusCollector, ok := collector.(prometheus.Collector); ok {
+			prometheusCollector.Describe(ch)
+		}
+	}
+	if c.missRatioCurve != nil && c.missRatioCurve.prometheus != nil {
+		c.missRatioCurve.prometheus.Describe(ch)
+	}
+	if c.shadows != nil && c.shadows.prometheus != nil {
+		c.shadows.prometheus.Describe(ch)
+	}
+	if c.windowRatioMetrics != nil {
+		c.windowRatioMetrics.Describe(ch)
+	}
+	if c.invalidation != nil && c.invalidation.prometheus != nil {
+		c.invalidation.prometheus.Describe(ch)
+	}
 }
 
 // Collect implements the prometheus.Collector interface.
 func (c *HotCache[K, V]) Collect(ch chan<- prometheus.Metric) {
-	// Triggers a size calculation.
-	// Warning: This is very slow.
+	c.refreshMetricGauges()
+
+	for _, collector := range c.prometheusCollectors {
+		if prometheusCollector, ok := collector.(prometheus.Collector); ok {
+			prometheusCollector.Collect(ch)
+		}
+	}
+	for _, collector := range c.loaderMetrics {
+		if prometheusCollector, ok := collector.(prometheus.Collector); ok {
+			prometheusCollector.Collect(ch)
+		}
+	}
+	for _, collector := range c.lookupMetrics {
+		if prometheusCollector, ok := collector.(prometheus.Collector); ok {
+			prometheusCollector.Collect(ch)
+		}
+	}
+	if c.hotKeyTracker != nil && c.hotKeyTracker.prometheus != nil {
+		c.hotKeyTracker.prometheus.Collect(ch)
+	}
+	for _, collector := range c.evictionRegretMetrics {
+		if prometheusCollector, ok := collector.(prometheus.Collector); ok {
+			prometheusCollector.Collect(ch)
+		}
+	}
+	if c.missRatioCurve != nil && c.missRatioCurve.prometheus != nil {
+		c.missRatioCurve.prometheus.Collect(ch)
+	}
+	if c.shadows != nil && c.shadows.prometheus != nil {
+		c.shadows.prometheus.Collect(ch)
+	}
+	if c.windowRatioMetrics != nil {
+		c.windowRatioMetrics.Collect(ch)
+	}
+	if c.invalidation != nil && c.invalidation.prometheus != nil {
+		c.invalidation.prometheus.Collect(ch)
+	}
+}
+
+// refreshMetricGauges updates the size and length gauges of the collectors.
+// Warning: This is very slow, since the size of every entry is computed.
+func (c *HotCache[K, V]) refreshMetricGauges() {
 	c.cache.SizeBytes()
 	c.cache.Len()
 	if c.missingCache != nil {
 		c.missingCache.SizeBytes()
 		c.missingCache.Len()
 	}
+}
 
-	for _, collector := range c.prometheusCollectors {
-		if prometheusCollector, ok := collector.(prometheus.Collector); ok {
-			prometheusCollector.Collect(ch)
-		}
-	}
+// registerOpenTelemetryCallback reports the OpenTelemetry collectors every time the meter is collected.
+func (c *HotCache[K, V]) registerOpenTelemetryCallback(meter metric.Meter, instruments *metrics.OpenTelemetryInstruments, collectors []*metrics.OpenTelemetryCollector) (metric.Registration, error) {
+	return meter.RegisterCallback(
+		func(ctx context.Context, observer metric.Observer) error {
+			c.refreshMetricGauges()
+			for _, collector := range collectors {
+				collector.Observe(ctx, observer)
+			}
+			return nil
+		},
+		instruments.Observables()...,
+	)
 }
@@ -1,11 +1,14 @@
 package hot
 
 import (
+	"context"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/samber/go-singleflightx"
 	"github.com/samber/hot/pkg/safe"
 	"github.com/stretchr/testify/assert"
@@ -15,8 +18,8 @@ func TestNewHotCache(t *testing.T) {
 	is := assert.New(t)
 	t.Parallel()
 
-	lru := composeInternalCache[int, int](false, LRU, 42, 0, -1, nil, nil, nil)
-	safeLru := composeInternalCache[int, int](true, LRU, 42, 0, -1, nil, nil, nil)
+	lru := composeInternalCache[int, int](false, LRU, 42, 0, -1, nil, nil, nil, nil, nil)
+	safeLru := composeInternalCache[int, int](true, LRU, 42, 0, -1, nil, nil, nil, nil, nil)
 
 	// locking
 	cache := newHotCache(lru, false, nil, 0, 0, 0, 0, nil, nil, DropOnError, nil, nil, nil, nil)
@@ -30,7 +33,7 @@ func TestNewHotCache(t *testing.T) {
 
 	// ttl, stale, jitter
 	cache = newHotCache(safeLru, false, nil, 42_000, 21_000, 2, time.Second, nil, nil, DropOnError, nil, nil, nil, nil)
-	is.Equal(&HotCache[int, int]{sync.RWMutex{}, nil, nil, nil, nil, safeLru, false, nil, 42_000, 21_000, 2, time.Second, nil, nil, DropOnError, nil, nil, nil, singleflightx.Group[int, int]{}, nil}, cache)
+	is.Equal(&HotCache[int, int]{sync.RWMutex{}, nil, nil, nil, nil, safeLru, false, nil, 42_000, 21_000, 2, time.Second, nil, nil, DropOnError, nil, nil, nil, singleflightx.Group[int, int]{}, 0, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil}, cache)
 
 	// @TODO: test locks
 	// @TODO: more tests
@@ -1186,6 +1189,122 @@ func TestHotCache_Algorithm(t *testing.T) {
 	is.Equal("lfu", b)
 }
 
+func TestHotCache_WindowRatio(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	_, ok := NewHotCache[int, int](LRU, 1000).Build().WindowRatio()
+	is.False(ok)
+
+	ratio, ok := NewHotCache[int, int](WTinyLFU, 1000).WithSharding(4, func(k int) uint64 { return uint64(k) }).Build().WindowRatio()
+	is.True(ok)
+	is.InDelta(0.01, ratio, 1e-9)
+
+	cache := NewHotCache[int, int](WTinyLFU, 1000).
+		WithSharding(4, func(k int) uint64 { return uint64(k) }).
+		WithWindowRatio(0.2).
+		WithPrometheusMetrics("test-cache").
+		Build()
+	ratio, ok = cache.WindowRatio()
+	is.True(ok)
+	is.InDelta(0.2, ratio, 1e-9)
+
+	expected := `
+# HELP hot_window_ratio Fraction of the cache capacity used by the admission window
+# TYPE hot_window_ratio gauge
+hot_window_ratio{name="test-cache"} 0.2
+`
+	is.NoError(testutil.CollectAndCompare(cache, strings.NewReader(expected), "hot_window_ratio"))
+
+	is.PanicsWithValue("window ratio requires the wtinylfu eviction algorithm", func() {
+		_ = NewHotCache[int, int](LRU, 1000).WithWindowRatio(0.2)
+	})
+	is.PanicsWithValue("window ratio must be in (0, 1)", func() {
+		_ = NewHotCache[int, int](WTinyLFU, 1000).WithWindowRatio(1)
+	})
+}
+
+func TestHotCache_TTL(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewHotCache[string, int](LRU, 10).
+		Build()
+	ttl, stale := cache.TTL()
+	is.Equal(time.Duration(0), ttl)
+	is.Equal(time.Duration(0), stale)
+
+	cache = NewHotCache[string, int](LRU, 10).
+		WithTTL(time.Minute).
+		WithRevalidation(time.Second).
+		Build()
+	ttl, stale = cache.TTL()
+	is.Equal(time.Minute, ttl)
+	is.Equal(time.Second, stale)
+}
+
+func TestHotCache_Shards(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewHotCache[string, int](LRU, 10).
+		Build()
+	is.Equal(1, cache.Shards())
+
+	cache = NewHotCache[string, int](LRU, 10).
+		WithSharding(4, func(key string) uint64 { return uint64(len(key)) }).
+		Build()
+	is.Equal(4, cache.Shards())
+}
+
+func TestHotCache_Inspect(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewHotCache[string, int](LRU, 10).
+		WithTTL(time.Minute).
+		WithRevalidation(time.Second).
+		WithMissingCache(LRU, 10).
+		WithCopyOnRead(func(nb int) int {
+			return nb * 2
+		}).
+		Build()
+
+	_, ok := cache.Inspect("a")
+	is.False(ok)
+
+	cache.Set("a", 21)
+	info, ok := cache.Inspect("a")
+	is.True(ok)
+	is.Equal(42, info.Value)
+	is.False(info.Missing)
+	is.False(info.Stale)
+	is.False(info.Expired)
+	is.WithinDuration(time.Now().Add(time.Minute), info.ExpiresAt, 100*time.Millisecond)
+	is.Equal(time.Second, info.StaleUntil.Sub(info.ExpiresAt))
+
+	cache.SetMissingWithTTL("b", 10*time.Millisecond)
+	info, ok = cache.Inspect("b")
+	is.True(ok)
+	is.True(info.Missing)
+	is.Equal(0, info.Value)
+
+	time.Sleep(20 * time.Millisecond)
+	info, ok = cache.Inspect("b")
+	is.True(ok)
+	is.True(info.Stale)
+	is.False(info.Expired)
+
+	// no ttl
+	cache = NewHotCache[string, int](LRU, 10).
+		Build()
+	cache.Set("a", 1)
+	info, ok = cache.Inspect("a")
+	is.True(ok)
+	is.True(info.ExpiresAt.IsZero())
+	is.True(info.StaleUntil.IsZero())
+}
+
 func TestHotCache_Len(t *testing.T) {
 	is := assert.New(t)
 	t.Parallel()
@@ -1848,8 +1967,10 @@ func TestHotCache_loadAndSetMany(t *testing.T) {
 
 	cache.Purge()
 	v, err := cache.loadAndSetMany(
+		context.Background(),
 		[]string{"a", "b"},
 		LoaderChain[string, int]{},
+		nil,
 	)
 	is.NoError(err)
 	is.NotNil(v)
@@ -1859,6 +1980,7 @@ func TestHotCache_loadAndSetMany(t *testing.T) {
 
 	cache.Purge()
 	v, err = cache.loadAndSetMany(
+		context.Background(),
 		[]string{},
 		LoaderChain[string, int]{
 			func(keys []string) (map[string]int, error) {
@@ -1872,6 +1994,7 @@ func TestHotCache_loadAndSetMany(t *testing.T) {
 				return map[string]int{"a": 2}, nil
 			},
 		},
+		nil,
 	)
 	is.NoError(err)
 	is.NotNil(v)
@@ -1879,6 +2002,7 @@ func TestHotCache_loadAndSetMany(t *testing.T) {
 
 	cache.Purge()
 	v, err = cache.loadAndSetMany(
+		context.Background(),
 		[]string{"a"},
 		LoaderChain[string, int]{
 			func(keys []string) (map[string]int, error) {
@@ -1891,6 +2015,7 @@ func TestHotCache_loadAndSetMany(t *testing.T) {
 				return map[string]int{"a": 2}, nil
 			},
 		},
+		nil,
 	)
 	is.EqualError(err, assert.AnError.Error())
 	is.NotNil(v)
@@ -1899,6 +2024,7 @@ func TestHotCache_loadAndSetMany(t *testing.T) {
 
 	cache.Purge()
 	v, err = cache.loadAndSetMany(
+		context.Background(),
 		[]string{"a", "b"},
 		LoaderChain[string, int]{
 			func(keys []string) (map[string]int, error) {
@@ -1912,6 +2038,7 @@ func TestHotCache_loadAndSetMany(t *testing.T) {
 				return map[string]int{"a": 2}, nil
 			},
 		},
+		nil,
 	)
 	is.NoError(err)
 	is.Len(v, 2)
@@ -1929,6 +2056,7 @@ func TestHotCache_loadAndSetMany(t *testing.T) {
 		}).
 		Build()
 	v, err = cache.loadAndSetMany(
+		context.Background(),
 		[]string{"a", "b"},
 		LoaderChain[string, int]{
 			func(keys []string) (map[string]int, error) {
@@ -1942,6 +2070,7 @@ func TestHotCache_loadAndSetMany(t *testing.T) {
 				return map[string]int{"a": 2}, nil
 			},
 		},
+		nil,
 	)
 	is.NoError(err)
 	is.Len(v, 2)
@@ -1959,6 +2088,7 @@ func TestHotCache_loadAndSetMany(t *testing.T) {
 		}).
 		Build()
 	v, err = cache.loadAndSetMany(
+		context.Background(),
 		[]string{"a", "b"},
 		LoaderChain[string, int]{
 			func(keys []string) (map[string]int, error) {
@@ -1972,6 +2102,7 @@ func TestHotCache_loadAndSetMany(t *testing.T) {
 				return map[string]int{"a": 2}, nil
 			},
 		},
+		nil,
 	)
 	is.NoError(err)
 	is.Len(v, 2)
@@ -0,0 +1,233 @@
+package hot
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/samber/hot/internal"
+	"github.com/samber/hot/internal/mrc"
+	"github.com/samber/hot/internal/sketch"
+	"github.com/samber/hot/pkg/metrics"
+	"github.com/samber/hot/pkg/replication"
+)
+
+// HotKey is a frequently accessed key, returned by HotKeys.
+type HotKey[K comparable] struct {
+	Key K
+	// Count is the estimated number of accesses over the decaying window.
+	Count uint64
+	// Error is the maximum overestimation of Count. Count-Error is a lower bound of the accesses.
+	Error uint64
+}
+
+const (
+	// hotKeyMaxStripes is the maximum number of stripes of the hot key tracker.
+	hotKeyMaxStripes = 16
+	// hotKeyMinStripeCapacity is the minimum number of keys monitored by a stripe: smaller
+	// summaries are too inaccurate, and small trackers are not striped at all.
+	hotKeyMinStripeCapacity = 64
+	// hotKeyRefreshAheadInterval is the time between two computations of the keys refreshed ahead.
+	hotKeyRefreshAheadInterval = 100 * time.Millisecond
+)
+
+// hotKeyTracker counts the accesses of the keys read from the cache, with Space-Saving top-K summaries.
+// Keys are spread over stripes by hash, each stripe monitoring its own keys under its own lock,
+// so that concurrent reads of different keys rarely contend. Counts are halved every half-life.
+type hotKeyTracker[K comparable] struct {
+	stripes      []hotKeyStripe[K]
+	halfLifeNano int64 // 0 disables the decay
+
+	// Keys ranked in the first refreshAheadTop are reloaded refreshAheadNano before their expiration.
+	// The ranking is computed at most once per hotKeyRefreshAheadInterval.
+	refreshAheadTop    int
+	refreshAheadNano   int64
+	refreshAheadKeys   atomic.Pointer[map[K]struct{}]
+	refreshAheadAtNano atomic.Int64
+
+	// Exporter of the top keys, nil when disabled.
+	prometheus *metrics.PrometheusHotKeysCollector
+}
+
+// hotKeyStripe is the summary of the keys of a stripe.
+type hotKeyStripe[K comparable] struct {
+	mu            sync.Mutex
+	topk          *sketch.TopK[K]
+	lastDecayNano int64
+}
+
+func newHotKeyTracker[K comparable](capacity int, halfLifeNano int64) *hotKeyTracker[K] {
+	// A power of two, so that the stripe of a key is a mask of its hash.
+	stripes := 1
+	for stripes*2 <= hotKeyMaxStripes && capacity/(stripes*2) >= hotKeyMinStripeCapacity {
+		stripes *= 2
+	}
+
+	t := &hotKeyTracker[K]{
+		stripes:      make([]hotKeyStripe[K], stripes),
+		halfLifeNano: halfLifeNano,
+	}
+
+	nowNano := internal.NowNano()
+	for i := range t.stripes {
+		t.stripes[i].topk = sketch.NewTopK[K]((capacity + stripes - 1) / stripes)
+		t.stripes[i].lastDecayNano = nowNano
+	}
+
+	return t
+}
+
+func (t *hotKeyTracker[K]) stripe(key K) *hotKeyStripe[K] {
+	if len(t.stripes) == 1 {
+		return &t.stripes[0]
+	}
+
+	return &t.stripes[mrc.Hash(key)&uint64(len(t.stripes)-1)]
+}
+
+// decay halves the counts once per elapsed half-life. The caller must hold the lock of the stripe.
+func (t *hotKeyTracker[K]) decay(s *hotKeyStripe[K], nowNano int64) {
+	if t.halfLifeNano <= 0 {
+		return
+	}
+
+	periods := (nowNano - s.lastDecayNano) / t.halfLifeNano
+	if periods <= 0 {
+		return
+	}
+
+	s.topk.Decay(uint(periods))
+	s.lastDecayNano += periods * t.halfLifeNano
+}
+
+// inc records an access to each key. A nil tracker records nothing.
+func (t *hotKeyTracker[K]) inc(keys ...K) {
+	if t == nil {
+		return
+	}
+
+	nowNano := internal.NowNano()
+
+	for _, key := range keys {
+		s := t.stripe(key)
+		s.mu.Lock()
+		t.decay(s, nowNano)
+		s.topk.Inc(key)
+		s.mu.Unlock()
+	}
+}
+
+// top merges the top n keys of every stripe. Stripes monitor distinct keys.
+func (t *hotKeyTracker[K]) top(n int) []HotKey[K] {
+	nowNano := internal.NowNano()
+
+	entries := []sketch.TopKEntry[K]{}
+	for i := range t.stripes {
+		s := &t.stripes[i]
+		s.mu.Lock()
+		t.decay(s, nowNano)
+		entries = append(entries, s.topk.Top(n)...)
+		s.mu.Unlock()
+	}
+
+	if len(t.stripes) > 1 {
+		// Same order as TopK.Top.
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].Count != entries[j].Count {
+				return entries[i].Count > entries[j].Count
+			}
+			return entries[i].Error < entries[j].Error
+		})
+		if n > 0 && n < len(entries) {
+			entries = entries[:n]
+		}
+	}
+
+	keys := make([]HotKey[K], 0, len(entries))
+	for _, e := range entries {
+		keys = append(keys, HotKey[K]{Key: e.Key, Count: e.Count, Error: e.Error})
+	}
+
+	return keys
+}
+
+// shouldRefreshAhead returns true when an entry expiring at expiryNano is close enough to its expiration,
+// and its key is hot enough, to be reloaded before expiring. A nil tracker never refreshes.
+func (t *hotKeyTracker[K]) shouldRefreshAhead(key K, expiryNano int64) bool {
+	if t == nil || t.refreshAheadNano <= 0 || expiryNano <= 0 {
+		return false
+	}
+
+	nowNano := internal.NowNano()
+	if expiryNano-nowNano > t.refreshAheadNano {
+		return false
+	}
+
+	// A single caller ranks the keys again, the others use the previous ranking meanwhile.
+	at := t.refreshAheadAtNano.Load()
+	if nowNano-at >= hotKeyRefreshAheadInterval.Nanoseconds() && t.refreshAheadAtNano.CompareAndSwap(at, nowNano) {
+		top := t.top(t.refreshAheadTop)
+		keys := make(map[K]struct{}, len(top))
+		for _, hotKey := range top {
+			keys[hotKey.Key] = struct{}{}
+		}
+		t.refreshAheadKeys.Store(&keys)
+	}
+
+	keys := t.refreshAheadKeys.Load()
+	if keys == nil {
+		return false
+	}
+
+	_, ok := (*keys)[key]
+	return ok
+}
+
+// HotKeys returns up to n of the most accessed keys, by descending estimated count.
+// Accesses are the keys requested through Get and GetMany (and their variants), hits and misses alike.
+// A non-positive n returns every tracked key. Returns nil when hot key tracking is not enabled.
+func (c *HotCache[K, V]) HotKeys(n int) []HotKey[K] {
+	if c.hotKeyTracker == nil {
+		return nil
+	}
+
+	return c.hotKeyTracker.top(n)
+}
+
+// prometheusHotKeys returns the top n keys formatted with `label`, for the Prometheus exporter.
+func (c *HotCache[K, V]) prometheusHotKeys(n int, label func(K) string) func() []metrics.HotKey {
+	if label == nil {
+		label = func(key K) string {
+			return fmt.Sprint(key)
+		}
+	}
+
+	return func() []metrics.HotKey {
+		top := c.hotKeyTracker.top(n)
+		keys := make([]metrics.HotKey, 0, len(top))
+		for _, hotKey := range top {
+			keys = append(keys, metrics.HotKey{Key: label(hotKey.Key), Count: hotKey.Count})
+		}
+		return keys
+	}
+}
+
+// sortSnapshotByHotness moves the events of the tracked keys to the end of the snapshot, hottest last.
+// Followers apply the snapshot in order, so that the hottest keys are the most recently written
+// and the last to be evicted when the follower is smaller than the primary.
+func (c *HotCache[K, V]) sortSnapshotByHotness(events []replication.Event[K, V]) {
+	if c.hotKeyTracker == nil {
+		return
+	}
+
+	counts := map[K]uint64{}
+	for _, hotKey := range c.hotKeyTracker.top(0) {
+		counts[hotKey.Key] = hotKey.Count
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return counts[events[i].Key] < counts[events[j].Key]
+	})
+}
@@ -0,0 +1,207 @@
+package hot
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/samber/hot/pkg/replication"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHotCache_HotKeys(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewHotCache[string, int](LRU, 10).Build()
+	_, _, _ = cache.Get("a")
+	is.Nil(cache.HotKeys(10))
+
+	cache = NewHotCache[string, int](LRU, 10).
+		WithHotKeyTracking(3, 0).
+		Build()
+	cache.Set("a", 1)
+
+	for i := 0; i < 5; i++ {
+		_, _, _ = cache.Get("a")
+	}
+	_, _, _ = cache.GetMany([]string{"a", "b", "b"})
+	_, _, _ = cache.Get("c")
+	cache.Peek("c") // not an access
+
+	is.Equal([]HotKey[string]{
+		{Key: "a", Count: 6},
+		{Key: "b", Count: 2},
+		{Key: "c", Count: 1},
+	}, cache.HotKeys(0))
+	is.Equal([]HotKey[string]{{Key: "a", Count: 6}}, cache.HotKeys(1))
+
+	// "d" replaces the coldest key.
+	_, _, _ = cache.Get("d")
+	is.Equal([]HotKey[string]{
+		{Key: "a", Count: 6},
+		{Key: "b", Count: 2},
+		{Key: "d", Count: 2, Error: 1},
+	}, cache.HotKeys(0))
+}
+
+func TestHotCache_HotKeys_Decay(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewHotCache[string, int](LRU, 10).
+		WithHotKeyTracking(10, 20*time.Millisecond).
+		Build()
+
+	for i := 0; i < 8; i++ {
+		_, _, _ = cache.Get("a")
+	}
+	is.Equal([]HotKey[string]{{Key: "a", Count: 8}}, cache.HotKeys(0))
+
+	time.Sleep(25 * time.Millisecond)
+	is.Equal([]HotKey[string]{{Key: "a", Count: 4}}, cache.HotKeys(0))
+
+	time.Sleep(100 * time.Millisecond)
+	is.Empty(cache.HotKeys(0))
+}
+
+func TestHotCache_HotKeys_Striped(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	is.Len(newHotKeyTracker[int](100, 0).stripes, 1)
+	is.Len(newHotKeyTracker[int](128, 0).stripes, 2)
+	is.Len(newHotKeyTracker[int](100_000, 0).stripes, hotKeyMaxStripes)
+
+	cache := NewHotCache[int, int](LRU, 10).
+		WithHotKeyTracking(1024, 0).
+		Build()
+
+	// Key i is read i times, concurrently.
+	var wg sync.WaitGroup
+	for i := 1; i <= 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < i; j++ {
+				_, _, _ = cache.Get(i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The keys of every stripe are merged by descending count.
+	top := cache.HotKeys(3)
+	is.Equal([]HotKey[int]{{Key: 100, Count: 100}, {Key: 99, Count: 99}, {Key: 98, Count: 98}}, top)
+	is.Len(cache.HotKeys(0), 100)
+}
+
+func TestWithHotKeyRefreshAhead(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	loads := map[string]*atomic.Int64{"a": {}, "b": {}}
+	cache := NewHotCache[string, int](LRU, 10).
+		WithTTL(100*time.Millisecond).
+		WithHotKeyTracking(10, 0).
+		WithHotKeyRefreshAhead(1, 50*time.Millisecond).
+		WithLoaders(func(keys []string) (map[string]int, error) {
+			values := map[string]int{}
+			for _, key := range keys {
+				loads[key].Add(1)
+				values[key] = len(key)
+			}
+			return values, nil
+		}).
+		Build()
+
+	_, _, _ = cache.GetMany([]string{"a", "b"})
+	_, _, _ = cache.Get("a")
+	is.Equal(int64(1), loads["a"].Load())
+	is.Equal(int64(1), loads["b"].Load())
+
+	// Far from the expiration, keys are not reloaded.
+	_, _, _ = cache.Get("a")
+	time.Sleep(10 * time.Millisecond)
+	is.Equal(int64(1), loads["a"].Load())
+
+	// Close to the expiration, only the hottest key is reloaded.
+	time.Sleep(60 * time.Millisecond)
+	_, _, _ = cache.GetMany([]string{"a", "b"})
+	is.Eventually(func() bool {
+		return loads["a"].Load() == 2
+	}, time.Second, 5*time.Millisecond)
+	is.Equal(int64(1), loads["b"].Load())
+
+	// The reloaded value outlives the original TTL.
+	time.Sleep(50 * time.Millisecond)
+	_, _, _ = cache.GetMany([]string{"a", "b"})
+	is.Equal(int64(2), loads["a"].Load())
+	is.Equal(int64(2), loads["b"].Load())
+
+	is.PanicsWithValue("hot key refresh-ahead requires hot key tracking", func() {
+		_ = NewHotCache[string, int](LRU, 10).WithHotKeyRefreshAhead(1, time.Second).Build()
+	})
+	is.Panics(func() {
+		_ = NewHotCache[string, int](LRU, 10).WithHotKeyRefreshAhead(0, time.Second)
+	})
+}
+
+func TestWithHotKeyMetrics(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewHotCache[int, int](LRU, 10).
+		WithPrometheusMetrics("test-cache").
+		WithHotKeyTracking(10, 0).
+		WithHotKeyMetrics(2, nil).
+		Build()
+
+	_, _, _ = cache.GetMany([]int{1, 1, 1, 2, 2, 3})
+
+	expected := `
+# HELP hot_key_accesses Estimated number of accesses of the most accessed keys, over a decaying window
+# TYPE hot_key_accesses gauge
+hot_key_accesses{key="1",name="test-cache",rank="1"} 3
+hot_key_accesses{key="2",name="test-cache",rank="2"} 2
+`
+	is.NoError(testutil.CollectAndCompare(cache, strings.NewReader(expected), "hot_key_accesses"))
+
+	is.PanicsWithValue("hot key metrics require prometheus metrics", func() {
+		_ = NewHotCache[int, int](LRU, 10).WithHotKeyTracking(10, 0).WithHotKeyMetrics(2, nil).Build()
+	})
+	is.PanicsWithValue("hot key metrics require hot key tracking", func() {
+		_ = NewHotCache[int, int](LRU, 10).WithPrometheusMetrics("test-cache").WithHotKeyMetrics(2, nil).Build()
+	})
+}
+
+func TestWithHotKeyTracking_ReplicationSnapshot(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	log := replication.NewLog[string, int](100)
+	cache := NewHotCache[string, int](LRU, 10).
+		WithHotKeyTracking(10, 0).
+		WithReplicationLog(log).
+		Build()
+
+	cache.SetMany(map[string]int{"a": 1, "b": 2, "c": 3, "d": 4})
+	_, _, _ = cache.GetMany([]string{"b", "b", "b", "a"})
+
+	// The hottest keys come last, so that they are the last evicted from smaller followers.
+	snapshot, _ := log.Snapshot()
+	is.Len(snapshot, 5)
+	is.ElementsMatch([]string{"c", "d"}, []string{snapshot[1].Key, snapshot[2].Key})
+	is.Equal("a", snapshot[3].Key)
+	is.Equal("b", snapshot[4].Key)
+
+	is.Panics(func() {
+		_ = NewHotCache[string, int](LRU, 10).WithHotKeyTracking(0, 0)
+	})
+	is.Panics(func() {
+		_ = NewHotCache[string, int](LRU, 10).WithHotKeyTracking(10, -1)
+	})
+}
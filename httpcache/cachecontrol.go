@@ -0,0 +1,72 @@
+package httpcache
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cacheControl holds the Cache-Control directives used by the middleware.
+// Durations are -1 when the directive is absent.
+type cacheControl struct {
+	noStore              bool
+	noCache              bool
+	private              bool
+	public               bool
+	mustRevalidate       bool
+	maxAge               time.Duration
+	sMaxAge              time.Duration
+	staleWhileRevalidate time.Duration
+	staleIfError         time.Duration
+}
+
+// parseCacheControl parses the Cache-Control headers. Unknown directives are ignored.
+func parseCacheControl(header http.Header) cacheControl {
+	cc := cacheControl{
+		maxAge:               -1,
+		sMaxAge:              -1,
+		staleWhileRevalidate: -1,
+		staleIfError:         -1,
+	}
+
+	for _, line := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(line, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			name = strings.ToLower(strings.TrimSpace(name))
+			value = strings.Trim(strings.TrimSpace(value), `"`)
+
+			switch name {
+			case "no-store":
+				cc.noStore = true
+			case "no-cache":
+				cc.noCache = true
+			case "private":
+				cc.private = true
+			case "public":
+				cc.public = true
+			case "must-revalidate":
+				cc.mustRevalidate = true
+			case "max-age":
+				cc.maxAge = parseSeconds(value)
+			case "s-maxage":
+				cc.sMaxAge = parseSeconds(value)
+			case "stale-while-revalidate":
+				cc.staleWhileRevalidate = parseSeconds(value)
+			case "stale-if-error":
+				cc.staleIfError = parseSeconds(value)
+			}
+		}
+	}
+
+	return cc
+}
+
+// parseSeconds parses a delta-seconds value. Invalid values are treated as 0, as RFC 9111 recommends.
+func parseSeconds(value string) time.Duration {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
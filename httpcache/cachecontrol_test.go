@@ -0,0 +1,33 @@
+package httpcache
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCacheControl(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cc := parseCacheControl(http.Header{})
+	is.Equal(cacheControl{maxAge: -1, sMaxAge: -1, staleWhileRevalidate: -1, staleIfError: -1}, cc)
+
+	header := http.Header{}
+	header.Add("Cache-Control", `public, Max-Age=60, s-maxage="120"`)
+	header.Add("Cache-Control", "stale-while-revalidate=30, stale-if-error=invalid, no-cache, private, no-store, must-revalidate, unknown")
+	cc = parseCacheControl(header)
+	is.Equal(cacheControl{
+		noStore:              true,
+		noCache:              true,
+		private:              true,
+		public:               true,
+		mustRevalidate:       true,
+		maxAge:               time.Minute,
+		sMaxAge:              2 * time.Minute,
+		staleWhileRevalidate: 30 * time.Second,
+		staleIfError:         0,
+	}, cc)
+}
@@ -0,0 +1,301 @@
+package httpcache
+
+import (
+	"context"
+	"net/http"
+	"net/textproto"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samber/go-singleflightx"
+	"github.com/samber/hot"
+)
+
+// CacheStatusHeader is the response header reporting how the response was served: HIT, STALE or MISS.
+const CacheStatusHeader = "X-Cache"
+
+// KeyFunc derives the cache key of a request.
+type KeyFunc func(r *http.Request) string
+
+// DefaultKey derives the cache key from the method, the path and the query string.
+// Query parameters are sorted, so that their order does not change the key.
+func DefaultKey(r *http.Request) string {
+	return r.Method + " " + r.URL.Path + "?" + r.URL.Query().Encode()
+}
+
+// defaultCacheableStatus lists the status codes that are cacheable by default, per RFC 9110.
+var defaultCacheableStatus = []int{
+	http.StatusOK,
+	http.StatusNonAuthoritativeInfo,
+	http.StatusNoContent,
+	http.StatusPartialContent,
+	http.StatusMultipleChoices,
+	http.StatusMovedPermanently,
+	http.StatusPermanentRedirect,
+	http.StatusNotFound,
+	http.StatusMethodNotAllowed,
+	http.StatusGone,
+	http.StatusRequestURITooLong,
+	http.StatusNotImplemented,
+}
+
+// NewMiddleware creates a middleware caching full responses of GET and HEAD requests in the provided cache.
+// Entries are stored with a TTL covering the freshness lifetime and the stale windows of the response.
+func NewMiddleware(cache *hot.HotCache[string, *Response]) MiddlewareConfig {
+	return MiddlewareConfig{
+		cache:           cache,
+		keyFn:           DefaultKey,
+		cacheableStatus: defaultCacheableStatus,
+	}
+}
+
+// MiddlewareConfig holds the configuration of the middleware.
+type MiddlewareConfig struct {
+	cache           *hot.HotCache[string, *Response]
+	keyFn           KeyFunc
+	varyHeaders     []string
+	defaultTTL      time.Duration
+	cacheableStatus []int
+}
+
+// WithKeyFunc sets the function deriving the cache key of a request. Default is DefaultKey.
+func (cfg MiddlewareConfig) WithKeyFunc(keyFn KeyFunc) MiddlewareConfig {
+	cfg.keyFn = keyFn
+	return cfg
+}
+
+// WithVaryHeaders adds the values of the provided request headers to the cache key.
+// Headers listed in the Vary response header are always checked before serving a cached response.
+func (cfg MiddlewareConfig) WithVaryHeaders(headers ...string) MiddlewareConfig {
+	cfg.varyHeaders = append([]string{}, headers...)
+	return cfg
+}
+
+// WithDefaultTTL sets the freshness lifetime of responses without max-age nor s-maxage directive.
+// By default, such responses are not cached. Responses to requests with an Authorization header
+// are not cached on the default TTL.
+func (cfg MiddlewareConfig) WithDefaultTTL(ttl time.Duration) MiddlewareConfig {
+	cfg.defaultTTL = ttl
+	return cfg
+}
+
+// WithCacheableStatus sets the status codes that can be cached.
+func (cfg MiddlewareConfig) WithCacheableStatus(status ...int) MiddlewareConfig {
+	cfg.cacheableStatus = append([]int{}, status...)
+	return cfg
+}
+
+// Build returns the middleware.
+func (cfg MiddlewareConfig) Build() func(http.Handler) http.Handler {
+	if cfg.cache == nil {
+		panic("cache is required")
+	}
+	if cfg.keyFn == nil {
+		panic("key function is required")
+	}
+	if cfg.defaultTTL < 0 {
+		panic("default TTL must be a positive value")
+	}
+
+	varyHeaders := make([]string, len(cfg.varyHeaders))
+	for i, h := range cfg.varyHeaders {
+		varyHeaders[i] = textproto.CanonicalMIMEHeaderKey(h)
+	}
+	cfg.varyHeaders = varyHeaders
+
+	return func(next http.Handler) http.Handler {
+		return &handler{
+			cfg:  cfg,
+			next: next,
+		}
+	}
+}
+
+type handler struct {
+	cfg   MiddlewareConfig
+	next  http.Handler
+	group singleflightx.Group[string, fetched]
+}
+
+// fetched is a response generated by the wrapped handler.
+type fetched struct {
+	res    *Response
+	stored bool // The response was stored in the cache
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	reqCC := parseCacheControl(r.Header)
+	if reqCC.noStore {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+
+	key := h.key(r)
+	now := time.Now()
+
+	cached, found, _ := h.cfg.cache.Get(key)
+	if found && !cached.matches(r) {
+		found = false
+	}
+
+	if found && !reqCC.noCache && (reqCC.maxAge < 0 || cached.age(now) <= reqCC.maxAge) {
+		age := cached.age(now)
+		switch {
+		case age <= cached.Fresh:
+			h.write(w, r, cached, now, "HIT")
+			return
+		case age <= cached.Fresh+cached.StaleWhileRevalidate:
+			h.revalidate(key, r)
+			h.write(w, r, cached, now, "STALE")
+			return
+		}
+	}
+
+	res := h.fetchShared(key, r)
+
+	// stale-if-error
+	if res.StatusCode >= http.StatusInternalServerError && found && cached.age(now) <= cached.Fresh+cached.StaleIfError {
+		h.write(w, r, cached, now, "STALE")
+		return
+	}
+
+	h.write(w, r, res, now, "MISS")
+}
+
+// key derives the cache key, including the configured vary headers.
+func (h *handler) key(r *http.Request) string {
+	key := h.cfg.keyFn(r)
+	for _, name := range h.cfg.varyHeaders {
+		key += "\n" + name + ": " + strings.Join(r.Header.Values(name), ", ")
+	}
+	return key
+}
+
+// fetchShared calls the wrapped handler once for the concurrent requests of a key.
+// The response is only shared with the other requests when it was stored in the cache and
+// matches their Vary headers, as if it was served from the cache. Otherwise, such as for
+// private responses, each request calls the wrapped handler on its own.
+// The shared call does not depend on the cancellation of the request that started it.
+func (h *handler) fetchShared(key string, r *http.Request) *Response {
+	leader := false
+	result, _, _ := h.group.Do(key, func() (fetched, error) {
+		leader = true
+		return h.fetch(context.WithoutCancel(r.Context()), key, r), nil
+	})
+
+	if leader || (result.stored && result.res.matches(r)) {
+		return result.res
+	}
+
+	return h.fetch(r.Context(), key, r).res
+}
+
+// fetch calls the wrapped handler and stores the response when it is cacheable.
+// Conditional headers are removed, so that the handler returns a full response.
+func (h *handler) fetch(ctx context.Context, key string, r *http.Request) fetched {
+	req := r.Clone(ctx)
+	req.Header.Del("If-None-Match")
+	req.Header.Del("If-Modified-Since")
+
+	rec := newRecorder()
+	h.next.ServeHTTP(rec, req)
+
+	res := rec.response(time.Now())
+	ttl, ok := h.cacheable(r, res)
+	if ok {
+		h.cfg.cache.SetWithTTL(key, res, ttl)
+	}
+
+	return fetched{res: res, stored: ok}
+}
+
+// revalidate refreshes a stale response in the background.
+func (h *handler) revalidate(key string, r *http.Request) {
+	req := r.Clone(context.Background())
+	go h.group.Do(key, func() (fetched, error) { //nolint:errcheck
+		return h.fetch(req.Context(), key, req), nil
+	})
+}
+
+// cacheable checks the response and computes its freshness lifetime.
+// It returns the TTL of the cache entry.
+//
+// The cache is shared between users (RFC 9111 §3.5): responses to requests with an Authorization
+// header are only stored when explicitly allowed with public, s-maxage or must-revalidate, and
+// responses setting cookies are never stored.
+func (h *handler) cacheable(r *http.Request, res *Response) (time.Duration, bool) {
+	if !slices.Contains(h.cfg.cacheableStatus, res.StatusCode) {
+		return 0, false
+	}
+
+	cc := parseCacheControl(res.Header)
+	if cc.noStore || cc.noCache || cc.private {
+		return 0, false
+	}
+
+	if r.Header.Get("Authorization") != "" && !cc.public && cc.sMaxAge < 0 && !cc.mustRevalidate {
+		return 0, false
+	}
+
+	if len(res.Header.Values("Set-Cookie")) > 0 {
+		return 0, false
+	}
+
+	vary, ok := varyValues(r, res.Header)
+	if !ok {
+		return 0, false
+	}
+	res.Vary = vary
+
+	switch {
+	case cc.sMaxAge >= 0:
+		res.Fresh = cc.sMaxAge
+	case cc.maxAge >= 0:
+		res.Fresh = cc.maxAge
+	default:
+		res.Fresh = h.cfg.defaultTTL
+	}
+
+	// must-revalidate forbids serving the response once stale.
+	if !cc.mustRevalidate {
+		res.StaleWhileRevalidate = max(cc.staleWhileRevalidate, 0)
+		res.StaleIfError = max(cc.staleIfError, 0)
+	}
+
+	ttl := res.Fresh + max(res.StaleWhileRevalidate, res.StaleIfError)
+	if ttl <= 0 {
+		return 0, false
+	}
+
+	return ttl, true
+}
+
+// write sends a response, or a 304 when the request is conditional and the ETag matches.
+func (h *handler) write(w http.ResponseWriter, r *http.Request, res *Response, now time.Time, status string) {
+	header := w.Header()
+	for name, values := range res.Header {
+		header[name] = append([]string{}, values...)
+	}
+	header.Set(CacheStatusHeader, status)
+	if status != "MISS" {
+		header.Set("Age", strconv.Itoa(int(res.age(now).Seconds())))
+	}
+
+	if res.StatusCode == http.StatusOK && res.notModified(r) {
+		header.Del("Content-Length")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.WriteHeader(res.StatusCode)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(res.Body)
+	}
+}
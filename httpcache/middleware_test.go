@@ -0,0 +1,483 @@
+package httpcache
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samber/hot"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestHandler(t *testing.T, cfg func(MiddlewareConfig) MiddlewareConfig, next http.HandlerFunc) http.Handler {
+	t.Helper()
+
+	cache := hot.NewHotCache[string, *Response](hot.LRU, 100).Build()
+	config := NewMiddleware(cache)
+	if cfg != nil {
+		config = cfg(config)
+	}
+	return config.Build()(next)
+}
+
+func do(h http.Handler, method string, target string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_Hit(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var calls atomic.Int32
+	h := newTestHandler(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("X-Custom", "foo")
+		fmt.Fprintf(w, "hello %d", n)
+	})
+
+	w := do(h, http.MethodGet, "/foo?b=2&a=1")
+	is.Equal(http.StatusOK, w.Code)
+	is.Equal("hello 1", w.Body.String())
+	is.Equal("MISS", w.Header().Get(CacheStatusHeader))
+
+	// query parameters order does not matter
+	w = do(h, http.MethodGet, "/foo?a=1&b=2")
+	is.Equal(http.StatusOK, w.Code)
+	is.Equal("hello 1", w.Body.String())
+	is.Equal("HIT", w.Header().Get(CacheStatusHeader))
+	is.Equal("foo", w.Header().Get("X-Custom"))
+	is.Equal("0", w.Header().Get("Age"))
+
+	// HEAD is cached separately, without body
+	w = do(h, http.MethodHead, "/foo?a=1&b=2")
+	is.Equal("MISS", w.Header().Get(CacheStatusHeader))
+	is.Empty(w.Body.String())
+
+	// other methods are not cached
+	w = do(h, http.MethodPost, "/foo?a=1&b=2")
+	is.Equal("hello 3", w.Body.String())
+	is.Empty(w.Header().Get(CacheStatusHeader))
+
+	// request no-store and no-cache
+	w = do(h, http.MethodGet, "/foo?a=1&b=2", "Cache-Control", "no-store")
+	is.Equal("hello 4", w.Body.String())
+	w = do(h, http.MethodGet, "/foo?a=1&b=2", "Cache-Control", "no-cache")
+	is.Equal("hello 5", w.Body.String())
+	w = do(h, http.MethodGet, "/foo?a=1&b=2")
+	is.Equal("hello 5", w.Body.String())
+	is.Equal("HIT", w.Header().Get(CacheStatusHeader))
+}
+
+func TestMiddleware_ResponseCacheControl(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var calls atomic.Int32
+	h := newTestHandler(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Cache-Control", r.URL.Query().Get("cc"))
+		if status := r.URL.Query().Get("status"); status != "" {
+			var code int
+			fmt.Sscanf(status, "%d", &code)
+			w.WriteHeader(code)
+		}
+		fmt.Fprintf(w, "%d", n)
+	})
+
+	testCases := []struct {
+		query  string
+		cached bool
+	}{
+		{query: "cc=max-age=60", cached: true},
+		{query: "cc=s-maxage=60,max-age=0", cached: true},
+		{query: "cc=max-age=0", cached: false},
+		{query: "cc=", cached: false},
+		{query: "cc=max-age=60,no-store", cached: false},
+		{query: "cc=max-age=60,private", cached: false},
+		{query: "cc=max-age=60,no-cache", cached: false},
+		{query: "cc=max-age=60&status=404", cached: true},
+		{query: "cc=max-age=60&status=500", cached: false},
+		{query: "cc=max-age=60&status=201", cached: false},
+	}
+
+	for _, tc := range testCases {
+		first := do(h, http.MethodGet, "/?"+tc.query)
+		second := do(h, http.MethodGet, "/?"+tc.query)
+		is.Equal(tc.cached, first.Body.String() == second.Body.String(), tc.query)
+	}
+}
+
+func TestMiddleware_DefaultTTL(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var calls atomic.Int32
+	h := newTestHandler(t,
+		func(cfg MiddlewareConfig) MiddlewareConfig {
+			return cfg.WithDefaultTTL(time.Minute).WithCacheableStatus(http.StatusOK, http.StatusCreated)
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, "%d", calls.Add(1))
+		},
+	)
+
+	is.Equal("1", do(h, http.MethodGet, "/").Body.String())
+	is.Equal("1", do(h, http.MethodGet, "/").Body.String())
+
+	is.Panics(func() {
+		_ = NewMiddleware(nil).Build()
+	})
+	is.Panics(func() {
+		_ = NewMiddleware(hot.NewHotCache[string, *Response](hot.LRU, 1).Build()).WithDefaultTTL(-1).Build()
+	})
+}
+
+func TestMiddleware_Vary(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var calls atomic.Int32
+	h := newTestHandler(t,
+		func(cfg MiddlewareConfig) MiddlewareConfig {
+			return cfg.WithVaryHeaders("accept-language")
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "max-age=60")
+			w.Header().Set("Vary", "Accept-Encoding")
+			fmt.Fprintf(w, "%d %s %s", calls.Add(1), r.Header.Get("Accept-Language"), r.Header.Get("Accept-Encoding"))
+		},
+	)
+
+	is.Equal("1 fr gzip", do(h, http.MethodGet, "/", "Accept-Language", "fr", "Accept-Encoding", "gzip").Body.String())
+	is.Equal("2 en gzip", do(h, http.MethodGet, "/", "Accept-Language", "en", "Accept-Encoding", "gzip").Body.String())
+	is.Equal("1 fr gzip", do(h, http.MethodGet, "/", "Accept-Language", "fr", "Accept-Encoding", "gzip").Body.String())
+
+	// Vary response header mismatch
+	is.Equal("3 fr br", do(h, http.MethodGet, "/", "Accept-Language", "fr", "Accept-Encoding", "br").Body.String())
+	is.Equal("3 fr br", do(h, http.MethodGet, "/", "Accept-Language", "fr", "Accept-Encoding", "br").Body.String())
+
+	// Vary: *
+	h = newTestHandler(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Vary", "*")
+		fmt.Fprintf(w, "%d", calls.Add(1))
+	})
+	is.Equal("4", do(h, http.MethodGet, "/").Body.String())
+	is.Equal("5", do(h, http.MethodGet, "/").Body.String())
+}
+
+func TestMiddleware_IfNoneMatch(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	h := newTestHandler(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		is.Empty(r.Header.Get("If-None-Match"))
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, "hello")
+	})
+
+	w := do(h, http.MethodGet, "/", "If-None-Match", `"v1"`)
+	is.Equal(http.StatusNotModified, w.Code)
+	is.Empty(w.Body.String())
+	is.Equal(`"v1"`, w.Header().Get("ETag"))
+
+	w = do(h, http.MethodGet, "/", "If-None-Match", `"v0", W/"v1"`)
+	is.Equal(http.StatusNotModified, w.Code)
+	is.Equal("HIT", w.Header().Get(CacheStatusHeader))
+
+	w = do(h, http.MethodGet, "/", "If-None-Match", "*")
+	is.Equal(http.StatusNotModified, w.Code)
+
+	w = do(h, http.MethodGet, "/", "If-None-Match", `"v0"`)
+	is.Equal(http.StatusOK, w.Code)
+	is.Equal("hello", w.Body.String())
+}
+
+func TestMiddleware_StaleWhileRevalidate(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var calls atomic.Int32
+	h := newTestHandler(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=0, stale-while-revalidate=60")
+		fmt.Fprintf(w, "%d", calls.Add(1))
+	})
+
+	is.Equal("1", do(h, http.MethodGet, "/").Body.String())
+
+	time.Sleep(5 * time.Millisecond)
+	w := do(h, http.MethodGet, "/")
+	is.Equal("1", w.Body.String())
+	is.Equal("STALE", w.Header().Get(CacheStatusHeader))
+
+	is.Eventually(func() bool {
+		return calls.Load() == 2
+	}, time.Second, 5*time.Millisecond)
+	is.Eventually(func() bool {
+		return do(h, http.MethodGet, "/", "Cache-Control", "max-age=60").Body.String() == "2"
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestMiddleware_StaleIfError(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var failing atomic.Bool
+	h := newTestHandler(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Cache-Control", "max-age=0, stale-if-error=60")
+		fmt.Fprint(w, "hello")
+	})
+
+	is.Equal("hello", do(h, http.MethodGet, "/").Body.String())
+
+	failing.Store(true)
+	time.Sleep(5 * time.Millisecond)
+	w := do(h, http.MethodGet, "/")
+	is.Equal(http.StatusOK, w.Code)
+	is.Equal("hello", w.Body.String())
+	is.Equal("STALE", w.Header().Get(CacheStatusHeader))
+
+	w = do(h, http.MethodGet, "/other")
+	is.Equal(http.StatusBadGateway, w.Code)
+}
+
+func TestMiddleware_Authorization(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	for cacheControl, shared := range map[string]bool{
+		"":                            false,
+		"max-age=60":                  false,
+		"public, max-age=60":          true,
+		"s-maxage=60":                 true,
+		"must-revalidate, max-age=60": true,
+	} {
+		var calls atomic.Int32
+		h := newTestHandler(t, func(cfg MiddlewareConfig) MiddlewareConfig {
+			return cfg.WithDefaultTTL(time.Minute)
+		}, func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			if cacheControl != "" {
+				w.Header().Set("Cache-Control", cacheControl)
+			}
+			fmt.Fprint(w, r.Header.Get("Authorization"))
+		})
+
+		is.Equal("alice", do(h, http.MethodGet, "/", "Authorization", "alice").Body.String(), cacheControl)
+		w := do(h, http.MethodGet, "/", "Authorization", "bob")
+		if shared {
+			is.Equal("alice", w.Body.String(), cacheControl)
+			is.Equal("HIT", w.Header().Get(CacheStatusHeader), cacheControl)
+			is.Equal(int32(1), calls.Load(), cacheControl)
+		} else {
+			is.Equal("bob", w.Body.String(), cacheControl)
+			is.Equal("MISS", w.Header().Get(CacheStatusHeader), cacheControl)
+			is.Equal(int32(2), calls.Load(), cacheControl)
+		}
+	}
+}
+
+func TestMiddleware_SetCookie(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var calls atomic.Int32
+	h := newTestHandler(t, func(cfg MiddlewareConfig) MiddlewareConfig {
+		return cfg.WithDefaultTTL(time.Minute)
+	}, func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		w.Header().Set("Set-Cookie", fmt.Sprintf("session=%d", n))
+		fmt.Fprint(w, "hello")
+	})
+
+	// the session cookie of a user is never replayed to another one
+	is.Equal("session=1", do(h, http.MethodGet, "/").Header().Get("Set-Cookie"))
+	w := do(h, http.MethodGet, "/")
+	is.Equal("session=2", w.Header().Get("Set-Cookie"))
+	is.Equal("MISS", w.Header().Get(CacheStatusHeader))
+}
+
+func TestMiddleware_MustRevalidate(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	h := newTestHandler(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60, must-revalidate, stale-while-revalidate=60, stale-if-error=60")
+		fmt.Fprint(w, "hello")
+	})
+
+	do(h, http.MethodGet, "/")
+	w := do(h, http.MethodGet, "/")
+	is.Equal("HIT", w.Header().Get(CacheStatusHeader))
+
+	// stale responses are never served
+	res, found, _ := h.(*handler).cfg.cache.Get(DefaultKey(httptest.NewRequest(http.MethodGet, "/", nil)))
+	is.True(found)
+	is.Zero(res.StaleWhileRevalidate)
+	is.Zero(res.StaleIfError)
+}
+
+func TestMiddleware_Singleflight(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	h := newTestHandler(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Header().Set("Cache-Control", "max-age=60")
+		fmt.Fprint(w, "hello")
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			is.Equal("hello", do(h, http.MethodGet, "/").Body.String())
+		}()
+	}
+
+	is.Eventually(func() bool {
+		return calls.Load() == 1
+	}, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	is.LessOrEqual(calls.Load(), int32(2))
+}
+
+func TestMiddleware_SingleflightNotStored(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	// without Cache-Control, authorized responses are not stored, even with a default TTL
+	for _, cacheControl := range []string{"private", "no-store", "no-cache", ""} {
+		var calls atomic.Int32
+		release := make(chan struct{})
+		h := newTestHandler(t, func(cfg MiddlewareConfig) MiddlewareConfig {
+			return cfg.WithDefaultTTL(time.Minute)
+		}, func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			<-release
+			if cacheControl != "" {
+				w.Header().Set("Cache-Control", cacheControl)
+			}
+			fmt.Fprint(w, r.Header.Get("Authorization"))
+		})
+
+		var wg sync.WaitGroup
+		for _, user := range []string{"alice", "bob", "carol"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				// responses that are not stored are never shared
+				is.Equal(user, do(h, http.MethodGet, "/", "Authorization", user).Body.String())
+			}()
+		}
+
+		is.Eventually(func() bool {
+			return calls.Load() >= 1
+		}, time.Second, time.Millisecond)
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		is.Equal(int32(3), calls.Load(), cacheControl)
+	}
+}
+
+func TestMiddleware_SingleflightVaryMismatch(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	h := newTestHandler(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Vary", "Accept-Language")
+		fmt.Fprint(w, r.Header.Get("Accept-Language"))
+	})
+
+	var wg sync.WaitGroup
+	for _, lang := range []string{"en", "fr", "fr"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			is.Equal(lang, do(h, http.MethodGet, "/", "Accept-Language", lang).Body.String())
+		}()
+	}
+
+	is.Eventually(func() bool {
+		return calls.Load() >= 1
+	}, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// the first response is only shared with the requests matching its Vary headers
+	is.GreaterOrEqual(calls.Load(), int32(2))
+}
+
+func TestMiddleware_SingleflightDetachedContext(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	h := newTestHandler(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		if r.Context().Err() != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Cache-Control", "max-age=60")
+		fmt.Fprint(w, "hello")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan *httptest.ResponseRecorder)
+	go func() {
+		req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		first <- w
+	}()
+	<-started
+
+	second := make(chan *httptest.ResponseRecorder)
+	go func() {
+		second <- do(h, http.MethodGet, "/")
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	// the first client goes away: the shared call completes for the other one
+	cancel()
+	close(release)
+	is.Equal("hello", (<-second).Body.String())
+	is.Equal("hello", (<-first).Body.String())
+}
@@ -0,0 +1,157 @@
+package httpcache
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Response is a cached HTTP response.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// StoredAt is the time the response was generated.
+	StoredAt time.Time
+	// Fresh is the freshness lifetime of the response.
+	Fresh time.Duration
+	// StaleWhileRevalidate is the time after Fresh during which the response is served while being refreshed.
+	StaleWhileRevalidate time.Duration
+	// StaleIfError is the time after Fresh during which the response is served when the handler fails.
+	StaleIfError time.Duration
+	// Vary holds the request headers listed in the Vary response header, with their values.
+	Vary map[string]string
+}
+
+// age returns the time elapsed since the response was generated.
+func (r *Response) age(now time.Time) time.Duration {
+	return now.Sub(r.StoredAt)
+}
+
+// matches checks that the request carries the same values as the request that generated the response,
+// for every header listed in Vary.
+func (r *Response) matches(req *http.Request) bool {
+	for name, value := range r.Vary {
+		if strings.Join(req.Header.Values(name), ", ") != value {
+			return false
+		}
+	}
+	return true
+}
+
+// varyValues returns the values of the request headers listed in the Vary response header.
+// It returns false for "Vary: *", which cannot be cached.
+func varyValues(req *http.Request, header http.Header) (map[string]string, bool) {
+	vary := map[string]string{}
+	for _, line := range header.Values("Vary") {
+		for _, name := range strings.Split(line, ",") {
+			name = strings.TrimSpace(name)
+			if name == "*" {
+				return nil, false
+			}
+			if name != "" {
+				name = textproto.CanonicalMIMEHeaderKey(name)
+				vary[name] = strings.Join(req.Header.Values(name), ", ")
+			}
+		}
+	}
+	return vary, true
+}
+
+// httpResponse builds a client response from a cached response.
+func (r *Response) httpResponse(req *http.Request, now time.Time, status string) *http.Response {
+	header := r.Header.Clone()
+	header.Set(CacheStatusHeader, status)
+	if status != "MISS" {
+		header.Set("Age", strconv.Itoa(int(r.age(now).Seconds())))
+	}
+
+	body := r.Body
+	if req.Method == http.MethodHead {
+		body = nil
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// notModified checks the If-None-Match request header against the ETag of the response.
+func (r *Response) notModified(req *http.Request) bool {
+	etag := r.Header.Get("ETag")
+	ifNoneMatch := req.Header.Get("If-None-Match")
+	if etag == "" || ifNoneMatch == "" {
+		return false
+	}
+
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+
+	// Weak comparison, as required for If-None-Match.
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// recorder captures the response of the wrapped handler.
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+var _ http.ResponseWriter = (*recorder)(nil)
+
+func newRecorder() *recorder {
+	return &recorder{header: http.Header{}}
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+func (r *recorder) response(now time.Time) *Response {
+	status := r.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	return &Response{
+		StatusCode: status,
+		Header:     r.header,
+		Body:       r.body.Bytes(),
+		StoredAt:   now,
+	}
+}
@@ -0,0 +1,299 @@
+package httpcache
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/samber/go-singleflightx"
+	"github.com/samber/hot"
+)
+
+const (
+	// defaultMaxBodySize is the size above which response bodies are not cached.
+	defaultMaxBodySize = 1 << 20
+	// defaultStaleWindow is how long responses with validators are kept after expiring, to be revalidated.
+	defaultStaleWindow = time.Hour
+)
+
+// DefaultClientKey derives the cache key of an outgoing request from the method and the full URL.
+// Query parameters are sorted, so that their order does not change the key.
+func DefaultClientKey(r *http.Request) string {
+	u := *r.URL
+	u.RawQuery = r.URL.Query().Encode()
+	u.Fragment = ""
+	return r.Method + " " + u.String()
+}
+
+// NewTransport creates an http.RoundTripper caching responses of GET and HEAD requests, following
+// RFC 9111 semantics for a private cache. Entries are stored with a TTL derived from the response headers.
+func NewTransport(cache *hot.HotCache[string, *Response]) TransportConfig {
+	return TransportConfig{
+		cache:       cache,
+		transport:   http.DefaultTransport,
+		keyFn:       DefaultClientKey,
+		maxBodySize: defaultMaxBodySize,
+		staleWindow: defaultStaleWindow,
+	}
+}
+
+// TransportConfig holds the configuration of the transport.
+type TransportConfig struct {
+	cache       *hot.HotCache[string, *Response]
+	transport   http.RoundTripper
+	keyFn       KeyFunc
+	maxBodySize int64
+	staleWindow time.Duration
+}
+
+// WithTransport sets the transport sending the requests. Default is http.DefaultTransport.
+func (cfg TransportConfig) WithTransport(transport http.RoundTripper) TransportConfig {
+	cfg.transport = transport
+	return cfg
+}
+
+// WithKeyFunc sets the function deriving the cache key of a request. Default is DefaultClientKey.
+func (cfg TransportConfig) WithKeyFunc(keyFn KeyFunc) TransportConfig {
+	cfg.keyFn = keyFn
+	return cfg
+}
+
+// WithMaxBodySize sets the size above which response bodies are not cached. Default is 1MiB.
+func (cfg TransportConfig) WithMaxBodySize(size int64) TransportConfig {
+	cfg.maxBodySize = size
+	return cfg
+}
+
+// WithStaleWindow sets how long responses having an ETag or a Last-Modified header are kept after
+// expiring. During this window, they are revalidated with a conditional request. Default is 1 hour.
+func (cfg TransportConfig) WithStaleWindow(window time.Duration) TransportConfig {
+	cfg.staleWindow = window
+	return cfg
+}
+
+// Build returns the transport.
+func (cfg TransportConfig) Build() *Transport {
+	if cfg.cache == nil {
+		panic("cache is required")
+	}
+	if cfg.transport == nil {
+		panic("transport is required")
+	}
+	if cfg.keyFn == nil {
+		panic("key function is required")
+	}
+	if cfg.maxBodySize <= 0 {
+		panic("max body size must be a positive value")
+	}
+	if cfg.staleWindow < 0 {
+		panic("stale window must be a positive value")
+	}
+
+	return &Transport{cfg: cfg}
+}
+
+// Transport is a caching http.RoundTripper.
+// Concurrent requests with the same key are collapsed into a single outgoing request, whose response
+// is shared when it was stored in the cache and matches the Vary headers of every collapsed request.
+type Transport struct {
+	cfg   TransportConfig
+	group singleflightx.Group[string, *roundTrip]
+}
+
+var _ http.RoundTripper = (*Transport)(nil)
+
+// roundTrip is the result of an outgoing request, shared by the collapsed callers when stored.
+type roundTrip struct {
+	res    *Response      // Buffered response, nil when the body is too large to be buffered
+	stream *http.Response // Response streamed to the caller, when the body is too large to be buffered
+	stored bool           // The response is stored in the cache
+	status string
+	err    error
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return t.cfg.transport.RoundTrip(req)
+	}
+
+	// Requests with their own validators are sent as is: the caller manages its own cache.
+	reqCC := parseCacheControl(req.Header)
+	if reqCC.noStore || req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		return t.cfg.transport.RoundTrip(req)
+	}
+
+	key := t.cfg.keyFn(req)
+	now := time.Now()
+
+	cached, found, _ := t.cfg.cache.Get(key)
+	if found && !cached.matches(req) {
+		found = false
+	}
+
+	if found && !reqCC.noCache && (reqCC.maxAge < 0 || cached.age(now) <= reqCC.maxAge) && cached.age(now) <= cached.Fresh {
+		return cached.httpResponse(req, now, "HIT"), nil
+	}
+
+	if !found {
+		cached = nil
+	}
+
+	// The shared request does not depend on the cancellation of the caller that sent it.
+	leader := false
+	result, _, _ := t.group.Do(key, func() (*roundTrip, error) {
+		leader = true
+		return t.fetch(context.WithoutCancel(req.Context()), key, req, cached), nil
+	})
+
+	// Responses that could not have been served from the cache, such as errors, responses
+	// not stored or not matching the Vary headers, are not shared: the request is sent again.
+	if !leader && (!result.stored || !result.res.matches(req)) {
+		result = t.fetch(req.Context(), key, req, cached)
+	}
+
+	switch {
+	case result.err != nil:
+		return nil, result.err
+	case result.res != nil:
+		return result.res.httpResponse(req, time.Now(), result.status), nil
+	default:
+		return result.stream, nil
+	}
+}
+
+// fetch sends the request, conditional when a stale response with validators is cached.
+func (t *Transport) fetch(ctx context.Context, key string, req *http.Request, cached *Response) *roundTrip {
+	out := req.Clone(ctx)
+	if cached != nil {
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			out.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := cached.Header.Get("Last-Modified"); lastModified != "" {
+			out.Header.Set("If-Modified-Since", lastModified)
+		}
+	}
+
+	resp, err := t.cfg.transport.RoundTrip(out)
+	now := time.Now()
+
+	// stale-if-error
+	if cached != nil && (err != nil || resp.StatusCode >= http.StatusInternalServerError) && cached.age(now) <= cached.Fresh+cached.StaleIfError {
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		return &roundTrip{res: cached, stored: true, status: "STALE"}
+	}
+	if err != nil {
+		return &roundTrip{err: err}
+	}
+
+	// Revalidated: the cached response is fresh again.
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		_ = resp.Body.Close()
+
+		refreshed := *cached
+		refreshed.Header = cached.Header.Clone()
+		for _, name := range []string{"Cache-Control", "Date", "ETag", "Expires", "Last-Modified", "Vary"} {
+			if values := resp.Header.Values(name); len(values) > 0 {
+				refreshed.Header[name] = values
+			}
+		}
+		refreshed.StoredAt = now
+		stored := t.store(key, req, &refreshed)
+
+		return &roundTrip{res: &refreshed, stored: stored, status: "REVALIDATED"}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, t.cfg.maxBodySize+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return &roundTrip{err: err}
+	}
+
+	if int64(len(body)) > t.cfg.maxBodySize {
+		// Too large to be cached: the body is streamed to a single caller.
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return &roundTrip{stream: resp}
+	}
+	_ = resp.Body.Close()
+
+	res := &Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+		StoredAt:   now,
+	}
+	stored := t.store(key, req, res)
+
+	return &roundTrip{res: res, stored: stored, status: "MISS"}
+}
+
+// store computes the freshness of a response and caches it, if allowed.
+// It returns true if the response was stored.
+func (t *Transport) store(key string, req *http.Request, res *Response) bool {
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusNotFound && res.StatusCode != http.StatusGone &&
+		res.StatusCode != http.StatusMovedPermanently && res.StatusCode != http.StatusPermanentRedirect {
+		return false
+	}
+
+	cc := parseCacheControl(res.Header)
+	if cc.noStore {
+		return false
+	}
+
+	vary, ok := varyValues(req, res.Header)
+	if !ok {
+		return false
+	}
+	res.Vary = vary
+
+	res.Fresh = freshness(res.Header, cc, res.StoredAt)
+	res.StaleIfError = max(cc.staleIfError, 0)
+
+	stale := res.StaleIfError
+	if res.Header.Get("ETag") != "" || res.Header.Get("Last-Modified") != "" {
+		stale = max(stale, t.cfg.staleWindow)
+	}
+
+	ttl := res.Fresh + stale
+	if ttl <= 0 {
+		return false
+	}
+
+	t.cfg.cache.SetWithTTL(key, res, ttl)
+	return true
+}
+
+// freshness computes the freshness lifetime of a response for a private cache:
+// max-age first, then Expires relative to Date. Responses with no-cache must always be revalidated.
+func freshness(header http.Header, cc cacheControl, now time.Time) time.Duration {
+	if cc.noCache {
+		return 0
+	}
+	if cc.maxAge >= 0 {
+		return cc.maxAge
+	}
+
+	expires := header.Get("Expires")
+	if expires == "" {
+		return 0
+	}
+	expiresAt, err := http.ParseTime(expires)
+	if err != nil {
+		// Invalid dates mean "already expired".
+		return 0
+	}
+
+	date := now
+	if d, err := http.ParseTime(header.Get("Date")); err == nil {
+		date = d
+	}
+
+	return max(expiresAt.Sub(date), 0)
+}
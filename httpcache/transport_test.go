@@ -0,0 +1,412 @@
+package httpcache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samber/hot"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestClient(t *testing.T, cfg func(TransportConfig) TransportConfig, handler http.HandlerFunc) (*http.Client, *httptest.Server) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	config := NewTransport(hot.NewHotCache[string, *Response](hot.LRU, 100).Build()).
+		WithTransport(server.Client().Transport)
+	if cfg != nil {
+		config = cfg(config)
+	}
+
+	return &http.Client{Transport: config.Build()}, server
+}
+
+func get(t *testing.T, client *http.Client, url string, headers ...string) (*http.Response, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resp, string(body)
+}
+
+func TestTransport_Freshness(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var calls atomic.Int32
+	client, server := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		switch r.URL.Path {
+		case "/max-age":
+			w.Header().Set("Cache-Control", "private, max-age=60")
+		case "/expires":
+			w.Header().Set("Date", time.Now().UTC().Format(http.TimeFormat))
+			w.Header().Set("Expires", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+		case "/expired":
+			w.Header().Set("Expires", "0")
+		case "/no-store":
+			w.Header().Set("Cache-Control", "no-store, max-age=60")
+		}
+		fmt.Fprintf(w, "%d", n)
+	})
+
+	testCases := []struct {
+		path   string
+		cached bool
+	}{
+		{path: "/max-age?b=2&a=1", cached: true},
+		{path: "/expires", cached: true},
+		{path: "/expired", cached: false},
+		{path: "/no-store", cached: false},
+		{path: "/none", cached: false},
+	}
+
+	for _, tc := range testCases {
+		first, firstBody := get(t, client, server.URL+tc.path)
+		second, secondBody := get(t, client, server.URL+tc.path)
+		is.Equal("MISS", first.Header.Get(CacheStatusHeader), tc.path)
+		is.Equal(tc.cached, firstBody == secondBody, tc.path)
+		if tc.cached {
+			is.Equal("HIT", second.Header.Get(CacheStatusHeader), tc.path)
+			is.Equal(http.StatusOK, second.StatusCode)
+		}
+	}
+
+	// query parameters order does not matter
+	_, body := get(t, client, server.URL+"/max-age?a=1&b=2")
+	is.Equal("1", body)
+
+	// request directives
+	_, body = get(t, client, server.URL+"/max-age?a=1&b=2", "Cache-Control", "no-cache")
+	is.NotEqual("1", body)
+	calls.Store(100)
+	_, body = get(t, client, server.URL+"/max-age", "Cache-Control", "no-store")
+	is.Equal("101", body)
+}
+
+func TestTransport_Revalidation(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var calls atomic.Int32
+	var conditional atomic.Int32
+	client, server := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Cache-Control", "max-age=0")
+
+		switch r.URL.Path {
+		case "/etag":
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				conditional.Add(1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/last-modified":
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+			if r.Header.Get("If-Modified-Since") != "" {
+				conditional.Add(1)
+				w.Header().Set("Cache-Control", "max-age=60")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		fmt.Fprint(w, "hello")
+	})
+
+	resp, body := get(t, client, server.URL+"/etag")
+	is.Equal("MISS", resp.Header.Get(CacheStatusHeader))
+	is.Equal("hello", body)
+
+	resp, body = get(t, client, server.URL+"/etag")
+	is.Equal("REVALIDATED", resp.Header.Get(CacheStatusHeader))
+	is.Equal(http.StatusOK, resp.StatusCode)
+	is.Equal("hello", body)
+	is.Equal(int32(1), conditional.Load())
+
+	// the caller's own validators are forwarded as is
+	resp, _ = get(t, client, server.URL+"/etag", "If-None-Match", `"v1"`)
+	is.Equal(http.StatusNotModified, resp.StatusCode)
+	is.Empty(resp.Header.Get(CacheStatusHeader))
+
+	// the freshness is updated by the 304
+	get(t, client, server.URL+"/last-modified")
+	get(t, client, server.URL+"/last-modified")
+	resp, body = get(t, client, server.URL+"/last-modified")
+	is.Equal("HIT", resp.Header.Get(CacheStatusHeader))
+	is.Equal("hello", body)
+	is.Equal(int32(5), calls.Load())
+}
+
+func TestTransport_Vary(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var calls atomic.Int32
+	client, server := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Vary", "Accept-Language")
+		fmt.Fprintf(w, "%d %s", calls.Add(1), r.Header.Get("Accept-Language"))
+	})
+
+	_, body := get(t, client, server.URL, "Accept-Language", "fr")
+	is.Equal("1 fr", body)
+	_, body = get(t, client, server.URL, "Accept-Language", "fr")
+	is.Equal("1 fr", body)
+	_, body = get(t, client, server.URL, "Accept-Language", "en")
+	is.Equal("2 en", body)
+}
+
+func TestTransport_StaleIfError(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var failing atomic.Bool
+	client, server := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Cache-Control", "max-age=0, stale-if-error=60")
+		fmt.Fprint(w, "hello")
+	})
+
+	get(t, client, server.URL)
+	failing.Store(true)
+
+	resp, body := get(t, client, server.URL)
+	is.Equal("STALE", resp.Header.Get(CacheStatusHeader))
+	is.Equal("hello", body)
+
+	resp, _ = get(t, client, server.URL+"/other")
+	is.Equal(http.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func TestTransport_MaxBodySize(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var calls atomic.Int32
+	client, server := newTestClient(t,
+		func(cfg TransportConfig) TransportConfig {
+			return cfg.WithMaxBodySize(10)
+		},
+		func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.Header().Set("Cache-Control", "max-age=60")
+			fmt.Fprint(w, strings.Repeat("x", 20))
+		},
+	)
+
+	_, body := get(t, client, server.URL)
+	is.Equal(strings.Repeat("x", 20), body)
+	_, body = get(t, client, server.URL)
+	is.Equal(strings.Repeat("x", 20), body)
+	is.Equal(int32(2), calls.Load())
+}
+
+func TestTransport_Singleflight(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	client, server := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Header().Set("Cache-Control", "max-age=60")
+		fmt.Fprint(w, "hello")
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, body := get(t, client, server.URL)
+			is.Equal("hello", body)
+		}()
+	}
+
+	is.Eventually(func() bool {
+		return calls.Load() == 1
+	}, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	is.LessOrEqual(calls.Load(), int32(2))
+}
+
+func TestTransport_SingleflightNotStored(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	for _, cacheControl := range []string{"no-store", "no-cache", ""} {
+		var calls atomic.Int32
+		release := make(chan struct{})
+		client, server := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			<-release
+			if cacheControl != "" {
+				w.Header().Set("Cache-Control", cacheControl)
+			}
+			fmt.Fprint(w, r.Header.Get("Authorization"))
+		})
+
+		var wg sync.WaitGroup
+		for _, user := range []string{"alice", "bob", "carol"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				// responses that are not stored are never shared
+				_, body := get(t, client, server.URL, "Authorization", user)
+				is.Equal(user, body)
+			}()
+		}
+
+		is.Eventually(func() bool {
+			return calls.Load() >= 1
+		}, time.Second, time.Millisecond)
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		is.Equal(int32(3), calls.Load(), cacheControl)
+	}
+}
+
+func TestTransport_SingleflightVaryMismatch(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	client, server := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Vary", "Accept-Language")
+		fmt.Fprint(w, r.Header.Get("Accept-Language"))
+	})
+
+	var wg sync.WaitGroup
+	for _, lang := range []string{"en", "fr", "fr"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, body := get(t, client, server.URL, "Accept-Language", lang)
+			is.Equal(lang, body)
+		}()
+	}
+
+	is.Eventually(func() bool {
+		return calls.Load() >= 1
+	}, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// the first response is only shared with the requests matching its Vary headers
+	is.GreaterOrEqual(calls.Load(), int32(2))
+}
+
+func TestTransport_SingleflightDetachedContext(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	client, server := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Header().Set("Cache-Control", "max-age=60")
+		fmt.Fprint(w, "hello")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		resp, err := client.Do(req)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		first <- err
+	}()
+	is.Eventually(func() bool {
+		return calls.Load() == 1
+	}, time.Second, time.Millisecond)
+
+	second := make(chan string)
+	go func() {
+		_, body := get(t, client, server.URL)
+		second <- body
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	// the first caller goes away: the shared request completes for the other one
+	cancel()
+	close(release)
+	is.Equal("hello", <-second)
+	<-first
+	is.Equal(int32(1), calls.Load())
+}
+
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("boom")
+}
+
+func TestTransport_Errors(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := hot.NewHotCache[string, *Response](hot.LRU, 100).Build()
+	client := &http.Client{Transport: NewTransport(cache).WithTransport(failingTransport{}).Build()}
+
+	_, err := client.Get("http://example.com/") //nolint:noctx
+	is.ErrorContains(err, "boom")
+
+	_, err = client.Post("http://example.com/", "text/plain", nil) //nolint:noctx
+	is.ErrorContains(err, "boom")
+
+	is.Panics(func() {
+		_ = NewTransport(nil).Build()
+	})
+	is.Panics(func() {
+		_ = NewTransport(cache).WithMaxBodySize(0).Build()
+	})
+	is.Panics(func() {
+		_ = NewTransport(cache).WithStaleWindow(-1).Build()
+	})
+}
@@ -0,0 +1,68 @@
+package mrc
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// Hash returns a 64-bit hash of the key, used to sample keys.
+// String and integer keys are hashed without allocating; other keys are formatted with fmt.
+func Hash[K comparable](key K) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return hashString(k)
+	case int:
+		return mix64(uint64(k))
+	case int8:
+		return mix64(uint64(k))
+	case int16:
+		return mix64(uint64(k))
+	case int32:
+		return mix64(uint64(k))
+	case int64:
+		return mix64(uint64(k))
+	case uint:
+		return mix64(uint64(k))
+	case uint8:
+		return mix64(uint64(k))
+	case uint16:
+		return mix64(uint64(k))
+	case uint32:
+		return mix64(uint64(k))
+	case uint64:
+		return mix64(k)
+	case uintptr:
+		return mix64(uint64(k))
+	default:
+		h := fnv.New64a()
+		_, _ = fmt.Fprintf(h, "%v", key)
+		return mix64(h.Sum64())
+	}
+}
+
+// hashString is FNV-1a, without the allocation of hash/fnv.
+func hashString(s string) uint64 {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+
+	h := uint64(offset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= prime64
+	}
+
+	return mix64(h)
+}
+
+// mix64 is a step of SplitMix64. It spreads consecutive integers, including 0, over the whole range.
+func mix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
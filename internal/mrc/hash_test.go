@@ -0,0 +1,22 @@
+package mrc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHash(t *testing.T) {
+	is := assert.New(t)
+
+	is.Equal(Hash("a"), Hash("a"))
+	is.NotEqual(Hash("a"), Hash("b"))
+	is.NotEqual(Hash(1), Hash(2))
+	is.Equal(Hash(struct{ A int }{1}), Hash(struct{ A int }{1}))
+
+	is.Zero(testing.AllocsPerRun(100, func() {
+		_ = Hash("some-key")
+		_ = Hash(42)
+		_ = Hash(uint32(42))
+	}))
+}
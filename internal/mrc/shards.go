@@ -0,0 +1,243 @@
+// Package mrc estimates the miss-ratio curve of an LRU cache from a stream of accesses.
+package mrc
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// Modulus is the range of the spatial hash used for sampling.
+const Modulus = 1 << 24
+
+// SHARDS estimates the LRU miss-ratio curve with Spatially Hashed Approximate Reuse Distance Sampling
+// (Waldspurger et al., "Efficient MRC Construction with SHARDS", FAST'15).
+//
+// A key is sampled when hash(key) % Modulus < threshold, so that every access of a sampled key is seen.
+// The reuse distance of an access is the number of distinct sampled keys accessed since the previous
+// access to the same key, scaled by 1/rate. An LRU cache of capacity c hits when the distance is below c.
+//
+// Memory is bounded by maxKeys (fixed-size SHARDS): when more keys are sampled, the key with the highest
+// hash is dropped and the threshold is lowered to its hash, which lowers the sampling rate.
+//
+// When a few hot keys get sampled, or not, the sampled accesses are far from rate × accesses.
+// As in SHARDS_adj, the difference is added to the first bucket of the histogram.
+// Accesses of keys that are not sampled must then be counted too, with Access or AddUnsampled.
+// SHARDS is not safe for concurrent use.
+type SHARDS[K comparable] struct {
+	threshold uint64
+	maxKeys   int
+
+	keys   map[K]*sampledKey[K]
+	byHash sampledKeyHeap[K] // Max-heap on hash
+	tree   fenwick           // Count of live keys by time of last access
+	clock  int               // Time of the last access, in sampled accesses
+
+	// Histogram of the scaled distances, in buckets of bucketWidth keys.
+	// Accesses at a larger distance, and first accesses, are only counted in total.
+	buckets     []float64
+	bucketWidth float64
+	total       float64 // Sampled accesses
+	expected    float64 // Accesses × rate
+}
+
+type sampledKey[K comparable] struct {
+	key        K
+	hash       uint64
+	lastAccess int // Position in the tree
+}
+
+// NewSHARDS creates an estimator sampling `rate` of the keys (0 < rate <= 1), tracking at most `maxKeys` keys.
+// The curve is computed up to `maxSize` keys, with a resolution of maxSize/buckets keys.
+func NewSHARDS[K comparable](rate float64, maxKeys int, maxSize int, buckets int) *SHARDS[K] {
+	if rate <= 0 || rate > 1 {
+		panic("sampling rate must be in (0, 1]")
+	}
+	if maxKeys <= 0 {
+		panic("max sampled keys must be greater than 0")
+	}
+	if maxSize <= 0 || buckets <= 0 {
+		panic("curve size and buckets must be greater than 0")
+	}
+
+	return &SHARDS[K]{
+		threshold:   max(uint64(rate*Modulus), 1),
+		maxKeys:     maxKeys,
+		keys:        make(map[K]*sampledKey[K], maxKeys+1),
+		byHash:      make(sampledKeyHeap[K], 0, maxKeys+1),
+		tree:        newFenwick(2*maxKeys + 1),
+		buckets:     make([]float64, buckets),
+		bucketWidth: float64(maxSize) / float64(buckets),
+	}
+}
+
+// Sampled returns true when the accesses of a key with this hash are recorded.
+func (s *SHARDS[K]) Sampled(hash uint64) bool {
+	return hash%Modulus < s.threshold
+}
+
+// Threshold returns the current sampling threshold, for lock-free sampling decisions.
+// A key is sampled when hash % Modulus < Threshold.
+func (s *SHARDS[K]) Threshold() uint64 {
+	return s.threshold
+}
+
+// Rate returns the current sampling rate.
+func (s *SHARDS[K]) Rate() float64 {
+	return float64(s.threshold) / Modulus
+}
+
+// AddUnsampled counts accesses of keys that are not sampled, when the caller filters them with Threshold.
+func (s *SHARDS[K]) AddUnsampled(count int64) {
+	s.expected += float64(count) * s.Rate()
+}
+
+// Access records an access of a key. Accesses of keys that are not sampled are only counted.
+func (s *SHARDS[K]) Access(key K, hash uint64) {
+	s.expected += s.Rate()
+	if !s.Sampled(hash) {
+		return
+	}
+
+	if s.clock+1 >= s.tree.len() {
+		s.compact()
+	}
+	s.clock++
+
+	scale := 1 / s.Rate()
+	s.total++
+
+	if k, ok := s.keys[key]; ok {
+		// Keys accessed after the previous access of this key.
+		distance := len(s.keys) - s.tree.sum(k.lastAccess)
+		bucket := int(float64(distance) * scale / s.bucketWidth)
+		if bucket < len(s.buckets) {
+			s.buckets[bucket]++
+		}
+
+		s.tree.add(k.lastAccess, -1)
+		k.lastAccess = s.clock
+		s.tree.add(k.lastAccess, 1)
+		return
+	}
+
+	k := &sampledKey[K]{key: key, hash: hash % Modulus, lastAccess: s.clock}
+	s.keys[key] = k
+	heap.Push(&s.byHash, k)
+	s.tree.add(k.lastAccess, 1)
+
+	for len(s.keys) > s.maxKeys {
+		s.lowerThreshold()
+	}
+}
+
+// lowerThreshold drops the sampled keys with the highest hash, and lowers the sampling rate accordingly.
+// The histogram is rescaled, as if it had been built with the new rate.
+func (s *SHARDS[K]) lowerThreshold() {
+	oldRate := s.Rate()
+	s.threshold = s.byHash[0].hash
+
+	for len(s.byHash) > 0 && s.byHash[0].hash >= s.threshold {
+		k := heap.Pop(&s.byHash).(*sampledKey[K])
+		delete(s.keys, k.key)
+		s.tree.add(k.lastAccess, -1)
+	}
+
+	ratio := s.Rate() / oldRate
+	for i := range s.buckets {
+		s.buckets[i] *= ratio
+	}
+	s.total *= ratio
+	s.expected *= ratio
+}
+
+// compact renumbers the last accesses from 1, in order, when the clock reaches the end of the tree.
+func (s *SHARDS[K]) compact() {
+	keys := make([]*sampledKey[K], 0, len(s.keys))
+	for _, k := range s.keys {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].lastAccess < keys[j].lastAccess
+	})
+
+	s.tree.reset()
+	for i, k := range keys {
+		k.lastAccess = i + 1
+		s.tree.add(k.lastAccess, 1)
+	}
+	s.clock = len(keys)
+}
+
+// HitRatio returns the estimated hit ratio of an LRU cache of `size` keys.
+// Sizes are rounded down to the resolution of the curve. Returns 0 before any sampled access.
+func (s *SHARDS[K]) HitRatio(size int) float64 {
+	if s.total == 0 || s.expected == 0 || float64(size) < s.bucketWidth {
+		return 0
+	}
+
+	// SHARDS_adj: the first bucket absorbs the difference between the expected and the sampled accesses.
+	hits := s.expected - s.total
+	for i, count := range s.buckets {
+		if float64(i+1)*s.bucketWidth > float64(size) {
+			break
+		}
+		hits += count
+	}
+
+	return min(max(hits/s.expected, 0), 1)
+}
+
+// Len returns the number of sampled keys.
+func (s *SHARDS[K]) Len() int {
+	return len(s.keys)
+}
+
+// fenwick is a binary indexed tree of counts, indexed from 1.
+type fenwick []int32
+
+func newFenwick(size int) fenwick {
+	return make(fenwick, size)
+}
+
+func (f fenwick) len() int {
+	return len(f)
+}
+
+func (f fenwick) add(i int, delta int32) {
+	for ; i < len(f); i += i & -i {
+		f[i] += delta
+	}
+}
+
+// sum returns the sum of the counts from 1 to i.
+func (f fenwick) sum(i int) int {
+	total := 0
+	for ; i > 0; i -= i & -i {
+		total += int(f[i])
+	}
+	return total
+}
+
+func (f fenwick) reset() {
+	clear(f)
+}
+
+// sampledKeyHeap implements heap.Interface.
+type sampledKeyHeap[K comparable] []*sampledKey[K]
+
+func (h sampledKeyHeap[K]) Len() int           { return len(h) }
+func (h sampledKeyHeap[K]) Less(i, j int) bool { return h[i].hash > h[j].hash }
+func (h sampledKeyHeap[K]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *sampledKeyHeap[K]) Push(x any) {
+	*h = append(*h, x.(*sampledKey[K]))
+}
+
+func (h *sampledKeyHeap[K]) Pop() any {
+	old := *h
+	n := len(old)
+	k := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return k
+}
@@ -0,0 +1,134 @@
+package mrc
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/samber/hot/pkg/lru"
+	"github.com/stretchr/testify/assert"
+)
+
+// lruHitRatio replays the trace in an LRU cache of the given capacity.
+func lruHitRatio(trace []int, capacity int) float64 {
+	cache := lru.NewLRUCache[int, struct{}](capacity)
+	hits := 0
+	for _, key := range trace {
+		if _, ok := cache.Get(key); ok {
+			hits++
+		} else {
+			cache.Set(key, struct{}{})
+		}
+	}
+	return float64(hits) / float64(len(trace))
+}
+
+func zipfTrace(seed int64, keys uint64, length int) []int {
+	zipf := rand.NewZipf(rand.New(rand.NewSource(seed)), 1.1, 1, keys-1)
+	trace := make([]int, length)
+	for i := range trace {
+		trace[i] = int(zipf.Uint64())
+	}
+	return trace
+}
+
+func TestSHARDS_exact(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	// Without sampling, the curve is exact at the bucket boundaries.
+	trace := zipfTrace(42, 10_000, 50_000)
+	shards := NewSHARDS[int](1, 20_000, 4_000, 64)
+	for _, key := range trace {
+		shards.Access(key, Hash(key))
+	}
+
+	is.InDelta(1.0, shards.Rate(), 0)
+	for _, size := range []int{250, 500, 1_000, 2_000, 4_000} {
+		is.InDelta(lruHitRatio(trace, size), shards.HitRatio(size), 1e-9, "size %d", size)
+	}
+	is.InDelta(0.0, shards.HitRatio(0), 0)
+}
+
+func TestSHARDS_sampled(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	trace := zipfTrace(7, 100_000, 500_000)
+	shards := NewSHARDS[int](0.05, 100_000, 8_000, 64)
+	for _, key := range trace {
+		shards.Access(key, Hash(key))
+	}
+
+	is.Less(shards.Len(), 10_000)
+	for _, size := range []int{500, 1_000, 2_000, 4_000, 8_000} {
+		is.InDelta(lruHitRatio(trace, size), shards.HitRatio(size), 0.03, "size %d", size)
+	}
+}
+
+func TestSHARDS_fixedSize(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	trace := zipfTrace(13, 100_000, 500_000)
+	shards := NewSHARDS[int](1, 2_000, 8_000, 64)
+	for _, key := range trace {
+		shards.Access(key, Hash(key))
+	}
+
+	// The rate is lowered to keep at most maxKeys keys.
+	is.LessOrEqual(shards.Len(), 2_000)
+	is.Less(shards.Rate(), 0.1)
+	is.Equal(shards.Threshold(), uint64(shards.Rate()*Modulus))
+	for _, size := range []int{1_000, 4_000, 8_000} {
+		is.InDelta(lruHitRatio(trace, size), shards.HitRatio(size), 0.05, "size %d", size)
+	}
+
+	// Keys above the threshold are ignored.
+	length := shards.Len()
+	for i := 0; i < 1_000; i++ {
+		if h := Hash(-i - 1); !shards.Sampled(h) {
+			shards.Access(-i-1, h)
+		}
+	}
+	is.Equal(length, shards.Len())
+}
+
+func TestSHARDS_AddUnsampled(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	// Filtering keys with Threshold and counting the others with AddUnsampled
+	// is equivalent to calling Access for every key.
+	trace := zipfTrace(21, 10_000, 100_000)
+	a := NewSHARDS[int](0.1, 10_000, 2_000, 16)
+	b := NewSHARDS[int](0.1, 10_000, 2_000, 16)
+	for _, key := range trace {
+		h := Hash(key)
+		a.Access(key, h)
+		if h%Modulus < b.Threshold() {
+			b.Access(key, h)
+		} else {
+			b.AddUnsampled(1)
+		}
+	}
+
+	for _, size := range []int{500, 1_000, 2_000} {
+		is.InDelta(a.HitRatio(size), b.HitRatio(size), 1e-9)
+		is.InDelta(lruHitRatio(trace, size), b.HitRatio(size), 0.03, "size %d", size)
+	}
+}
+
+func TestNewSHARDS(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	shards := NewSHARDS[string](0.01, 100, 1_000, 10)
+	is.InDelta(0.0, shards.HitRatio(1_000), 0)
+	is.InDelta(0.01, shards.Rate(), 1e-6)
+
+	is.Panics(func() { NewSHARDS[string](0, 100, 1_000, 10) })
+	is.Panics(func() { NewSHARDS[string](1.5, 100, 1_000, 10) })
+	is.Panics(func() { NewSHARDS[string](0.1, 0, 1_000, 10) })
+	is.Panics(func() { NewSHARDS[string](0.1, 100, 0, 10) })
+	is.Panics(func() { NewSHARDS[string](0.1, 100, 1_000, 0) })
+}
@@ -0,0 +1,63 @@
+package sketch
+
+const (
+	counterBits     = 4
+	countersPerWord = 64 / counterBits
+	counterMask     = 1<<counterBits - 1
+
+	// maxCount is the saturation value of the 4-bit counters. Admission only compares
+	// frequencies, so larger counts do not matter once aged.
+	maxCount = counterMask
+
+	// halveMask clears the bit shifted in from the neighbour counter when halving a word.
+	halveMask = 0x7777777777777777
+)
+
+// counterTable holds depth rows of width 4-bit counters, packed 16 per word.
+type counterTable struct {
+	width int
+	rows  [][]uint64
+}
+
+func newCounterTable(width, depth int) counterTable {
+	rows := make([][]uint64, depth)
+	for i := range rows {
+		rows[i] = make([]uint64, (width+countersPerWord-1)/countersPerWord)
+	}
+
+	return counterTable{
+		width: width,
+		rows:  rows,
+	}
+}
+
+// get returns the counter at the given slot of a row.
+func (t counterTable) get(row int, slot uint64) int {
+	shift := (slot % countersPerWord) * counterBits
+	return int(t.rows[row][slot/countersPerWord]>>shift) & counterMask
+}
+
+// inc increments the counter at the given slot of a row, saturating at maxCount.
+func (t counterTable) inc(row int, slot uint64) {
+	shift := (slot % countersPerWord) * counterBits
+	word := &t.rows[row][slot/countersPerWord]
+	if (*word>>shift)&counterMask < counterMask {
+		*word += 1 << shift
+	}
+}
+
+// halve divides every counter by 2, a word at a time.
+func (t counterTable) halve() {
+	for _, row := range t.rows {
+		for i := range row {
+			row[i] = (row[i] >> 1) & halveMask
+		}
+	}
+}
+
+// reset sets every counter to 0.
+func (t counterTable) reset() {
+	for _, row := range t.rows {
+		clear(row)
+	}
+}
@@ -1,45 +1,39 @@
 package sketch
 
 import (
-	"fmt"
-	"hash/fnv"
-	"math"
+	"math/bits"
 )
 
 // DoorkeeperCountMinSketch extends CountMinSketch with Doorkeeper optimization.
 // The Doorkeeper is a Bloom filter that tracks singletons to avoid updating
 // the Count-Min Sketch for items seen only once, improving efficiency.
+//
+// When aging is enabled, every sampleSize increments, the counters are halved
+// and the Doorkeeper is cleared, as in the TinyLFU paper.
 type DoorkeeperCountMinSketch[K comparable] struct {
-	width    int       // Width of each hash table
-	depth    int       // Number of hash functions
-	counters [][]uint8 // 2D array of counters
-	seeds    []uint64  // Seeds for hash functions
+	width    int          // Width of each hash table
+	depth    int          // Number of hash functions
+	counters counterTable // 2D array of 4-bit counters
+	hasher   hasher[K]
+
+	sampleSize int // Increments between two agings, 0 disables aging
+	additions  int // Increments since the last aging
 
 	// Doorkeeper Bloom filter for singleton tracking
-	doorkeeper      []uint64 // Bloom filter bit array
-	doorkeeperSeeds []uint64 // Seeds for doorkeeper hash functions
-	doorkeeperSize  int      // Size of doorkeeper bloom filter
+	doorkeeper     []uint64 // Bloom filter bit array
+	doorkeeperSize int      // Size of doorkeeper bloom filter
 }
 
 // NewDoorkeeperCountMinSketch creates a new Count-Min Sketch with Doorkeeper optimization.
 // According to TinyLFU paper, this combination provides better efficiency for singleton tracking.
-func NewDoorkeeperCountMinSketch[K comparable](width, depth int) *DoorkeeperCountMinSketch[K] {
+// A positive sampleSize ages the sketch every sampleSize increments (10 × the cache capacity is a good default).
+func NewDoorkeeperCountMinSketch[K comparable](width, depth, sampleSize int) *DoorkeeperCountMinSketch[K] {
 	cms := &DoorkeeperCountMinSketch[K]{
-		width:           width,
-		depth:           depth,
-		counters:        make([][]byte, depth),
-		seeds:           make([]uint64, depth),
-		doorkeeperSeeds: make([]uint64, 4), // 4 hash functions for doorkeeper
-	}
-
-	// Initialize Count-Min Sketch counters
-	for i := range cms.counters {
-		cms.counters[i] = make([]byte, cms.width)
-	}
-
-	// Initialize Count-Min Sketch seeds
-	for i := 0; i < depth; i++ {
-		cms.seeds[i] = uint64(i * 1000)
+		width:      width,
+		depth:      depth,
+		counters:   newCounterTable(width, depth),
+		hasher:     newHasher[K](),
+		sampleSize: sampleSize,
 	}
 
 	// Initialize Doorkeeper Bloom filter
@@ -50,10 +44,6 @@ func NewDoorkeeperCountMinSketch[K comparable](width, depth int) *DoorkeeperCoun
 	}
 	cms.doorkeeper = make([]uint64, (cms.doorkeeperSize+63)/64) // Round up to 64-bit words
 
-	for i := 0; i < 4; i++ {
-		cms.doorkeeperSeeds[i] = uint64(i*2000 + 1000)
-	}
-
 	return cms
 }
 
@@ -61,55 +51,46 @@ func NewDoorkeeperCountMinSketch[K comparable](width, depth int) *DoorkeeperCoun
 // If the key is a singleton (first time seen), it only tracks it in the doorkeeper.
 // Only on second access does it increment the Count-Min Sketch counters.
 func (cms *DoorkeeperCountMinSketch[K]) Inc(key K) {
-	if cms.isInDoorkeeper(key) {
+	hash := cms.hasher.hash(key)
+
+	if cms.isInDoorkeeper(hash) {
 		// Key already seen at least once, increment CMS counters
-		hashes := cms.hash(key)
 		for i := 0; i < cms.depth; i++ {
-			slot := hashes[i] % uint64(cms.width)
-			if cms.counters[i][slot] < math.MaxUint8 {
-				cms.counters[i][slot]++
-			}
+			cms.counters.inc(i, probe(hash, i, cms.width))
 		}
 	} else {
 		// First time seeing this key, add to doorkeeper
-		cms.addToDoorkeeper(key)
+		cms.addToDoorkeeper(hash)
 	}
-}
 
-// Estimate returns the estimated count for the given key.
-// For items in doorkeeper only, returns 1. For others, uses CMS estimate.
-func (cms *DoorkeeperCountMinSketch[K]) Estimate(key K) int {
-	if !cms.isInDoorkeeper(key) {
-		return 0
+	cms.additions++
+	if cms.sampleSize > 0 && cms.additions >= cms.sampleSize {
+		cms.age()
 	}
+}
 
-	// Check if it's only in doorkeeper (count = 1) or also in CMS (count >= 2)
-	hashes := cms.hash(key)
-	minCount := math.MaxUint8
+// Estimate returns the estimated count for the given key: the CMS estimate,
+// plus 1 when the key is in the doorkeeper.
+// After an aging, a key may be counted by the CMS but not be in the doorkeeper anymore.
+func (cms *DoorkeeperCountMinSketch[K]) Estimate(key K) int {
+	hash := cms.hasher.hash(key)
 
+	minCount := maxCount
 	for i := 0; i < cms.depth; i++ {
-		slot := hashes[i] % uint64(cms.width)
-		if int(cms.counters[i][slot]) < minCount {
-			minCount = int(cms.counters[i][slot])
-		}
+		minCount = min(minCount, cms.counters.get(i, probe(hash, i, cms.width)))
 	}
 
-	// If CMS shows 0, it's only in doorkeeper (count = 1)
-	// If CMS shows > 0, actual count is CMS + 1
-	if minCount == 0 {
-		return 1
+	if cms.isInDoorkeeper(hash) {
+		return minCount + 1
 	}
-	return minCount + 1
+	return minCount
 }
 
 // Reset resets both the counters and doorkeeper to 0.
 func (cms *DoorkeeperCountMinSketch[K]) Reset() {
 	// Reset Count-Min Sketch counters
-	for i := 0; i < cms.depth; i++ {
-		for j := 0; j < cms.width; j++ {
-			cms.counters[i][j] = 0
-		}
-	}
+	cms.counters.reset()
+	cms.additions = 0
 
 	// Reset Doorkeeper Bloom filter
 	for i := range cms.doorkeeper {
@@ -117,60 +98,45 @@ func (cms *DoorkeeperCountMinSketch[K]) Reset() {
 	}
 }
 
-// isInDoorkeeper checks if the key exists in the doorkeeper bloom filter.
-func (cms *DoorkeeperCountMinSketch[K]) isInDoorkeeper(key K) bool {
-	hashes := cms.doorkeeperHash(key)
+// age halves the counters and clears the doorkeeper.
+func (cms *DoorkeeperCountMinSketch[K]) age() {
+	cms.counters.halve()
+	cms.additions /= 2
 
-	for _, hash := range hashes {
-		wordIdx := hash % uint64(cms.doorkeeperSize)
-		bitIdx := wordIdx % 64
-		word := cms.doorkeeper[wordIdx/64]
-
-		if (word & (1 << bitIdx)) == 0 {
-			return false
-		}
+	for i := range cms.doorkeeper {
+		cms.doorkeeper[i] = 0
 	}
-
-	return true
 }
 
-// addToDoorkeeper adds the key to the doorkeeper bloom filter.
-func (cms *DoorkeeperCountMinSketch[K]) addToDoorkeeper(key K) {
-	hashes := cms.doorkeeperHash(key)
-
-	for _, hash := range hashes {
-		wordIdx := hash % uint64(cms.doorkeeperSize)
-		bitIdx := wordIdx % 64
-		wordIdx /= 64
+// doorkeeperProbes is the number of hash functions of the doorkeeper.
+const doorkeeperProbes = 4
 
-		cms.doorkeeper[wordIdx] |= 1 << bitIdx
-	}
-}
+// isInDoorkeeper checks if the hash of a key exists in the doorkeeper bloom filter.
+func (cms *DoorkeeperCountMinSketch[K]) isInDoorkeeper(hash uint64) bool {
+	hash = doorkeeperHash(hash)
 
-// hash generates hash values for Count-Min Sketch using FNV-1a.
-func (cms *DoorkeeperCountMinSketch[K]) hash(key K) []uint64 {
-	keyString := fmt.Sprintf("%v", key)
-
-	hashes := make([]uint64, cms.depth)
-	for i := 0; i < cms.depth; i++ {
-		h := fnv.New64a()
-		_, _ = fmt.Fprintf(h, "%v%d", keyString, cms.seeds[i])
-		hashes[i] = h.Sum64()
+	for i := 0; i < doorkeeperProbes; i++ {
+		bit := probe(hash, i, cms.doorkeeperSize)
+		if cms.doorkeeper[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
 	}
 
-	return hashes
+	return true
 }
 
-// doorkeeperHash generates hash values for Doorkeeper Bloom filter using FNV-1a.
-func (cms *DoorkeeperCountMinSketch[K]) doorkeeperHash(key K) []uint64 {
-	keyString := fmt.Sprintf("%v", key)
+// addToDoorkeeper adds the hash of a key to the doorkeeper bloom filter.
+func (cms *DoorkeeperCountMinSketch[K]) addToDoorkeeper(hash uint64) {
+	hash = doorkeeperHash(hash)
 
-	hashes := make([]uint64, 4)
-	for i := 0; i < 4; i++ {
-		h := fnv.New64a()
-		_, _ = fmt.Fprintf(h, "%v%d", keyString, cms.doorkeeperSeeds[i])
-		hashes[i] = h.Sum64()
+	for i := 0; i < doorkeeperProbes; i++ {
+		bit := probe(hash, i, cms.doorkeeperSize)
+		cms.doorkeeper[bit/64] |= 1 << (bit % 64)
 	}
+}
 
-	return hashes
+// doorkeeperHash derives the hash of the doorkeeper from the hash of the key,
+// so that its bits do not follow the slots of the Count-Min Sketch.
+func doorkeeperHash(hash uint64) uint64 {
+	return bits.RotateLeft64(hash, 16)
 }
@@ -0,0 +1,70 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/maphash"
+	"math/bits"
+)
+
+// hasher hashes keys to 64 bits with hash/maphash. String and integer keys are hashed
+// without allocating. Other keys are formatted with fmt, which allocates.
+// Hashes are seeded per hasher, so they differ between processes.
+type hasher[K comparable] struct {
+	seed maphash.Seed
+}
+
+func newHasher[K comparable]() hasher[K] {
+	return hasher[K]{
+		seed: maphash.MakeSeed(),
+	}
+}
+
+// hash returns a 64-bit hash of the key.
+func (h hasher[K]) hash(key K) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return maphash.String(h.seed, k)
+	case int:
+		return h.hashUint64(uint64(k))
+	case int8:
+		return h.hashUint64(uint64(k))
+	case int16:
+		return h.hashUint64(uint64(k))
+	case int32:
+		return h.hashUint64(uint64(k))
+	case int64:
+		return h.hashUint64(uint64(k))
+	case uint:
+		return h.hashUint64(uint64(k))
+	case uint8:
+		return h.hashUint64(uint64(k))
+	case uint16:
+		return h.hashUint64(uint64(k))
+	case uint32:
+		return h.hashUint64(uint64(k))
+	case uint64:
+		return h.hashUint64(k)
+	case uintptr:
+		return h.hashUint64(uint64(k))
+	default:
+		var m maphash.Hash
+		m.SetSeed(h.seed)
+		_, _ = fmt.Fprintf(&m, "%v", key)
+		return m.Sum64()
+	}
+}
+
+func (h hasher[K]) hashUint64(v uint64) uint64 {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return maphash.Bytes(h.seed, buf[:])
+}
+
+// probe returns the i-th index in [0, size) derived from a 64-bit hash by double hashing
+// (Kirsch & Mitzenmacher): the halves of the hash make two independent hashes, and the
+// odd step never collapses the indexes of a power-of-2 size.
+func probe(hash uint64, i int, size int) uint64 {
+	step := bits.RotateLeft64(hash, 32) | 1
+	return (hash + uint64(i)*step) % uint64(size)
+}
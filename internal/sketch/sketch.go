@@ -1,37 +1,36 @@
 package sketch
 
-import (
-	"fmt"
-	"hash/fnv"
-	"math"
-)
+// DefaultSampleSizeRatio is the default number of increments between two agings of a sketch,
+// relative to the capacity of the cache, as recommended by the TinyLFU paper.
+const DefaultSampleSizeRatio = 10
 
 // According to the paper, Count-Min Sketch is faster than the Spectral Bloom Filters
 // but less accurate.
-
+//
+// Counters are 4-bit, as in the TinyLFU paper. When aging is enabled, all counters are halved
+// every sampleSize increments, so that keys popular in the past do not crowd out new ones.
+//
+// Keys are hashed once: the slot of each row is derived from the hash by double hashing.
 type CountMinSketch[K comparable] struct {
-	width    int       // Width of each hash table
-	depth    int       // Number of hash functions
-	counters [][]uint8 // 2D array of counters
-	seeds    []uint64  // Seeds for hash functions
+	width    int          // Width of each hash table
+	depth    int          // Number of rows
+	counters counterTable // 2D array of 4-bit counters
+	hasher   hasher[K]
+
+	sampleSize int // Increments between two agings, 0 disables aging
+	additions  int // Increments since the last aging
 }
 
 // More width = fewer collisions
 // More depth = better estimates.
-func NewCountMinSketch[K comparable](width, depth int) *CountMinSketch[K] {
+// A positive sampleSize halves the counters every sampleSize increments (10 × the cache capacity is a good default).
+func NewCountMinSketch[K comparable](width, depth, sampleSize int) *CountMinSketch[K] {
 	cms := &CountMinSketch[K]{
-		width:    width,
-		depth:    depth,
-		counters: make([][]byte, depth),
-		seeds:    make([]uint64, depth),
-	}
-
-	for i := range cms.counters {
-		cms.counters[i] = make([]byte, cms.width)
-	}
-
-	for i := 0; i < depth; i++ {
-		cms.seeds[i] = uint64(i * 1000) // Simple seed generation
+		width:      width,
+		depth:      depth,
+		counters:   newCounterTable(width, depth),
+		hasher:     newHasher[K](),
+		sampleSize: sampleSize,
 	}
 
 	return cms
@@ -39,48 +38,35 @@ func NewCountMinSketch[K comparable](width, depth int) *CountMinSketch[K] {
 
 // Inc increments the count for the given key.
 func (cms *CountMinSketch[K]) Inc(key K) {
-	hashes := cms.hash(key)
+	hash := cms.hasher.hash(key)
 	for i := 0; i < cms.depth; i++ {
-		slot := hashes[i] % uint64(cms.width)
-		if cms.counters[i][slot] < math.MaxUint8 {
-			cms.counters[i][slot]++
-		}
+		cms.counters.inc(i, probe(hash, i, cms.width))
+	}
+
+	cms.additions++
+	if cms.sampleSize > 0 && cms.additions >= cms.sampleSize {
+		cms.age()
 	}
 }
 
 // Estimate returns the estimated count for the given key.
 func (cms *CountMinSketch[K]) Estimate(key K) int {
-	overflow := math.MaxUint8
-
-	hashes := cms.hash(key)
+	overflow := maxCount
+	hash := cms.hasher.hash(key)
 	for i := 0; i < cms.depth; i++ {
-		slot := hashes[i] % uint64(cms.width)
-		if int(cms.counters[i][slot]) < overflow {
-			overflow = int(cms.counters[i][slot])
-		}
+		overflow = min(overflow, cms.counters.get(i, probe(hash, i, cms.width)))
 	}
-
 	return overflow
 }
 
 // Reset resets the counters to 0.
 func (cms *CountMinSketch[K]) Reset() {
-	for i := 0; i < cms.depth; i++ {
-		for j := 0; j < cms.width; j++ {
-			cms.counters[i][j] = 0
-		}
-	}
+	cms.counters.reset()
+	cms.additions = 0
 }
 
-func (cms *CountMinSketch[K]) hash(key K) []uint64 {
-	keyString := fmt.Sprintf("%v", key)
-
-	hashes := make([]uint64, cms.depth)
-	for i := 0; i < cms.depth; i++ {
-		h := fnv.New64a()
-		_, _ = fmt.Fprintf(h, "%v%d", keyString, cms.seeds[i])
-		hashes[i] = h.Sum64()
-	}
-
-	return hashes
+// age halves the counters.
+func (cms *CountMinSketch[K]) age() {
+	cms.counters.halve()
+	cms.additions /= 2
 }
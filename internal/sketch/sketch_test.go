@@ -11,38 +11,31 @@ func TestNewCountMinSketch(t *testing.T) {
 	t.Parallel()
 
 	// Test with valid parameters
-	cms := NewCountMinSketch[string](100, 4)
+	cms := NewCountMinSketch[string](100, 4, 0)
 	is.Equal(100, cms.width)
 	is.Equal(4, cms.depth)
-	is.Len(cms.counters, 4)
-	is.Len(cms.seeds, 4)
+	is.Len(cms.counters.rows, 4)
 
 	// Verify all counters are initialized to zero
 	for i := 0; i < cms.depth; i++ {
-		is.Len(cms.counters[i], cms.width)
+		is.Len(cms.counters.rows[i], (cms.width+15)/16)
 		for j := 0; j < cms.width; j++ {
-			is.Equal(uint8(0), cms.counters[i][j])
+			is.Equal(0, cms.counters.get(i, uint64(j)))
 		}
 	}
 
-	// Verify seeds are different
-	for i := 0; i < cms.depth-1; i++ {
-		is.NotEqual(cms.seeds[i], cms.seeds[i+1])
-	}
-
 	// Test with different parameters
-	cms2 := NewCountMinSketch[string](50, 2)
+	cms2 := NewCountMinSketch[string](50, 2, 0)
 	is.Equal(50, cms2.width)
 	is.Equal(2, cms2.depth)
-	is.Len(cms2.counters, 2)
-	is.Len(cms2.seeds, 2)
+	is.Len(cms2.counters.rows, 2)
 }
 
 func TestCountMinSketch_Inc(t *testing.T) {
 	is := assert.New(t)
 	t.Parallel()
 
-	cms := NewCountMinSketch[string](10, 3)
+	cms := NewCountMinSketch[string](10, 3, 0)
 
 	// Test incrementing a single key
 	cms.Inc("test")
@@ -51,7 +44,7 @@ func TestCountMinSketch_Inc(t *testing.T) {
 	hasIncrement := false
 	for i := 0; i < cms.depth; i++ {
 		for j := 0; j < cms.width; j++ {
-			if cms.counters[i][j] > 0 {
+			if cms.counters.get(i, uint64(j)) > 0 {
 				hasIncrement = true
 				break
 			}
@@ -79,7 +72,7 @@ func TestCountMinSketch_Estimate(t *testing.T) {
 	is := assert.New(t)
 	t.Parallel()
 
-	cms := NewCountMinSketch[string](10, 3)
+	cms := NewCountMinSketch[string](10, 3, 0)
 
 	// Test estimate for non-existent key
 	estimate := cms.Estimate("nonexistent")
@@ -106,7 +99,7 @@ func TestCountMinSketch_Reset(t *testing.T) {
 	is := assert.New(t)
 	t.Parallel()
 
-	cms := NewCountMinSketch[string](10, 3)
+	cms := NewCountMinSketch[string](10, 3, 0)
 
 	// Add some data
 	cms.Inc("test")
@@ -123,7 +116,7 @@ func TestCountMinSketch_Reset(t *testing.T) {
 	// Verify all counters are reset
 	for i := 0; i < cms.depth; i++ {
 		for j := 0; j < cms.width; j++ {
-			is.Equal(uint8(0), cms.counters[i][j], "All counters should be reset to 0")
+			is.Equal(0, cms.counters.get(i, uint64(j)), "All counters should be reset to 0")
 		}
 	}
 
@@ -136,52 +129,89 @@ func TestCountMinSketch_Hash(t *testing.T) {
 	is := assert.New(t)
 	t.Parallel()
 
-	cms := NewCountMinSketch[string](10, 3)
+	cms := NewCountMinSketch[string](10, 3, 0)
 
 	// Test hash consistency
-	hashes1 := cms.hash("test")
-	hashes2 := cms.hash("test")
-	is.Equal(hashes1, hashes2, "Hash should be consistent for the same key")
+	is.Equal(cms.hasher.hash("test"), cms.hasher.hash("test"), "Hash should be consistent for the same key")
 
 	// Test hash uniqueness for different keys
-	hashesTest := cms.hash("test")
-	hashesOther := cms.hash("other")
-	is.NotEqual(hashesTest, hashesOther, "Hash should be different for different keys")
-
-	// Test hash length
-	is.Len(hashes1, cms.depth, "Hash should return one value per depth")
-
-	// Test that all hash values are different (very likely with good hash function)
-	allDifferent := true
-	for i := 0; i < len(hashes1)-1; i++ {
-		if hashes1[i] == hashes1[i+1] {
-			allDifferent = false
-			break
-		}
+	is.NotEqual(cms.hasher.hash("test"), cms.hasher.hash("other"), "Hash should be different for different keys")
+
+	// Integer and composite keys
+	ints := newHasher[int]()
+	is.Equal(ints.hash(42), ints.hash(42))
+	is.NotEqual(ints.hash(42), ints.hash(43))
+
+	type compositeKey struct {
+		a string
+		b int
 	}
-	is.True(allDifferent, "Hash values should be different across depths")
+	composites := newHasher[compositeKey]()
+	is.Equal(composites.hash(compositeKey{"a", 1}), composites.hash(compositeKey{"a", 1}))
+	is.NotEqual(composites.hash(compositeKey{"a", 1}), composites.hash(compositeKey{"a", 2}))
+
+	// Hashes are seeded per sketch
+	is.NotEqual(newHasher[string]().hash("test"), newHasher[string]().hash("test"))
+}
+
+func TestProbe(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	// Rows get different slots from a single hash
+	hash := uint64(0x0123456789abcdef)
+	slots := map[uint64]bool{}
+	for i := 0; i < 4; i++ {
+		slot := probe(hash, i, 1000)
+		is.Less(slot, uint64(1000))
+		slots[slot] = true
+	}
+	is.Len(slots, 4)
+
+	// The step is odd: probes cover a power-of-2 size
+	slots = map[uint64]bool{}
+	for i := 0; i < 16; i++ {
+		slots[probe(hash<<8, i, 16)] = true
+	}
+	is.Len(slots, 16)
+}
+
+func TestCountMinSketch_Allocations(t *testing.T) {
+	is := assert.New(t)
+
+	strings := NewCountMinSketch[string](100, 4, 1_000)
+	is.Zero(testing.AllocsPerRun(100, func() {
+		strings.Inc("key")
+		_ = strings.Estimate("key")
+	}))
+
+	ints := NewDoorkeeperCountMinSketch[int](100, 4, 1_000)
+	is.Zero(testing.AllocsPerRun(100, func() {
+		ints.Inc(42)
+		_ = ints.Estimate(42)
+	}))
 }
 
 func TestCountMinSketch_Overflow(t *testing.T) {
 	is := assert.New(t)
 	t.Parallel()
 
-	cms := NewCountMinSketch[string](10, 3)
+	cms := NewCountMinSketch[string](10, 3, 0)
 
 	// Test counter overflow behavior
 	// Increment the same key many times to potentially cause overflow
-	for i := 0; i < 300; i++ { // More than uint8 max (255)
+	for i := 0; i < 300; i++ { // More than the 4-bit max (15)
 		cms.Inc("overflow_test")
 	}
 
-	// The estimate should not exceed the maximum possible value
+	// The estimate saturates at the maximum possible value
 	estimate := cms.Estimate("overflow_test")
-	is.LessOrEqual(estimate, 255, "Estimate should not exceed uint8 max value")
+	is.Equal(15, estimate, "Estimate should saturate at the 4-bit max value")
 
-	// Verify that counters don't overflow beyond uint8 max
+	// Verify that counters don't overflow into their neighbours
 	for i := 0; i < cms.depth; i++ {
 		for j := 0; j < cms.width; j++ {
-			is.LessOrEqual(cms.counters[i][j], uint8(255), "Counters should not overflow beyond uint8 max")
+			is.LessOrEqual(cms.counters.get(i, uint64(j)), 15, "Counters should not overflow beyond the 4-bit max")
 		}
 	}
 }
@@ -190,7 +220,7 @@ func TestCountMinSketch_Accuracy(t *testing.T) {
 	is := assert.New(t)
 	t.Parallel()
 
-	cms := NewCountMinSketch[string](100, 4)
+	cms := NewCountMinSketch[string](100, 4, 0)
 
 	// Test with known frequencies
 	testCases := []struct {
@@ -213,7 +243,7 @@ func TestCountMinSketch_Accuracy(t *testing.T) {
 	// Test estimates
 	for _, tc := range testCases {
 		estimate := cms.Estimate(tc.key)
-		is.GreaterOrEqual(estimate, tc.frequency, "Estimate should be at least the actual frequency")
+		is.GreaterOrEqual(estimate, min(tc.frequency, maxCount), "Estimate should be at least the actual frequency, up to the 4-bit max")
 		// Note: Due to hash collisions, estimate might be higher than actual frequency
 	}
 
@@ -227,7 +257,7 @@ func TestCountMinSketch_CollisionHandling(t *testing.T) {
 	is := assert.New(t)
 	t.Parallel()
 
-	cms := NewCountMinSketch[string](5, 2) // Small sketch to increase collision probability
+	cms := NewCountMinSketch[string](5, 2, 0) // Small sketch to increase collision probability
 
 	// Add many different keys to cause collisions
 	keys := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
@@ -246,7 +276,7 @@ func TestCountMinSketch_EmptyKey(t *testing.T) {
 	is := assert.New(t)
 	t.Parallel()
 
-	cms := NewCountMinSketch[string](10, 3)
+	cms := NewCountMinSketch[string](10, 3, 0)
 
 	// Test with empty string
 	cms.Inc("")
@@ -264,7 +294,7 @@ func TestCountMinSketch_LongKey(t *testing.T) {
 	is := assert.New(t)
 	t.Parallel()
 
-	cms := NewCountMinSketch[string](10, 3)
+	cms := NewCountMinSketch[string](10, 3, 0)
 
 	// Test with very long key
 	longKey := "this_is_a_very_long_key_that_might_cause_issues_with_hashing_but_should_still_work_correctly"
@@ -282,7 +312,7 @@ func TestCountMinSketch_SpecialCharacters(t *testing.T) {
 	is := assert.New(t)
 	t.Parallel()
 
-	cms := NewCountMinSketch[string](10, 3)
+	cms := NewCountMinSketch[string](10, 3, 0)
 
 	// Test with various special characters
 	specialKeys := []string{
@@ -305,7 +335,7 @@ func TestCountMinSketch_ConcurrentAccess(t *testing.T) {
 	is := assert.New(t)
 	t.Parallel()
 
-	cms := NewCountMinSketch[string](100, 4)
+	cms := NewCountMinSketch[string](100, 4, 0)
 
 	// Test that the sketch can handle rapid successive operations
 	// (Note: This doesn't test actual concurrency since the sketch isn't thread-safe)
@@ -327,7 +357,7 @@ func TestCountMinSketch_EstimateAccuracy(t *testing.T) {
 	t.Parallel()
 
 	// Use a larger sketch for better accuracy
-	cms := NewCountMinSketch[string](1000, 4)
+	cms := NewCountMinSketch[string](1000, 4, 0)
 
 	// Test with a single key to minimize collisions
 	cms.Inc("single_key")
@@ -346,7 +376,7 @@ func TestCountMinSketch_ResetBehavior(t *testing.T) {
 	is := assert.New(t)
 	t.Parallel()
 
-	cms := NewCountMinSketch[string](10, 3)
+	cms := NewCountMinSketch[string](10, 3, 0)
 
 	// Add data
 	cms.Inc("key1")
@@ -368,3 +398,121 @@ func TestCountMinSketch_ResetBehavior(t *testing.T) {
 	cms.Inc("key3")
 	is.Positive(cms.Estimate("key3"))
 }
+
+func TestCountMinSketch_Aging(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cms := NewCountMinSketch[string](1000, 4, 20)
+
+	for i := 0; i < 10; i++ {
+		cms.Inc("old")
+	}
+	is.Equal(10, cms.Estimate("old"))
+
+	// The 20th increment halves all counters.
+	for i := 0; i < 10; i++ {
+		cms.Inc("new")
+	}
+	is.Equal(5, cms.Estimate("old"))
+	is.Equal(5, cms.Estimate("new"))
+	is.Equal(10, cms.additions)
+
+	// Without aging, counters saturate.
+	cms = NewCountMinSketch[string](1000, 4, 0)
+	for i := 0; i < 100; i++ {
+		cms.Inc("old")
+	}
+	is.Equal(15, cms.Estimate("old"))
+}
+
+func TestCounterTable(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	table := newCounterTable(20, 2)
+	is.Len(table.rows, 2)
+	is.Len(table.rows[0], 2)
+
+	for i := 0; i < 20; i++ {
+		table.inc(0, 15)
+	}
+	table.inc(0, 16)
+	table.inc(1, 15)
+	is.Equal(15, table.get(0, 15))
+	is.Equal(1, table.get(0, 16))
+	is.Equal(0, table.get(0, 14))
+	is.Equal(1, table.get(1, 15))
+
+	// Bits do not leak between neighbours.
+	table.halve()
+	is.Equal(7, table.get(0, 15))
+	is.Equal(0, table.get(0, 16))
+	is.Equal(0, table.get(1, 15))
+
+	table.reset()
+	is.Equal(0, table.get(0, 15))
+}
+
+func TestDoorkeeperCountMinSketch_Aging(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cms := NewDoorkeeperCountMinSketch[string](1000, 4, 8)
+
+	// The first increment only sets the doorkeeper.
+	cms.Inc("old")
+	is.Equal(1, cms.Estimate("old"))
+	for i := 0; i < 3; i++ {
+		cms.Inc("old")
+	}
+	is.Equal(4, cms.Estimate("old"))
+	is.Equal(0, cms.Estimate("new"))
+
+	// The 8th increment halves the counters and clears the doorkeeper.
+	for i := 0; i < 4; i++ {
+		cms.Inc("new")
+	}
+	is.Equal(1, cms.Estimate("old"))
+	is.Equal(1, cms.Estimate("new"))
+
+	cms.Reset()
+	is.Equal(0, cms.Estimate("old"))
+	is.Zero(cms.additions)
+}
+
+func BenchmarkCountMinSketch_String(b *testing.B) {
+	cms := NewCountMinSketch[string](10_000, 4, 100_000)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		cms.Inc("user:123456")
+		_ = cms.Estimate("user:123456")
+	}
+}
+
+func BenchmarkCountMinSketch_Int(b *testing.B) {
+	cms := NewCountMinSketch[int](10_000, 4, 100_000)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		cms.Inc(n)
+		_ = cms.Estimate(n)
+	}
+}
+
+func BenchmarkDoorkeeperCountMinSketch_String(b *testing.B) {
+	cms := NewDoorkeeperCountMinSketch[string](10_000, 4, 100_000)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		cms.Inc("user:123456")
+		_ = cms.Estimate("user:123456")
+	}
+}
+
+func BenchmarkDoorkeeperCountMinSketch_Int(b *testing.B) {
+	cms := NewDoorkeeperCountMinSketch[int](10_000, 4, 100_000)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		cms.Inc(n)
+		_ = cms.Estimate(n)
+	}
+}
@@ -0,0 +1,158 @@
+package sketch
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// TopK tracks the most frequent keys of a stream with the Space-Saving algorithm
+// (Metwally, Agrawal and El Abbadi, "Efficient Computation of Frequent and Top-k Elements in Data Streams").
+// It monitors at most `capacity` keys. When an unmonitored key arrives and the summary is full,
+// it replaces the key with the lowest count and inherits that count as its error.
+// Counts are overestimated by at most Error, never underestimated.
+//
+// Counts are halved by Decay, which turns the summary into a decaying window.
+// TopK is not safe for concurrent use.
+type TopK[K comparable] struct {
+	capacity int
+	entries  map[K]*topKEntry[K]
+	heap     topKHeap[K] // Min-heap on count
+}
+
+// TopKEntry is a key monitored by TopK.
+type TopKEntry[K comparable] struct {
+	Key   K
+	Count uint64 // Estimated number of occurrences
+	Error uint64 // Maximum overestimation of Count
+}
+
+type topKEntry[K comparable] struct {
+	TopKEntry[K]
+	index int // Position in the heap
+}
+
+// NewTopK creates a summary monitoring up to `capacity` keys.
+// More capacity = more accurate counts for the top keys.
+func NewTopK[K comparable](capacity int) *TopK[K] {
+	if capacity <= 0 {
+		panic("top-k capacity must be greater than 0")
+	}
+
+	return &TopK[K]{
+		capacity: capacity,
+		entries:  make(map[K]*topKEntry[K], capacity),
+		heap:     make(topKHeap[K], 0, capacity),
+	}
+}
+
+// Inc records an occurrence of the key.
+func (t *TopK[K]) Inc(key K) {
+	if e, ok := t.entries[key]; ok {
+		e.Count++
+		heap.Fix(&t.heap, e.index)
+		return
+	}
+
+	if len(t.heap) < t.capacity {
+		e := &topKEntry[K]{TopKEntry: TopKEntry[K]{Key: key, Count: 1}}
+		t.entries[key] = e
+		heap.Push(&t.heap, e)
+		return
+	}
+
+	// Replace the key with the lowest count.
+	e := t.heap[0]
+	delete(t.entries, e.Key)
+	e.Key = key
+	e.Error = e.Count
+	e.Count++
+	t.entries[key] = e
+	heap.Fix(&t.heap, 0)
+}
+
+// Estimate returns the estimated count of the key, or 0 when the key is not monitored.
+func (t *TopK[K]) Estimate(key K) uint64 {
+	if e, ok := t.entries[key]; ok {
+		return e.Count
+	}
+
+	return 0
+}
+
+// Top returns up to n monitored keys, by descending count. Keys with a count of 0 are skipped.
+// A non-positive n returns every monitored key.
+func (t *TopK[K]) Top(n int) []TopKEntry[K] {
+	top := make([]TopKEntry[K], 0, len(t.heap))
+	for _, e := range t.heap {
+		if e.Count > 0 {
+			top = append(top, e.TopKEntry)
+		}
+	}
+
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		// Keys with a lower error are more likely to be accurate.
+		return top[i].Error < top[j].Error
+	})
+
+	if n > 0 && n < len(top) {
+		top = top[:n]
+	}
+
+	return top
+}
+
+// Decay divides every count and error by 2^times.
+// Halving preserves the order of the counts, so the heap does not need to be rebuilt.
+func (t *TopK[K]) Decay(times uint) {
+	if times == 0 {
+		return
+	}
+	if times > 63 {
+		times = 63
+	}
+
+	for _, e := range t.heap {
+		e.Count >>= times
+		e.Error >>= times
+	}
+}
+
+// Len returns the number of monitored keys.
+func (t *TopK[K]) Len() int {
+	return len(t.heap)
+}
+
+// Reset forgets every monitored key.
+func (t *TopK[K]) Reset() {
+	clear(t.entries)
+	t.heap = t.heap[:0]
+}
+
+// topKHeap implements heap.Interface.
+type topKHeap[K comparable] []*topKEntry[K]
+
+func (h topKHeap[K]) Len() int           { return len(h) }
+func (h topKHeap[K]) Less(i, j int) bool { return h[i].Count < h[j].Count }
+func (h topKHeap[K]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *topKHeap[K]) Push(x any) {
+	e := x.(*topKEntry[K])
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *topKHeap[K]) Pop() any {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return e
+}
@@ -0,0 +1,75 @@
+package sketch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopK(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	is.Panics(func() {
+		_ = NewTopK[string](0)
+	})
+
+	topk := NewTopK[string](3)
+	for i := 0; i < 10; i++ {
+		topk.Inc("a")
+	}
+	for i := 0; i < 5; i++ {
+		topk.Inc("b")
+	}
+	topk.Inc("c")
+	is.Equal(3, topk.Len())
+	is.Equal(uint64(10), topk.Estimate("a"))
+	is.Equal(uint64(0), topk.Estimate("d"))
+
+	// "d" replaces "c", the key with the lowest count, and inherits its count as error.
+	topk.Inc("d")
+	is.Equal(3, topk.Len())
+	is.Equal(uint64(0), topk.Estimate("c"))
+	is.Equal([]TopKEntry[string]{
+		{Key: "a", Count: 10},
+		{Key: "b", Count: 5},
+		{Key: "d", Count: 2, Error: 1},
+	}, topk.Top(0))
+	is.Equal([]TopKEntry[string]{{Key: "a", Count: 10}}, topk.Top(1))
+
+	topk.Decay(0)
+	is.Equal(uint64(10), topk.Estimate("a"))
+	topk.Decay(1)
+	is.Equal([]TopKEntry[string]{
+		{Key: "a", Count: 5},
+		{Key: "b", Count: 2},
+		{Key: "d", Count: 1},
+	}, topk.Top(0))
+	topk.Decay(100)
+	is.Empty(topk.Top(0))
+	is.Equal(uint64(0), topk.Estimate("a"))
+	is.Equal(3, topk.Len())
+
+	topk.Reset()
+	is.Equal(0, topk.Len())
+	is.Empty(topk.Top(0))
+}
+
+func TestTopK_skewed(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	// Heavy hitters are found despite a long tail of unique keys.
+	topk := NewTopK[int](10)
+	for i := 0; i < 10_000; i++ {
+		topk.Inc(i % 3)
+		topk.Inc(1_000 + i)
+	}
+
+	top := topk.Top(3)
+	is.Len(top, 3)
+	for _, e := range top {
+		is.Less(e.Key, 3)
+		is.GreaterOrEqual(e.Count-e.Error, uint64(3_333))
+	}
+}
@@ -0,0 +1,91 @@
+package hot
+
+import (
+	"sync/atomic"
+
+	"github.com/samber/hot/pkg/invalidation"
+	"github.com/samber/hot/pkg/metrics"
+)
+
+// invalidationBus broadcasts local invalidations and applies the ones received from other instances.
+type invalidationBus struct {
+	invalidator invalidation.Invalidator
+	origin      string
+	version     atomic.Uint64
+	versions    *invalidation.Versions
+
+	// Number of invalidations not encoded, or not published to every other instance.
+	publishErrors atomic.Int64
+	// Exporter of the publish errors, nil when disabled.
+	prometheus *metrics.PrometheusInvalidationCollector
+}
+
+// startInvalidation subscribes to the invalidations of other instances.
+func (c *HotCache[K, V]) startInvalidation(invalidator invalidation.Invalidator) {
+	c.invalidation = &invalidationBus{
+		invalidator: invalidator,
+		origin:      invalidation.NewOrigin(),
+		versions:    invalidation.NewVersions(),
+	}
+
+	invalidator.Subscribe(c.onInvalidation)
+}
+
+// stopInvalidation closes the transport.
+func (c *HotCache[K, V]) stopInvalidation() error {
+	if c.invalidation == nil {
+		return nil
+	}
+
+	return c.invalidation.invalidator.Close()
+}
+
+// broadcastInvalidation sends a local invalidation to the other instances.
+func (c *HotCache[K, V]) broadcastInvalidation(op invalidation.Op, keys []K) {
+	bus := c.invalidation
+	if bus == nil {
+		return
+	}
+
+	payload, err := invalidation.Encode(invalidation.Message[K]{
+		Origin:  bus.origin,
+		Version: bus.version.Add(1),
+		Op:      op,
+		Keys:    keys,
+	})
+	if err == nil {
+		err = bus.invalidator.Publish(payload)
+	}
+	if err != nil {
+		// The other instances keep serving the keys until they expire.
+		bus.publishErrors.Add(1)
+	}
+}
+
+// invalidationPublishErrors returns the number of invalidations not published, or 0 without invalidator.
+func (c *HotCache[K, V]) invalidationPublishErrors() int64 {
+	if c.invalidation == nil {
+		return 0
+	}
+
+	return c.invalidation.publishErrors.Load()
+}
+
+// onInvalidation applies an invalidation received from another instance, without broadcasting it again.
+func (c *HotCache[K, V]) onInvalidation(payload []byte) {
+	msg, err := invalidation.Decode[K](payload)
+	if err != nil || msg.Origin == c.invalidation.origin {
+		return
+	}
+
+	if !c.invalidation.versions.Accept(msg.Origin, msg.Version, msg.Op) {
+		return
+	}
+
+	switch msg.Op {
+	case invalidation.OpDelete:
+		c.deleteManyLocal(msg.Keys)
+	case invalidation.OpPurge:
+		c.purgeLocal()
+	}
+}
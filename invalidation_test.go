@@ -0,0 +1,205 @@
+package hot
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/samber/hot/pkg/invalidation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithInvalidator(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	hub := invalidation.NewInProcessHub()
+	newCache := func() *HotCache[string, int] {
+		return NewHotCache[string, int](LRU, 10).
+			WithMissingCache(LRU, 10).
+			WithInvalidator(hub.Join()).
+			Build()
+	}
+
+	a := newCache()
+	b := newCache()
+	c := newCache()
+	defer a.Close()
+	defer b.Close()
+	defer c.Close()
+
+	for _, cache := range []*HotCache[string, int]{a, b, c} {
+		cache.Set("foo", 1)
+		cache.Set("bar", 2)
+		cache.Set("baz", 3)
+		cache.SetMissing("missing")
+	}
+
+	is.True(a.Delete("foo"))
+	is.False(b.Has("foo"))
+	is.False(c.Has("foo"))
+
+	is.Equal(map[string]bool{"bar": true, "missing": true}, b.DeleteMany([]string{"bar", "missing"}))
+	is.False(a.Has("bar"))
+	is.False(c.Has("bar"))
+	is.False(c.Has("missing"))
+	is.True(a.Has("baz"))
+
+	c.Purge()
+	is.Equal(0, a.Len())
+	is.Equal(0, b.Len())
+	is.Equal(0, c.Len())
+
+	is.Panics(func() {
+		_ = NewHotCache[string, int](LRU, 10).WithInvalidator(nil)
+	})
+}
+
+// countingInvalidator counts the payloads published by a cache.
+type countingInvalidator struct {
+	invalidation.Invalidator
+	published atomic.Int64
+}
+
+func (i *countingInvalidator) Publish(payload []byte) error {
+	i.published.Add(1)
+	return i.Invalidator.Publish(payload)
+}
+
+func TestWithInvalidator_NoRebroadcast(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	hub := invalidation.NewInProcessHub()
+	remote := hub.Join()
+	transportA := &countingInvalidator{Invalidator: hub.Join()}
+	transportB := &countingInvalidator{Invalidator: hub.Join()}
+
+	a := NewHotCache[string, int](LRU, 10).WithInvalidator(transportA).Build()
+	b := NewHotCache[string, int](LRU, 10).WithInvalidator(transportB).Build()
+	defer a.Close()
+	defer b.Close()
+
+	a.Set("foo", 1)
+	b.Set("foo", 1)
+	a.Set("bar", 2)
+	b.Set("bar", 2)
+
+	publish := func(version uint64, op invalidation.Op, keys ...string) {
+		payload, err := invalidation.Encode(invalidation.Message[string]{Origin: "remote", Version: version, Op: op, Keys: keys})
+		is.NoError(err)
+		is.NoError(remote.Publish(payload))
+	}
+
+	// received invalidations are applied, and not broadcast again
+	publish(1, invalidation.OpDelete, "foo")
+	is.False(a.Has("foo"))
+	is.False(b.Has("foo"))
+	publish(2, invalidation.OpPurge)
+	is.Equal(0, a.Len())
+	is.Equal(0, b.Len())
+	is.Equal(int64(0), transportA.published.Load())
+	is.Equal(int64(0), transportB.published.Load())
+
+	// local invalidations are broadcast
+	a.Set("foo", 1)
+	b.Set("foo", 1)
+	a.Delete("foo")
+	is.False(b.Has("foo"))
+	is.Equal(int64(1), transportA.published.Load())
+	is.Equal(int64(0), transportB.published.Load())
+}
+
+// fullInvalidator drops every payload, like a transport with a full queue.
+type fullInvalidator struct {
+	invalidation.Invalidator
+}
+
+func (i *fullInvalidator) Publish(payload []byte) error {
+	return invalidation.ErrQueueFull
+}
+
+func TestWithInvalidator_PublishErrors(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	hub := invalidation.NewInProcessHub()
+	cache := NewHotCache[string, int](LRU, 10).
+		WithInvalidator(&fullInvalidator{Invalidator: hub.Join()}).
+		WithPrometheusMetrics("test-cache").
+		Build()
+	defer cache.Close()
+
+	cache.Set("foo", 1)
+	is.True(cache.Delete("foo"))
+	cache.DeleteMany([]string{"bar", "baz"})
+	cache.Purge()
+
+	is.Equal(int64(3), cache.Stats().InvalidationPublishErrors)
+	is.Equal(3.0, testutil.ToFloat64(cache.invalidation.prometheus))
+
+	is.Equal(int64(0), NewHotCache[string, int](LRU, 10).Build().Stats().InvalidationPublishErrors)
+}
+
+func TestWithInvalidator_Duplicates(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	hub := invalidation.NewInProcessHub()
+	remote := hub.Join()
+	cache := NewHotCache[string, int](LRU, 10).
+		WithInvalidator(hub.Join()).
+		Build()
+	defer cache.Close()
+
+	publish := func(version uint64, op invalidation.Op, keys ...string) {
+		payload, err := invalidation.Encode(invalidation.Message[string]{Origin: "remote", Version: version, Op: op, Keys: keys})
+		is.NoError(err)
+		is.NoError(remote.Publish(payload))
+	}
+
+	cache.Set("a", 1)
+	publish(1, invalidation.OpDelete, "a")
+	is.False(cache.Has("a"))
+
+	// duplicate
+	cache.Set("a", 1)
+	publish(1, invalidation.OpDelete, "a")
+	is.True(cache.Has("a"))
+
+	// deletion older than a purge
+	publish(3, invalidation.OpPurge)
+	is.False(cache.Has("a"))
+	cache.Set("a", 1)
+	publish(2, invalidation.OpDelete, "a")
+	is.True(cache.Has("a"))
+
+	// invalid payload
+	is.NoError(remote.Publish([]byte("not json")))
+	is.True(cache.Has("a"))
+}
+
+func TestWithInvalidator_TCP(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+	testWithTimeout(t, 2*time.Second)
+
+	transportB, err := invalidation.NewTCPInvalidator("127.0.0.1:0", nil)
+	is.NoError(err)
+	transportA, err := invalidation.NewTCPInvalidator("127.0.0.1:0", []string{transportB.Addr().String()})
+	is.NoError(err)
+
+	a := NewHotCache[string, int](LRU, 10).WithInvalidator(transportA).Build()
+	b := NewHotCache[string, int](LRU, 10).WithInvalidator(transportB).Build()
+
+	b.Set("foo", 1)
+	a.Delete("foo")
+
+	is.Eventually(func() bool {
+		return !b.Has("foo")
+	}, time.Second, 5*time.Millisecond)
+
+	is.NoError(a.Close())
+	is.NoError(b.Close())
+}
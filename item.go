@@ -1,11 +1,14 @@
 package hot
 
 import (
+	"encoding/binary"
+	"errors"
 	"math"
 	"math/rand/v2"
 	"time"
 
 	"github.com/samber/hot/internal"
+	"github.com/samber/hot/pkg/disk"
 )
 
 // newItem creates a new cache item with the specified value, TTL, and stale duration.
@@ -80,6 +83,102 @@ func (i *item[V]) shouldRevalidate(nowNano int64) bool {
 	return i.expiryNano > 0 && nowNano > i.expiryNano && nowNano < i.staleExpiryNano
 }
 
+// EntryInfo describes a cached entry, as returned by Inspect.
+type EntryInfo[V any] struct {
+	// Value is the cached value, or the zero value when the key is cached as missing.
+	Value V
+	// Missing is true when the key is cached as missing.
+	Missing bool
+	// ExpiresAt is the end of freshness. It is zero when the entry never expires.
+	ExpiresAt time.Time
+	// StaleUntil is the end of the stale window, after which the entry is expired.
+	StaleUntil time.Time
+	// Stale is true when the entry is past ExpiresAt but still served while revalidating.
+	Stale bool
+	// Expired is true when the entry is past StaleUntil and waiting to be removed.
+	Expired bool
+}
+
+// entryInfo converts an item into the public EntryInfo, converting monotonic nanoseconds to wall clock times.
+func (i *item[V]) entryInfo(nowNano int64, now time.Time) EntryInfo[V] {
+	info := EntryInfo[V]{
+		Value:   i.value,
+		Missing: !i.hasValue,
+		Stale:   i.shouldRevalidate(nowNano),
+		Expired: i.isExpired(nowNano),
+	}
+
+	if i.expiryNano > 0 {
+		info.ExpiresAt = now.Add(time.Duration(i.expiryNano - nowNano))
+		info.StaleUntil = now.Add(time.Duration(i.staleExpiryNano - nowNano))
+	}
+
+	return info
+}
+
+// itemHeaderSize is the size of the item metadata written before the encoded value: hasValue + expiryNano + staleExpiryNano.
+const itemHeaderSize = 1 + 8 + 8
+
+// itemCodec serializes items for the disk tier, wrapping the user codec with the item metadata.
+type itemCodec[V any] struct {
+	codec disk.Codec[V]
+}
+
+var _ disk.Codec[*item[int]] = (*itemCodec[int])(nil)
+
+// Marshal encodes the item metadata followed by the value.
+func (c itemCodec[V]) Marshal(i *item[V]) ([]byte, error) {
+	var value []byte
+	if i.hasValue {
+		var err error
+		value, err = c.codec.Marshal(i.value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	buf := make([]byte, itemHeaderSize, itemHeaderSize+len(value))
+	if i.hasValue {
+		buf[0] = 1
+	}
+	binary.BigEndian.PutUint64(buf[1:9], uint64(i.expiryNano))
+	binary.BigEndian.PutUint64(buf[9:17], uint64(i.staleExpiryNano))
+
+	return append(buf, value...), nil
+}
+
+// Unmarshal decodes an item written by Marshal.
+func (c itemCodec[V]) Unmarshal(data []byte) (*item[V], error) {
+	if len(data) < itemHeaderSize {
+		return nil, errors.New("invalid item encoding")
+	}
+
+	i := &item[V]{
+		hasValue:        data[0] == 1,
+		expiryNano:      int64(binary.BigEndian.Uint64(data[1:9])),
+		staleExpiryNano: int64(binary.BigEndian.Uint64(data[9:17])),
+	}
+
+	if i.hasValue {
+		value, err := c.codec.Unmarshal(data[itemHeaderSize:])
+		if err != nil {
+			return nil, err
+		}
+		i.value = value
+	}
+
+	return i, nil
+}
+
+// itemDiskExpiry returns the time after which an item stored on disk can be dropped: stale items are kept,
+// so that they can still be served while being revalidated.
+func itemDiskExpiry[V any](i *item[V]) int64 {
+	if i.expiryNano == 0 {
+		return 0
+	}
+	return i.staleExpiryNano
+}
+
 // zero returns the zero value for type V.
 func zero[V any]() V {
 	var v V
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/samber/hot/internal"
+	"github.com/samber/hot/pkg/disk"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -157,3 +158,38 @@ func TestApplyJitter(t *testing.T) {
 	is.InEpsilon(1_000_000, applyJitter(1_000_000, 3, 100*time.Millisecond), 100_000)
 	is.InEpsilon(1_000_000, applyJitter(1_000_000, 3, 100*time.Millisecond), 100_000)
 }
+
+func TestItemCodec(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	codec := itemCodec[string]{codec: disk.JSONCodec[string]{}}
+
+	data, err := codec.Marshal(&item[string]{hasValue: true, value: "foo", expiryNano: 42, staleExpiryNano: 84})
+	is.NoError(err)
+	i, err := codec.Unmarshal(data)
+	is.NoError(err)
+	is.Equal(&item[string]{hasValue: true, value: "foo", expiryNano: 42, staleExpiryNano: 84}, i)
+
+	data, err = codec.Marshal(&item[string]{hasValue: false, expiryNano: 42, staleExpiryNano: 42})
+	is.NoError(err)
+	is.Len(data, itemHeaderSize)
+	i, err = codec.Unmarshal(data)
+	is.NoError(err)
+	is.Equal(&item[string]{hasValue: false, expiryNano: 42, staleExpiryNano: 42}, i)
+
+	_, err = codec.Unmarshal([]byte{1, 2, 3})
+	is.Error(err)
+	invalid := append(make([]byte, itemHeaderSize), '{')
+	invalid[0] = 1
+	_, err = codec.Unmarshal(invalid)
+	is.Error(err)
+}
+
+func TestItemDiskExpiry(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	is.Equal(int64(0), itemDiskExpiry(&item[int]{}))
+	is.Equal(int64(84), itemDiskExpiry(&item[int]{expiryNano: 42, staleExpiryNano: 84}))
+}
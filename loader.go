@@ -1,19 +1,39 @@
 package hot
 
+import (
+	"strconv"
+)
+
 // Loader is a function type that loads values for the given keys.
 // It should return a map of found key-value pairs and an error if the operation fails.
 // Keys that cannot be found should not be included in the returned map.
 type Loader[K comparable, V any] func(keys []K) (found map[K]V, err error)
 
+// NamedLoader is a loader with a name, used as the `loader` label of the loader metrics.
+type NamedLoader[K comparable, V any] struct {
+	Name   string
+	Loader Loader[K, V]
+}
+
 // LoaderChain is a slice of loaders that are executed in sequence.
 // Each loader is called with the keys that were not found by previous loaders.
 type LoaderChain[K comparable, V any] []Loader[K, V]
 
+// loaderObserver is notified before each loader of a chain is called, with the index of the loader
+// in the chain and the requested keys. The returned function is called with the outcome of the loader:
+// the number of requested keys it found, or its error.
+type loaderObserver[K comparable] func(index int, keys []K) func(found int, err error)
+
 // run executes the loader chain with the given missing keys.
 // It returns found values, still missing keys, and an error if any loader fails.
 // If a loader returns an error, the entire operation fails and no values are returned.
 // Values returned by later loaders in the chain will overwrite values from earlier loaders.
 func (loaders LoaderChain[K, V]) run(missing []K) (results map[K]V, other []K, err error) {
+	return loaders.runObserved(missing, nil)
+}
+
+// runObserved is like run, and notifies the observer around each loader call. The observer can be nil.
+func (loaders LoaderChain[K, V]) runObserved(missing []K, observer loaderObserver[K]) (results map[K]V, other []K, err error) {
 	results = map[K]V{}
 
 	stillMissing := map[K]struct{}{}
@@ -32,8 +52,16 @@ func (loaders LoaderChain[K, V]) run(missing []K) (results map[K]V, other []K, e
 			toFetch = append(toFetch, key)
 		}
 
+		var done func(found int, err error)
+		if observer != nil {
+			done = observer(i, toFetch)
+		}
+
 		found, err := loaders[i](toFetch)
 		if err != nil {
+			if done != nil {
+				done(0, err)
+			}
 			return map[K]V{}, []K{}, err
 		}
 
@@ -42,6 +70,10 @@ func (loaders LoaderChain[K, V]) run(missing []K) (results map[K]V, other []K, e
 			results[k] = v
 			delete(stillMissing, k)
 		}
+
+		if done != nil {
+			done(count-len(stillMissing), nil)
+		}
 	}
 
 	missing = make([]K, 0, len(stillMissing))
@@ -51,3 +83,11 @@ func (loaders LoaderChain[K, V]) run(missing []K) (results map[K]V, other []K, e
 
 	return results, missing, nil
 }
+
+// loaderName returns the name of the loader at the given index of a chain, defaulting to the index.
+func loaderName(names []string, index int) string {
+	if index < len(names) && names[index] != "" {
+		return names[index]
+	}
+	return strconv.Itoa(index)
+}
@@ -2,6 +2,7 @@ package hot
 
 import (
 	"errors"
+	"fmt"
 	"sort"
 	"sync/atomic"
 	"testing"
@@ -234,3 +235,38 @@ func TestLoaders_runWithStringKeys(t *testing.T) {
 	is.Equal([]string{"d"}, missing)
 	is.NoError(err)
 }
+
+func TestLoaders_runObserved(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	loaders := LoaderChain[int, int]{
+		func(keys []int) (map[int]int, error) {
+			return map[int]int{1: 10}, nil
+		},
+		func(keys []int) (map[int]int, error) {
+			return nil, assert.AnError
+		},
+	}
+
+	calls := []string{}
+	observer := func(index int, keys []int) func(found int, err error) {
+		keys = append([]int{}, keys...)
+		sort.Ints(keys) // keys are collected from a map
+		calls = append(calls, fmt.Sprintf("start %d %v", index, keys))
+		return func(found int, err error) {
+			calls = append(calls, fmt.Sprintf("end %d %d %v", index, found, err))
+		}
+	}
+
+	results, missing, err := loaders.runObserved([]int{1, 2}, observer)
+	is.Empty(results)
+	is.Empty(missing)
+	is.ErrorIs(err, assert.AnError)
+	is.Equal([]string{
+		"start 0 [1 2]",
+		"end 0 1 <nil>",
+		"start 1 [2]",
+		"end 1 0 " + assert.AnError.Error(),
+	}, calls)
+}
@@ -0,0 +1,141 @@
+package hot
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/samber/hot/internal/mrc"
+	"github.com/samber/hot/pkg/metrics"
+)
+
+// missRatioCurveBuckets is the resolution of the curve: capacity × 4 / missRatioCurveBuckets keys.
+const missRatioCurveBuckets = 256
+
+// missRatioCurveRatios are the capacities reported by MissRatioCurve, relative to the current capacity.
+var missRatioCurveRatios = []float64{0.25, 0.5, 1, 2, 4}
+
+// MissRatioCurve is an estimate of the hit ratio the cache would have with other capacities,
+// if it used the LRU eviction policy. It is returned by HotCache.MissRatioCurve.
+type MissRatioCurve struct {
+	// Capacity is the current capacity of the cache.
+	Capacity int `json:"capacity"`
+	// SampleRate is the fraction of the keys sampled. It decreases when more than the maximum number of keys are sampled.
+	SampleRate float64 `json:"sample_rate"`
+	// SampledKeys is the number of keys currently tracked.
+	SampledKeys int `json:"sampled_keys"`
+	// Points are the estimates at 0.25×, 0.5×, 1×, 2× and 4× the current capacity.
+	Points []MissRatioPoint `json:"points"`
+}
+
+// MissRatioPoint is the estimated hit ratio of an LRU cache of a given capacity.
+type MissRatioPoint struct {
+	// CapacityRatio is Capacity relative to the current capacity of the cache.
+	CapacityRatio float64 `json:"capacity_ratio"`
+	Capacity      int     `json:"capacity"`
+	HitRatio      float64 `json:"hit_ratio"`
+}
+
+// MissRatio returns 1 - HitRatio.
+func (p MissRatioPoint) MissRatio() float64 {
+	return 1 - p.HitRatio
+}
+
+// missRatioCurve samples the accesses of the cache with SHARDS.
+// Keys that are not sampled are filtered without locking.
+type missRatioCurve[K comparable] struct {
+	mu       sync.Mutex
+	shards   *mrc.SHARDS[K]
+	capacity int
+
+	threshold atomic.Uint64 // Copy of shards.Threshold()
+	unsampled atomic.Int64  // Accesses not yet reported to shards.AddUnsampled
+
+	// Exporter of the curve, nil when disabled.
+	prometheus *metrics.PrometheusMissRatioCurveCollector
+}
+
+func newMissRatioCurve[K comparable](capacity int, sampleRate float64, maxKeys int) *missRatioCurve[K] {
+	maxSize := int(float64(capacity) * missRatioCurveRatios[len(missRatioCurveRatios)-1])
+
+	m := &missRatioCurve[K]{
+		shards:   mrc.NewSHARDS[K](sampleRate, maxKeys, max(maxSize, 1), missRatioCurveBuckets),
+		capacity: capacity,
+	}
+	m.threshold.Store(m.shards.Threshold())
+
+	return m
+}
+
+// access records the accesses of the keys. A nil curve records nothing.
+func (m *missRatioCurve[K]) access(keys ...K) {
+	if m == nil {
+		return
+	}
+
+	unsampled := int64(0)
+	for _, key := range keys {
+		hash := mrc.Hash(key)
+		if hash%mrc.Modulus >= m.threshold.Load() {
+			unsampled++
+			continue
+		}
+
+		m.mu.Lock()
+		m.shards.AddUnsampled(m.unsampled.Swap(0))
+		m.shards.Access(key, hash)
+		m.threshold.Store(m.shards.Threshold())
+		m.mu.Unlock()
+	}
+
+	if unsampled > 0 {
+		m.unsampled.Add(unsampled)
+	}
+}
+
+func (m *missRatioCurve[K]) curve() MissRatioCurve {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.shards.AddUnsampled(m.unsampled.Swap(0))
+
+	curve := MissRatioCurve{
+		Capacity:    m.capacity,
+		SampleRate:  m.shards.Rate(),
+		SampledKeys: m.shards.Len(),
+		Points:      make([]MissRatioPoint, 0, len(missRatioCurveRatios)),
+	}
+	for _, ratio := range missRatioCurveRatios {
+		capacity := int(float64(m.capacity) * ratio)
+		curve.Points = append(curve.Points, MissRatioPoint{
+			CapacityRatio: ratio,
+			Capacity:      capacity,
+			HitRatio:      m.shards.HitRatio(capacity),
+		})
+	}
+
+	return curve
+}
+
+// MissRatioCurve returns the estimated hit ratio of the cache at 0.25×, 0.5×, 1×, 2× and 4× its capacity,
+// computed from the keys requested through Get and GetMany (and their variants), as if the cache used LRU.
+// It helps choosing the capacity: a flat curve means a smaller cache would do as well.
+// Returns a curve without points when WithMissRatioCurve is not enabled.
+func (c *HotCache[K, V]) MissRatioCurve() MissRatioCurve {
+	if c.missRatioCurve == nil {
+		return MissRatioCurve{}
+	}
+
+	return c.missRatioCurve.curve()
+}
+
+// prometheusMissRatioCurve returns the curve formatted for the Prometheus exporter.
+func (c *HotCache[K, V]) prometheusMissRatioCurve() (float64, []metrics.MissRatioPoint) {
+	curve := c.missRatioCurve.curve()
+
+	points := make([]metrics.MissRatioPoint, 0, len(curve.Points))
+	for _, point := range curve.Points {
+		points = append(points, metrics.MissRatioPoint{CapacityRatio: point.CapacityRatio, HitRatio: point.HitRatio})
+	}
+
+	return curve.SampleRate, points
+}
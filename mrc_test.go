@@ -0,0 +1,97 @@
+package hot
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHotCache_MissRatioCurve(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	is.Equal(MissRatioCurve{}, NewHotCache[int, int](LRU, 10).Build().MissRatioCurve())
+
+	for _, sampleRate := range []float64{1, 0.1} {
+		cache := NewHotCache[int, int](LRU, 1_000).
+			WithStats().
+			WithMissRatioCurve(sampleRate, 100_000).
+			WithLoaders(func(keys []int) (map[int]int, error) {
+				values := make(map[int]int, len(keys))
+				for _, key := range keys {
+					values[key] = key
+				}
+				return values, nil
+			}).
+			Build()
+
+		zipf := rand.NewZipf(rand.New(rand.NewSource(42)), 1.1, 1, 99_999)
+		for i := 0; i < 200_000; i++ {
+			_, _, _ = cache.Get(int(zipf.Uint64()))
+		}
+
+		curve := cache.MissRatioCurve()
+		is.Equal(1_000, curve.Capacity)
+		is.InDelta(sampleRate, curve.SampleRate, 1e-6)
+		is.Positive(curve.SampledKeys)
+		is.Len(curve.Points, 5)
+
+		// The cache is LRU: the estimate at 1× is its own hit ratio.
+		is.Equal(1.0, curve.Points[2].CapacityRatio)
+		is.Equal(1_000, curve.Points[2].Capacity)
+		is.InDelta(cache.Stats().HitRatio(), curve.Points[2].HitRatio, 0.03)
+		is.InDelta(1-curve.Points[2].HitRatio, curve.Points[2].MissRatio(), 1e-9)
+
+		// The hit ratio grows with the capacity.
+		for i := 1; i < len(curve.Points); i++ {
+			is.Equal(curve.Points[i-1].Capacity*2, curve.Points[i].Capacity)
+			is.Greater(curve.Points[i].HitRatio, curve.Points[i-1].HitRatio)
+		}
+	}
+}
+
+func TestWithMissRatioCurve(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewHotCache[int, int](LRU, 100).
+		WithMissRatioCurve(1, 1_000).
+		WithPrometheusMetrics("test-cache").
+		Build()
+
+	// Sequential scans twice the size of the cache never hit an LRU cache of 1×.
+	for i := 0; i < 4; i++ {
+		keys := make([]int, 200)
+		for j := range keys {
+			keys[j] = j
+		}
+		_, _, _ = cache.GetMany(keys)
+	}
+
+	expected := `
+# HELP hot_mrc_hit_ratio Estimated hit ratio of an LRU cache of capacity_ratio times the current capacity
+# TYPE hot_mrc_hit_ratio gauge
+hot_mrc_hit_ratio{capacity_ratio="0.25",name="test-cache"} 0
+hot_mrc_hit_ratio{capacity_ratio="0.5",name="test-cache"} 0
+hot_mrc_hit_ratio{capacity_ratio="1",name="test-cache"} 0
+hot_mrc_hit_ratio{capacity_ratio="2",name="test-cache"} 0.75
+hot_mrc_hit_ratio{capacity_ratio="4",name="test-cache"} 0.75
+# HELP hot_mrc_sample_rate Fraction of the keys sampled to estimate the miss-ratio curve
+# TYPE hot_mrc_sample_rate gauge
+hot_mrc_sample_rate{name="test-cache"} 1
+`
+	is.NoError(testutil.CollectAndCompare(cache, strings.NewReader(expected), "hot_mrc_hit_ratio", "hot_mrc_sample_rate"))
+
+	is.Panics(func() {
+		_ = NewHotCache[int, int](LRU, 100).WithMissRatioCurve(0, 1_000)
+	})
+	is.Panics(func() {
+		_ = NewHotCache[int, int](LRU, 100).WithMissRatioCurve(1.5, 1_000)
+	})
+	is.Panics(func() {
+		_ = NewHotCache[int, int](LRU, 100).WithMissRatioCurve(0.1, 0)
+	})
+}
@@ -0,0 +1,13 @@
+package peer
+
+// Discovery returns the base URLs of every peer, including the current instance.
+// It is called on every lookup and must be fast: the ring is only rebuilt when the list changes.
+type Discovery func() []string
+
+// StaticPeers returns a Discovery with a fixed list of peers.
+func StaticPeers(urls ...string) Discovery {
+	peers := append([]string{}, urls...)
+	return func() []string {
+		return peers
+	}
+}
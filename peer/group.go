@@ -0,0 +1,365 @@
+package peer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/samber/hot"
+)
+
+// DefaultPath is the path on which peers serve each other.
+const DefaultPath = "/_hot/peers"
+
+// defaultReplicas is the number of virtual nodes per peer on the hash ring.
+const defaultReplicas = 64
+
+// defaultMaxRequestBytes is the maximum size of a request body served to other peers.
+const defaultMaxRequestBytes = 1 << 20
+
+// NewGroup creates a group of peers sharing the keyspace of a cache.
+// `self` is the base URL of the current instance, as returned by the discovery function.
+// `cache` is the local cache, with the loaders used for the keys owned by the current instance.
+// Keys and values are sent between peers as JSON.
+func NewGroup[K comparable, V any](self string, discovery Discovery, cache *hot.HotCache[K, V]) GroupConfig[K, V] {
+	return GroupConfig[K, V]{
+		self:      self,
+		discovery: discovery,
+		cache:     cache,
+		path:      DefaultPath,
+		replicas:  defaultReplicas,
+		client:    &http.Client{Timeout: 5 * time.Second},
+
+		maxRequestBytes: defaultMaxRequestBytes,
+	}
+}
+
+// GroupConfig holds the configuration of a Group.
+type GroupConfig[K comparable, V any] struct {
+	self      string
+	discovery Discovery
+	cache     *hot.HotCache[K, V]
+	replica   *hot.HotCache[K, V]
+	path      string
+	replicas  int
+	client    *http.Client
+
+	maxRequestBytes int64
+}
+
+// WithHotReplication stores the values fetched from other peers in a local cache,
+// so that hot keys are served without a network round trip. Use a short TTL, since
+// the replica is not invalidated when the owner changes the value.
+func (cfg GroupConfig[K, V]) WithHotReplication(replica *hot.HotCache[K, V]) GroupConfig[K, V] {
+	cfg.replica = replica
+	return cfg
+}
+
+// WithPath sets the path on which peers serve each other. Default is DefaultPath.
+func (cfg GroupConfig[K, V]) WithPath(path string) GroupConfig[K, V] {
+	cfg.path = path
+	return cfg
+}
+
+// WithVirtualNodes sets the number of virtual nodes per peer on the hash ring.
+// More virtual nodes spread the keys more evenly.
+func (cfg GroupConfig[K, V]) WithVirtualNodes(n int) GroupConfig[K, V] {
+	cfg.replicas = n
+	return cfg
+}
+
+// WithHTTPClient sets the client used to fetch keys from other peers.
+func (cfg GroupConfig[K, V]) WithHTTPClient(client *http.Client) GroupConfig[K, V] {
+	cfg.client = client
+	return cfg
+}
+
+// WithMaxRequestBytes sets the maximum size of a request body served to other peers.
+// Larger requests are rejected with 413 Request Entity Too Large. Default is 1MiB.
+func (cfg GroupConfig[K, V]) WithMaxRequestBytes(n int64) GroupConfig[K, V] {
+	cfg.maxRequestBytes = n
+	return cfg
+}
+
+// Build creates the group.
+func (cfg GroupConfig[K, V]) Build() *Group[K, V] {
+	if cfg.self == "" {
+		panic("self URL is required")
+	}
+	if cfg.discovery == nil {
+		panic("peer discovery is required")
+	}
+	if cfg.cache == nil {
+		panic("cache is required")
+	}
+	if cfg.replicas < 1 {
+		panic("virtual nodes must be greater than or equal to 1")
+	}
+	if cfg.client == nil {
+		panic("http client is required")
+	}
+	if cfg.maxRequestBytes <= 0 {
+		panic("max request bytes must be greater than 0")
+	}
+
+	return &Group[K, V]{
+		self:      strings.TrimSuffix(cfg.self, "/"),
+		discovery: cfg.discovery,
+		cache:     cfg.cache,
+		replica:   cfg.replica,
+		path:      cfg.path,
+		replicas:  cfg.replicas,
+		client:    cfg.client,
+
+		maxRequestBytes: cfg.maxRequestBytes,
+	}
+}
+
+// Group routes each key to the peer owning it on a consistent-hash ring.
+// The owner loads the key with its own cache (loaders and singleflight included),
+// other peers fetch it from the owner over HTTP. When the owner cannot be reached,
+// the key is loaded locally.
+type Group[K comparable, V any] struct {
+	self      string
+	discovery Discovery
+	cache     *hot.HotCache[K, V]
+	replica   *hot.HotCache[K, V]
+	path      string
+	replicas  int
+	client    *http.Client
+
+	maxRequestBytes int64
+
+	// membership is replaced on every change of the peer list. Lookups read it without locking.
+	membership atomic.Pointer[membership]
+	mu         sync.Mutex // Serializes rebuilds of the ring
+}
+
+// membership is the ring built for a peer list, as returned by the discovery function.
+type membership struct {
+	peers []string
+	ring  *ring
+}
+
+var _ http.Handler = (*Group[string, int])(nil)
+
+// request is the body sent to the owner.
+type request[K comparable] struct {
+	Keys []K `json:"keys"`
+}
+
+// response is the body returned by the owner.
+type response[K comparable, V any] struct {
+	Entries []entry[K, V] `json:"entries"`
+	Missing []K           `json:"missing"`
+}
+
+type entry[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+// Get returns a value from the peer owning the key.
+func (g *Group[K, V]) Get(key K) (value V, found bool, err error) {
+	values, _, err := g.GetMany([]K{key})
+	if err != nil {
+		return value, false, err
+	}
+
+	value, found = values[key]
+	return value, found, nil
+}
+
+// GetMany returns values from the peers owning the keys. Keys are grouped by owner,
+// with a single request per peer.
+func (g *Group[K, V]) GetMany(keys []K) (values map[K]V, missing []K, err error) {
+	values = map[K]V{}
+	missing = []K{}
+
+	byOwner, err := g.partition(keys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for owner, ownedKeys := range byOwner {
+		var found map[K]V
+		var notFound []K
+
+		switch {
+		case owner == g.self || owner == "":
+			found, notFound, err = g.cache.GetMany(ownedKeys)
+		case g.replica != nil:
+			found, notFound, err = g.replica.GetManyWithLoaders(ownedKeys, g.fetchOrLoad(owner))
+		default:
+			found, err = g.fetchOrLoad(owner)(ownedKeys)
+			notFound = missingKeys(ownedKeys, found)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for k, v := range found {
+			values[k] = v
+		}
+		missing = append(missing, notFound...)
+	}
+
+	return values, missing, nil
+}
+
+// Owner returns the base URL of the peer owning a key.
+func (g *Group[K, V]) Owner(key K) (string, error) {
+	encoded, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+
+	return g.currentRing().owner(encoded), nil
+}
+
+// ServeHTTP serves the keys requested by other peers, from the local cache.
+// Keys are never forwarded to another peer, even if the ring of the caller is outdated.
+func (g *Group[K, V]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req request[K]
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, g.maxRequestBytes)).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	found, missing, err := g.cache.GetMany(req.Keys)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	res := response[K, V]{
+		Entries: make([]entry[K, V], 0, len(found)),
+		Missing: missing,
+	}
+	for k, v := range found {
+		res.Entries = append(res.Entries, entry[K, V]{Key: k, Value: v})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// partition groups keys by owner.
+func (g *Group[K, V]) partition(keys []K) (map[string][]K, error) {
+	r := g.currentRing()
+
+	byOwner := map[string][]K{}
+	for _, key := range keys {
+		encoded, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		owner := r.owner(encoded)
+		byOwner[owner] = append(byOwner[owner], key)
+	}
+
+	return byOwner, nil
+}
+
+// currentRing returns the ring of the current peer list. The ring is only built when
+// the list returned by the discovery function has changed, then shared by every lookup.
+func (g *Group[K, V]) currentRing() *ring {
+	peers := g.discovery()
+	if m := g.membership.Load(); m != nil && slices.Equal(m.peers, peers) {
+		return m.ring
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if m := g.membership.Load(); m != nil && slices.Equal(m.peers, peers) {
+		return m.ring
+	}
+
+	normalized := make([]string, len(peers))
+	for i, peer := range peers {
+		normalized[i] = strings.TrimSuffix(peer, "/")
+	}
+	slices.Sort(normalized)
+
+	m := &membership{
+		peers: slices.Clone(peers),
+		ring:  newRing(normalized, g.replicas),
+	}
+	g.membership.Store(m)
+
+	return m.ring
+}
+
+// fetchOrLoad returns a loader fetching keys from the owner, and falling back
+// to the local cache when the owner cannot be reached.
+func (g *Group[K, V]) fetchOrLoad(owner string) hot.Loader[K, V] {
+	return func(keys []K) (map[K]V, error) {
+		found, err := g.fetch(owner, keys)
+		if err == nil {
+			return found, nil
+		}
+
+		found, _, err = g.cache.GetMany(keys)
+		return found, err
+	}
+}
+
+// fetch requests keys from a peer.
+func (g *Group[K, V]) fetch(owner string, keys []K) (map[K]V, error) {
+	body, err := json.Marshal(request[K]{Keys: keys})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.client.Post(owner+g.path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("peer %s: unexpected status %d", owner, resp.StatusCode)
+	}
+
+	var res response[K, V]
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, err
+	}
+
+	found := make(map[K]V, len(res.Entries))
+	for _, e := range res.Entries {
+		found[e.Key] = e.Value
+	}
+
+	return found, nil
+}
+
+func missingKeys[K comparable, V any](keys []K, found map[K]V) []K {
+	missing := []K{}
+	for _, key := range keys {
+		if _, ok := found[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
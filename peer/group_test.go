@@ -0,0 +1,262 @@
+package peer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samber/hot"
+	"github.com/stretchr/testify/assert"
+)
+
+type testNode struct {
+	server *httptest.Server
+	cache  *hot.HotCache[string, int]
+	group  *Group[string, int]
+
+	mu     sync.Mutex
+	loaded []string
+}
+
+// newTestCluster starts n peers in the current process.
+func newTestCluster(t *testing.T, n int, replicate bool) []*testNode {
+	nodes := make([]*testNode, n)
+	urls := make([]string, n)
+
+	for i := range nodes {
+		node := &testNode{}
+		node.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			node.group.ServeHTTP(w, r)
+		}))
+		t.Cleanup(node.server.Close)
+
+		node.cache = hot.NewHotCache[string, int](hot.LRU, 100).
+			WithLoaders(func(keys []string) (map[string]int, error) {
+				node.mu.Lock()
+				node.loaded = append(node.loaded, keys...)
+				node.mu.Unlock()
+
+				found := map[string]int{}
+				for _, key := range keys {
+					if key != "missing" {
+						found[key] = len(key)
+					}
+				}
+				return found, nil
+			}).
+			Build()
+
+		nodes[i] = node
+		urls[i] = node.server.URL
+	}
+
+	for _, node := range nodes {
+		cfg := NewGroup(node.server.URL, StaticPeers(urls...), node.cache)
+		if replicate {
+			cfg = cfg.WithHotReplication(hot.NewHotCache[string, int](hot.LRU, 10).WithTTL(time.Minute).Build())
+		}
+		node.group = cfg.Build()
+	}
+
+	return nodes
+}
+
+func TestGroup_Get(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	nodes := newTestCluster(t, 3, false)
+
+	keys := []string{}
+	for i := 0; i < 30; i++ {
+		keys = append(keys, fmt.Sprintf("key-%d", i))
+	}
+
+	for _, node := range nodes {
+		values, missing, err := node.group.GetMany(append(keys, "missing"))
+		is.NoError(err)
+		is.Len(values, 30)
+		is.Equal([]string{"missing"}, missing)
+	}
+
+	// every key is loaded once, by its owner
+	total := 0
+	for _, node := range nodes {
+		for _, key := range node.loaded {
+			owner, err := node.group.Owner(key)
+			is.NoError(err)
+			is.Equal(node.server.URL, owner)
+			if key != "missing" {
+				total++
+			}
+		}
+	}
+	is.Equal(30, total)
+
+	// non-owners do not store values
+	for _, node := range nodes {
+		for _, key := range node.cache.Keys() {
+			owner, _ := node.group.Owner(key)
+			is.Equal(node.server.URL, owner)
+		}
+	}
+
+	value, found, err := nodes[0].group.Get("key-1")
+	is.NoError(err)
+	is.True(found)
+	is.Equal(5, value)
+
+	_, found, err = nodes[0].group.Get("missing")
+	is.NoError(err)
+	is.False(found)
+}
+
+func TestGroup_HotReplication(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	nodes := newTestCluster(t, 2, true)
+
+	// find a key owned by the second node
+	key := ""
+	for i := 0; key == ""; i++ {
+		k := fmt.Sprintf("key-%d", i)
+		if owner, _ := nodes[0].group.Owner(k); owner == nodes[1].server.URL {
+			key = k
+		}
+	}
+
+	value, found, err := nodes[0].group.Get(key)
+	is.NoError(err)
+	is.True(found)
+	is.Equal(len(key), value)
+	is.True(nodes[0].group.replica.Has(key))
+	is.False(nodes[0].cache.Has(key))
+
+	// served by the replica, without asking the owner
+	nodes[1].cache.Purge()
+	_, found, err = nodes[0].group.Get(key)
+	is.NoError(err)
+	is.True(found)
+	is.False(nodes[1].cache.Has(key))
+}
+
+func TestGroup_OwnerUnreachable(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	nodes := newTestCluster(t, 2, false)
+
+	key := ""
+	for i := 0; key == ""; i++ {
+		k := fmt.Sprintf("key-%d", i)
+		if owner, _ := nodes[0].group.Owner(k); owner == nodes[1].server.URL {
+			key = k
+		}
+	}
+
+	nodes[1].server.Close()
+
+	// loaded locally
+	value, found, err := nodes[0].group.Get(key)
+	is.NoError(err)
+	is.True(found)
+	is.Equal(len(key), value)
+	is.Equal([]string{key}, nodes[0].loaded)
+}
+
+func TestGroup_Discovery(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var mu sync.Mutex
+	peers := []string{"http://a"}
+	discovery := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return peers
+	}
+
+	cache := hot.NewHotCache[string, int](hot.LRU, 10).Build()
+	group := NewGroup("http://a/", discovery, cache).Build()
+
+	owner, err := group.Owner("foo")
+	is.NoError(err)
+	is.Equal("http://a", owner)
+
+	mu.Lock()
+	peers = []string{"http://b/"}
+	mu.Unlock()
+
+	owner, err = group.Owner("foo")
+	is.NoError(err)
+	is.Equal("http://b", owner)
+
+	// the ring is built once per peer list
+	r := group.currentRing()
+	is.Same(r, group.currentRing())
+
+	mu.Lock()
+	peers = []string{"http://a", "http://b/"}
+	mu.Unlock()
+	is.NotSame(r, group.currentRing())
+	is.Equal([]string{"http://a", "http://b/"}, group.membership.Load().peers)
+}
+
+func TestGroup_ServeHTTP(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := hot.NewHotCache[string, int](hot.LRU, 10).
+		WithLoaders(func(keys []string) (map[string]int, error) {
+			return nil, errors.New("boom")
+		}).
+		Build()
+	group := NewGroup("http://a", StaticPeers("http://a"), cache).Build()
+
+	server := httptest.NewServer(group)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	is.NoError(err)
+	resp.Body.Close()
+	is.Equal(http.StatusMethodNotAllowed, resp.StatusCode)
+
+	_, err = group.fetch(server.URL, []string{"foo"})
+	is.EqualError(err, fmt.Sprintf("peer %s: unexpected status 502", server.URL))
+
+	resp, err = http.Post(server.URL, "application/json", strings.NewReader("not json"))
+	is.NoError(err)
+	resp.Body.Close()
+	is.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	// request bodies are bounded
+	small := httptest.NewServer(NewGroup("http://a", StaticPeers("http://a"), cache).WithMaxRequestBytes(16).Build())
+	defer small.Close()
+
+	resp, err = http.Post(small.URL, "application/json", strings.NewReader(`{"keys":["`+strings.Repeat("x", 64)+`"]}`))
+	is.NoError(err)
+	resp.Body.Close()
+	is.Equal(http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	is.Panics(func() {
+		_ = NewGroup("", StaticPeers("http://a"), cache).Build()
+	})
+	is.Panics(func() {
+		_ = NewGroup("http://a", nil, cache).Build()
+	})
+	is.Panics(func() {
+		_ = NewGroup[string, int]("http://a", StaticPeers("http://a"), nil).Build()
+	})
+	is.Panics(func() {
+		_ = NewGroup("http://a", StaticPeers("http://a"), cache).WithVirtualNodes(0).Build()
+	})
+	is.Panics(func() {
+		_ = NewGroup("http://a", StaticPeers("http://a"), cache).WithMaxRequestBytes(0).Build()
+	})
+}
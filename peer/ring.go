@@ -0,0 +1,62 @@
+package peer
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// ring is a consistent-hash ring with virtual nodes.
+// It is immutable once built.
+type ring struct {
+	hashes []uint64          // Sorted hashes of the virtual nodes
+	owners map[uint64]string // Virtual node hash -> peer URL
+}
+
+// newRing places `replicas` virtual nodes per peer on the ring.
+func newRing(peers []string, replicas int) *ring {
+	r := &ring{
+		hashes: make([]uint64, 0, len(peers)*replicas),
+		owners: make(map[uint64]string, len(peers)*replicas),
+	}
+
+	for _, peer := range peers {
+		for i := 0; i < replicas; i++ {
+			h := hash([]byte(strconv.Itoa(i) + peer))
+			if _, ok := r.owners[h]; ok {
+				// Collisions are resolved in favor of the smallest URL, so that the ring does not depend on the peer order.
+				if r.owners[h] < peer {
+					continue
+				}
+			} else {
+				r.hashes = append(r.hashes, h)
+			}
+			r.owners[h] = peer
+		}
+	}
+
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+
+	return r
+}
+
+// owner returns the peer owning a key, or "" when the ring is empty.
+func (r *ring) owner(key []byte) string {
+	if len(r.hashes) == 0 {
+		return ""
+	}
+
+	h := hash(key)
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if i == len(r.hashes) {
+		i = 0
+	}
+
+	return r.owners[r.hashes[i]]
+}
+
+func hash(b []byte) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write(b)
+	return h.Sum64()
+}
@@ -0,0 +1,52 @@
+package peer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRing(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	is.Equal("", newRing(nil, 10).owner([]byte("a")))
+
+	peers := []string{"http://a", "http://b", "http://c"}
+	r := newRing(peers, 64)
+	is.Len(r.hashes, 3*64)
+
+	// the ring does not depend on the peer order
+	reversed := newRing([]string{"http://c", "http://b", "http://a"}, 64)
+
+	counts := map[string]int{}
+	for i := 0; i < 3000; i++ {
+		key := []byte(fmt.Sprintf("key-%d", i))
+		owner := r.owner(key)
+		is.Equal(owner, reversed.owner(key))
+		counts[owner]++
+	}
+	for _, peer := range peers {
+		is.Greater(counts[peer], 500)
+	}
+
+	// removing a peer only moves its keys
+	smaller := newRing([]string{"http://a", "http://b"}, 64)
+	for i := 0; i < 1000; i++ {
+		key := []byte(fmt.Sprintf("key-%d", i))
+		if owner := r.owner(key); owner != "http://c" {
+			is.Equal(owner, smaller.owner(key))
+		}
+	}
+}
+
+func TestStaticPeers(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	urls := []string{"http://a", "http://b"}
+	discovery := StaticPeers(urls...)
+	urls[0] = "http://c"
+	is.Equal([]string{"http://a", "http://b"}, discovery())
+}
@@ -0,0 +1,145 @@
+package accesstrace
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOp_String(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	is.Equal("get", OpGet.String())
+	is.Equal("set", OpSet.String())
+	is.Equal("delete", OpDelete.String())
+	is.Equal("unknown", Op(3).String())
+}
+
+func TestReader(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	start := time.Unix(1_700_000_000, 0)
+	records := []Record{
+		{Time: start.Add(time.Millisecond), Op: OpGet, Hit: true, KeyHash: 42, Key: "user:1", ValueSize: 128},
+		{Time: start.Add(2 * time.Millisecond), Op: OpSet, KeyHash: 1<<64 - 1, ValueSize: 64},
+		// Records of concurrent callers may be written out of order.
+		{Time: start, Op: OpDelete, Hit: true, KeyHash: 7},
+	}
+
+	buf := appendHeader(nil, start.UnixNano())
+	prevNano := start.UnixNano()
+	for _, r := range records {
+		buf = appendRecord(buf, r, prevNano)
+		prevNano = r.Time.UnixNano()
+	}
+
+	reader, err := NewReader(bytes.NewReader(buf))
+	is.NoError(err)
+	for _, expected := range records {
+		r, err := reader.Next()
+		is.NoError(err)
+		is.True(expected.Time.Equal(r.Time))
+		r.Time = expected.Time
+		is.Equal(expected, r)
+	}
+	_, err = reader.Next()
+	is.ErrorIs(err, io.EOF)
+
+	// A truncated record ends the trace.
+	reader, err = NewReader(bytes.NewReader(buf[:len(buf)-3]))
+	is.NoError(err)
+	_, _ = reader.Next()
+	_, _ = reader.Next()
+	_, err = reader.Next()
+	is.ErrorIs(err, io.EOF)
+
+	_, err = NewReader(bytes.NewReader([]byte("not a trace file")))
+	is.ErrorIs(err, ErrInvalidFormat)
+	_, err = NewReader(bytes.NewReader(nil))
+	is.ErrorIs(err, ErrInvalidFormat)
+}
+
+func TestRecorder(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "trace")
+	recorder, err := NewRecorder(path, 1_000, 3, 1_000)
+	is.NoError(err)
+
+	now := time.Now()
+	for i := 0; i < 200; i++ {
+		is.True(recorder.Record(Record{Time: now.Add(time.Duration(i)), Op: OpGet, KeyHash: uint64(i), Key: "key"}))
+	}
+	is.EqualValues(200, recorder.Recorded())
+	is.Zero(recorder.Dropped())
+
+	is.NoError(recorder.Close())
+	is.NoError(recorder.Close())
+	is.False(recorder.Record(Record{Time: now}))
+
+	// 200 records of 14 bytes do not fit in 3 files of 1000 bytes: the oldest were dropped by the rotation.
+	files, err := Files(path)
+	is.NoError(err)
+	is.Equal([]string{path + ".2", path + ".1", path}, files)
+
+	hashes := []uint64{}
+	err = ReadFiles(path, func(r Record) error {
+		is.Equal("key", r.Key)
+		hashes = append(hashes, r.KeyHash)
+		return nil
+	})
+	is.NoError(err)
+	is.Less(len(hashes), 200)
+	is.Greater(len(hashes), 100)
+	for i, hash := range hashes {
+		is.Equal(uint64(200-len(hashes)+i), hash)
+	}
+
+	stop := errors.New("stop")
+	is.ErrorIs(ReadFiles(path, func(Record) error { return stop }), stop)
+
+	// Existing files are rotated by a new recorder.
+	recorder, err = NewRecorder(path, 1_000, 3, 1_000)
+	is.NoError(err)
+	is.NoError(recorder.Close())
+
+	info, err := os.Stat(path)
+	is.NoError(err)
+	is.EqualValues(headerSize, info.Size())
+	files, _ = Files(path)
+	is.Len(files, 3)
+}
+
+func TestRecorder_dropsWhenFull(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	recorder, err := NewRecorder(filepath.Join(t.TempDir(), "trace"), 1<<20, 1, 1)
+	is.NoError(err)
+
+	// The buffer holds a single record: some records are dropped, and no call blocks.
+	for i := 0; i < 10_000; i++ {
+		recorder.Record(Record{Time: time.Now(), KeyHash: uint64(i)})
+	}
+	is.Positive(recorder.Dropped())
+	is.EqualValues(10_000, recorder.Recorded()+recorder.Dropped())
+	is.NoError(recorder.Close())
+
+	_, err = NewRecorder("trace", 1, 1, 1)
+	is.Error(err)
+	_, err = NewRecorder("trace", 1_000, 0, 1)
+	is.Error(err)
+	_, err = NewRecorder("trace", 1_000, 1, 0)
+	is.Error(err)
+	_, err = NewRecorder(filepath.Join(t.TempDir(), "missing", "trace"), 1_000, 1, 1)
+	is.Error(err)
+}
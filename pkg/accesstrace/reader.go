@@ -0,0 +1,164 @@
+package accesstrace
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// maxKeyLength bounds the length of the raw keys, to detect corrupted files.
+const maxKeyLength = 1 << 20
+
+// Reader reads the records of a trace file, in order.
+type Reader struct {
+	r        *bufio.Reader
+	prevNano int64
+}
+
+// NewReader creates a reader of a trace file, and checks its header.
+func NewReader(r io.Reader) (*Reader, error) {
+	reader := &Reader{r: bufio.NewReader(r)}
+
+	var header [headerSize]byte
+	if _, err := io.ReadFull(reader.r, header[:]); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFormat, err)
+	}
+	if [8]byte(header[:8]) != magic {
+		return nil, ErrInvalidFormat
+	}
+	reader.prevNano = int64(binary.LittleEndian.Uint64(header[8:]))
+
+	return reader, nil
+}
+
+// Next returns the next record, or io.EOF at the end of the file.
+// A truncated last record, left by a process that did not close its recorder, is reported as io.EOF.
+func (r *Reader) Next() (Record, error) {
+	delta, err := binary.ReadVarint(r.r)
+	if err != nil {
+		return Record{}, eof(err)
+	}
+
+	flags, err := r.r.ReadByte()
+	if err != nil {
+		return Record{}, eof(err)
+	}
+
+	var hash [8]byte
+	if _, err := io.ReadFull(r.r, hash[:]); err != nil {
+		return Record{}, eof(err)
+	}
+
+	record := Record{
+		Op:      Op(flags & flagOpMask),
+		Hit:     flags&flagHit != 0,
+		KeyHash: binary.LittleEndian.Uint64(hash[:]),
+	}
+
+	if flags&flagRawKey != 0 {
+		length, err := binary.ReadUvarint(r.r)
+		if err != nil {
+			return Record{}, eof(err)
+		}
+		if length > maxKeyLength {
+			return Record{}, ErrInvalidFormat
+		}
+
+		key := make([]byte, length)
+		if _, err := io.ReadFull(r.r, key); err != nil {
+			return Record{}, eof(err)
+		}
+		record.Key = string(key)
+	}
+
+	size, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return Record{}, eof(err)
+	}
+	record.ValueSize = int64(size)
+
+	r.prevNano += delta
+	record.Time = time.Unix(0, r.prevNano)
+
+	return record, nil
+}
+
+// eof reports a truncated record as the end of the file.
+func eof(err error) error {
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return io.EOF
+	}
+	return err
+}
+
+// Files returns the files written by a recorder to path, from the oldest to the most recent:
+// path.N, ..., path.1, then path. Missing files are skipped.
+func Files(path string) ([]string, error) {
+	var files []string
+
+	for i := 1; ; i++ {
+		name := rotatedName(path, i)
+		if _, err := os.Stat(name); errors.Is(err, os.ErrNotExist) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		files = append([]string{name}, files...)
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		files = append(files, path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// ReadFiles calls fn with the records of the files written by a recorder to path, from the oldest.
+// It stops at the first error returned by fn.
+func ReadFiles(path string, fn func(Record) error) error {
+	files, err := Files(path)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range files {
+		if err := readFile(name, fn); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func readFile(name string, fn func(Record) error) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader, err := NewReader(f)
+	if err != nil {
+		return err
+	}
+
+	for {
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+}
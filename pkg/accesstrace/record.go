@@ -0,0 +1,97 @@
+// Package accesstrace records the accesses of a cache to rotating files, and reads them back,
+// to replay real workloads offline (see cmd/hot-sim).
+//
+// Files start with an 8-byte magic and the Unix time of the file in nanoseconds. Each record is then:
+//
+//	varint    time elapsed since the previous record, in nanoseconds
+//	byte      flags: operation (bits 0-1), hit (bit 2), raw key (bit 3)
+//	uint64    hash of the key, little endian
+//	uvarint   length of the raw key, followed by the key, when the raw key flag is set
+//	uvarint   size of the value, in bytes
+package accesstrace
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// magic identifies the files of this package, and the version of the format.
+var magic = [8]byte{'H', 'O', 'T', 'T', 'R', 'A', 'C', 1}
+
+// headerSize is the size of the magic and the time of the file.
+const headerSize = 16
+
+// ErrInvalidFormat is returned by the reader when a file is not an access trace, or is corrupted.
+var ErrInvalidFormat = errors.New("accesstrace: invalid format")
+
+// Op is the operation of a record.
+type Op uint8
+
+const (
+	OpGet    Op = iota // Read of a key, through Get, GetMany or their variants
+	OpSet              // Write of a key, through Set, SetMany or their variants
+	OpDelete           // Deletion of a key, through Delete or DeleteMany
+)
+
+// String returns the name of the operation.
+func (op Op) String() string {
+	switch op {
+	case OpGet:
+		return "get"
+	case OpSet:
+		return "set"
+	case OpDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	flagOpMask = 0b0011
+	flagHit    = 0b0100
+	flagRawKey = 0b1000
+)
+
+// Record is an access to a key.
+type Record struct {
+	Time time.Time
+	Op   Op
+	// Hit is true when a read found the key in the cache, or a deletion removed it.
+	Hit bool
+	// KeyHash is the hash of the key, always set. Sampling is based on it.
+	KeyHash uint64
+	// Key is the raw key, empty when only hashes are recorded.
+	Key string
+	// ValueSize is the size of the value read or written, in bytes. 0 on a miss.
+	ValueSize int64
+}
+
+// appendRecord encodes a record after the previous one, written at prevNano.
+func appendRecord(buf []byte, r Record, prevNano int64) []byte {
+	buf = binary.AppendVarint(buf, r.Time.UnixNano()-prevNano)
+
+	flags := byte(r.Op) & flagOpMask
+	if r.Hit {
+		flags |= flagHit
+	}
+	if r.Key != "" {
+		flags |= flagRawKey
+	}
+	buf = append(buf, flags)
+
+	buf = binary.LittleEndian.AppendUint64(buf, r.KeyHash)
+	if r.Key != "" {
+		buf = binary.AppendUvarint(buf, uint64(len(r.Key)))
+		buf = append(buf, r.Key...)
+	}
+
+	return binary.AppendUvarint(buf, uint64(max(r.ValueSize, 0)))
+}
+
+// appendHeader encodes the header of a file started at nowNano.
+func appendHeader(buf []byte, nowNano int64) []byte {
+	buf = append(buf, magic[:]...)
+	return binary.LittleEndian.AppendUint64(buf, uint64(nowNano))
+}
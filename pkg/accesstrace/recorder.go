@@ -0,0 +1,209 @@
+package accesstrace
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// flushInterval is the maximum delay before buffered records are written to the file.
+const flushInterval = time.Second
+
+// Recorder writes records to a file from a background goroutine. When the file reaches its maximum
+// size, it is renamed to path.1, path.1 to path.2, and so on: at most maxFiles files are kept.
+//
+// Records are queued in a bounded buffer: when the buffer is full, records are dropped,
+// so that callers never wait for the disk.
+type Recorder struct {
+	path        string
+	maxFileSize int64
+	maxFiles    int
+
+	records  chan Record
+	recorded atomic.Uint64
+	dropped  atomic.Uint64
+
+	// Owned by the background goroutine.
+	file     *os.File
+	writer   *bufio.Writer
+	size     int64
+	prevNano int64
+	buf      []byte
+	err      error
+
+	closeOnce sync.Once
+	closed    atomic.Bool
+	mu        sync.RWMutex // Excludes Record from Close, which closes the channel
+	done      chan struct{}
+}
+
+// NewRecorder creates a recorder writing to path. Existing files are rotated.
+// bufferSize is the number of records queued before dropping new ones.
+func NewRecorder(path string, maxFileSize int64, maxFiles int, bufferSize int) (*Recorder, error) {
+	if maxFileSize <= headerSize {
+		return nil, errors.New("accesstrace: max file size is too small")
+	}
+	if maxFiles < 1 {
+		return nil, errors.New("accesstrace: max files must be at least 1")
+	}
+	if bufferSize < 1 {
+		return nil, errors.New("accesstrace: buffer size must be at least 1")
+	}
+
+	r := &Recorder{
+		path:        path,
+		maxFileSize: maxFileSize,
+		maxFiles:    maxFiles,
+		records:     make(chan Record, bufferSize),
+		done:        make(chan struct{}),
+	}
+
+	if err := r.rotate(); err != nil {
+		return nil, err
+	}
+
+	go r.loop()
+
+	return r, nil
+}
+
+// Record queues a record without blocking. Returns false when the record is dropped,
+// because the buffer is full or the recorder is closed.
+func (r *Recorder) Record(record Record) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed.Load() {
+		return false
+	}
+
+	select {
+	case r.records <- record:
+		r.recorded.Add(1)
+		return true
+	default:
+		r.dropped.Add(1)
+		return false
+	}
+}
+
+// Recorded returns the number of records queued since the creation of the recorder.
+func (r *Recorder) Recorded() uint64 {
+	return r.recorded.Load()
+}
+
+// Dropped returns the number of records dropped because the buffer was full.
+func (r *Recorder) Dropped() uint64 {
+	return r.dropped.Load()
+}
+
+// Close writes the queued records, and closes the file. It returns the first write error, if any.
+// This method is safe to call multiple times.
+func (r *Recorder) Close() error {
+	r.closeOnce.Do(func() {
+		r.mu.Lock()
+		r.closed.Store(true)
+		close(r.records)
+		r.mu.Unlock()
+	})
+
+	<-r.done
+	return r.err
+}
+
+func (r *Recorder) loop() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case record, ok := <-r.records:
+			if !ok {
+				r.setErr(r.writer.Flush())
+				r.setErr(r.file.Close())
+				return
+			}
+			r.write(record)
+		case <-ticker.C:
+			r.setErr(r.writer.Flush())
+		}
+	}
+}
+
+// write encodes a record, and rotates the file when it is full.
+// After a write error, records are discarded.
+func (r *Recorder) write(record Record) {
+	if r.err != nil {
+		return
+	}
+
+	r.buf = appendRecord(r.buf[:0], record, r.prevNano)
+	if r.size+int64(len(r.buf)) > r.maxFileSize {
+		if r.setErr(r.rotate()) {
+			return
+		}
+		r.buf = appendRecord(r.buf[:0], record, r.prevNano)
+	}
+
+	n, err := r.writer.Write(r.buf)
+	r.size += int64(n)
+	r.prevNano = record.Time.UnixNano()
+	r.setErr(err)
+}
+
+// rotate closes the current file, shifts the previous ones, and starts a new file.
+func (r *Recorder) rotate() error {
+	if r.file != nil {
+		if err := r.writer.Flush(); err != nil {
+			return err
+		}
+		if err := r.file.Close(); err != nil {
+			return err
+		}
+	}
+
+	// path.(maxFiles-1) is overwritten, then every file moves one rank up.
+	for i := r.maxFiles - 1; i >= 1; i-- {
+		from := r.path
+		if i > 1 {
+			from = rotatedName(r.path, i-1)
+		}
+		if err := os.Rename(from, rotatedName(r.path, i)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	nowNano := time.Now().UnixNano()
+	header := appendHeader(nil, nowNano)
+
+	r.file = file
+	r.writer = bufio.NewWriter(file)
+	r.size = int64(len(header))
+	r.prevNano = nowNano
+
+	_, err = r.writer.Write(header)
+	return err
+}
+
+// setErr keeps the first error. Returns true when err is not nil.
+func (r *Recorder) setErr(err error) bool {
+	if err != nil && r.err == nil {
+		r.err = err
+	}
+	return err != nil
+}
+
+func rotatedName(path string, i int) string {
+	return path + "." + strconv.Itoa(i)
+}
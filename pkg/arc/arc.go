@@ -89,6 +89,7 @@ type ARCCache[K comparable, V any] struct {
 
 // Ensure ARCCache implements InMemoryCache interface.
 var _ base.InMemoryCache[string, int] = (*ARCCache[string, int])(nil)
+var _ base.RetentionOrderedCache[string] = (*ARCCache[string, int])(nil)
 
 // Set stores a key-value pair in the cache.
 // If the key already exists, its value is updated and it becomes the most recently used item.
@@ -365,6 +366,23 @@ func (c *ARCCache[K, V]) Keys() []K {
 	return all
 }
 
+// KeysByRetention returns up to n keys: frequently used keys (T2) first, then recently used keys (T1),
+// each list ordered from the most recently used to the least recently used.
+// Ghost entries are not returned. A non-positive n returns all keys.
+func (c *ARCCache[K, V]) KeysByRetention(n int) []K {
+	if n <= 0 || n > c.t1.Len()+c.t2.Len() {
+		n = c.t1.Len() + c.t2.Len()
+	}
+
+	keys := make([]K, 0, n)
+	for _, l := range []*list.List[*entry[K, V]]{c.t2, c.t1} {
+		for e := l.Front(); e != nil && len(keys) < n; e = e.Next() {
+			keys = append(keys, e.Value.key)
+		}
+	}
+	return keys
+}
+
 // Values returns all values currently in the cache.
 func (c *ARCCache[K, V]) Values() []V {
 	all := make([]V, 0, c.t1.Len()+c.t2.Len())
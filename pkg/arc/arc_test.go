@@ -1255,3 +1255,18 @@ func TestARCWithManyGhostEntries(t *testing.T) {
 	is.LessOrEqual(cache.b1.Len(), cache.capacity)
 	is.LessOrEqual(cache.b2.Len(), cache.capacity)
 }
+
+func TestKeysByRetention(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewARCCache[string, int](4)
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+	cache.Get("a")
+
+	// "a" was promoted to T2, "b" and "c" are still in T1
+	is.Equal([]string{"a", "c", "b"}, cache.KeysByRetention(0))
+	is.Equal([]string{"a"}, cache.KeysByRetention(1))
+}
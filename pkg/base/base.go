@@ -73,3 +73,74 @@ type InMemoryCache[K comparable, V any] interface {
 	// SizeBytes returns the total size of all cache entries in bytes.
 	SizeBytes() int64
 }
+
+// RetentionOrderedCache is implemented by caches able to list their keys in the order
+// the eviction policy would keep them. It is optional: callers must type-assert an
+// InMemoryCache and fall back to Keys() when the assertion fails.
+type RetentionOrderedCache[K comparable] interface {
+	// KeysByRetention returns up to n keys, starting with the key the eviction policy
+	// would keep the longest. A non-positive n returns all keys.
+	KeysByRetention(n int) []K
+}
+
+// KeysByRetention returns up to n keys of the cache, in retention order when the cache
+// implements RetentionOrderedCache, or in no particular order otherwise.
+// A non-positive n returns all keys.
+func KeysByRetention[K comparable, V any](cache InMemoryCache[K, V], n int) []K {
+	if ordered, ok := cache.(RetentionOrderedCache[K]); ok {
+		return ordered.KeysByRetention(n)
+	}
+
+	keys := cache.Keys()
+	if n > 0 && n < len(keys) {
+		keys = keys[:n]
+	}
+	return keys
+}
+
+// TieredStorageCache is implemented by caches keeping part of their entries outside of memory,
+// such as the disk tier. It is optional, and wrappers forward it.
+type TieredStorageCache[K comparable, V any] interface {
+	// RangeMemory iterates over the entries held in memory only, without reading the other tiers.
+	// The iteration stops if the function returns false.
+	RangeMemory(f func(K, V) bool)
+}
+
+// RangeMemory iterates over the entries of the cache held in memory. It is equivalent to Range
+// when the cache does not implement TieredStorageCache.
+func RangeMemory[K comparable, V any](cache InMemoryCache[K, V], f func(K, V) bool) {
+	if tiered, ok := cache.(TieredStorageCache[K, V]); ok {
+		tiered.RangeMemory(f)
+		return
+	}
+	cache.Range(f)
+}
+
+// AdaptiveWindowCache is implemented by caches splitting their capacity between an admission
+// window and a main cache, such as W-TinyLFU. It is optional, and wrappers forward it: the
+// boolean results report whether the underlying cache has an admission window.
+type AdaptiveWindowCache interface {
+	// WindowRatio returns the fraction of the capacity used by the admission window.
+	WindowRatio() (float64, bool)
+	// PinWindowRatio resizes the admission window to a fraction of the capacity, in (0, 1),
+	// and stops its adaptation to the workload.
+	PinWindowRatio(ratio float64) bool
+}
+
+// WindowRatio returns the fraction of the capacity used by the admission window of the cache,
+// or false when the cache has no admission window.
+func WindowRatio[K comparable, V any](cache InMemoryCache[K, V]) (float64, bool) {
+	if adaptive, ok := cache.(AdaptiveWindowCache); ok {
+		return adaptive.WindowRatio()
+	}
+	return 0, false
+}
+
+// PinWindowRatio resizes the admission window of the cache to a fraction of the capacity,
+// in (0, 1), and stops its adaptation. It returns false when the cache has no admission window.
+func PinWindowRatio[K comparable, V any](cache InMemoryCache[K, V], ratio float64) bool {
+	if adaptive, ok := cache.(AdaptiveWindowCache); ok {
+		return adaptive.PinWindowRatio(ratio)
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// unorderedCache is an InMemoryCache that does not implement RetentionOrderedCache.
+type unorderedCache struct {
+	InMemoryCache[string, int]
+	keys []string
+}
+
+func (c *unorderedCache) Keys() []string {
+	return c.keys
+}
+
+func TestKeysByRetention_Fallback(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := &unorderedCache{keys: []string{"a", "b", "c"}}
+
+	is.Equal([]string{"a", "b", "c"}, KeysByRetention[string, int](cache, 0))
+	is.Equal([]string{"a", "b"}, KeysByRetention[string, int](cache, 2))
+	is.Equal([]string{"a", "b", "c"}, KeysByRetention[string, int](cache, 5))
+}
@@ -6,4 +6,5 @@ const (
 	CacheModeMain CacheMode = "main"
 	// CacheModeShared  CacheMode = "shared".
 	CacheModeMissing CacheMode = "missing"
+	CacheModeDisk    CacheMode = "disk"
 )
@@ -0,0 +1,27 @@
+package disk
+
+import "encoding/json"
+
+// Codec serializes the values stored on disk.
+// Implementations must be safe for concurrent use.
+type Codec[V any] interface {
+	Marshal(value V) ([]byte, error)
+	Unmarshal(data []byte) (V, error)
+}
+
+// JSONCodec is a Codec based on encoding/json.
+type JSONCodec[V any] struct{}
+
+var _ Codec[int] = (*JSONCodec[int])(nil)
+
+// Marshal encodes the value as JSON.
+func (JSONCodec[V]) Marshal(value V) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Unmarshal decodes a JSON-encoded value.
+func (JSONCodec[V]) Unmarshal(data []byte) (V, error) {
+	var value V
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
@@ -0,0 +1,30 @@
+package disk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONCodec(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	type value struct {
+		A string
+		B int
+	}
+
+	codec := JSONCodec[value]{}
+
+	data, err := codec.Marshal(value{A: "a", B: 42})
+	is.NoError(err)
+	is.JSONEq(`{"A":"a","B":42}`, string(data))
+
+	v, err := codec.Unmarshal(data)
+	is.NoError(err)
+	is.Equal(value{A: "a", B: 42}, v)
+
+	_, err = codec.Unmarshal([]byte("not json"))
+	is.Error(err)
+}
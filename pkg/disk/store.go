@@ -0,0 +1,520 @@
+package disk
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/samber/hot/internal"
+	"github.com/samber/hot/internal/container/list"
+	"github.com/samber/hot/pkg/base"
+	"github.com/samber/hot/pkg/metrics"
+)
+
+// recordHeaderSize is the size of the length prefix written before each value.
+const recordHeaderSize = 4
+
+// minCompactionBytes is the minimum segment size before automatic compaction is considered.
+const minCompactionBytes = 1 << 20
+
+// ErrClosed is returned when the store is used after Close.
+var ErrClosed = errors.New("disk store is closed")
+
+// record locates a value in the segment file.
+type record[K comparable] struct {
+	key        K
+	offset     int64 // Offset of the payload (after the length prefix)
+	length     int64 // Length of the payload
+	expiryNano int64 // 0 means no expiration
+}
+
+// NewStore creates a disk store backed by an append-only segment file at path.
+// The file is truncated: entries do not survive a restart, since expiration times are process-relative.
+// When more than `capacity` entries are stored, the oldest entries are dropped.
+// `expiry` returns the expiration time of a value in internal.NowNano() units (0 = never), it can be nil.
+// `onEviction` is called when an entry is dropped because of capacity or expiration, once the store is unlocked.
+// `collector` receives the disk tier metrics, it can be nil.
+func NewStore[K comparable, V any](path string, capacity int, codec Codec[V], expiry func(V) int64, onEviction base.EvictionCallback[K, V], collector metrics.Collector) (*Store[K, V], error) {
+	if capacity <= 0 {
+		panic("capacity must be greater than 0")
+	}
+	if codec == nil {
+		panic("codec is required")
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	if collector == nil {
+		collector = &metrics.NoOpCollector{}
+	}
+
+	return &Store[K, V]{
+		path:     path,
+		file:     file,
+		capacity: capacity,
+
+		codec:      codec,
+		expiry:     expiry,
+		onEviction: onEviction,
+		collector:  collector,
+
+		ll:    list.New[*record[K]](),
+		index: make(map[K]*list.Element[*record[K]]),
+	}, nil
+}
+
+// Store is an append-only segment file with an in-memory index.
+// Overwritten and deleted values leave garbage in the file, which is reclaimed by compaction.
+// It is safe for concurrent access.
+type Store[K comparable, V any] struct {
+	noCopy internal.NoCopy // Prevents accidental copying of the store
+
+	mu       sync.Mutex
+	path     string
+	file     *os.File // nil once closed
+	capacity int
+
+	codec      Codec[V]
+	expiry     func(V) int64
+	onEviction base.EvictionCallback[K, V]
+	collector  metrics.Collector
+
+	ll    *list.List[*record[K]]          // Insertion order (oldest at front)
+	index map[K]*list.Element[*record[K]] // Map for O(1) key lookups to records
+
+	fileSize  int64 // Bytes written to the segment file, including garbage
+	liveBytes int64 // Bytes of the records referenced by the index
+
+	evicted []evictedEntry[K, V] // Entries evicted under the lock, passed to onEviction once unlocked
+}
+
+// evictedEntry is an entry waiting for the eviction callback.
+type evictedEntry[K comparable, V any] struct {
+	reason base.EvictionReason
+	key    K
+	value  V
+}
+
+// Set stores a value on disk, replacing any previous value for the key.
+// If the store is at capacity, the oldest entry is dropped.
+func (s *Store[K, V]) Set(key K, value V) error {
+	data, err := s.codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	var expiryNano int64
+	if s.expiry != nil {
+		expiryNano = s.expiry(value)
+	}
+
+	defer s.notifyEvictions()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return ErrClosed
+	}
+
+	buf := make([]byte, recordHeaderSize+len(data))
+	binary.BigEndian.PutUint32(buf, uint32(len(data)))
+	copy(buf[recordHeaderSize:], data)
+
+	if _, err := s.file.WriteAt(buf, s.fileSize); err != nil {
+		return err
+	}
+
+	if e, ok := s.index[key]; ok {
+		s.removeElement(e)
+	}
+
+	rec := &record[K]{
+		key:        key,
+		offset:     s.fileSize + recordHeaderSize,
+		length:     int64(len(data)),
+		expiryNano: expiryNano,
+	}
+	s.index[key] = s.ll.PushBack(rec)
+	s.fileSize += int64(len(buf))
+	s.liveBytes += int64(len(buf))
+	s.collector.IncInsertion()
+
+	for s.ll.Len() > s.capacity {
+		s.evictElement(s.ll.Front(), base.EvictionReasonCapacity)
+	}
+
+	// The value is written and indexed at this point: a failed compaction leaves
+	// garbage in the segment file, and is attempted again on the next write.
+	_ = s.maybeCompact()
+
+	return nil
+}
+
+// Get returns a value from disk. Expired values are dropped and reported as missing.
+func (s *Store[K, V]) Get(key K) (value V, ok bool, err error) {
+	defer s.notifyEvictions()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.lookup(key)
+	if !ok {
+		s.collector.IncMiss()
+		return value, false, nil
+	}
+
+	value, err = s.read(e.Value)
+	if err != nil {
+		return value, false, err
+	}
+
+	s.collector.IncHit()
+	return value, true, nil
+}
+
+// Take returns a value from disk and removes it from the store.
+// This is used to promote a value back to memory.
+func (s *Store[K, V]) Take(key K) (value V, ok bool, err error) {
+	defer s.notifyEvictions()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.lookup(key)
+	if !ok {
+		s.collector.IncMiss()
+		return value, false, nil
+	}
+
+	value, err = s.read(e.Value)
+	s.removeElement(e)
+	if err != nil {
+		return value, false, err
+	}
+
+	s.collector.IncHit()
+	return value, true, nil
+}
+
+// Has checks if a non-expired value is stored for the key.
+func (s *Store[K, V]) Has(key K) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.index[key]
+	return ok && !isExpired(e.Value, internal.NowNano())
+}
+
+// Delete removes a value from disk.
+// Returns true if the key was found and removed, false otherwise.
+func (s *Store[K, V]) Delete(key K) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.index[key]
+	if !ok {
+		return false
+	}
+
+	s.removeElement(e)
+	s.collector.IncEviction(base.EvictionReasonManual)
+	return true
+}
+
+// discard removes a value from disk without counting an eviction, when the key is written to another tier.
+// Returns true if the key was found and removed, false otherwise.
+func (s *Store[K, V]) discard(key K) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.index[key]
+	if !ok {
+		return false
+	}
+
+	s.removeElement(e)
+	return true
+}
+
+// DeleteExpired drops the expired entries, using the expiration times kept in the index.
+// Values are only read from disk when an eviction callback is registered.
+// Returns the number of entries dropped.
+func (s *Store[K, V]) DeleteExpired() int {
+	defer s.notifyEvictions()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.deleteExpired(internal.NowNano())
+}
+
+// Keys returns the keys of all non-expired values, from the oldest to the newest.
+func (s *Store[K, V]) Keys() []K {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	nowNano := internal.NowNano()
+	keys := make([]K, 0, s.ll.Len())
+	for e := s.ll.Front(); e != nil; e = e.Next() {
+		if !isExpired(e.Value, nowNano) {
+			keys = append(keys, e.Value.key)
+		}
+	}
+	return keys
+}
+
+// Range iterates over all non-expired values, from the oldest to the newest.
+// Values that cannot be read or decoded are skipped. The iteration stops if the function returns false.
+// Warning: every value is read from disk and decoded.
+func (s *Store[K, V]) Range(f func(K, V) bool) {
+	for _, key := range s.Keys() {
+		s.mu.Lock()
+		e, ok := s.lookup(key)
+		var value V
+		var err error
+		if ok {
+			value, err = s.read(e.Value)
+		}
+		s.mu.Unlock()
+		s.notifyEvictions()
+
+		if !ok || err != nil {
+			continue
+		}
+
+		if !f(key, value) {
+			return
+		}
+	}
+}
+
+// Len returns the number of entries stored on disk, including expired entries not collected yet.
+func (s *Store[K, V]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	length := s.ll.Len()
+	s.collector.UpdateLength(int64(length))
+	return length
+}
+
+// SizeBytes returns the number of bytes used by live records in the segment file.
+func (s *Store[K, V]) SizeBytes() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.collector.UpdateSizeBytes(s.liveBytes)
+	return s.liveBytes
+}
+
+// Capacity returns the maximum number of entries the store can hold.
+func (s *Store[K, V]) Capacity() int {
+	return s.capacity
+}
+
+// Purge removes all entries and truncates the segment file.
+func (s *Store[K, V]) Purge() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ll.Len() > 0 {
+		s.collector.AddEvictions(base.EvictionReasonManual, int64(s.ll.Len()))
+	}
+
+	s.ll = list.New[*record[K]]()
+	s.index = make(map[K]*list.Element[*record[K]])
+	s.fileSize = 0
+	s.liveBytes = 0
+
+	if s.file == nil {
+		return nil
+	}
+
+	return s.file.Truncate(0)
+}
+
+// Compact rewrites the segment file with live records only, dropping expired entries.
+func (s *Store[K, V]) Compact() error {
+	defer s.notifyEvictions()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.compact()
+}
+
+// Close closes the segment file and removes it. The store cannot be used afterwards.
+// This method is safe to call multiple times.
+func (s *Store[K, V]) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Close()
+	s.file = nil
+	if rmErr := os.Remove(s.path); err == nil && !errors.Is(rmErr, os.ErrNotExist) {
+		err = rmErr
+	}
+
+	return err
+}
+
+// lookup returns the element of a non-expired key, dropping the key if it has expired.
+// The caller must hold the lock.
+func (s *Store[K, V]) lookup(key K) (*list.Element[*record[K]], bool) {
+	e, ok := s.index[key]
+	if !ok {
+		return nil, false
+	}
+
+	if isExpired(e.Value, internal.NowNano()) {
+		s.evictElement(e, base.EvictionReasonTTL)
+		return nil, false
+	}
+
+	return e, true
+}
+
+// read loads and decodes a record from the segment file.
+// The caller must hold the lock.
+func (s *Store[K, V]) read(rec *record[K]) (value V, err error) {
+	if s.file == nil {
+		return value, ErrClosed
+	}
+
+	data := make([]byte, rec.length)
+	if _, err := s.file.ReadAt(data, rec.offset); err != nil {
+		return value, err
+	}
+
+	return s.codec.Unmarshal(data)
+}
+
+// removeElement removes a record from the index. Its bytes become garbage until the next compaction.
+// The caller must hold the lock.
+func (s *Store[K, V]) removeElement(e *list.Element[*record[K]]) {
+	s.ll.Remove(e)
+	delete(s.index, e.Value.key)
+	s.liveBytes -= e.Value.length + recordHeaderSize
+}
+
+// evictElement removes a record and queues it for the eviction callback.
+// The value is only read from disk when a callback is registered.
+// The caller must hold the lock, and call notifyEvictions once unlocked.
+func (s *Store[K, V]) evictElement(e *list.Element[*record[K]], reason base.EvictionReason) {
+	var value V
+	var err error
+	if s.onEviction != nil {
+		value, err = s.read(e.Value)
+	}
+
+	s.removeElement(e)
+	s.collector.IncEviction(reason)
+
+	if s.onEviction != nil && err == nil {
+		s.evicted = append(s.evicted, evictedEntry[K, V]{reason: reason, key: e.Value.key, value: value})
+	}
+}
+
+// notifyEvictions calls the eviction callback for the entries evicted since the last call.
+// The callback runs without the lock, so that it can use the store.
+func (s *Store[K, V]) notifyEvictions() {
+	if s.onEviction == nil {
+		return
+	}
+
+	s.mu.Lock()
+	evicted := s.evicted
+	s.evicted = nil
+	s.mu.Unlock()
+
+	for _, entry := range evicted {
+		s.onEviction(entry.reason, entry.key, entry.value)
+	}
+}
+
+// deleteExpired drops the entries expired at nowNano and returns their count.
+// The caller must hold the lock.
+func (s *Store[K, V]) deleteExpired(nowNano int64) int {
+	deleted := 0
+	for e := s.ll.Front(); e != nil; {
+		next := e.Next()
+		if isExpired(e.Value, nowNano) {
+			s.evictElement(e, base.EvictionReasonTTL)
+			deleted++
+		}
+		e = next
+	}
+	return deleted
+}
+
+// maybeCompact compacts the segment file when garbage exceeds live data.
+// The caller must hold the lock.
+func (s *Store[K, V]) maybeCompact() error {
+	if s.fileSize < minCompactionBytes || s.fileSize-s.liveBytes <= s.liveBytes {
+		return nil
+	}
+
+	return s.compact()
+}
+
+// compact copies live records to a new segment file, then atomically replaces the current one.
+// The caller must hold the lock.
+func (s *Store[K, V]) compact() error {
+	if s.file == nil {
+		return ErrClosed
+	}
+
+	s.deleteExpired(internal.NowNano())
+
+	tmpPath := s.path + ".compact"
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	var offset int64
+	offsets := make([]int64, 0, s.ll.Len())
+	for e := s.ll.Front(); e != nil; e = e.Next() {
+		buf := make([]byte, recordHeaderSize+e.Value.length)
+		if _, err = s.file.ReadAt(buf, e.Value.offset-recordHeaderSize); err != nil {
+			break
+		}
+		if _, err = tmp.WriteAt(buf, offset); err != nil {
+			break
+		}
+
+		offsets = append(offsets, offset+recordHeaderSize)
+		offset += int64(len(buf))
+	}
+
+	if err == nil {
+		err = os.Rename(tmpPath, s.path)
+	}
+	if err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	_ = s.file.Close()
+	s.file = tmp
+
+	i := 0
+	for e := s.ll.Front(); e != nil; e = e.Next() {
+		e.Value.offset = offsets[i]
+		i++
+	}
+	s.fileSize = offset
+	s.liveBytes = offset
+
+	return nil
+}
+
+// isExpired checks if the record has expired based on the current time.
+func isExpired[K comparable](rec *record[K], nowNano int64) bool {
+	return rec.expiryNano > 0 && nowNano > rec.expiryNano
+}
@@ -0,0 +1,299 @@
+package disk
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/samber/hot/internal"
+	"github.com/samber/hot/pkg/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStore(t *testing.T, capacity int, expiry func(int) int64, onEviction base.EvictionCallback[string, int]) *Store[string, int] {
+	store, err := NewStore(filepath.Join(t.TempDir(), "segment.seg"), capacity, JSONCodec[int]{}, expiry, onEviction, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestNewStore(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "segment.seg")
+	store, err := NewStore[string, int](path, 10, JSONCodec[int]{}, nil, nil, nil)
+	is.NoError(err)
+	is.Equal(10, store.Capacity())
+	is.Equal(0, store.Len())
+	is.Equal(int64(0), store.SizeBytes())
+	is.FileExists(path)
+
+	is.NoError(store.Close())
+	is.NoError(store.Close())
+	is.NoFileExists(path)
+
+	is.ErrorIs(store.Set("a", 1), ErrClosed)
+
+	_, err = NewStore[string, int](filepath.Join(t.TempDir(), "missing", "segment.seg"), 10, JSONCodec[int]{}, nil, nil, nil)
+	is.Error(err)
+
+	is.Panics(func() {
+		_, _ = NewStore[string, int](path, 0, JSONCodec[int]{}, nil, nil, nil)
+	})
+	is.Panics(func() {
+		_, _ = NewStore[string, int](path, 10, nil, nil, nil, nil)
+	})
+}
+
+func TestStore_SetGet(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	store := newTestStore(t, 10, nil, nil)
+
+	is.NoError(store.Set("a", 1))
+	is.NoError(store.Set("b", 2))
+	is.NoError(store.Set("a", 3))
+	is.Equal(2, store.Len())
+	is.Equal([]string{"b", "a"}, store.Keys())
+	is.True(store.Has("a"))
+	is.False(store.Has("c"))
+
+	v, ok, err := store.Get("a")
+	is.NoError(err)
+	is.True(ok)
+	is.Equal(3, v)
+
+	_, ok, err = store.Get("c")
+	is.NoError(err)
+	is.False(ok)
+
+	v, ok, err = store.Take("b")
+	is.NoError(err)
+	is.True(ok)
+	is.Equal(2, v)
+	is.False(store.Has("b"))
+
+	is.True(store.Delete("a"))
+	is.False(store.Delete("a"))
+	is.Equal(0, store.Len())
+}
+
+func TestStore_Capacity(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	evicted := map[string]int{}
+	store := newTestStore(t, 2, nil, func(reason base.EvictionReason, k string, v int) {
+		is.Equal(base.EvictionReasonCapacity, reason)
+		evicted[k] = v
+	})
+
+	is.NoError(store.Set("a", 1))
+	is.NoError(store.Set("b", 2))
+	is.NoError(store.Set("c", 3))
+	is.Equal(2, store.Len())
+	is.Equal([]string{"b", "c"}, store.Keys())
+	is.Equal(map[string]int{"a": 1}, evicted)
+}
+
+func TestStore_EvictionCallbackUnlocked(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	// The callback uses the store: it must not be called under the lock.
+	var store *Store[string, int]
+	lengths := []int{}
+	store = newTestStore(t, 1,
+		func(v int) int64 {
+			if v < 0 {
+				return internal.NowNano() - 1
+			}
+			return 0
+		},
+		func(reason base.EvictionReason, k string, v int) {
+			lengths = append(lengths, store.Len())
+		},
+	)
+
+	is.NoError(store.Set("a", 1))
+	is.NoError(store.Set("b", -1))
+	_, ok, err := store.Get("b")
+	is.NoError(err)
+	is.False(ok)
+	is.Equal([]int{1, 0}, lengths)
+}
+
+func TestStore_TTL(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	evicted := []string{}
+	store := newTestStore(t, 10,
+		func(v int) int64 {
+			if v < 0 {
+				return internal.NowNano() - 1
+			}
+			return 0
+		},
+		func(reason base.EvictionReason, k string, v int) {
+			is.Equal(base.EvictionReasonTTL, reason)
+			evicted = append(evicted, k)
+		},
+	)
+
+	is.NoError(store.Set("a", 1))
+	is.NoError(store.Set("b", -1))
+	is.True(store.Has("a"))
+	is.False(store.Has("b"))
+	is.Equal([]string{"a"}, store.Keys())
+
+	_, ok, err := store.Get("b")
+	is.NoError(err)
+	is.False(ok)
+	is.Equal([]string{"b"}, evicted)
+	is.Equal(1, store.Len())
+}
+
+func TestStore_Range(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	store := newTestStore(t, 10, nil, nil)
+	is.NoError(store.Set("a", 1))
+	is.NoError(store.Set("b", 2))
+	is.NoError(store.Set("c", 3))
+
+	keys := []string{}
+	values := []int{}
+	store.Range(func(k string, v int) bool {
+		keys = append(keys, k)
+		values = append(values, v)
+		return k != "b"
+	})
+	is.Equal([]string{"a", "b"}, keys)
+	is.Equal([]int{1, 2}, values)
+}
+
+func TestStore_PurgeCompact(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "segment.seg")
+	store, err := NewStore[string, string](path, 10, JSONCodec[string]{}, nil, nil, nil)
+	is.NoError(err)
+	defer store.Close()
+
+	value := strings.Repeat("x", 100)
+	for i := 0; i < 10; i++ {
+		is.NoError(store.Set("a", value))
+	}
+	is.NoError(store.Set("b", value))
+
+	info, err := os.Stat(path)
+	is.NoError(err)
+	is.Greater(info.Size(), store.SizeBytes())
+
+	is.NoError(store.Compact())
+	info, err = os.Stat(path)
+	is.NoError(err)
+	is.Equal(store.SizeBytes(), info.Size())
+
+	v, ok, err := store.Get("a")
+	is.NoError(err)
+	is.True(ok)
+	is.Equal(value, v)
+	v, ok, err = store.Get("b")
+	is.NoError(err)
+	is.True(ok)
+	is.Equal(value, v)
+
+	is.NoError(store.Purge())
+	is.Equal(0, store.Len())
+	is.Equal(int64(0), store.SizeBytes())
+	info, err = os.Stat(path)
+	is.NoError(err)
+	is.Equal(int64(0), info.Size())
+}
+
+// countingCodec counts the values decoded by the store.
+type countingCodec struct {
+	JSONCodec[int]
+	decoded *int64
+}
+
+func (c countingCodec) Unmarshal(data []byte) (int, error) {
+	atomic.AddInt64(c.decoded, 1)
+	return c.JSONCodec.Unmarshal(data)
+}
+
+func TestStore_DeleteExpired(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var decoded int64
+	expiry := func(v int) int64 {
+		if v < 0 {
+			return internal.NowNano() - 1
+		}
+		return 0
+	}
+
+	store, err := NewStore[string, int](filepath.Join(t.TempDir(), "segment.seg"), 10, countingCodec{decoded: &decoded}, expiry, nil, nil)
+	is.NoError(err)
+	defer store.Close()
+
+	is.NoError(store.Set("a", 1))
+	is.NoError(store.Set("b", -1))
+	is.NoError(store.Set("c", -2))
+
+	// expiration times are kept in the index: nothing is decoded
+	is.Equal(2, store.DeleteExpired())
+	is.Equal(0, store.DeleteExpired())
+	is.Equal(int64(0), atomic.LoadInt64(&decoded))
+	is.Equal([]string{"a"}, store.Keys())
+
+	// values are decoded for the eviction callback only
+	evicted := map[string]int{}
+	store, err = NewStore[string, int](filepath.Join(t.TempDir(), "segment.seg"), 10, countingCodec{decoded: &decoded}, expiry, func(reason base.EvictionReason, k string, v int) {
+		is.Equal(base.EvictionReasonTTL, reason)
+		evicted[k] = v
+	}, nil)
+	is.NoError(err)
+	defer store.Close()
+
+	is.NoError(store.Set("a", 1))
+	is.NoError(store.Set("b", -1))
+	is.Equal(1, store.DeleteExpired())
+	is.Equal(map[string]int{"b": -1}, evicted)
+	is.Equal(int64(1), atomic.LoadInt64(&decoded))
+}
+
+func TestStore_SetIgnoresCompactionFailure(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "segment.seg")
+	store, err := NewStore[string, string](path, 10, JSONCodec[string]{}, nil, nil, nil)
+	is.NoError(err)
+	defer store.Close()
+
+	// the compacted segment cannot be created
+	is.NoError(os.Mkdir(path+".compact", 0o700))
+
+	value := strings.Repeat("x", minCompactionBytes/2)
+	for i := 0; i < 4; i++ {
+		is.NoError(store.Set("a", value))
+	}
+	is.Error(store.Compact())
+
+	v, ok, err := store.Get("a")
+	is.NoError(err)
+	is.True(ok)
+	is.Equal(value, v)
+}
@@ -0,0 +1,272 @@
+package disk
+
+import (
+	"sync"
+
+	"github.com/samber/hot/pkg/base"
+)
+
+// NewTieredCache creates a two-tier cache: the in-memory cache is the first tier and the disk store is the second tier.
+// Values evicted from memory for capacity must be sent to the store with Demote, usually from the eviction callback
+// of the in-memory cache. Values found on disk are promoted back to memory.
+func NewTieredCache[K comparable, V any](memory base.InMemoryCache[K, V], store *Store[K, V]) *TieredCache[K, V] {
+	return &TieredCache[K, V]{
+		memory: memory,
+		store:  store,
+	}
+}
+
+// TieredCache serves reads from memory first, then from disk.
+// A key is stored in a single tier at a time.
+// It is as safe for concurrent access as the underlying in-memory cache.
+type TieredCache[K comparable, V any] struct {
+	// mu serializes promotions from disk with writes, so that a value read from disk
+	// never overwrites a newer value written to memory in the meantime.
+	// Reads served from memory do not take it.
+	mu     sync.Mutex
+	memory base.InMemoryCache[K, V]
+	store  *Store[K, V]
+}
+
+// Ensure TieredCache implements InMemoryCache interface.
+var _ base.InMemoryCache[string, int] = (*TieredCache[string, int])(nil)
+var _ base.RetentionOrderedCache[string] = (*TieredCache[string, int])(nil)
+var _ base.AdaptiveWindowCache = (*TieredCache[string, int])(nil)
+var _ base.TieredStorageCache[string, int] = (*TieredCache[string, int])(nil)
+
+// Demote moves a value evicted from memory to disk.
+// Returns false if the value could not be written.
+func (c *TieredCache[K, V]) Demote(key K, value V) bool {
+	return c.store.Set(key, value) == nil
+}
+
+// Set stores a key-value pair in memory and removes any stale copy from disk.
+func (c *TieredCache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.store.discard(key)
+	c.memory.Set(key, value)
+}
+
+// Has checks if a key exists in memory or on disk.
+func (c *TieredCache[K, V]) Has(key K) bool {
+	return c.memory.Has(key) || c.store.Has(key)
+}
+
+// Get retrieves a value from memory, or from disk. Values found on disk are promoted to memory.
+func (c *TieredCache[K, V]) Get(key K) (value V, ok bool) {
+	if value, ok = c.memory.Get(key); ok {
+		return value, true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The key may have been written since the first lookup.
+	if value, ok = c.memory.Get(key); ok {
+		return value, true
+	}
+
+	// Read errors are reported as misses, the value will be loaded again.
+	value, ok, _ = c.store.Take(key)
+	if ok {
+		c.memory.Set(key, value)
+	}
+
+	return value, ok
+}
+
+// Peek retrieves a value from memory, or from disk, without updating access order nor promoting it.
+func (c *TieredCache[K, V]) Peek(key K) (value V, ok bool) {
+	if value, ok = c.memory.Peek(key); ok {
+		return value, true
+	}
+
+	value, ok, _ = c.store.Get(key)
+	return value, ok
+}
+
+// Keys returns all keys stored in memory and on disk.
+func (c *TieredCache[K, V]) Keys() []K {
+	return append(c.memory.Keys(), c.store.Keys()...)
+}
+
+// KeysByRetention returns up to n keys: keys stored in memory first, in the retention order of the in-memory tier,
+// then keys stored on disk, from the newest to the oldest. A non-positive n returns all keys.
+func (c *TieredCache[K, V]) KeysByRetention(n int) []K {
+	keys := base.KeysByRetention(c.memory, n)
+
+	onDisk := c.store.Keys()
+	for i := len(onDisk) - 1; i >= 0 && (n <= 0 || len(keys) < n); i-- {
+		keys = append(keys, onDisk[i])
+	}
+	return keys
+}
+
+// WindowRatio returns the fraction of the capacity used by the admission window of the in-memory tier.
+func (c *TieredCache[K, V]) WindowRatio() (float64, bool) {
+	return base.WindowRatio(c.memory)
+}
+
+// PinWindowRatio pins the admission window of the in-memory tier to a fraction of its capacity.
+func (c *TieredCache[K, V]) PinWindowRatio(ratio float64) bool {
+	return base.PinWindowRatio(c.memory, ratio)
+}
+
+// Values returns all values stored in memory and on disk.
+// Warning: every value stored on disk is read and decoded.
+func (c *TieredCache[K, V]) Values() []V {
+	values := c.memory.Values()
+	c.store.Range(func(_ K, v V) bool {
+		values = append(values, v)
+		return true
+	})
+	return values
+}
+
+// All returns all key-value pairs stored in memory and on disk.
+// Warning: every value stored on disk is read and decoded.
+func (c *TieredCache[K, V]) All() map[K]V {
+	all := c.memory.All()
+	c.store.Range(func(k K, v V) bool {
+		all[k] = v
+		return true
+	})
+	return all
+}
+
+// Range iterates over all key-value pairs, in memory first, then on disk.
+// The iteration stops if the function returns false.
+// Warning: every value stored on disk is read and decoded.
+func (c *TieredCache[K, V]) Range(f func(K, V) bool) {
+	stopped := false
+	c.memory.Range(func(k K, v V) bool {
+		stopped = !f(k, v)
+		return !stopped
+	})
+	if stopped {
+		return
+	}
+
+	c.store.Range(f)
+}
+
+// RangeMemory iterates over the key-value pairs held in memory, without reading the disk tier.
+// The iteration stops if the function returns false.
+func (c *TieredCache[K, V]) RangeMemory(f func(K, V) bool) {
+	c.memory.Range(f)
+}
+
+// Delete removes a key from memory and from disk.
+// Returns true if the key was found and removed, false otherwise.
+func (c *TieredCache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	inMemory := c.memory.Delete(key)
+	onDisk := c.store.Delete(key)
+	return inMemory || onDisk
+}
+
+// Purge removes all keys and values from memory and from disk.
+func (c *TieredCache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.memory.Purge()
+	_ = c.store.Purge()
+}
+
+// SetMany stores multiple key-value pairs in memory and removes any stale copy from disk.
+func (c *TieredCache[K, V]) SetMany(items map[K]V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k := range items {
+		c.store.discard(k)
+	}
+	c.memory.SetMany(items)
+}
+
+// HasMany checks if multiple keys exist in memory or on disk.
+func (c *TieredCache[K, V]) HasMany(keys []K) map[K]bool {
+	m := c.memory.HasMany(keys)
+	for k, ok := range m {
+		if !ok {
+			m[k] = c.store.Has(k)
+		}
+	}
+	return m
+}
+
+// GetMany retrieves multiple values from memory, or from disk. Values found on disk are promoted to memory.
+func (c *TieredCache[K, V]) GetMany(keys []K) (map[K]V, []K) {
+	found, missing := c.memory.GetMany(keys)
+
+	stillMissing := []K{}
+	for _, k := range missing {
+		if v, ok := c.Get(k); ok {
+			found[k] = v
+		} else {
+			stillMissing = append(stillMissing, k)
+		}
+	}
+
+	return found, stillMissing
+}
+
+// PeekMany retrieves multiple values from memory, or from disk, without updating access order nor promoting them.
+func (c *TieredCache[K, V]) PeekMany(keys []K) (map[K]V, []K) {
+	found, missing := c.memory.PeekMany(keys)
+
+	stillMissing := []K{}
+	for _, k := range missing {
+		if v, ok, _ := c.store.Get(k); ok {
+			found[k] = v
+		} else {
+			stillMissing = append(stillMissing, k)
+		}
+	}
+
+	return found, stillMissing
+}
+
+// DeleteMany removes multiple keys from memory and from disk.
+func (c *TieredCache[K, V]) DeleteMany(keys []K) map[K]bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m := c.memory.DeleteMany(keys)
+	for _, k := range keys {
+		if c.store.Delete(k) {
+			m[k] = true
+		}
+	}
+	return m
+}
+
+// Capacity returns the capacity of the in-memory tier.
+func (c *TieredCache[K, V]) Capacity() int {
+	return c.memory.Capacity()
+}
+
+// Algorithm returns the eviction algorithm of the in-memory tier.
+func (c *TieredCache[K, V]) Algorithm() string {
+	return c.memory.Algorithm()
+}
+
+// Len returns the number of items in memory and on disk.
+func (c *TieredCache[K, V]) Len() int {
+	return c.memory.Len() + c.store.Len()
+}
+
+// SizeBytes returns the size of the in-memory tier plus the live bytes of the disk tier.
+func (c *TieredCache[K, V]) SizeBytes() int64 {
+	return c.memory.SizeBytes() + c.store.SizeBytes()
+}
+
+// Store returns the disk tier.
+func (c *TieredCache[K, V]) Store() *Store[K, V] {
+	return c.store
+}
@@ -0,0 +1,230 @@
+package disk
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samber/hot/pkg/base"
+	"github.com/samber/hot/pkg/lru"
+	"github.com/samber/hot/pkg/metrics"
+	"github.com/samber/hot/pkg/safe"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTieredCache(t *testing.T, memoryCapacity int, diskCapacity int) *TieredCache[string, int] {
+	store, err := NewStore[string, int](filepath.Join(t.TempDir(), "segment.seg"), diskCapacity, JSONCodec[int]{}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	var cache *TieredCache[string, int]
+	memory := lru.NewLRUCacheWithEvictionCallback(memoryCapacity, func(reason base.EvictionReason, k string, v int) {
+		if reason == base.EvictionReasonCapacity {
+			cache.Demote(k, v)
+		}
+	})
+	cache = NewTieredCache[string, int](memory, store)
+	return cache
+}
+
+func TestTieredCache_Demote(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := newTestTieredCache(t, 2, 10)
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+
+	is.Equal(3, cache.Len())
+	is.Equal(1, cache.Store().Len())
+	is.True(cache.Has("a"))
+	is.Equal(2, cache.Capacity())
+	is.Equal("lru", cache.Algorithm())
+
+	// peek does not promote
+	v, ok := cache.Peek("a")
+	is.True(ok)
+	is.Equal(1, v)
+	is.Equal(1, cache.Store().Len())
+
+	// get promotes, and demotes the least recently used key
+	v, ok = cache.Get("a")
+	is.True(ok)
+	is.Equal(1, v)
+	is.Equal([]string{"b"}, cache.Store().Keys())
+	is.Equal([]string{"a", "c", "b"}, cache.KeysByRetention(0))
+	is.Equal([]string{"a", "c"}, cache.KeysByRetention(2))
+	is.Equal(map[string]int{"a": 1, "b": 2, "c": 3}, cache.All())
+	is.ElementsMatch([]int{1, 2, 3}, cache.Values())
+
+	// set removes the stale copy from disk
+	cache.Set("b", 20)
+	is.Equal([]string{"c"}, cache.Store().Keys())
+	v, ok = cache.Peek("b")
+	is.True(ok)
+	is.Equal(20, v)
+}
+
+// evictionCountingCollector counts the evictions of the disk tier, by reason.
+type evictionCountingCollector struct {
+	metrics.NoOpCollector
+	evictions map[base.EvictionReason]int64
+}
+
+func (c *evictionCountingCollector) IncEviction(reason base.EvictionReason) {
+	c.evictions[reason]++
+}
+
+func TestTieredCache_SetIsNotAnEviction(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	collector := &evictionCountingCollector{evictions: map[base.EvictionReason]int64{}}
+	store, err := NewStore[string, int](filepath.Join(t.TempDir(), "segment.seg"), 10, JSONCodec[int]{}, nil, nil, collector)
+	is.NoError(err)
+	t.Cleanup(func() { _ = store.Close() })
+
+	cache := NewTieredCache[string, int](lru.NewLRUCache[string, int](10), store)
+	is.True(cache.Demote("a", 1))
+	is.True(cache.Demote("b", 2))
+
+	// Writing a key moves it to memory: the copy on disk is not evicted.
+	cache.Set("a", 10)
+	cache.SetMany(map[string]int{"b": 20})
+	is.Equal(0, store.Len())
+	is.Empty(collector.evictions)
+
+	// Deleting a key is.
+	is.True(cache.Demote("c", 3))
+	is.True(cache.Delete("c"))
+	is.Equal(map[base.EvictionReason]int64{base.EvictionReasonManual: 1}, collector.evictions)
+}
+
+func TestTieredCache_Many(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := newTestTieredCache(t, 2, 10)
+	cache.SetMany(map[string]int{"a": 1})
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+
+	is.Equal(map[string]bool{"a": true, "b": true, "d": false}, cache.HasMany([]string{"a", "b", "d"}))
+
+	found, missing := cache.PeekMany([]string{"a", "b", "d"})
+	is.Equal(map[string]int{"a": 1, "b": 2}, found)
+	is.Equal([]string{"d"}, missing)
+
+	found, missing = cache.GetMany([]string{"a", "b", "d"})
+	is.Equal(map[string]int{"a": 1, "b": 2}, found)
+	is.Equal([]string{"d"}, missing)
+
+	deleted := cache.DeleteMany([]string{"a", "b", "c", "d"})
+	is.Equal(map[string]bool{"a": true, "b": true, "c": true, "d": false}, deleted)
+	is.Equal(0, cache.Len())
+}
+
+func TestTieredCache_DeletePurge(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := newTestTieredCache(t, 1, 10)
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	is.Equal(1, cache.Store().Len())
+
+	is.True(cache.Delete("a"))
+	is.False(cache.Delete("a"))
+	is.Equal(1, cache.Len())
+
+	cache.Set("c", 3)
+	is.Greater(cache.SizeBytes(), int64(0))
+
+	keys := []string{}
+	cache.Range(func(k string, v int) bool {
+		keys = append(keys, k)
+		return true
+	})
+	is.ElementsMatch([]string{"b", "c"}, keys)
+
+	cache.Purge()
+	is.Equal(0, cache.Len())
+	is.Empty(cache.Keys())
+}
+
+func TestTieredCache_RangeMemory(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := newTestTieredCache(t, 1, 10)
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+
+	keys := []string{}
+	base.RangeMemory[string, int](cache, func(k string, v int) bool {
+		keys = append(keys, k)
+		return true
+	})
+	is.Equal([]string{"b"}, keys)
+	is.Equal([]string{"a"}, cache.Store().Keys())
+}
+
+// promotionHookCache calls onSet before storing a value in memory.
+type promotionHookCache struct {
+	base.InMemoryCache[string, int]
+	onSet func(key string, value int)
+}
+
+func (c *promotionHookCache) Set(key string, value int) {
+	c.onSet(key, value)
+	c.InMemoryCache.Set(key, value)
+}
+
+func TestTieredCache_PromotionDoesNotOverwriteSet(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	store, err := NewStore[string, int](filepath.Join(t.TempDir(), "segment.seg"), 10, JSONCodec[int]{}, nil, nil, nil)
+	is.NoError(err)
+	defer store.Close()
+
+	var cache *TieredCache[string, int]
+	var wg sync.WaitGroup
+	memory := &promotionHookCache{
+		InMemoryCache: safe.NewSafeInMemoryCache[string, int](lru.NewLRUCache[string, int](10)),
+		onSet: func(key string, value int) {
+			if value != -1 {
+				return
+			}
+
+			// a concurrent write while the stale value is being promoted
+			wg.Add(1)
+			done := make(chan struct{})
+			go func() {
+				defer wg.Done()
+				cache.Set(key, 1)
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(50 * time.Millisecond):
+			}
+		},
+	}
+	cache = NewTieredCache[string, int](memory, store)
+
+	is.NoError(store.Set("a", -1))
+	v, ok := cache.Get("a")
+	is.True(ok)
+	is.Equal(-1, v)
+	wg.Wait()
+
+	v, ok = cache.Get("a")
+	is.True(ok)
+	is.Equal(1, v)
+	is.False(store.Has("a"))
+}
@@ -51,6 +51,7 @@ type FIFOCache[K comparable, V any] struct { //nolint:revive
 
 // Ensure FIFOCache implements InMemoryCache interface.
 var _ base.InMemoryCache[string, int] = (*FIFOCache[string, int])(nil)
+var _ base.RetentionOrderedCache[string] = (*FIFOCache[string, int])(nil)
 
 // Set stores a key-value pair in the cache.
 // If the key already exists, its value is updated but its position remains unchanged.
@@ -109,6 +110,20 @@ func (c *FIFOCache[K, V]) Keys() []K {
 	return all
 }
 
+// KeysByRetention returns up to n keys, from the newest inserted to the oldest inserted.
+// A non-positive n returns all keys.
+func (c *FIFOCache[K, V]) KeysByRetention(n int) []K {
+	if n <= 0 || n > c.ll.Len() {
+		n = c.ll.Len()
+	}
+
+	keys := make([]K, 0, n)
+	for e := c.ll.Back(); e != nil && len(keys) < n; e = e.Prev() {
+		keys = append(keys, e.Value.key)
+	}
+	return keys
+}
+
 // Values returns all values currently in the cache.
 func (c *FIFOCache[K, V]) Values() []V {
 	all := make([]V, 0, c.ll.Len())
@@ -437,3 +437,17 @@ func TestFIFOCache_UpdateExistingKey(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, 10, value)
 }
+
+func TestKeysByRetention(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewFIFOCache[string, int](3)
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+	cache.Get("a")
+
+	is.Equal([]string{"c", "b", "a"}, cache.KeysByRetention(0))
+	is.Equal([]string{"c"}, cache.KeysByRetention(1))
+}
@@ -0,0 +1,72 @@
+package invalidation
+
+import "sync"
+
+// NewInProcessHub creates a hub connecting invalidators of the same process.
+// This is mostly useful for tests.
+func NewInProcessHub() *InProcessHub {
+	return &InProcessHub{
+		members: map[*inProcessInvalidator]struct{}{},
+	}
+}
+
+// InProcessHub delivers payloads synchronously to every other member.
+type InProcessHub struct {
+	mu      sync.RWMutex
+	members map[*inProcessInvalidator]struct{}
+}
+
+// Join returns a new invalidator connected to the hub.
+func (h *InProcessHub) Join() Invalidator {
+	member := &inProcessInvalidator{hub: h}
+
+	h.mu.Lock()
+	h.members[member] = struct{}{}
+	h.mu.Unlock()
+
+	return member
+}
+
+type inProcessInvalidator struct {
+	hub     *InProcessHub
+	mu      sync.RWMutex
+	handler func(payload []byte)
+}
+
+var _ Invalidator = (*inProcessInvalidator)(nil)
+
+func (i *inProcessInvalidator) Publish(payload []byte) error {
+	i.hub.mu.RLock()
+	members := make([]*inProcessInvalidator, 0, len(i.hub.members))
+	for member := range i.hub.members {
+		if member != i {
+			members = append(members, member)
+		}
+	}
+	i.hub.mu.RUnlock()
+
+	for _, member := range members {
+		member.mu.RLock()
+		handler := member.handler
+		member.mu.RUnlock()
+
+		if handler != nil {
+			handler(append([]byte{}, payload...))
+		}
+	}
+
+	return nil
+}
+
+func (i *inProcessInvalidator) Subscribe(handler func(payload []byte)) {
+	i.mu.Lock()
+	i.handler = handler
+	i.mu.Unlock()
+}
+
+func (i *inProcessInvalidator) Close() error {
+	i.hub.mu.Lock()
+	delete(i.hub.members, i)
+	i.hub.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,29 @@
+package invalidation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInProcessHub(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	hub := NewInProcessHub()
+	a := hub.Join()
+	b := hub.Join()
+	c := hub.Join()
+
+	received := map[string][]string{}
+	a.Subscribe(func(payload []byte) { received["a"] = append(received["a"], string(payload)) })
+	b.Subscribe(func(payload []byte) { received["b"] = append(received["b"], string(payload)) })
+
+	is.NoError(a.Publish([]byte("1")))
+	is.NoError(c.Publish([]byte("2")))
+	is.Equal(map[string][]string{"a": {"2"}, "b": {"1", "2"}}, received)
+
+	is.NoError(b.Close())
+	is.NoError(a.Publish([]byte("3")))
+	is.Equal(map[string][]string{"a": {"2"}, "b": {"1", "2"}}, received)
+}
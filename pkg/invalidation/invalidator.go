@@ -0,0 +1,14 @@
+package invalidation
+
+// Invalidator is the transport used to broadcast invalidations between cache instances.
+// Payloads are opaque to the transport. Delivery can be unordered and duplicated:
+// receivers de-duplicate messages with Versions.
+type Invalidator interface {
+	// Publish sends a payload to the other instances.
+	Publish(payload []byte) error
+	// Subscribe registers the function called for every payload received from the other instances.
+	// A later call replaces the previous handler.
+	Subscribe(handler func(payload []byte))
+	// Close stops the transport. This method is safe to call multiple times.
+	Close() error
+}
@@ -0,0 +1,45 @@
+package invalidation
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// Op is the kind of invalidation carried by a message.
+type Op uint8
+
+const (
+	// OpDelete removes the message keys.
+	OpDelete Op = iota + 1
+	// OpPurge removes every key.
+	OpPurge
+)
+
+// Message is an invalidation broadcast by a cache instance.
+// Version is a per-origin sequence number, starting at 1.
+type Message[K comparable] struct {
+	Origin  string `json:"origin"`
+	Version uint64 `json:"version"`
+	Op      Op     `json:"op"`
+	Keys    []K    `json:"keys,omitempty"`
+}
+
+// Encode serializes a message. Keys are encoded as JSON.
+func Encode[K comparable](msg Message[K]) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+// Decode deserializes a message written by Encode.
+func Decode[K comparable](payload []byte) (Message[K], error) {
+	var msg Message[K]
+	err := json.Unmarshal(payload, &msg)
+	return msg, err
+}
+
+// NewOrigin returns a random identifier for a cache instance.
+func NewOrigin() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
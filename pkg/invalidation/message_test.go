@@ -0,0 +1,34 @@
+package invalidation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessage_EncodeDecode(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	msg := Message[int]{Origin: "a", Version: 42, Op: OpDelete, Keys: []int{1, 2, 3}}
+
+	payload, err := Encode(msg)
+	is.NoError(err)
+
+	decoded, err := Decode[int](payload)
+	is.NoError(err)
+	is.Equal(msg, decoded)
+
+	_, err = Decode[int]([]byte(`{"keys":["a"]}`))
+	is.Error(err)
+}
+
+func TestNewOrigin(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	a := NewOrigin()
+	b := NewOrigin()
+	is.Len(a, 16)
+	is.NotEqual(a, b)
+}
@@ -0,0 +1,284 @@
+package invalidation
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// maxFrameSize protects receivers against corrupted length prefixes.
+	maxFrameSize = 16 << 20
+	// tcpTimeout bounds dialing and writing to a peer, so that a slow peer does not hold its queue for long.
+	tcpTimeout = time.Second
+	// defaultTCPQueueSize is the number of payloads buffered per peer.
+	defaultTCPQueueSize = 1024
+)
+
+// ErrQueueFull is returned by Publish when the payload was dropped for at least one peer,
+// because its queue was full.
+var ErrQueueFull = errors.New("invalidation queue is full")
+
+// NewTCPInvalidator listens for invalidations on listenAddr and fans out published payloads
+// to every peer address, over long-lived TCP connections. Connections to peers are opened lazily
+// and re-opened after a failure. Payloads sent while a peer is unreachable are lost: the TTL
+// remains the upper bound of staleness.
+func NewTCPInvalidator(listenAddr string, peers []string) (*TCPInvalidator, error) {
+	return NewTCPInvalidatorWithQueueSize(listenAddr, peers, defaultTCPQueueSize)
+}
+
+// NewTCPInvalidatorWithQueueSize is like NewTCPInvalidator, with up to queueSize payloads buffered per peer.
+// Each peer is written by its own goroutine: Publish never waits for the network, and drops
+// the payload for the peers whose queue is full.
+func NewTCPInvalidatorWithQueueSize(listenAddr string, peers []string, queueSize int) (*TCPInvalidator, error) {
+	if queueSize <= 0 {
+		panic("queue size must be greater than 0")
+	}
+
+	listener, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	i := &TCPInvalidator{
+		listener: listener,
+		peers:    make([]*tcpPeer, 0, len(peers)),
+		incoming: map[net.Conn]struct{}{},
+		closed:   make(chan struct{}),
+	}
+	for _, addr := range peers {
+		i.peers = append(i.peers, &tcpPeer{addr: addr, queue: make(chan []byte, queueSize)})
+	}
+
+	i.wg.Add(1 + len(i.peers))
+	go i.accept()
+	for _, peer := range i.peers {
+		go i.send(peer)
+	}
+
+	return i, nil
+}
+
+// TCPInvalidator is an Invalidator based on TCP fan-out.
+// Each payload is written to every peer, prefixed by its length.
+type TCPInvalidator struct {
+	listener net.Listener
+	peers    []*tcpPeer
+
+	mu       sync.RWMutex
+	handler  func(payload []byte)
+	incoming map[net.Conn]struct{}
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	wg        sync.WaitGroup
+}
+
+// tcpPeer is an outgoing connection, opened on first use by the goroutine draining its queue.
+type tcpPeer struct {
+	addr  string
+	queue chan []byte
+
+	mu     sync.Mutex
+	conn   net.Conn
+	closed bool
+}
+
+var _ Invalidator = (*TCPInvalidator)(nil)
+
+// Addr returns the address the invalidator listens on.
+func (i *TCPInvalidator) Addr() net.Addr {
+	return i.listener.Addr()
+}
+
+// Publish queues the payload for every peer, without blocking.
+// It returns ErrQueueFull when the payload was dropped for at least one peer.
+func (i *TCPInvalidator) Publish(payload []byte) error {
+	select {
+	case <-i.closed:
+		return net.ErrClosed
+	default:
+	}
+
+	frame := make([]byte, 4+len(payload))
+	binary.BigEndian.PutUint32(frame, uint32(len(payload)))
+	copy(frame[4:], payload)
+
+	var err error
+	for _, peer := range i.peers {
+		select {
+		case peer.queue <- frame:
+		default:
+			err = ErrQueueFull
+		}
+	}
+
+	return err
+}
+
+// Subscribe registers the function called for every payload received from the peers.
+func (i *TCPInvalidator) Subscribe(handler func(payload []byte)) {
+	i.mu.Lock()
+	i.handler = handler
+	i.mu.Unlock()
+}
+
+// Close stops listening and closes every connection.
+func (i *TCPInvalidator) Close() error {
+	var err error
+	i.closeOnce.Do(func() {
+		close(i.closed)
+		err = i.listener.Close()
+
+		i.mu.Lock()
+		for conn := range i.incoming {
+			_ = conn.Close()
+		}
+		i.mu.Unlock()
+
+		for _, peer := range i.peers {
+			peer.close()
+		}
+
+		i.wg.Wait()
+	})
+	return err
+}
+
+func (i *TCPInvalidator) accept() {
+	defer i.wg.Done()
+
+	for {
+		conn, err := i.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		i.mu.Lock()
+		select {
+		case <-i.closed:
+			i.mu.Unlock()
+			_ = conn.Close()
+			return
+		default:
+		}
+		i.incoming[conn] = struct{}{}
+		i.mu.Unlock()
+
+		i.wg.Add(1)
+		go i.read(conn)
+	}
+}
+
+func (i *TCPInvalidator) read(conn net.Conn) {
+	defer i.wg.Done()
+	defer func() {
+		i.mu.Lock()
+		delete(i.incoming, conn)
+		i.mu.Unlock()
+		_ = conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	header := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return
+		}
+
+		size := binary.BigEndian.Uint32(header)
+		if size > maxFrameSize {
+			return
+		}
+
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return
+		}
+
+		i.mu.RLock()
+		handler := i.handler
+		i.mu.RUnlock()
+
+		if handler != nil {
+			handler(payload)
+		}
+	}
+}
+
+// send writes the queued payloads to a peer, until the invalidator is closed.
+// Payloads that cannot be written are lost.
+func (i *TCPInvalidator) send(peer *tcpPeer) {
+	defer i.wg.Done()
+	defer peer.close()
+
+	for {
+		select {
+		case <-i.closed:
+			return
+		default:
+		}
+
+		select {
+		case <-i.closed:
+			return
+		case frame := <-peer.queue:
+			_ = peer.write(frame)
+		}
+	}
+}
+
+// write sends a frame, opening the connection first if needed.
+// It is only called by the goroutine draining the peer queue.
+func (p *tcpPeer) write(frame []byte) error {
+	p.mu.Lock()
+	conn := p.conn
+	p.mu.Unlock()
+
+	if conn == nil {
+		var err error
+		conn, err = net.DialTimeout("tcp", p.addr, tcpTimeout)
+		if err != nil {
+			return err
+		}
+
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			_ = conn.Close()
+			return net.ErrClosed
+		}
+		p.conn = conn
+		p.mu.Unlock()
+	}
+
+	_ = conn.SetWriteDeadline(time.Now().Add(tcpTimeout))
+	_, err := conn.Write(frame)
+	if err != nil {
+		// The connection is re-opened on the next write.
+		p.mu.Lock()
+		if p.conn == conn {
+			p.conn = nil
+		}
+		p.mu.Unlock()
+		_ = conn.Close()
+	}
+
+	return err
+}
+
+// close closes the connection, interrupting a pending write. The peer cannot be reconnected afterwards.
+func (p *tcpPeer) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	if p.conn != nil {
+		_ = p.conn.Close()
+		p.conn = nil
+	}
+}
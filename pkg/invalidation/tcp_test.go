@@ -0,0 +1,100 @@
+package invalidation
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTCPInvalidator(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	b, err := NewTCPInvalidator("127.0.0.1:0", nil)
+	is.NoError(err)
+	defer b.Close()
+
+	var mu sync.Mutex
+	received := []string{}
+	b.Subscribe(func(payload []byte) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, string(payload))
+	})
+
+	a, err := NewTCPInvalidator("127.0.0.1:0", []string{b.Addr().String()})
+	is.NoError(err)
+	defer a.Close()
+
+	is.NoError(a.Publish([]byte("hello")))
+	is.NoError(a.Publish([]byte("")))
+	is.NoError(a.Publish([]byte("world")))
+
+	is.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received) == 3
+	}, time.Second, 5*time.Millisecond)
+	is.Equal([]string{"hello", "", "world"}, received)
+
+	is.NoError(a.Close())
+	is.NoError(a.Close())
+	is.Error(a.Publish([]byte("closed")))
+}
+
+func TestTCPInvalidator_UnreachablePeer(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	// reserve an address, then release it
+	tmp, err := NewTCPInvalidator("127.0.0.1:0", nil)
+	is.NoError(err)
+	addr := tmp.Addr().String()
+	is.NoError(tmp.Close())
+
+	a, err := NewTCPInvalidator("127.0.0.1:0", []string{addr})
+	is.NoError(err)
+	defer a.Close()
+
+	// payloads are queued, and lost
+	is.NoError(a.Publish([]byte("hello")))
+
+	_, err = NewTCPInvalidator("invalid address", nil)
+	is.Error(err)
+	is.Panics(func() {
+		_, _ = NewTCPInvalidatorWithQueueSize("127.0.0.1:0", nil, 0)
+	})
+}
+
+func TestTCPInvalidator_SlowPeer(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	// a peer that never reads
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	is.NoError(err)
+	defer listener.Close()
+
+	a, err := NewTCPInvalidatorWithQueueSize("127.0.0.1:0", []string{listener.Addr().String()}, 1)
+	is.NoError(err)
+
+	payload := make([]byte, 1<<20)
+	start := time.Now()
+	errs := 0
+	for n := 0; n < 10; n++ {
+		if err := a.Publish(payload); err != nil {
+			is.ErrorIs(err, ErrQueueFull)
+			errs++
+		}
+	}
+	is.Less(time.Since(start), 500*time.Millisecond)
+	is.Positive(errs)
+
+	// a pending write is interrupted
+	start = time.Now()
+	is.NoError(a.Close())
+	is.Less(time.Since(start), 500*time.Millisecond)
+}
@@ -0,0 +1,107 @@
+package invalidation
+
+import (
+	"sync"
+	"time"
+
+	"github.com/samber/hot/internal"
+)
+
+// versionWindow is the number of versions tracked per origin for de-duplication.
+const versionWindow = 1024
+
+// originIdleTimeout is the time after which an origin without messages is forgotten.
+// Instances get a new origin on every start: without expiry, the origins of the instances
+// restarted over the lifetime of the process would be kept forever.
+const originIdleTimeout = time.Hour
+
+// NewVersions creates an empty version tracker.
+func NewVersions() *Versions {
+	return &Versions{
+		origins: map[string]*originVersions{},
+	}
+}
+
+// Versions de-duplicates invalidation messages and handles out-of-order deliveries.
+// For each origin, it keeps a sliding window of the last versions received.
+// Origins idle for an hour are forgotten.
+// It is safe for concurrent access.
+type Versions struct {
+	mu      sync.Mutex
+	origins map[string]*originVersions
+}
+
+// originVersions is the state of a single origin.
+type originVersions struct {
+	highest uint64                     // Highest version received
+	seen    [versionWindow / 64]uint64 // Bitmap of the versions received in (highest-versionWindow, highest]
+	purge   uint64                     // Version of the last purge received
+	seenAt  int64                      // Time of the last message received, in nanoseconds
+}
+
+// Accept reports whether a message must be applied. It rejects:
+//   - duplicates;
+//   - messages older than the sliding window;
+//   - deletions older than a purge already applied, since the purge removed the keys
+//     and newer values might have been stored since then.
+func (v *Versions) Accept(origin string, version uint64, op Op) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	nowNano := internal.NowNano()
+
+	state, ok := v.origins[origin]
+	if !ok {
+		// New origins are rare: the idle ones are removed at the same time.
+		v.forgetIdle(nowNano)
+		state = &originVersions{}
+		v.origins[origin] = state
+	}
+	state.seenAt = nowNano
+
+	if version > state.highest {
+		if version-state.highest >= versionWindow {
+			state.seen = [versionWindow / 64]uint64{}
+		} else {
+			for i := state.highest + 1; i < version; i++ {
+				state.clear(i)
+			}
+		}
+		state.highest = version
+	} else if state.highest-version >= versionWindow || state.has(version) {
+		return false
+	}
+
+	state.set(version)
+
+	if op == OpPurge {
+		state.purge = max(state.purge, version)
+		return true
+	}
+
+	return version > state.purge
+}
+
+// forgetIdle removes the origins idle for longer than originIdleTimeout.
+func (v *Versions) forgetIdle(nowNano int64) {
+	for origin, state := range v.origins {
+		if nowNano-state.seenAt > originIdleTimeout.Nanoseconds() {
+			delete(v.origins, origin)
+		}
+	}
+}
+
+func (s *originVersions) has(version uint64) bool {
+	slot := version % versionWindow
+	return s.seen[slot/64]&(1<<(slot%64)) != 0
+}
+
+func (s *originVersions) set(version uint64) {
+	slot := version % versionWindow
+	s.seen[slot/64] |= 1 << (slot % 64)
+}
+
+func (s *originVersions) clear(version uint64) {
+	slot := version % versionWindow
+	s.seen[slot/64] &^= 1 << (slot % 64)
+}
@@ -0,0 +1,71 @@
+package invalidation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersions_Accept(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	v := NewVersions()
+
+	// duplicates
+	is.True(v.Accept("a", 1, OpDelete))
+	is.False(v.Accept("a", 1, OpDelete))
+
+	// out of order
+	is.True(v.Accept("a", 3, OpDelete))
+	is.True(v.Accept("a", 2, OpDelete))
+	is.False(v.Accept("a", 2, OpDelete))
+	is.False(v.Accept("a", 3, OpDelete))
+
+	// origins are independent
+	is.True(v.Accept("b", 1, OpDelete))
+
+	// too old for the window
+	is.True(v.Accept("a", 3+versionWindow, OpDelete))
+	is.False(v.Accept("a", 3, OpDelete))
+	is.True(v.Accept("a", 4, OpDelete))
+	is.False(v.Accept("a", 4, OpDelete))
+
+	// slots reused by newer versions
+	is.True(v.Accept("a", 4+versionWindow, OpDelete))
+	is.False(v.Accept("a", 4+versionWindow, OpDelete))
+}
+
+func TestVersions_Purge(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	v := NewVersions()
+
+	is.True(v.Accept("a", 1, OpDelete))
+	is.True(v.Accept("a", 5, OpPurge))
+
+	// deletions older than the purge are already covered
+	is.False(v.Accept("a", 3, OpDelete))
+	// purges are always applied
+	is.True(v.Accept("a", 4, OpPurge))
+	is.True(v.Accept("a", 6, OpDelete))
+	is.False(v.Accept("a", 5, OpPurge))
+}
+
+func TestVersions_ForgetIdle(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	v := NewVersions()
+
+	is.True(v.Accept("a", 1, OpDelete))
+	is.True(v.Accept("b", 1, OpDelete))
+	v.origins["a"].seenAt -= 2 * originIdleTimeout.Nanoseconds()
+
+	// idle origins are forgotten when a new origin shows up
+	is.True(v.Accept("c", 1, OpDelete))
+	is.Len(v.origins, 2)
+	is.NotContains(v.origins, "a")
+	is.False(v.Accept("b", 1, OpDelete))
+}
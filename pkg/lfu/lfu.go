@@ -1,6 +1,8 @@
 package lfu
 
 import (
+	"sort"
+
 	"github.com/samber/hot/internal/container/list"
 
 	"github.com/DmitriyVTitov/size"
@@ -81,6 +83,7 @@ type LFUCache[K comparable, V any] struct { //nolint:revive
 
 // Ensure LFUCache implements InMemoryCache interface.
 var _ base.InMemoryCache[string, int] = (*LFUCache[string, int])(nil)
+var _ base.RetentionOrderedCache[string] = (*LFUCache[string, int])(nil)
 
 // Set stores a key-value pair in the cache.
 // If the key already exists, its value is updated and its frequency is incremented.
@@ -149,6 +152,29 @@ func (c *LFUCache[K, V]) Keys() []K {
 	return all
 }
 
+// KeysByRetention returns up to n keys, from the most frequently used to the least frequently used.
+// Keys sharing the same frequency are ordered from the most recently used to the least recently used.
+// A non-positive n returns all keys.
+func (c *LFUCache[K, V]) KeysByRetention(n int) []K {
+	if n <= 0 || n > len(c.cache) {
+		n = len(c.cache)
+	}
+
+	freqs := make([]int, 0, len(c.freqMap))
+	for freq := range c.freqMap {
+		freqs = append(freqs, freq)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(freqs)))
+
+	keys := make([]K, 0, n)
+	for _, freq := range freqs {
+		for e := c.freqMap[freq].Front(); e != nil && len(keys) < n; e = e.Next() {
+			keys = append(keys, e.Value.key)
+		}
+	}
+	return keys
+}
+
 // Values returns all values currently in the cache.
 func (c *LFUCache[K, V]) Values() []V {
 	all := make([]V, 0, len(c.cache))
@@ -999,3 +999,19 @@ func TestInternalState_ElementRelationships(t *testing.T) {
 	is.Equal(2, cache.freqMap[0].Len())
 	is.Equal(1, cache.freqMap[1].Len())
 }
+
+func TestKeysByRetention(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewLFUCache[string, int](3)
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+	cache.Get("b")
+	cache.Get("b")
+	cache.Get("c")
+
+	is.Equal([]string{"b", "c", "a"}, cache.KeysByRetention(0))
+	is.Equal([]string{"b", "c"}, cache.KeysByRetention(2))
+}
@@ -51,6 +51,7 @@ type LRUCache[K comparable, V any] struct { //nolint:revive
 
 // Ensure LRUCache implements InMemoryCache interface.
 var _ base.InMemoryCache[string, int] = (*LRUCache[string, int])(nil)
+var _ base.RetentionOrderedCache[string] = (*LRUCache[string, int])(nil)
 
 // Set stores a key-value pair in the cache.
 // If the key already exists, its value is updated and it becomes the most recently used item.
@@ -111,6 +112,20 @@ func (c *LRUCache[K, V]) Keys() []K {
 	return all
 }
 
+// KeysByRetention returns up to n keys, from the most recently used to the least recently used.
+// A non-positive n returns all keys.
+func (c *LRUCache[K, V]) KeysByRetention(n int) []K {
+	if n <= 0 || n > c.ll.Len() {
+		n = c.ll.Len()
+	}
+
+	keys := make([]K, 0, n)
+	for e := c.ll.Front(); e != nil && len(keys) < n; e = e.Next() {
+		keys = append(keys, e.Value.key)
+	}
+	return keys
+}
+
 // Values returns all values currently in the cache.
 func (c *LRUCache[K, V]) Values() []V {
 	all := make([]V, 0, c.ll.Len())
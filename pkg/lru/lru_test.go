@@ -941,3 +941,18 @@ func TestPeek(t *testing.T) {
 	is.True(cache.Has("b"))
 	is.True(cache.Has("c"))
 }
+
+func TestKeysByRetention(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewLRUCache[string, int](3)
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+	cache.Get("a")
+
+	is.Equal([]string{"a", "c", "b"}, cache.KeysByRetention(0))
+	is.Equal([]string{"a", "c"}, cache.KeysByRetention(2))
+	is.Equal([]string{"a", "c", "b"}, cache.KeysByRetention(10))
+}
@@ -5,6 +5,8 @@ import (
 )
 
 var _ base.InMemoryCache[string, int] = (*InstrumentedCache[string, int])(nil)
+var _ base.RetentionOrderedCache[string] = (*InstrumentedCache[string, int])(nil)
+var _ base.AdaptiveWindowCache = (*InstrumentedCache[string, int])(nil)
 
 // NewInstrumentedCache creates a new metrics wrapper around an existing cache.
 func NewInstrumentedCache[K comparable, V any](cache base.InMemoryCache[K, V], metrics Collector) *InstrumentedCache[K, V] {
@@ -134,6 +136,21 @@ func (m *InstrumentedCache[K, V]) Keys() []K {
 	return m.cache.Keys()
 }
 
+// KeysByRetention returns up to n keys in the retention order of the underlying cache.
+func (m *InstrumentedCache[K, V]) KeysByRetention(n int) []K {
+	return base.KeysByRetention(m.cache, n)
+}
+
+// WindowRatio returns the fraction of the capacity used by the admission window of the underlying cache.
+func (m *InstrumentedCache[K, V]) WindowRatio() (float64, bool) {
+	return base.WindowRatio(m.cache)
+}
+
+// PinWindowRatio pins the admission window of the underlying cache to a fraction of the capacity.
+func (m *InstrumentedCache[K, V]) PinWindowRatio(ratio float64) bool {
+	return base.PinWindowRatio(m.cache, ratio)
+}
+
 // Values returns all values currently in the cache.
 func (m *InstrumentedCache[K, V]) Values() []V {
 	return m.cache.Values()
@@ -761,3 +761,15 @@ func TestInstrumentedCache_LengthMetric(t *testing.T) {
 	instrumentedCache.Len() // This should update the metric
 	assert.Equal(t, int64(0), lastLength)
 }
+
+func TestInstrumentedCache_KeysByRetention(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewInstrumentedCache[string, int](lru.NewLRUCache[string, int](10), &NoOpCollector{})
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+
+	is.Equal([]string{"b", "a"}, cache.KeysByRetention(0))
+	is.Equal([]string{"b"}, cache.KeysByRetention(1))
+}
@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"time"
+)
+
+// EvictionRegretCollector defines the interface for the premature eviction metrics of a cache.
+// A premature eviction is a miss on a key that was evicted for capacity shortly before:
+// a larger cache would have served it.
+type EvictionRegretCollector interface {
+	// ObservePrematureEviction records a miss on a key evicted for capacity `age` ago.
+	ObservePrematureEviction(age time.Duration)
+}
+
+var _ EvictionRegretCollector = (MultiEvictionRegretCollector)(nil)
+
+// MultiEvictionRegretCollector forwards every premature eviction to several collectors.
+// It is used when metrics are exported to more than one backend.
+type MultiEvictionRegretCollector []EvictionRegretCollector
+
+// ObservePrematureEviction records the premature eviction in every collector.
+func (m MultiEvictionRegretCollector) ObservePrematureEviction(age time.Duration) {
+	for _, c := range m {
+		c.ObservePrematureEviction(age)
+	}
+}
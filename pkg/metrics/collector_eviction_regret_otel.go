@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var _ EvictionRegretCollector = (*OpenTelemetryEvictionRegretCollector)(nil)
+
+// OpenTelemetryEvictionRegretCollector implements EvictionRegretCollector using OpenTelemetry synchronous instruments.
+// Every measurement has the `name` attribute of the cache.
+type OpenTelemetryEvictionRegretCollector struct {
+	attributes metric.MeasurementOption
+
+	prematureEvictions metric.Int64Counter
+	age                metric.Float64Histogram
+}
+
+// NewOpenTelemetryEvictionRegretCollector creates the instruments of the premature eviction metrics.
+// Instrument names mirror the Prometheus metrics, without the `_total` suffix.
+func NewOpenTelemetryEvictionRegretCollector(meter metric.Meter, name string) (*OpenTelemetryEvictionRegretCollector, error) {
+	var err error
+	o := &OpenTelemetryEvictionRegretCollector{
+		attributes: metric.WithAttributeSet(attribute.NewSet(attribute.String("name", name))),
+	}
+
+	if o.prematureEvictions, err = meter.Int64Counter("hot.premature_eviction", metric.WithDescription("Total number of misses on keys recently evicted for capacity")); err != nil {
+		return nil, err
+	}
+	if o.age, err = meter.Float64Histogram("hot.premature_eviction.age", metric.WithDescription("Time elapsed between the eviction of a key for capacity and the next miss on it"), metric.WithUnit("s"), metric.WithExplicitBucketBoundaries(PrometheusEvictionAgeBuckets...)); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// ObservePrematureEviction records a miss on a key evicted for capacity `age` ago.
+func (o *OpenTelemetryEvictionRegretCollector) ObservePrematureEviction(age time.Duration) {
+	ctx := context.Background()
+	o.prematureEvictions.Add(ctx, 1, o.attributes)
+	o.age.Record(ctx, age.Seconds(), o.attributes)
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestOpenTelemetryEvictionRegretCollector(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	collector, err := NewOpenTelemetryEvictionRegretCollector(provider.Meter("test"), "test-cache")
+	is.NoError(err)
+
+	collector.ObservePrematureEviction(2 * time.Millisecond)
+	collector.ObservePrematureEviction(3 * time.Second)
+
+	data := collectOpenTelemetry(t, reader)
+
+	prematureEvictions := int64Points[int64](data["hot.premature_eviction"])
+	is.Len(prematureEvictions, 1)
+	is.Equal(int64(2), prematureEvictions[0].Value)
+	is.Equal(attribute.NewSet(attribute.String("name", "test-cache")), prematureEvictions[0].Attributes)
+
+	age, ok := data["hot.premature_eviction.age"].(metricdata.Histogram[float64])
+	is.True(ok)
+	is.Len(age.DataPoints, 1)
+	is.Equal(uint64(2), age.DataPoints[0].Count)
+	is.InDelta(3.002, age.DataPoints[0].Sum, 1e-9)
+	is.Equal(PrometheusEvictionAgeBuckets, age.DataPoints[0].Bounds)
+}
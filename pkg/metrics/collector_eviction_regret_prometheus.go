@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusEvictionAgeBuckets are the buckets of the age of the prematurely evicted keys, in seconds:
+// from 1ms to about 70 minutes.
+var PrometheusEvictionAgeBuckets = prometheus.ExponentialBuckets(0.001, 4, 12)
+
+var _ EvictionRegretCollector = (*PrometheusEvictionRegretCollector)(nil)
+var _ prometheus.Collector = (*PrometheusEvictionRegretCollector)(nil)
+
+// PrometheusEvictionRegretCollector implements EvictionRegretCollector using Prometheus metrics.
+// Every metric has the `name` label of the cache.
+type PrometheusEvictionRegretCollector struct {
+	prematureEvictions prometheus.Counter
+	age                prometheus.Histogram
+}
+
+// NewPrometheusEvictionRegretCollector creates a new Prometheus-based premature eviction metric collector.
+func NewPrometheusEvictionRegretCollector(name string) *PrometheusEvictionRegretCollector {
+	labels := prometheus.Labels{"name": name}
+
+	return &PrometheusEvictionRegretCollector{
+		prematureEvictions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        "hot_premature_eviction_total",
+			Help:        "Total number of misses on keys recently evicted for capacity",
+			ConstLabels: labels,
+		}),
+		age: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:        "hot_premature_eviction_age_seconds",
+			Help:        "Time elapsed between the eviction of a key for capacity and the next miss on it",
+			ConstLabels: labels,
+			Buckets:     PrometheusEvictionAgeBuckets,
+		}),
+	}
+}
+
+// ObservePrematureEviction records a miss on a key evicted for capacity `age` ago.
+func (p *PrometheusEvictionRegretCollector) ObservePrematureEviction(age time.Duration) {
+	p.prematureEvictions.Inc()
+	p.age.Observe(age.Seconds())
+}
+
+// Describe implements the prometheus.Collector interface.
+func (p *PrometheusEvictionRegretCollector) Describe(ch chan<- *prometheus.Desc) {
+	p.prematureEvictions.Describe(ch)
+	p.age.Describe(ch)
+}
+
+// Collect implements the prometheus.Collector interface.
+func (p *PrometheusEvictionRegretCollector) Collect(ch chan<- prometheus.Metric) {
+	p.prematureEvictions.Collect(ch)
+	p.age.Collect(ch)
+}
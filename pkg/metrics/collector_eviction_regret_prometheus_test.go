@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusEvictionRegretCollector(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	collector := NewPrometheusEvictionRegretCollector("test-cache")
+	collector.ObservePrematureEviction(2 * time.Millisecond)
+	collector.ObservePrematureEviction(3 * time.Second)
+
+	is.InDelta(2.0, testutil.ToFloat64(collector.prematureEvictions), 0)
+
+	registry := prometheus.NewRegistry()
+	is.NoError(registry.Register(collector))
+	families, err := registry.Gather()
+	is.NoError(err)
+
+	found := false
+	for _, family := range families {
+		if family.GetName() != "hot_premature_eviction_age_seconds" {
+			continue
+		}
+		found = true
+		is.Len(family.GetMetric(), 1)
+		histogram := family.GetMetric()[0].GetHistogram()
+		is.Equal(uint64(2), histogram.GetSampleCount())
+		is.InDelta(3.002, histogram.GetSampleSum(), 1e-9)
+		is.Len(histogram.GetBucket(), len(PrometheusEvictionAgeBuckets))
+		is.Equal("test-cache", family.GetMetric()[0].GetLabel()[0].GetValue())
+	}
+	is.True(found)
+}
+
+func TestMultiEvictionRegretCollector(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	a := NewPrometheusEvictionRegretCollector("a")
+	b := NewPrometheusEvictionRegretCollector("b")
+	MultiEvictionRegretCollector{a, b}.ObservePrematureEviction(time.Second)
+
+	is.InDelta(1.0, testutil.ToFloat64(a.prematureEvictions), 0)
+	is.InDelta(1.0, testutil.ToFloat64(b.prematureEvictions), 0)
+
+	is.NotPanics(func() {
+		MultiEvictionRegretCollector(nil).ObservePrematureEviction(time.Second)
+	})
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ prometheus.Collector = (*PrometheusHotKeysCollector)(nil)
+
+// HotKey is a frequently accessed key, formatted for export.
+type HotKey struct {
+	Key   string
+	Count uint64 // Estimated number of accesses over the decaying window
+}
+
+// PrometheusHotKeysCollector exports the most accessed keys of a cache as the `hot_key_accesses` gauge,
+// labeled by `key` and `rank`. The label set is bounded: only the keys returned by `top` are exported,
+// and series of keys leaving the top disappear on the next scrape.
+type PrometheusHotKeysCollector struct {
+	desc *prometheus.Desc
+	top  func() []HotKey
+}
+
+// NewPrometheusHotKeysCollector creates a collector exporting the keys returned by `top`, in order, on every scrape.
+func NewPrometheusHotKeysCollector(name string, top func() []HotKey) *PrometheusHotKeysCollector {
+	return &PrometheusHotKeysCollector{
+		desc: prometheus.NewDesc(
+			"hot_key_accesses",
+			"Estimated number of accesses of the most accessed keys, over a decaying window",
+			[]string{"key", "rank"},
+			prometheus.Labels{"name": name},
+		),
+		top: top,
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (p *PrometheusHotKeysCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- p.desc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (p *PrometheusHotKeysCollector) Collect(ch chan<- prometheus.Metric) {
+	for i, key := range p.top() {
+		ch <- prometheus.MustNewConstMetric(p.desc, prometheus.GaugeValue, float64(key.Count), key.Key, strconv.Itoa(i+1))
+	}
+}
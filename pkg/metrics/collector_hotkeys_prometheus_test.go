@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusHotKeysCollector(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	top := []HotKey{{Key: "tenant-1", Count: 42}, {Key: "tenant-2", Count: 7}}
+	collector := NewPrometheusHotKeysCollector("test-cache", func() []HotKey {
+		return top
+	})
+
+	expected := `
+# HELP hot_key_accesses Estimated number of accesses of the most accessed keys, over a decaying window
+# TYPE hot_key_accesses gauge
+hot_key_accesses{key="tenant-1",name="test-cache",rank="1"} 42
+hot_key_accesses{key="tenant-2",name="test-cache",rank="2"} 7
+`
+	is.NoError(testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+
+	// Keys leaving the top are no longer exported.
+	top = top[:1]
+	is.Equal(1, testutil.CollectAndCount(collector))
+}
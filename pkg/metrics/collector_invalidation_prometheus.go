@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ prometheus.Collector = (*PrometheusInvalidationCollector)(nil)
+
+// PrometheusInvalidationCollector exports the number of invalidations that could not be published
+// to the other instances of a cache as the `hot_invalidation_publish_errors_total` counter.
+type PrometheusInvalidationCollector struct {
+	publishErrorsDesc *prometheus.Desc
+	publishErrors     func() int64
+}
+
+// NewPrometheusInvalidationCollector creates a collector exporting the count returned by `publishErrors` on every scrape.
+func NewPrometheusInvalidationCollector(name string, publishErrors func() int64) *PrometheusInvalidationCollector {
+	return &PrometheusInvalidationCollector{
+		publishErrorsDesc: prometheus.NewDesc(
+			"hot_invalidation_publish_errors_total",
+			"Total number of invalidations not published to every other instance, such as dropped by a full queue",
+			nil,
+			prometheus.Labels{"name": name},
+		),
+		publishErrors: publishErrors,
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (p *PrometheusInvalidationCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- p.publishErrorsDesc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (p *PrometheusInvalidationCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(p.publishErrorsDesc, prometheus.CounterValue, float64(p.publishErrors()))
+}
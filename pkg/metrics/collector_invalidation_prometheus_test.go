@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusInvalidationCollector(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	collector := NewPrometheusInvalidationCollector("test-cache", func() int64 {
+		return 3
+	})
+
+	expected := `
+# HELP hot_invalidation_publish_errors_total Total number of invalidations not published to every other instance, such as dropped by a full queue
+# TYPE hot_invalidation_publish_errors_total counter
+hot_invalidation_publish_errors_total{name="test-cache"} 3
+`
+	is.NoError(testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
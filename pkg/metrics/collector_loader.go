@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"time"
+)
+
+// LoaderCollector defines the interface for loader metric collection operations.
+// Unlike Collector, which is attached to each shard of the cache, a LoaderCollector is attached to the
+// whole cache: loaders are called once per batch of missing keys, whatever the shards of the keys.
+type LoaderCollector interface {
+	// ObserveLoad records a call to a loader of a chain, with the number of requested and found keys.
+	// When the loader fails, found is 0 and err is not nil.
+	ObserveLoad(loader string, keys int, found int, duration time.Duration, err error)
+	// AddDeduplicatedKeys records keys that were not loaded because a concurrent load was in-flight for them (singleflight).
+	AddDeduplicatedKeys(count int64)
+}
+
+var _ LoaderCollector = (MultiLoaderCollector)(nil)
+
+// MultiLoaderCollector forwards every loader metric to several collectors.
+// It is used when metrics are exported to more than one backend.
+type MultiLoaderCollector []LoaderCollector
+
+// ObserveLoad records the call to a loader in every collector.
+func (m MultiLoaderCollector) ObserveLoad(loader string, keys int, found int, duration time.Duration, err error) {
+	for _, c := range m {
+		c.ObserveLoad(loader, keys, found, duration, err)
+	}
+}
+
+// AddDeduplicatedKeys adds the specified count to the deduplicated keys counter of every collector.
+func (m MultiLoaderCollector) AddDeduplicatedKeys(count int64) {
+	for _, c := range m {
+		c.AddDeduplicatedKeys(count)
+	}
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var _ LoaderCollector = (*OpenTelemetryLoaderCollector)(nil)
+
+// OpenTelemetryLoaderCollector implements LoaderCollector using OpenTelemetry synchronous instruments.
+// Every measurement has the `name` attribute of the cache, and the `loader` attribute, except the deduplicated keys counter.
+type OpenTelemetryLoaderCollector struct {
+	name       attribute.KeyValue
+	attributes metric.MeasurementOption
+
+	duration     metric.Float64Histogram
+	batchSize    metric.Int64Histogram
+	found        metric.Int64Counter
+	missing      metric.Int64Counter
+	errors       metric.Int64Counter
+	deduplicated metric.Int64Counter
+}
+
+// NewOpenTelemetryLoaderCollector creates the instruments of the loader metrics.
+// Instrument names mirror the Prometheus metrics, without the `_total` suffix.
+func NewOpenTelemetryLoaderCollector(meter metric.Meter, name string) (*OpenTelemetryLoaderCollector, error) {
+	var err error
+	o := &OpenTelemetryLoaderCollector{
+		name:       attribute.String("name", name),
+		attributes: metric.WithAttributes(attribute.String("name", name)),
+	}
+
+	if o.duration, err = meter.Float64Histogram("hot.loader.duration", metric.WithDescription("Duration of the loader calls in seconds"), metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if o.batchSize, err = meter.Int64Histogram("hot.loader.batch_size", metric.WithDescription("Number of keys requested by the loader calls"), metric.WithExplicitBucketBoundaries(PrometheusLoaderBatchSizeBuckets...)); err != nil {
+		return nil, err
+	}
+	if o.found, err = meter.Int64Counter("hot.loader.found", metric.WithDescription("Total number of keys found by the loaders")); err != nil {
+		return nil, err
+	}
+	if o.missing, err = meter.Int64Counter("hot.loader.missing", metric.WithDescription("Total number of keys requested but not found by the loaders")); err != nil {
+		return nil, err
+	}
+	if o.errors, err = meter.Int64Counter("hot.loader.error", metric.WithDescription("Total number of failed loader calls")); err != nil {
+		return nil, err
+	}
+	if o.deduplicated, err = meter.Int64Counter("hot.loader.deduplicated", metric.WithDescription("Total number of keys not loaded because a concurrent load was in-flight")); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// ObserveLoad records the call to a loader.
+func (o *OpenTelemetryLoaderCollector) ObserveLoad(loader string, keys int, found int, duration time.Duration, err error) {
+	ctx := context.Background()
+	attributes := metric.WithAttributes(o.name, attribute.String("loader", loader))
+
+	o.duration.Record(ctx, duration.Seconds(), attributes)
+	o.batchSize.Record(ctx, int64(keys), attributes)
+
+	if err != nil {
+		o.errors.Add(ctx, 1, attributes)
+		return
+	}
+
+	o.found.Add(ctx, int64(found), attributes)
+	o.missing.Add(ctx, int64(max(keys-found, 0)), attributes)
+}
+
+// AddDeduplicatedKeys adds the specified count to the deduplicated keys counter.
+func (o *OpenTelemetryLoaderCollector) AddDeduplicatedKeys(count int64) {
+	o.deduplicated.Add(context.Background(), count, o.attributes)
+}
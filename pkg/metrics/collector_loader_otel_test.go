@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestOpenTelemetryLoaderCollector(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	collector, err := NewOpenTelemetryLoaderCollector(provider.Meter("test"), "test-cache")
+	is.NoError(err)
+
+	collector.ObserveLoad("db", 4, 3, 20*time.Millisecond, nil)
+	collector.ObserveLoad("api", 1, 0, time.Second, errors.New("boom"))
+	collector.AddDeduplicatedKeys(5)
+
+	data := collectOpenTelemetry(t, reader)
+
+	loaderValue := func(name string, loader string) int64 {
+		for _, point := range int64Points[int64](data[name]) {
+			if v, _ := point.Attributes.Value("loader"); v.AsString() == loader {
+				return point.Value
+			}
+		}
+		return -1
+	}
+	is.Equal(int64(3), loaderValue("hot.loader.found", "db"))
+	is.Equal(int64(1), loaderValue("hot.loader.missing", "db"))
+	is.Equal(int64(1), loaderValue("hot.loader.error", "api"))
+	is.Equal(int64(-1), loaderValue("hot.loader.found", "api"))
+
+	deduplicated := int64Points[int64](data["hot.loader.deduplicated"])
+	is.Len(deduplicated, 1)
+	is.Equal(int64(5), deduplicated[0].Value)
+	is.Equal(attribute.NewSet(attribute.String("name", "test-cache")), deduplicated[0].Attributes)
+
+	duration, ok := data["hot.loader.duration"].(metricdata.Histogram[float64])
+	is.True(ok)
+	is.Len(duration.DataPoints, 2)
+
+	batchSize, ok := data["hot.loader.batch_size"].(metricdata.Histogram[int64])
+	is.True(ok)
+	is.Len(batchSize.DataPoints, 2)
+	for _, point := range batchSize.DataPoints {
+		is.Equal(uint64(1), point.Count)
+		is.Equal(PrometheusLoaderBatchSizeBuckets, point.Bounds)
+	}
+}
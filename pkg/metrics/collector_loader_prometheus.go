@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ LoaderCollector = (*PrometheusLoaderCollector)(nil)
+var _ prometheus.Collector = (*PrometheusLoaderCollector)(nil)
+
+// PrometheusLoaderBatchSizeBuckets are the buckets of the batch size histogram: 1, 2, 4, ..., 4096 keys.
+var PrometheusLoaderBatchSizeBuckets = prometheus.ExponentialBuckets(1, 2, 13)
+
+// PrometheusLoaderCollector implements LoaderCollector using Prometheus metrics.
+// Every metric has the `name` label of the cache, and the `loader` label, except the deduplicated keys counter.
+type PrometheusLoaderCollector struct {
+	duration     *prometheus.HistogramVec
+	batchSize    *prometheus.HistogramVec
+	found        *prometheus.CounterVec
+	missing      *prometheus.CounterVec
+	errors       *prometheus.CounterVec
+	deduplicated prometheus.Counter
+}
+
+// NewPrometheusLoaderCollector creates a new Prometheus-based loader metric collector.
+func NewPrometheusLoaderCollector(name string) *PrometheusLoaderCollector {
+	labels := prometheus.Labels{"name": name}
+
+	return &PrometheusLoaderCollector{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        "hot_loader_duration_seconds",
+			Help:        "Duration of the loader calls in seconds",
+			ConstLabels: labels,
+			Buckets:     prometheus.DefBuckets,
+		}, []string{"loader"}),
+		batchSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:        "hot_loader_batch_size",
+			Help:        "Number of keys requested by the loader calls",
+			ConstLabels: labels,
+			Buckets:     PrometheusLoaderBatchSizeBuckets,
+		}, []string{"loader"}),
+		found: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "hot_loader_found_total",
+			Help:        "Total number of keys found by the loaders",
+			ConstLabels: labels,
+		}, []string{"loader"}),
+		missing: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "hot_loader_missing_total",
+			Help:        "Total number of keys requested but not found by the loaders",
+			ConstLabels: labels,
+		}, []string{"loader"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "hot_loader_error_total",
+			Help:        "Total number of failed loader calls",
+			ConstLabels: labels,
+		}, []string{"loader"}),
+		deduplicated: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        "hot_loader_deduplicated_total",
+			Help:        "Total number of keys not loaded because a concurrent load was in-flight",
+			ConstLabels: labels,
+		}),
+	}
+}
+
+// ObserveLoad records the call to a loader.
+func (p *PrometheusLoaderCollector) ObserveLoad(loader string, keys int, found int, duration time.Duration, err error) {
+	p.duration.WithLabelValues(loader).Observe(duration.Seconds())
+	p.batchSize.WithLabelValues(loader).Observe(float64(keys))
+
+	if err != nil {
+		p.errors.WithLabelValues(loader).Inc()
+		return
+	}
+
+	p.found.WithLabelValues(loader).Add(float64(found))
+	p.missing.WithLabelValues(loader).Add(float64(max(keys-found, 0)))
+}
+
+// AddDeduplicatedKeys adds the specified count to the deduplicated keys counter.
+func (p *PrometheusLoaderCollector) AddDeduplicatedKeys(count int64) {
+	p.deduplicated.Add(float64(count))
+}
+
+// Describe implements the prometheus.Collector interface.
+func (p *PrometheusLoaderCollector) Describe(ch chan<- *prometheus.Desc) {
+	p.duration.Describe(ch)
+	p.batchSize.Describe(ch)
+	p.found.Describe(ch)
+	p.missing.Describe(ch)
+	p.errors.Describe(ch)
+	p.deduplicated.Describe(ch)
+}
+
+// Collect implements the prometheus.Collector interface.
+func (p *PrometheusLoaderCollector) Collect(ch chan<- prometheus.Metric) {
+	p.duration.Collect(ch)
+	p.batchSize.Collect(ch)
+	p.found.Collect(ch)
+	p.missing.Collect(ch)
+	p.errors.Collect(ch)
+	p.deduplicated.Collect(ch)
+}
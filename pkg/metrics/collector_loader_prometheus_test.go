@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusLoaderCollector(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	collector := NewPrometheusLoaderCollector("test-cache")
+	collector.ObserveLoad("db", 4, 3, 20*time.Millisecond, nil)
+	collector.ObserveLoad("db", 1, 0, 10*time.Millisecond, nil)
+	collector.ObserveLoad("api", 1, 0, time.Second, errors.New("boom"))
+	// Loaders returning more keys than requested do not count negative missing keys.
+	collector.ObserveLoad("api", 1, 2, time.Millisecond, nil)
+	collector.AddDeduplicatedKeys(5)
+
+	registry := prometheus.NewRegistry()
+	is.NoError(registry.Register(collector))
+	families, err := registry.Gather()
+	is.NoError(err)
+
+	type point struct {
+		loader string
+		value  float64
+		count  uint64
+	}
+	values := map[string][]point{}
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			p := point{}
+			for _, label := range m.GetLabel() {
+				switch label.GetName() {
+				case "name":
+					is.Equal("test-cache", label.GetValue())
+				case "loader":
+					p.loader = label.GetValue()
+				}
+			}
+			switch {
+			case m.GetCounter() != nil:
+				p.value = m.GetCounter().GetValue()
+			case m.GetHistogram() != nil:
+				p.value = m.GetHistogram().GetSampleSum()
+				p.count = m.GetHistogram().GetSampleCount()
+			}
+			values[family.GetName()] = append(values[family.GetName()], p)
+		}
+	}
+
+	is.ElementsMatch([]point{{"api", 2, 0}, {"db", 3, 0}}, values["hot_loader_found_total"])
+	is.ElementsMatch([]point{{"api", 0, 0}, {"db", 2, 0}}, values["hot_loader_missing_total"])
+	is.ElementsMatch([]point{{"api", 1, 0}}, values["hot_loader_error_total"])
+	is.ElementsMatch([]point{{"", 5, 0}}, values["hot_loader_deduplicated_total"])
+	is.ElementsMatch([]point{{"api", 2, 2}, {"db", 5, 2}}, values["hot_loader_batch_size"])
+
+	is.Len(values["hot_loader_duration_seconds"], 2)
+	for _, p := range values["hot_loader_duration_seconds"] {
+		is.Equal(uint64(2), p.count)
+		if p.loader == "db" {
+			is.InDelta(0.03, p.value, 1e-9)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiLoaderCollector(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	a := NewPrometheusLoaderCollector("a")
+	b := NewPrometheusLoaderCollector("b")
+	multi := MultiLoaderCollector{a, b}
+
+	multi.ObserveLoad("db", 10, 7, time.Millisecond, nil)
+	multi.ObserveLoad("db", 5, 0, time.Millisecond, errors.New("boom"))
+	multi.AddDeduplicatedKeys(3)
+
+	for _, c := range []*PrometheusLoaderCollector{a, b} {
+		is.InDelta(7.0, testutil.ToFloat64(c.found.WithLabelValues("db")), 0)
+		is.InDelta(3.0, testutil.ToFloat64(c.missing.WithLabelValues("db")), 0)
+		is.InDelta(1.0, testutil.ToFloat64(c.errors.WithLabelValues("db")), 0)
+		is.InDelta(3.0, testutil.ToFloat64(c.deduplicated), 0)
+	}
+
+	is.NotPanics(func() {
+		MultiLoaderCollector(nil).ObserveLoad("db", 1, 1, time.Millisecond, nil)
+		MultiLoaderCollector(nil).AddDeduplicatedKeys(1)
+	})
+}
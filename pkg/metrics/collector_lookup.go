@@ -0,0 +1,78 @@
+package metrics
+
+// LookupResult is the outcome of the lookup of a key in a cache, from the point of view of the caller.
+type LookupResult string
+
+// LookupResult values. Every key read with a loader falls in exactly one of them.
+const (
+	LookupResultFreshHit   LookupResult = "fresh_hit"   // A value was served, before its TTL.
+	LookupResultStaleHit   LookupResult = "stale_hit"   // A value was served during its stale window, and revalidated in the background.
+	LookupResultMissingHit LookupResult = "missing_hit" // A cached missing key was served (negative caching).
+	LookupResultLoaded     LookupResult = "loaded"      // A miss was loaded.
+	LookupResultNotFound   LookupResult = "not_found"   // A miss was still missing after calling the loaders.
+	LookupResultLoadError  LookupResult = "load_error"  // A miss failed to load.
+)
+
+// LookupResults lists every LookupResult.
+var LookupResults = []LookupResult{
+	LookupResultFreshHit,
+	LookupResultStaleHit,
+	LookupResultMissingHit,
+	LookupResultLoaded,
+	LookupResultNotFound,
+	LookupResultLoadError,
+}
+
+// RevalidationResult is the outcome of the background revalidation of a stale key.
+type RevalidationResult string
+
+// RevalidationResult values.
+const (
+	RevalidationResultSuccess     RevalidationResult = "success"       // The key was reloaded.
+	RevalidationResultFailure     RevalidationResult = "failure"       // The loaders failed, and the stale entry was dropped.
+	RevalidationResultKeptOnError RevalidationResult = "kept_on_error" // The loaders failed, and the stale entry was kept.
+)
+
+// RevalidationResults lists every RevalidationResult.
+var RevalidationResults = []RevalidationResult{
+	RevalidationResultSuccess,
+	RevalidationResultFailure,
+	RevalidationResultKeptOnError,
+}
+
+// LookupCollector defines the interface for the lookup metrics of a cache.
+// Unlike Collector, which counts the operations of each shard, a LookupCollector counts the keys requested
+// to the cache, once the TTL, the stale window and the loaders have been applied.
+type LookupCollector interface {
+	AddLookups(result LookupResult, count int64)
+	// AddExpiredOnRead counts the expired entries found by a read, and removed from the cache.
+	AddExpiredOnRead(count int64)
+	AddRevalidations(result RevalidationResult, count int64)
+}
+
+var _ LookupCollector = (MultiLookupCollector)(nil)
+
+// MultiLookupCollector forwards every lookup metric to several collectors.
+// It is used when metrics are exported to more than one backend.
+type MultiLookupCollector []LookupCollector
+
+// AddLookups adds the specified count to the lookup counter of every collector.
+func (m MultiLookupCollector) AddLookups(result LookupResult, count int64) {
+	for _, c := range m {
+		c.AddLookups(result, count)
+	}
+}
+
+// AddExpiredOnRead adds the specified count to the expired-on-read counter of every collector.
+func (m MultiLookupCollector) AddExpiredOnRead(count int64) {
+	for _, c := range m {
+		c.AddExpiredOnRead(count)
+	}
+}
+
+// AddRevalidations adds the specified count to the revalidation counter of every collector.
+func (m MultiLookupCollector) AddRevalidations(result RevalidationResult, count int64) {
+	for _, c := range m {
+		c.AddRevalidations(result, count)
+	}
+}
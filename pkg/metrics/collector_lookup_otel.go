@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var _ LookupCollector = (*OpenTelemetryLookupCollector)(nil)
+
+// OpenTelemetryLookupCollector implements LookupCollector using OpenTelemetry synchronous instruments.
+// Every measurement has the `name` attribute of the cache.
+type OpenTelemetryLookupCollector struct {
+	lookup        metric.Int64Counter
+	revalidation  metric.Int64Counter
+	expiredOnRead metric.Int64Counter
+
+	// Attributes of each result, resolved once.
+	attributes             metric.MeasurementOption
+	lookupAttributes       map[LookupResult]metric.MeasurementOption
+	revalidationAttributes map[RevalidationResult]metric.MeasurementOption
+}
+
+// NewOpenTelemetryLookupCollector creates the instruments of the lookup metrics.
+// Instrument names mirror the Prometheus metrics, without the `_total` suffix.
+func NewOpenTelemetryLookupCollector(meter metric.Meter, name string) (*OpenTelemetryLookupCollector, error) {
+	var err error
+	nameAttribute := attribute.String("name", name)
+	o := &OpenTelemetryLookupCollector{
+		attributes:             metric.WithAttributeSet(attribute.NewSet(nameAttribute)),
+		lookupAttributes:       make(map[LookupResult]metric.MeasurementOption, len(LookupResults)),
+		revalidationAttributes: make(map[RevalidationResult]metric.MeasurementOption, len(RevalidationResults)),
+	}
+
+	if o.lookup, err = meter.Int64Counter("hot.lookup", metric.WithDescription("Total number of keys read from the cache, by result")); err != nil {
+		return nil, err
+	}
+	if o.revalidation, err = meter.Int64Counter("hot.revalidation", metric.WithDescription("Total number of keys revalidated in the background, by result")); err != nil {
+		return nil, err
+	}
+	if o.expiredOnRead, err = meter.Int64Counter("hot.expired_on_read", metric.WithDescription("Total number of expired entries found by a read and removed from the cache")); err != nil {
+		return nil, err
+	}
+
+	for _, result := range LookupResults {
+		o.lookupAttributes[result] = metric.WithAttributeSet(attribute.NewSet(nameAttribute, attribute.String("result", string(result))))
+	}
+	for _, result := range RevalidationResults {
+		o.revalidationAttributes[result] = metric.WithAttributeSet(attribute.NewSet(nameAttribute, attribute.String("result", string(result))))
+	}
+
+	return o, nil
+}
+
+// AddLookups adds the specified count to the lookup counter of the given result.
+func (o *OpenTelemetryLookupCollector) AddLookups(result LookupResult, count int64) {
+	if attributes, ok := o.lookupAttributes[result]; ok {
+		o.lookup.Add(context.Background(), count, attributes)
+	}
+}
+
+// AddExpiredOnRead adds the specified count to the expired-on-read counter.
+func (o *OpenTelemetryLookupCollector) AddExpiredOnRead(count int64) {
+	o.expiredOnRead.Add(context.Background(), count, o.attributes)
+}
+
+// AddRevalidations adds the specified count to the revalidation counter of the given result.
+func (o *OpenTelemetryLookupCollector) AddRevalidations(result RevalidationResult, count int64) {
+	if attributes, ok := o.revalidationAttributes[result]; ok {
+		o.revalidation.Add(context.Background(), count, attributes)
+	}
+}
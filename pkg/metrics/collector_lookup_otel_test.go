@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestOpenTelemetryLookupCollector(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	collector, err := NewOpenTelemetryLookupCollector(provider.Meter("test"), "test-cache")
+	is.NoError(err)
+
+	collector.AddLookups(LookupResultMissingHit, 2)
+	collector.AddLookups(LookupResultLoaded, 3)
+	collector.AddLookups(LookupResult("unknown"), 2)
+	collector.AddExpiredOnRead(1)
+	collector.AddRevalidations(RevalidationResultKeptOnError, 4)
+
+	data := collectOpenTelemetry(t, reader)
+
+	values := func(name string) map[string]int64 {
+		output := map[string]int64{}
+		for _, point := range int64Points[int64](data[name]) {
+			v, _ := point.Attributes.Value("name")
+			is.Equal("test-cache", v.AsString())
+			result, _ := point.Attributes.Value("result")
+			output[result.AsString()] = point.Value
+		}
+		return output
+	}
+	is.Equal(map[string]int64{"missing_hit": 2, "loaded": 3}, values("hot.lookup"))
+	is.Equal(map[string]int64{"": 1}, values("hot.expired_on_read"))
+	is.Equal(map[string]int64{"kept_on_error": 4}, values("hot.revalidation"))
+}
@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ LookupCollector = (*PrometheusLookupCollector)(nil)
+var _ prometheus.Collector = (*PrometheusLookupCollector)(nil)
+
+// PrometheusLookupCollector implements LookupCollector using Prometheus metrics.
+// Every metric has the `name` label of the cache.
+type PrometheusLookupCollector struct {
+	lookupVec       *prometheus.CounterVec
+	revalidationVec *prometheus.CounterVec
+	expiredOnRead   prometheus.Counter
+
+	// Counters of each label value, resolved once.
+	lookups       map[LookupResult]prometheus.Counter
+	revalidations map[RevalidationResult]prometheus.Counter
+}
+
+// NewPrometheusLookupCollector creates a new Prometheus-based lookup metric collector.
+func NewPrometheusLookupCollector(name string) *PrometheusLookupCollector {
+	labels := prometheus.Labels{"name": name}
+
+	p := &PrometheusLookupCollector{
+		lookupVec: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "hot_lookup_total",
+			Help:        "Total number of keys read from the cache, by result",
+			ConstLabels: labels,
+		}, []string{"result"}),
+		revalidationVec: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "hot_revalidation_total",
+			Help:        "Total number of keys revalidated in the background, by result",
+			ConstLabels: labels,
+		}, []string{"result"}),
+		expiredOnRead: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        "hot_expired_on_read_total",
+			Help:        "Total number of expired entries found by a read and removed from the cache",
+			ConstLabels: labels,
+		}),
+		lookups:       make(map[LookupResult]prometheus.Counter, len(LookupResults)),
+		revalidations: make(map[RevalidationResult]prometheus.Counter, len(RevalidationResults)),
+	}
+
+	for _, result := range LookupResults {
+		p.lookups[result] = p.lookupVec.WithLabelValues(string(result))
+	}
+	for _, result := range RevalidationResults {
+		p.revalidations[result] = p.revalidationVec.WithLabelValues(string(result))
+	}
+
+	return p
+}
+
+// AddLookups adds the specified count to the lookup counter of the given result.
+func (p *PrometheusLookupCollector) AddLookups(result LookupResult, count int64) {
+	if counter, ok := p.lookups[result]; ok {
+		counter.Add(float64(count))
+	}
+}
+
+// AddExpiredOnRead adds the specified count to the expired-on-read counter.
+func (p *PrometheusLookupCollector) AddExpiredOnRead(count int64) {
+	p.expiredOnRead.Add(float64(count))
+}
+
+// AddRevalidations adds the specified count to the revalidation counter of the given result.
+func (p *PrometheusLookupCollector) AddRevalidations(result RevalidationResult, count int64) {
+	if counter, ok := p.revalidations[result]; ok {
+		counter.Add(float64(count))
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (p *PrometheusLookupCollector) Describe(ch chan<- *prometheus.Desc) {
+	p.lookupVec.Describe(ch)
+	p.revalidationVec.Describe(ch)
+	p.expiredOnRead.Describe(ch)
+}
+
+// Collect implements the prometheus.Collector interface.
+func (p *PrometheusLookupCollector) Collect(ch chan<- prometheus.Metric) {
+	p.lookupVec.Collect(ch)
+	p.revalidationVec.Collect(ch)
+	p.expiredOnRead.Collect(ch)
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusLookupCollector(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	collector := NewPrometheusLookupCollector("test-cache")
+	collector.AddLookups(LookupResultFreshHit, 5)
+	collector.AddLookups(LookupResultStaleHit, 1)
+	collector.AddLookups(LookupResultNotFound, 2)
+	collector.AddLookups(LookupResult("unknown"), 2)
+	collector.AddExpiredOnRead(3)
+	collector.AddRevalidations(RevalidationResultSuccess, 4)
+	collector.AddRevalidations(RevalidationResultFailure, 1)
+
+	registry := prometheus.NewRegistry()
+	is.NoError(registry.Register(collector))
+	families, err := registry.Gather()
+	is.NoError(err)
+
+	values := map[string]float64{}
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			name := family.GetName()
+			for _, label := range m.GetLabel() {
+				switch label.GetName() {
+				case "name":
+					is.Equal("test-cache", label.GetValue())
+				case "result":
+					name += "/" + label.GetValue()
+				}
+			}
+			values[name] = m.GetCounter().GetValue()
+		}
+	}
+
+	is.Equal(map[string]float64{
+		"hot_lookup_total/fresh_hit":           5,
+		"hot_lookup_total/stale_hit":           1,
+		"hot_lookup_total/missing_hit":         0,
+		"hot_lookup_total/loaded":              0,
+		"hot_lookup_total/not_found":           2,
+		"hot_lookup_total/load_error":          0,
+		"hot_expired_on_read_total":            3,
+		"hot_revalidation_total/success":       4,
+		"hot_revalidation_total/failure":       1,
+		"hot_revalidation_total/kept_on_error": 0,
+	}, values)
+}
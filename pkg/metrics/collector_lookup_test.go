@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiLookupCollector(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	a := NewPrometheusLookupCollector("a")
+	b := NewPrometheusLookupCollector("b")
+	multi := MultiLookupCollector{a, b}
+
+	multi.AddLookups(LookupResultFreshHit, 3)
+	multi.AddExpiredOnRead(2)
+	multi.AddRevalidations(RevalidationResultKeptOnError, 1)
+
+	for _, c := range []*PrometheusLookupCollector{a, b} {
+		is.InDelta(3.0, testutil.ToFloat64(c.lookups[LookupResultFreshHit]), 0)
+		is.InDelta(2.0, testutil.ToFloat64(c.expiredOnRead), 0)
+		is.InDelta(1.0, testutil.ToFloat64(c.revalidations[RevalidationResultKeptOnError]), 0)
+	}
+
+	is.NotPanics(func() {
+		MultiLookupCollector(nil).AddLookups(LookupResultFreshHit, 1)
+		MultiLookupCollector(nil).AddExpiredOnRead(1)
+		MultiLookupCollector(nil).AddRevalidations(RevalidationResultSuccess, 1)
+	})
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ prometheus.Collector = (*PrometheusMissRatioCurveCollector)(nil)
+
+// MissRatioPoint is the estimated hit ratio of a cache at a capacity relative to its current capacity.
+type MissRatioPoint struct {
+	CapacityRatio float64
+	HitRatio      float64
+}
+
+// PrometheusMissRatioCurveCollector exports the estimated miss-ratio curve of a cache as the
+// `hot_mrc_hit_ratio` gauge, labeled by `capacity_ratio`, and its sampling rate as `hot_mrc_sample_rate`.
+type PrometheusMissRatioCurveCollector struct {
+	hitRatioDesc   *prometheus.Desc
+	sampleRateDesc *prometheus.Desc
+	curve          func() (sampleRate float64, points []MissRatioPoint)
+}
+
+// NewPrometheusMissRatioCurveCollector creates a collector exporting the curve returned by `curve` on every scrape.
+func NewPrometheusMissRatioCurveCollector(name string, curve func() (sampleRate float64, points []MissRatioPoint)) *PrometheusMissRatioCurveCollector {
+	labels := prometheus.Labels{"name": name}
+
+	return &PrometheusMissRatioCurveCollector{
+		hitRatioDesc: prometheus.NewDesc(
+			"hot_mrc_hit_ratio",
+			"Estimated hit ratio of an LRU cache of capacity_ratio times the current capacity",
+			[]string{"capacity_ratio"},
+			labels,
+		),
+		sampleRateDesc: prometheus.NewDesc(
+			"hot_mrc_sample_rate",
+			"Fraction of the keys sampled to estimate the miss-ratio curve",
+			nil,
+			labels,
+		),
+		curve: curve,
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (p *PrometheusMissRatioCurveCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- p.hitRatioDesc
+	ch <- p.sampleRateDesc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (p *PrometheusMissRatioCurveCollector) Collect(ch chan<- prometheus.Metric) {
+	sampleRate, points := p.curve()
+
+	ch <- prometheus.MustNewConstMetric(p.sampleRateDesc, prometheus.GaugeValue, sampleRate)
+	for _, point := range points {
+		ch <- prometheus.MustNewConstMetric(p.hitRatioDesc, prometheus.GaugeValue, point.HitRatio, strconv.FormatFloat(point.CapacityRatio, 'f', -1, 64))
+	}
+}
@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusMissRatioCurveCollector(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	collector := NewPrometheusMissRatioCurveCollector("test-cache", func() (float64, []MissRatioPoint) {
+		return 0.01, []MissRatioPoint{{CapacityRatio: 0.25, HitRatio: 0.5}, {CapacityRatio: 4, HitRatio: 0.9}}
+	})
+
+	expected := `
+# HELP hot_mrc_hit_ratio Estimated hit ratio of an LRU cache of capacity_ratio times the current capacity
+# TYPE hot_mrc_hit_ratio gauge
+hot_mrc_hit_ratio{capacity_ratio="0.25",name="test-cache"} 0.5
+hot_mrc_hit_ratio{capacity_ratio="4",name="test-cache"} 0.9
+# HELP hot_mrc_sample_rate Fraction of the keys sampled to estimate the miss-ratio curve
+# TYPE hot_mrc_sample_rate gauge
+hot_mrc_sample_rate{name="test-cache"} 0.01
+`
+	is.NoError(testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
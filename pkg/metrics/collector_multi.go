@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"github.com/samber/hot/pkg/base"
+)
+
+var _ Collector = (MultiCollector)(nil)
+
+// MultiCollector forwards every metric to several collectors.
+// It is used when metrics are exported to more than one backend.
+type MultiCollector []Collector
+
+// IncInsertion increments the insertion counter of every collector.
+func (m MultiCollector) IncInsertion() {
+	for _, c := range m {
+		c.IncInsertion()
+	}
+}
+
+// AddInsertions adds the specified count to the insertion counter of every collector.
+func (m MultiCollector) AddInsertions(count int64) {
+	for _, c := range m {
+		c.AddInsertions(count)
+	}
+}
+
+// IncEviction increments the eviction counter of every collector.
+func (m MultiCollector) IncEviction(reason base.EvictionReason) {
+	for _, c := range m {
+		c.IncEviction(reason)
+	}
+}
+
+// AddEvictions adds the specified count to the eviction counter of every collector.
+func (m MultiCollector) AddEvictions(reason base.EvictionReason, count int64) {
+	for _, c := range m {
+		c.AddEvictions(reason, count)
+	}
+}
+
+// IncHit increments the hit counter of every collector.
+func (m MultiCollector) IncHit() {
+	for _, c := range m {
+		c.IncHit()
+	}
+}
+
+// AddHits adds the specified count to the hit counter of every collector.
+func (m MultiCollector) AddHits(count int64) {
+	for _, c := range m {
+		c.AddHits(count)
+	}
+}
+
+// IncMiss increments the miss counter of every collector.
+func (m MultiCollector) IncMiss() {
+	for _, c := range m {
+		c.IncMiss()
+	}
+}
+
+// AddMisses adds the specified count to the miss counter of every collector.
+func (m MultiCollector) AddMisses(count int64) {
+	for _, c := range m {
+		c.AddMisses(count)
+	}
+}
+
+// UpdateSizeBytes updates the cache size of every collector.
+func (m MultiCollector) UpdateSizeBytes(sizeBytes int64) {
+	for _, c := range m {
+		c.UpdateSizeBytes(sizeBytes)
+	}
+}
+
+// UpdateLength updates the cache length of every collector.
+func (m MultiCollector) UpdateLength(length int64) {
+	for _, c := range m {
+		c.UpdateLength(length)
+	}
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/samber/hot/pkg/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiCollector(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	a := NewPrometheusCollector("a", -1, base.CacheModeMain, 10, "lru", nil, nil, nil, nil, nil)
+	b := NewPrometheusCollector("b", -1, base.CacheModeMain, 10, "lru", nil, nil, nil, nil, nil)
+	multi := MultiCollector{a, b}
+
+	multi.IncInsertion()
+	multi.AddInsertions(2)
+	multi.IncEviction(base.EvictionReasonCapacity)
+	multi.AddEvictions(base.EvictionReasonTTL, 2)
+	multi.IncHit()
+	multi.AddHits(3)
+	multi.IncMiss()
+	multi.AddMisses(4)
+	multi.UpdateSizeBytes(100)
+	multi.UpdateLength(5)
+
+	for _, c := range []*PrometheusCollector{a, b} {
+		is.Equal(int64(3), atomic.LoadInt64(&c.insertionCount))
+		is.Equal(int64(1), atomic.LoadInt64(c.evictionCount[string(base.EvictionReasonCapacity)]))
+		is.Equal(int64(2), atomic.LoadInt64(c.evictionCount[string(base.EvictionReasonTTL)]))
+		is.Equal(int64(4), atomic.LoadInt64(&c.hitCount))
+		is.Equal(int64(5), atomic.LoadInt64(&c.missCount))
+		is.Equal(int64(100), atomic.LoadInt64(&c.sizeBytes))
+		is.Equal(int64(5), atomic.LoadInt64(&c.length))
+	}
+}
@@ -0,0 +1,257 @@
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/samber/hot/pkg/base"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var _ Collector = (*OpenTelemetryCollector)(nil)
+
+// OpenTelemetryInstruments holds the asynchronous instruments shared by the collectors of a meter.
+// Counters are accumulated with atomic operations, and reported when the meter is collected.
+type OpenTelemetryInstruments struct {
+	insertion metric.Int64ObservableCounter
+	eviction  metric.Int64ObservableCounter
+	hit       metric.Int64ObservableCounter
+	miss      metric.Int64ObservableCounter
+	size      metric.Int64ObservableGauge
+	length    metric.Int64ObservableGauge
+
+	settingsCapacity         metric.Int64ObservableGauge
+	settingsAlgorithm        metric.Int64ObservableGauge
+	settingsTTL              metric.Float64ObservableGauge
+	settingsJitterLambda     metric.Float64ObservableGauge
+	settingsJitterUpperBound metric.Float64ObservableGauge
+	settingsStale            metric.Float64ObservableGauge
+	settingsMissingCapacity  metric.Int64ObservableGauge
+}
+
+// NewOpenTelemetryInstruments creates the instruments of the cache metrics.
+// Instrument names mirror the Prometheus metrics, without the `_total` suffix.
+func NewOpenTelemetryInstruments(meter metric.Meter) (*OpenTelemetryInstruments, error) {
+	var err error
+	i := &OpenTelemetryInstruments{}
+
+	if i.insertion, err = meter.Int64ObservableCounter("hot.insertion", metric.WithDescription("Total number of items inserted into the cache")); err != nil {
+		return nil, err
+	}
+	if i.eviction, err = meter.Int64ObservableCounter("hot.eviction", metric.WithDescription("Total number of items evicted from the cache")); err != nil {
+		return nil, err
+	}
+	if i.hit, err = meter.Int64ObservableCounter("hot.hit", metric.WithDescription("Total number of cache hits")); err != nil {
+		return nil, err
+	}
+	if i.miss, err = meter.Int64ObservableCounter("hot.miss", metric.WithDescription("Total number of cache misses")); err != nil {
+		return nil, err
+	}
+	if i.size, err = meter.Int64ObservableGauge("hot.size", metric.WithDescription("Current size of the cache in bytes (including keys and values)"), metric.WithUnit("By")); err != nil {
+		return nil, err
+	}
+	if i.length, err = meter.Int64ObservableGauge("hot.length", metric.WithDescription("Current length of the cache")); err != nil {
+		return nil, err
+	}
+
+	if i.settingsCapacity, err = meter.Int64ObservableGauge("hot.settings.capacity", metric.WithDescription("Maximum number of items the cache can hold")); err != nil {
+		return nil, err
+	}
+	if i.settingsAlgorithm, err = meter.Int64ObservableGauge("hot.settings.algorithm", metric.WithDescription("Eviction algorithm type (0=lru, 1=lfu, 2=arc, 3=2q, 4=fifo, 5=tinylfu, 6=wtinylfu, 7=s3fifo, 8=sieve)")); err != nil {
+		return nil, err
+	}
+	if i.settingsTTL, err = meter.Float64ObservableGauge("hot.settings.ttl", metric.WithDescription("Time-to-live duration in seconds"), metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if i.settingsJitterLambda, err = meter.Float64ObservableGauge("hot.settings.jitter_lambda", metric.WithDescription("Jitter lambda parameter for TTL randomization")); err != nil {
+		return nil, err
+	}
+	if i.settingsJitterUpperBound, err = meter.Float64ObservableGauge("hot.settings.jitter_upper_bound", metric.WithDescription("Jitter upper bound duration in seconds"), metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if i.settingsStale, err = meter.Float64ObservableGauge("hot.settings.stale", metric.WithDescription("Stale duration in seconds"), metric.WithUnit("s")); err != nil {
+		return nil, err
+	}
+	if i.settingsMissingCapacity, err = meter.Int64ObservableGauge("hot.settings.missing_capacity", metric.WithDescription("Maximum number of missing keys the cache can hold")); err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// Observables returns the instruments, to be passed to metric.Meter.RegisterCallback.
+func (i *OpenTelemetryInstruments) Observables() []metric.Observable {
+	return []metric.Observable{
+		i.insertion, i.eviction, i.hit, i.miss, i.size, i.length,
+		i.settingsCapacity, i.settingsAlgorithm, i.settingsTTL, i.settingsJitterLambda,
+		i.settingsJitterUpperBound, i.settingsStale, i.settingsMissingCapacity,
+	}
+}
+
+// OpenTelemetryCollector implements Collector using OpenTelemetry asynchronous instruments.
+// Its values are reported by Observe, which is called from a callback registered on the meter.
+type OpenTelemetryCollector struct {
+	instruments *OpenTelemetryInstruments
+	attributes  metric.MeasurementOption
+
+	// Counters - use atomic operations for lock-free performance
+	insertionCount atomic.Int64
+	evictionCount  [4]atomic.Int64 // indexed like base.EvictionReasons
+	hitCount       atomic.Int64
+	missCount      atomic.Int64
+
+	// Gauges
+	sizeBytes atomic.Int64
+	length    atomic.Int64
+
+	// Static configuration
+	capacity         int64
+	algorithm        int64
+	ttl              *time.Duration
+	jitterLambda     *float64
+	jitterUpperBound *time.Duration
+	stale            *time.Duration
+	missingCapacity  *int
+}
+
+// NewOpenTelemetryCollector creates a new OpenTelemetry-based metric collector.
+// Attributes match the labels of the Prometheus collector: `name`, `mode` and `shard`.
+func NewOpenTelemetryCollector(instruments *OpenTelemetryInstruments, name string, shard int, mode base.CacheMode, capacity int, algorithm string, ttl *time.Duration, jitterLambda *float64, jitterUpperBound *time.Duration, stale *time.Duration, missingCapacity *int) *OpenTelemetryCollector {
+	attributes := []attribute.KeyValue{
+		attribute.String("name", name),
+		attribute.String("mode", string(mode)),
+	}
+	if shard >= 0 {
+		attributes = append(attributes, attribute.String("shard", strconv.Itoa(shard)))
+	}
+
+	return &OpenTelemetryCollector{
+		instruments: instruments,
+		attributes:  metric.WithAttributeSet(attribute.NewSet(attributes...)),
+
+		capacity:         int64(capacity),
+		algorithm:        algorithmValue(algorithm),
+		ttl:              ttl,
+		jitterLambda:     jitterLambda,
+		jitterUpperBound: jitterUpperBound,
+		stale:            stale,
+		missingCapacity:  missingCapacity,
+	}
+}
+
+// IncInsertion atomically increments the insertion counter.
+func (o *OpenTelemetryCollector) IncInsertion() {
+	o.insertionCount.Add(1)
+}
+
+// AddInsertions atomically adds the specified count to the insertion counter.
+func (o *OpenTelemetryCollector) AddInsertions(count int64) {
+	o.insertionCount.Add(count)
+}
+
+// IncEviction atomically increments the eviction counter for the given reason.
+func (o *OpenTelemetryCollector) IncEviction(reason base.EvictionReason) {
+	o.AddEvictions(reason, 1)
+}
+
+// AddEvictions atomically adds the specified count to the eviction counter for the given reason.
+func (o *OpenTelemetryCollector) AddEvictions(reason base.EvictionReason, count int64) {
+	for i, r := range base.EvictionReasons {
+		if r == reason {
+			o.evictionCount[i].Add(count)
+			return
+		}
+	}
+}
+
+// IncHit atomically increments the hit counter.
+func (o *OpenTelemetryCollector) IncHit() {
+	o.hitCount.Add(1)
+}
+
+// AddHits atomically adds the specified count to the hit counter.
+func (o *OpenTelemetryCollector) AddHits(count int64) {
+	o.hitCount.Add(count)
+}
+
+// IncMiss atomically increments the miss counter.
+func (o *OpenTelemetryCollector) IncMiss() {
+	o.missCount.Add(1)
+}
+
+// AddMisses atomically adds the specified count to the miss counter.
+func (o *OpenTelemetryCollector) AddMisses(count int64) {
+	o.missCount.Add(count)
+}
+
+// UpdateSizeBytes atomically updates the cache size in bytes.
+func (o *OpenTelemetryCollector) UpdateSizeBytes(sizeBytes int64) {
+	o.sizeBytes.Store(sizeBytes)
+}
+
+// UpdateLength atomically updates the cache length.
+func (o *OpenTelemetryCollector) UpdateLength(length int64) {
+	o.length.Store(length)
+}
+
+// Observe reports the current values to the observer of a meter callback.
+func (o *OpenTelemetryCollector) Observe(_ context.Context, observer metric.Observer) {
+	i := o.instruments
+
+	observer.ObserveInt64(i.insertion, o.insertionCount.Load(), o.attributes)
+	observer.ObserveInt64(i.hit, o.hitCount.Load(), o.attributes)
+	observer.ObserveInt64(i.miss, o.missCount.Load(), o.attributes)
+	observer.ObserveInt64(i.size, o.sizeBytes.Load(), o.attributes)
+	observer.ObserveInt64(i.length, o.length.Load(), o.attributes)
+
+	for idx, reason := range base.EvictionReasons {
+		observer.ObserveInt64(i.eviction, o.evictionCount[idx].Load(), o.attributes, metric.WithAttributes(attribute.String("reason", string(reason))))
+	}
+
+	observer.ObserveInt64(i.settingsCapacity, o.capacity, o.attributes)
+	observer.ObserveInt64(i.settingsAlgorithm, o.algorithm, o.attributes)
+	if o.ttl != nil {
+		observer.ObserveFloat64(i.settingsTTL, o.ttl.Seconds(), o.attributes)
+	}
+	if o.jitterLambda != nil {
+		observer.ObserveFloat64(i.settingsJitterLambda, *o.jitterLambda, o.attributes)
+	}
+	if o.jitterUpperBound != nil {
+		observer.ObserveFloat64(i.settingsJitterUpperBound, o.jitterUpperBound.Seconds(), o.attributes)
+	}
+	if o.stale != nil {
+		observer.ObserveFloat64(i.settingsStale, o.stale.Seconds(), o.attributes)
+	}
+	if o.missingCapacity != nil {
+		observer.ObserveInt64(i.settingsMissingCapacity, int64(*o.missingCapacity), o.attributes)
+	}
+}
+
+// algorithmValue converts an algorithm name to the numeric value of the `settings.algorithm` gauge.
+func algorithmValue(algorithm string) int64 {
+	switch algorithm {
+	case "lru":
+		return 0
+	case "lfu":
+		return 1
+	case "arc":
+		return 2
+	case "2q":
+		return 3
+	case "fifo":
+		return 4
+	case "tinylfu":
+		return 5
+	case "wtinylfu":
+		return 6
+	case "s3fifo":
+		return 7
+	case "sieve":
+		return 8
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/samber/hot/pkg/base"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func collectOpenTelemetry(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+
+	output := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			output[m.Name] = m.Data
+		}
+	}
+	return output
+}
+
+func int64Points[N int64 | float64](data metricdata.Aggregation) []metricdata.DataPoint[N] {
+	switch d := data.(type) {
+	case metricdata.Sum[N]:
+		return d.DataPoints
+	case metricdata.Gauge[N]:
+		return d.DataPoints
+	}
+	return nil
+}
+
+func TestOpenTelemetryCollector(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	meter := provider.Meter("test")
+
+	instruments, err := NewOpenTelemetryInstruments(meter)
+	is.NoError(err)
+
+	ttl := 30 * time.Second
+	collector := NewOpenTelemetryCollector(instruments, "test-cache", 2, base.CacheModeMain, 100, "arc", &ttl, nil, nil, nil, nil)
+
+	collector.IncInsertion()
+	collector.AddInsertions(2)
+	collector.IncHit()
+	collector.AddHits(4)
+	collector.IncMiss()
+	collector.AddMisses(2)
+	collector.IncEviction(base.EvictionReasonCapacity)
+	collector.AddEvictions(base.EvictionReasonTTL, 3)
+	collector.AddEvictions(base.EvictionReason("unknown"), 3)
+	collector.UpdateSizeBytes(1024)
+	collector.UpdateLength(7)
+
+	_, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		collector.Observe(ctx, o)
+		return nil
+	}, instruments.Observables()...)
+	is.NoError(err)
+
+	metrics := collectOpenTelemetry(t, reader)
+
+	expectedAttrs := attribute.NewSet(
+		attribute.String("name", "test-cache"),
+		attribute.String("mode", "main"),
+		attribute.String("shard", "2"),
+	)
+
+	values := map[string]int64{
+		"hot.insertion":          3,
+		"hot.hit":                5,
+		"hot.miss":               3,
+		"hot.size":               1024,
+		"hot.length":             7,
+		"hot.settings.capacity":  100,
+		"hot.settings.algorithm": 2,
+	}
+	for name, expected := range values {
+		points := int64Points[int64](metrics[name])
+		is.Len(points, 1, name)
+		is.Equal(expected, points[0].Value, name)
+		is.True(expectedAttrs.Equals(&points[0].Attributes), name)
+	}
+
+	sum, ok := metrics["hot.insertion"].(metricdata.Sum[int64])
+	is.True(ok)
+	is.True(sum.IsMonotonic)
+	is.Equal(metricdata.CumulativeTemporality, sum.Temporality)
+
+	evictions := map[string]int64{}
+	for _, point := range int64Points[int64](metrics["hot.eviction"]) {
+		reason, _ := point.Attributes.Value("reason")
+		evictions[reason.AsString()] = point.Value
+	}
+	is.Equal(map[string]int64{"capacity": 1, "ttl": 3, "manual": 0, "stale": 0}, evictions)
+
+	ttlPoints := int64Points[float64](metrics["hot.settings.ttl"])
+	is.Len(ttlPoints, 1)
+	is.InDelta(30, ttlPoints[0].Value, 0.001)
+
+	// optional settings are not reported
+	is.NotContains(metrics, "hot.settings.stale")
+	is.NotContains(metrics, "hot.settings.missing_capacity")
+}
+
+func TestOpenTelemetryCollector_noShard(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+
+	instruments, err := NewOpenTelemetryInstruments(meter)
+	is.NoError(err)
+
+	collector := NewOpenTelemetryCollector(instruments, "test-cache", -1, base.CacheModeMissing, 10, "unknown", nil, nil, nil, nil, nil)
+	_, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		collector.Observe(ctx, o)
+		return nil
+	}, instruments.Observables()...)
+	is.NoError(err)
+
+	points := int64Points[int64](collectOpenTelemetry(t, reader)["hot.settings.algorithm"])
+	is.Len(points, 1)
+	is.Equal(int64(-1), points[0].Value)
+	_, ok := points[0].Attributes.Value("shard")
+	is.False(ok)
+	mode, _ := points[0].Attributes.Value("mode")
+	is.Equal("missing", mode.AsString())
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ prometheus.Collector = (*PrometheusShadowCollector)(nil)
+
+// ShadowResult is the activity of a shadow cache, replaying the reads of a cache with another eviction algorithm.
+// When keys are sampled, Lookups and Hits are extrapolated from the sampled keys. Both must only grow.
+type ShadowResult struct {
+	Algorithm string
+	Lookups   float64
+	Hits      float64
+}
+
+// PrometheusShadowCollector exports the activity of the shadow caches as the `hot_shadow_lookup_total`
+// and `hot_shadow_hit_total` counters, labeled by `algorithm`. They compare to `hot_hit_total` and `hot_miss_total`.
+type PrometheusShadowCollector struct {
+	lookupsDesc *prometheus.Desc
+	hitsDesc    *prometheus.Desc
+	results     func() []ShadowResult
+}
+
+// NewPrometheusShadowCollector creates a collector exporting the results returned by `results` on every scrape.
+func NewPrometheusShadowCollector(name string, results func() []ShadowResult) *PrometheusShadowCollector {
+	labels := prometheus.Labels{"name": name}
+
+	return &PrometheusShadowCollector{
+		lookupsDesc: prometheus.NewDesc(
+			"hot_shadow_lookup_total",
+			"Number of keys read from the cache and replayed in a shadow cache using another eviction algorithm",
+			[]string{"algorithm"},
+			labels,
+		),
+		hitsDesc: prometheus.NewDesc(
+			"hot_shadow_hit_total",
+			"Estimated number of keys found in a shadow cache using another eviction algorithm",
+			[]string{"algorithm"},
+			labels,
+		),
+		results: results,
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (p *PrometheusShadowCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- p.lookupsDesc
+	ch <- p.hitsDesc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (p *PrometheusShadowCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, result := range p.results() {
+		ch <- prometheus.MustNewConstMetric(p.lookupsDesc, prometheus.CounterValue, result.Lookups, result.Algorithm)
+		ch <- prometheus.MustNewConstMetric(p.hitsDesc, prometheus.CounterValue, result.Hits, result.Algorithm)
+	}
+}
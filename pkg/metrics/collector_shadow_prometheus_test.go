@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusShadowCollector(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	collector := NewPrometheusShadowCollector("test-cache", func() []ShadowResult {
+		return []ShadowResult{
+			{Algorithm: "lru", Lookups: 100, Hits: 60},
+			{Algorithm: "arc", Lookups: 100, Hits: 75},
+		}
+	})
+
+	expected := `
+# HELP hot_shadow_hit_total Estimated number of keys found in a shadow cache using another eviction algorithm
+# TYPE hot_shadow_hit_total counter
+hot_shadow_hit_total{algorithm="lru",name="test-cache"} 60
+hot_shadow_hit_total{algorithm="arc",name="test-cache"} 75
+# HELP hot_shadow_lookup_total Number of keys read from the cache and replayed in a shadow cache using another eviction algorithm
+# TYPE hot_shadow_lookup_total counter
+hot_shadow_lookup_total{algorithm="lru",name="test-cache"} 100
+hot_shadow_lookup_total{algorithm="arc",name="test-cache"} 100
+`
+	is.NoError(testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
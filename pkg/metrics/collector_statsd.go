@@ -0,0 +1,354 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/samber/hot/pkg/base"
+)
+
+var _ Collector = (*StatsDCollector)(nil)
+
+// DefaultStatsDMaxPacketSize fits a datagram in the MTU of most networks, without fragmentation.
+const DefaultStatsDMaxPacketSize = 1432
+
+// StatsDClient sends the values of StatsD collectors over UDP, in the DogStatsD format.
+// Collectors aggregate values client-side, and the client sends them every flush interval
+// from a background goroutine, so cache operations never wait for the network.
+type StatsDClient struct {
+	conn          net.Conn
+	prefix        string
+	maxPacketSize int
+
+	mu            sync.Mutex
+	registrations []*StatsDRegistration
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewStatsDClient creates a client sending metrics to the UDP address of a StatsD or DogStatsD agent,
+// such as "127.0.0.1:8125". Metric names are prefixed with prefix, such as "hot.".
+// Metrics are flushed every flushInterval, until Close.
+func NewStatsDClient(address string, prefix string, flushInterval time.Duration) (*StatsDClient, error) {
+	if flushInterval <= 0 {
+		return nil, errors.New("flush interval must be a positive value")
+	}
+
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &StatsDClient{
+		conn:          conn,
+		prefix:        prefix,
+		maxPacketSize: DefaultStatsDMaxPacketSize,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+
+	go c.loop(flushInterval)
+
+	return c, nil
+}
+
+// StatsDRegistration is a group of collectors registered on a client, with the callback
+// refreshing their gauges before each flush.
+type StatsDRegistration struct {
+	client     *StatsDClient
+	callback   func()
+	collectors []*StatsDCollector
+}
+
+// Register adds collectors to the client. The callback, if not nil, is called before each flush.
+func (c *StatsDClient) Register(callback func(), collectors ...*StatsDCollector) *StatsDRegistration {
+	r := &StatsDRegistration{
+		client:     c,
+		callback:   callback,
+		collectors: collectors,
+	}
+
+	c.mu.Lock()
+	c.registrations = append(c.registrations, r)
+	c.mu.Unlock()
+
+	return r
+}
+
+// Unregister sends the pending values of the collectors and removes them from the client.
+func (r *StatsDRegistration) Unregister() error {
+	c := r.client
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, registration := range c.registrations {
+		if registration == r {
+			c.registrations = append(c.registrations[:i], c.registrations[i+1:]...)
+			return c.send([]*StatsDRegistration{r})
+		}
+	}
+
+	return nil
+}
+
+// Flush sends the values of every registered collector.
+func (c *StatsDClient) Flush() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.send(c.registrations)
+}
+
+// Close stops the background flushes, sends the pending values and closes the connection.
+func (c *StatsDClient) Close() error {
+	var err error
+	c.stopOnce.Do(func() {
+		close(c.stop)
+		<-c.done
+
+		err = c.Flush()
+		if closeErr := c.conn.Close(); err == nil {
+			err = closeErr
+		}
+	})
+
+	return err
+}
+
+func (c *StatsDClient) loop(flushInterval time.Duration) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// Errors are not reported: metrics are lost when the agent is unreachable.
+			_ = c.Flush()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// send writes the lines of the collectors in packets of at most maxPacketSize bytes.
+// Must be called with the lock held.
+func (c *StatsDClient) send(registrations []*StatsDRegistration) error {
+	var err error
+	packet := bytes.Buffer{}
+
+	write := func() {
+		if packet.Len() == 0 {
+			return
+		}
+		if _, writeErr := c.conn.Write(packet.Bytes()); err == nil {
+			err = writeErr
+		}
+		packet.Reset()
+	}
+
+	for _, registration := range registrations {
+		if registration.callback != nil {
+			registration.callback()
+		}
+
+		for _, collector := range registration.collectors {
+			collector.lines(c.prefix, func(line []byte) {
+				if packet.Len() > 0 && packet.Len()+1+len(line) > c.maxPacketSize {
+					write()
+				}
+				if packet.Len() > 0 {
+					packet.WriteByte('\n')
+				}
+				packet.Write(line)
+			})
+		}
+	}
+	write()
+
+	return err
+}
+
+// StatsDCollector implements Collector by aggregating values in memory, until a StatsDClient sends them.
+// Counters are sent as deltas since the previous flush, gauges as their last value.
+type StatsDCollector struct {
+	tags         string
+	evictionTags [4]string // indexed like base.EvictionReasons
+
+	// Counters - use atomic operations for lock-free performance
+	insertionCount atomic.Int64
+	evictionCount  [4]atomic.Int64 // indexed like base.EvictionReasons
+	hitCount       atomic.Int64
+	missCount      atomic.Int64
+
+	// Gauges
+	sizeBytes atomic.Int64
+	length    atomic.Int64
+
+	// Static configuration
+	capacity         int64
+	algorithm        int64
+	ttl              *time.Duration
+	jitterLambda     *float64
+	jitterUpperBound *time.Duration
+	stale            *time.Duration
+	missingCapacity  *int
+}
+
+// NewStatsDCollector creates a new StatsD-based metric collector.
+// Tags match the labels of the Prometheus collector: `name`, `mode` and `shard`.
+func NewStatsDCollector(name string, shard int, mode base.CacheMode, capacity int, algorithm string, ttl *time.Duration, jitterLambda *float64, jitterUpperBound *time.Duration, stale *time.Duration, missingCapacity *int) *StatsDCollector {
+	tags := "name:" + statsDTagValue(name) + ",mode:" + statsDTagValue(string(mode))
+	if shard >= 0 {
+		tags += ",shard:" + strconv.Itoa(shard)
+	}
+
+	c := &StatsDCollector{
+		tags: tags,
+
+		capacity:         int64(capacity),
+		algorithm:        algorithmValue(algorithm),
+		ttl:              ttl,
+		jitterLambda:     jitterLambda,
+		jitterUpperBound: jitterUpperBound,
+		stale:            stale,
+		missingCapacity:  missingCapacity,
+	}
+	for i, reason := range base.EvictionReasons {
+		c.evictionTags[i] = tags + ",reason:" + statsDTagValue(string(reason))
+	}
+
+	return c
+}
+
+// IncInsertion atomically increments the insertion counter.
+func (s *StatsDCollector) IncInsertion() {
+	s.insertionCount.Add(1)
+}
+
+// AddInsertions atomically adds the specified count to the insertion counter.
+func (s *StatsDCollector) AddInsertions(count int64) {
+	s.insertionCount.Add(count)
+}
+
+// IncEviction atomically increments the eviction counter for the given reason.
+func (s *StatsDCollector) IncEviction(reason base.EvictionReason) {
+	s.AddEvictions(reason, 1)
+}
+
+// AddEvictions atomically adds the specified count to the eviction counter for the given reason.
+func (s *StatsDCollector) AddEvictions(reason base.EvictionReason, count int64) {
+	for i, r := range base.EvictionReasons {
+		if r == reason {
+			s.evictionCount[i].Add(count)
+			return
+		}
+	}
+}
+
+// IncHit atomically increments the hit counter.
+func (s *StatsDCollector) IncHit() {
+	s.hitCount.Add(1)
+}
+
+// AddHits atomically adds the specified count to the hit counter.
+func (s *StatsDCollector) AddHits(count int64) {
+	s.hitCount.Add(count)
+}
+
+// IncMiss atomically increments the miss counter.
+func (s *StatsDCollector) IncMiss() {
+	s.missCount.Add(1)
+}
+
+// AddMisses atomically adds the specified count to the miss counter.
+func (s *StatsDCollector) AddMisses(count int64) {
+	s.missCount.Add(count)
+}
+
+// UpdateSizeBytes atomically updates the cache size in bytes.
+func (s *StatsDCollector) UpdateSizeBytes(sizeBytes int64) {
+	s.sizeBytes.Store(sizeBytes)
+}
+
+// UpdateLength atomically updates the cache length.
+func (s *StatsDCollector) UpdateLength(length int64) {
+	s.length.Store(length)
+}
+
+// lines emits the DogStatsD lines of the collector, and resets the counters.
+// Counters without new values are skipped.
+func (s *StatsDCollector) lines(prefix string, emit func(line []byte)) {
+	buf := []byte{}
+
+	counter := func(name string, value int64, tags string) {
+		if value == 0 {
+			return
+		}
+		buf = appendStatsDLine(buf[:0], prefix, name, strconv.AppendInt(nil, value, 10), 'c', tags)
+		emit(buf)
+	}
+	gauge := func(name string, value []byte) {
+		buf = appendStatsDLine(buf[:0], prefix, name, value, 'g', s.tags)
+		emit(buf)
+	}
+
+	counter("insertion", s.insertionCount.Swap(0), s.tags)
+	counter("hit", s.hitCount.Swap(0), s.tags)
+	counter("miss", s.missCount.Swap(0), s.tags)
+	for i := range base.EvictionReasons {
+		counter("eviction", s.evictionCount[i].Swap(0), s.evictionTags[i])
+	}
+
+	gauge("size", strconv.AppendInt(nil, s.sizeBytes.Load(), 10))
+	gauge("length", strconv.AppendInt(nil, s.length.Load(), 10))
+
+	gauge("settings.capacity", strconv.AppendInt(nil, s.capacity, 10))
+	gauge("settings.algorithm", strconv.AppendInt(nil, s.algorithm, 10))
+	if s.ttl != nil {
+		gauge("settings.ttl", strconv.AppendFloat(nil, s.ttl.Seconds(), 'f', -1, 64))
+	}
+	if s.jitterLambda != nil {
+		gauge("settings.jitter_lambda", strconv.AppendFloat(nil, *s.jitterLambda, 'f', -1, 64))
+	}
+	if s.jitterUpperBound != nil {
+		gauge("settings.jitter_upper_bound", strconv.AppendFloat(nil, s.jitterUpperBound.Seconds(), 'f', -1, 64))
+	}
+	if s.stale != nil {
+		gauge("settings.stale", strconv.AppendFloat(nil, s.stale.Seconds(), 'f', -1, 64))
+	}
+	if s.missingCapacity != nil {
+		gauge("settings.missing_capacity", strconv.AppendInt(nil, int64(*s.missingCapacity), 10))
+	}
+}
+
+// appendStatsDLine appends a line such as `hot.hit:3|c|#name:users,mode:main`.
+func appendStatsDLine(buf []byte, prefix string, name string, value []byte, kind byte, tags string) []byte {
+	buf = append(buf, prefix...)
+	buf = append(buf, name...)
+	buf = append(buf, ':')
+	buf = append(buf, value...)
+	buf = append(buf, '|', kind)
+	if tags != "" {
+		buf = append(buf, "|#"...)
+		buf = append(buf, tags...)
+	}
+	return buf
+}
+
+var statsDTagReplacer = strings.NewReplacer(",", "_", "|", "_", "#", "_", "\n", "_")
+
+// statsDTagValue replaces the characters having a meaning in the DogStatsD format.
+func statsDTagValue(value string) string {
+	return statsDTagReplacer.Replace(value)
+}
@@ -0,0 +1,214 @@
+package metrics
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samber/hot/pkg/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func newStatsDListener(t *testing.T) *net.UDPConn {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+// readStatsDPackets returns the packets received until no packet arrives for the timeout.
+func readStatsDPackets(t *testing.T, conn *net.UDPConn, timeout time.Duration) []string {
+	t.Helper()
+
+	packets := []string{}
+	buf := make([]byte, 65536)
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(timeout))
+		n, err := conn.Read(buf)
+		if err != nil {
+			return packets
+		}
+		packets = append(packets, string(buf[:n]))
+	}
+}
+
+func statsDLines(packets []string) []string {
+	lines := []string{}
+	for _, packet := range packets {
+		lines = append(lines, strings.Split(packet, "\n")...)
+	}
+	return lines
+}
+
+func TestNewStatsDClient(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	_, err := NewStatsDClient("127.0.0.1:8125", "hot.", 0)
+	is.EqualError(err, "flush interval must be a positive value")
+
+	_, err = NewStatsDClient("invalid-address", "hot.", time.Second)
+	is.Error(err)
+}
+
+func TestStatsDCollector(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	listener := newStatsDListener(t)
+	client, err := NewStatsDClient(listener.LocalAddr().String(), "hot.", time.Hour)
+	is.NoError(err)
+	defer client.Close()
+
+	ttl := 30 * time.Second
+	collector := NewStatsDCollector("test-cache", 2, base.CacheModeMain, 100, "arc", &ttl, nil, nil, nil, nil)
+	callbacks := 0
+	client.Register(func() { callbacks++ }, collector)
+
+	collector.IncInsertion()
+	collector.AddInsertions(2)
+	collector.IncHit()
+	collector.AddHits(4)
+	collector.AddMisses(3)
+	collector.IncEviction(base.EvictionReasonCapacity)
+	collector.UpdateSizeBytes(1024)
+	collector.UpdateLength(10)
+
+	is.NoError(client.Flush())
+	is.Equal(1, callbacks)
+
+	lines := statsDLines(readStatsDPackets(t, listener, 100*time.Millisecond))
+	tags := "|#name:test-cache,mode:main,shard:2"
+	is.ElementsMatch([]string{
+		"hot.insertion:3|c" + tags,
+		"hot.hit:5|c" + tags,
+		"hot.miss:3|c" + tags,
+		"hot.eviction:1|c" + tags + ",reason:capacity",
+		"hot.size:1024|g" + tags,
+		"hot.length:10|g" + tags,
+		"hot.settings.capacity:100|g" + tags,
+		"hot.settings.algorithm:2|g" + tags,
+		"hot.settings.ttl:30|g" + tags,
+	}, lines)
+
+	// Counters are sent as deltas: only the gauges are sent again.
+	collector.IncMiss()
+	is.NoError(client.Flush())
+
+	lines = statsDLines(readStatsDPackets(t, listener, 100*time.Millisecond))
+	is.ElementsMatch([]string{
+		"hot.miss:1|c" + tags,
+		"hot.size:1024|g" + tags,
+		"hot.length:10|g" + tags,
+		"hot.settings.capacity:100|g" + tags,
+		"hot.settings.algorithm:2|g" + tags,
+		"hot.settings.ttl:30|g" + tags,
+	}, lines)
+}
+
+func TestStatsDCollector_tags(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	collector := NewStatsDCollector("a,b|c", -1, base.CacheModeMissing, 10, "lru", nil, nil, nil, nil, nil)
+
+	lines := []string{}
+	collector.lines("", func(line []byte) {
+		lines = append(lines, string(line))
+	})
+	is.Contains(lines, "length:0|g|#name:a_b_c,mode:missing")
+}
+
+func TestStatsDClient_flushInterval(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	listener := newStatsDListener(t)
+	client, err := NewStatsDClient(listener.LocalAddr().String(), "", 10*time.Millisecond)
+	is.NoError(err)
+	defer client.Close()
+
+	collector := NewStatsDCollector("test-cache", -1, base.CacheModeMain, 100, "lru", nil, nil, nil, nil, nil)
+	client.Register(nil, collector)
+	collector.AddHits(7)
+
+	// The client flushes continuously: only the first packet is read.
+	buf := make([]byte, 65536)
+	_ = listener.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := listener.Read(buf)
+	is.NoError(err)
+	is.Contains(statsDLines([]string{string(buf[:n])}), "hit:7|c|#name:test-cache,mode:main")
+}
+
+func TestStatsDClient_packetSize(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	listener := newStatsDListener(t)
+	client, err := NewStatsDClient(listener.LocalAddr().String(), "hot.", time.Hour)
+	is.NoError(err)
+	defer client.Close()
+
+	collectors := []*StatsDCollector{}
+	for i := 0; i < 50; i++ {
+		collectors = append(collectors, NewStatsDCollector("test-cache", i, base.CacheModeMain, 100, "lru", nil, nil, nil, nil, nil))
+	}
+	client.Register(nil, collectors...)
+
+	is.NoError(client.Flush())
+
+	packets := readStatsDPackets(t, listener, 100*time.Millisecond)
+	is.Greater(len(packets), 1)
+	for _, packet := range packets {
+		is.LessOrEqual(len(packet), DefaultStatsDMaxPacketSize)
+	}
+	is.Len(statsDLines(packets), 50*4)
+}
+
+func TestStatsDRegistration_Unregister(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	listener := newStatsDListener(t)
+	client, err := NewStatsDClient(listener.LocalAddr().String(), "", time.Hour)
+	is.NoError(err)
+	defer client.Close()
+
+	collector := NewStatsDCollector("test-cache", -1, base.CacheModeMain, 100, "lru", nil, nil, nil, nil, nil)
+	registration := client.Register(nil, collector)
+	collector.AddInsertions(2)
+
+	// Pending values are sent on unregistration.
+	is.NoError(registration.Unregister())
+	lines := statsDLines(readStatsDPackets(t, listener, 100*time.Millisecond))
+	is.Contains(lines, "insertion:2|c|#name:test-cache,mode:main")
+
+	is.NoError(registration.Unregister())
+	is.NoError(client.Flush())
+	is.Empty(readStatsDPackets(t, listener, 50*time.Millisecond))
+}
+
+func TestStatsDClient_Close(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	listener := newStatsDListener(t)
+	client, err := NewStatsDClient(listener.LocalAddr().String(), "", time.Hour)
+	is.NoError(err)
+
+	collector := NewStatsDCollector("test-cache", -1, base.CacheModeMain, 100, "lru", nil, nil, nil, nil, nil)
+	client.Register(nil, collector)
+	collector.AddHits(1)
+
+	is.NoError(client.Close())
+	is.NoError(client.Close())
+
+	lines := statsDLines(readStatsDPackets(t, listener, 100*time.Millisecond))
+	is.Contains(lines, "hit:1|c|#name:test-cache,mode:main")
+}
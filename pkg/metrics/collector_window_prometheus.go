@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var _ prometheus.Collector = (*PrometheusWindowRatioCollector)(nil)
+
+// PrometheusWindowRatioCollector exports the fraction of the capacity used by the admission window
+// of a cache (W-TinyLFU) as the `hot_window_ratio` gauge.
+type PrometheusWindowRatioCollector struct {
+	windowRatioDesc *prometheus.Desc
+	windowRatio     func() float64
+}
+
+// NewPrometheusWindowRatioCollector creates a collector exporting the ratio returned by `windowRatio` on every scrape.
+func NewPrometheusWindowRatioCollector(name string, windowRatio func() float64) *PrometheusWindowRatioCollector {
+	return &PrometheusWindowRatioCollector{
+		windowRatioDesc: prometheus.NewDesc(
+			"hot_window_ratio",
+			"Fraction of the cache capacity used by the admission window",
+			nil,
+			prometheus.Labels{"name": name},
+		),
+		windowRatio: windowRatio,
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (p *PrometheusWindowRatioCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- p.windowRatioDesc
+}
+
+// Collect implements the prometheus.Collector interface.
+func (p *PrometheusWindowRatioCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(p.windowRatioDesc, prometheus.GaugeValue, p.windowRatio())
+}
@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusWindowRatioCollector(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	collector := NewPrometheusWindowRatioCollector("test-cache", func() float64 {
+		return 0.25
+	})
+
+	expected := `
+# HELP hot_window_ratio Fraction of the cache capacity used by the admission window
+# TYPE hot_window_ratio gauge
+hot_window_ratio{name="test-cache"} 0.25
+`
+	is.NoError(testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
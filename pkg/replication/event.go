@@ -0,0 +1,76 @@
+package replication
+
+import "time"
+
+// Op is the kind of write carried by an event.
+type Op uint8
+
+const (
+	// OpSet stores a value.
+	OpSet Op = iota + 1
+	// OpSetMissing stores a missing key.
+	OpSetMissing
+	// OpDelete removes a key.
+	OpDelete
+	// OpPurge removes every key.
+	OpPurge
+	// OpExpire removes a key that expired on the primary.
+	OpExpire
+)
+
+// DefaultTTL is the TTL of a write forwarded by a follower without TTL: the primary applies
+// its own default TTL and jitter. Events streamed by the primary always carry the actual TTL.
+const DefaultTTL time.Duration = -1
+
+// Event is a write applied to the primary cache.
+// Seq is assigned by the log, starting at 1.
+type Event[K comparable, V any] struct {
+	Seq   uint64        `json:"seq"`
+	Op    Op            `json:"op"`
+	Key   K             `json:"key"`
+	Value V             `json:"value"`
+	TTL   time.Duration `json:"ttl,omitempty"` // Remaining time-to-live, 0 means no expiration, DefaultTTL the default TTL of the cache
+}
+
+// Applier applies replicated writes to a cache. *hot.HotCache implements it.
+type Applier[K comparable, V any] interface {
+	Set(key K, v V)
+	SetMissing(key K)
+	SetWithTTL(key K, v V, ttl time.Duration)
+	SetMissingWithTTL(key K, ttl time.Duration)
+	Delete(key K) bool
+	Purge()
+}
+
+// MissingKeysApplier is implemented by appliers that might not store missing keys, such as
+// a *hot.HotCache without missing cache. It is optional.
+type MissingKeysApplier interface {
+	MissingKeysEnabled() bool
+}
+
+// Apply applies an event to a cache.
+// Missing keys are deleted from appliers that do not store them.
+func Apply[K comparable, V any](applier Applier[K, V], event Event[K, V]) {
+	switch event.Op {
+	case OpSet:
+		if event.TTL < 0 {
+			applier.Set(event.Key, event.Value)
+			return
+		}
+		applier.SetWithTTL(event.Key, event.Value, event.TTL)
+	case OpSetMissing:
+		if missing, ok := applier.(MissingKeysApplier); ok && !missing.MissingKeysEnabled() {
+			applier.Delete(event.Key)
+			return
+		}
+		if event.TTL < 0 {
+			applier.SetMissing(event.Key)
+			return
+		}
+		applier.SetMissingWithTTL(event.Key, event.TTL)
+	case OpDelete, OpExpire:
+		applier.Delete(event.Key)
+	case OpPurge:
+		applier.Purge()
+	}
+}
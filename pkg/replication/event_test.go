@@ -0,0 +1,117 @@
+package replication
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mapApplier is a minimal Applier for tests.
+type mapApplier struct {
+	mu     sync.Mutex
+	values map[string]int
+	ttls   map[string]time.Duration
+}
+
+func newMapApplier() *mapApplier {
+	return &mapApplier{values: map[string]int{}, ttls: map[string]time.Duration{}}
+}
+
+func (a *mapApplier) SetWithTTL(key string, v int, ttl time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.values[key] = v
+	a.ttls[key] = ttl
+}
+
+func (a *mapApplier) Set(key string, v int) {
+	a.SetWithTTL(key, v, DefaultTTL)
+}
+
+func (a *mapApplier) SetMissing(key string) {
+	a.SetMissingWithTTL(key, DefaultTTL)
+}
+
+func (a *mapApplier) SetMissingWithTTL(key string, ttl time.Duration) {
+	a.SetWithTTL(key, -1, ttl)
+}
+
+func (a *mapApplier) Delete(key string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, ok := a.values[key]
+	delete(a.values, key)
+	delete(a.ttls, key)
+	return ok
+}
+
+func (a *mapApplier) Purge() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.values = map[string]int{}
+	a.ttls = map[string]time.Duration{}
+}
+
+func (a *mapApplier) snapshot() map[string]int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	values := map[string]int{}
+	for k, v := range a.values {
+		values[k] = v
+	}
+	return values
+}
+
+func TestApply(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	a := newMapApplier()
+	Apply[string, int](a, Event[string, int]{Op: OpSet, Key: "a", Value: 1, TTL: time.Second})
+	Apply[string, int](a, Event[string, int]{Op: OpSet, Key: "b", Value: 2})
+	Apply[string, int](a, Event[string, int]{Op: OpSetMissing, Key: "c"})
+	is.Equal(map[string]int{"a": 1, "b": 2, "c": -1}, a.snapshot())
+	is.Equal(time.Second, a.ttls["a"])
+
+	Apply[string, int](a, Event[string, int]{Op: OpDelete, Key: "a"})
+	Apply[string, int](a, Event[string, int]{Op: OpExpire, Key: "b"})
+	is.Equal(map[string]int{"c": -1}, a.snapshot())
+
+	Apply[string, int](a, Event[string, int]{Op: OpPurge})
+	is.Empty(a.snapshot())
+
+	// default TTL of the applier
+	Apply[string, int](a, Event[string, int]{Op: OpSet, Key: "d", Value: 4, TTL: DefaultTTL})
+	Apply[string, int](a, Event[string, int]{Op: OpSetMissing, Key: "e", TTL: DefaultTTL})
+	is.Equal(map[string]int{"d": 4, "e": -1}, a.snapshot())
+	is.Equal(DefaultTTL, a.ttls["d"])
+	is.Equal(DefaultTTL, a.ttls["e"])
+}
+
+// noMissingApplier does not store missing keys.
+type noMissingApplier struct {
+	*mapApplier
+}
+
+func (a noMissingApplier) SetMissingWithTTL(key string, ttl time.Duration) {
+	panic("missing cache is not enabled")
+}
+
+func (a noMissingApplier) MissingKeysEnabled() bool {
+	return false
+}
+
+func TestApply_MissingKeysDisabled(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	a := noMissingApplier{newMapApplier()}
+	Apply[string, int](a, Event[string, int]{Op: OpSet, Key: "a", Value: 1})
+	is.NotPanics(func() {
+		Apply[string, int](a, Event[string, int]{Op: OpSetMissing, Key: "a"})
+		Apply[string, int](a, Event[string, int]{Op: OpSetMissing, Key: "b"})
+	})
+	is.Empty(a.snapshot())
+}
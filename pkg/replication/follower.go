@@ -0,0 +1,278 @@
+package replication
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	dialTimeout = time.Second
+	minBackoff  = 50 * time.Millisecond
+	maxBackoff  = 5 * time.Second
+)
+
+// ErrNotConnected is returned when a write is forwarded while the follower is disconnected from the primary.
+var ErrNotConnected = errors.New("replication: not connected to the primary")
+
+// FollowerStats reports the replication state of a follower.
+type FollowerStats struct {
+	// Connected is true while the follower is connected to the primary.
+	Connected bool
+	// AppliedSeq is the sequence number of the last event applied.
+	AppliedSeq uint64
+	// PrimarySeq is the last sequence number known to the primary.
+	PrimarySeq uint64
+	// Lag is the number of events not applied yet.
+	Lag uint64
+	// LastContact is the time of the last frame received from the primary.
+	LastContact time.Time
+	// Reconnects is the number of connections to the primary after the first one.
+	Reconnects uint64
+}
+
+// NewFollower connects to the primary in the background and applies its writes to `cache`.
+// The connection is re-opened after a failure, and the stream resumes after the last event applied.
+// Writes must not be applied to `cache` directly: use the follower methods to forward them to the primary.
+// `name` labels the Prometheus metrics of the follower, like the cache name of the other collectors.
+func NewFollower[K comparable, V any](name string, addr string, cache Applier[K, V]) *Follower[K, V] {
+	if cache == nil {
+		panic("cache is required")
+	}
+
+	labels := prometheus.Labels{"name": name}
+
+	f := &Follower[K, V]{
+		addr:   addr,
+		cache:  cache,
+		closed: make(chan struct{}),
+		descs: map[string]*prometheus.Desc{
+			"lag":          prometheus.NewDesc("hot_replication_lag_events", "Number of replication events not applied yet by the follower", nil, labels),
+			"applied":      prometheus.NewDesc("hot_replication_applied_sequence", "Sequence number of the last replication event applied by the follower", nil, labels),
+			"connected":    prometheus.NewDesc("hot_replication_connected", "1 when the follower is connected to the primary", nil, labels),
+			"last_contact": prometheus.NewDesc("hot_replication_last_contact_seconds", "Unix time of the last frame received from the primary", nil, labels),
+			"reconnects":   prometheus.NewDesc("hot_replication_reconnects_total", "Number of reconnections to the primary", nil, labels),
+		},
+	}
+
+	f.wg.Add(1)
+	go f.run()
+
+	return f
+}
+
+// Follower applies the writes streamed by a primary to a local cache.
+type Follower[K comparable, V any] struct {
+	addr  string
+	cache Applier[K, V]
+
+	mu   sync.Mutex // Protects conn and serializes forwarded writes
+	conn net.Conn
+	enc  *json.Encoder
+
+	epoch       string // Epoch of the log the applied sequence number refers to, only used by the session goroutine
+	applied     atomic.Uint64
+	primarySeq  atomic.Uint64
+	lastContact atomic.Int64 // Unix nanoseconds
+	sessions    atomic.Uint64
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	wg        sync.WaitGroup
+
+	descs map[string]*prometheus.Desc
+}
+
+var _ prometheus.Collector = (*Follower[string, int])(nil)
+
+// Set forwards a write to the primary, stored with the default TTL of the primary cache.
+// The value is visible locally once replicated back.
+func (f *Follower[K, V]) Set(key K, v V) error {
+	return f.forward(Event[K, V]{Op: OpSet, Key: key, Value: v, TTL: DefaultTTL})
+}
+
+// SetWithTTL forwards a write with a time-to-live to the primary.
+func (f *Follower[K, V]) SetWithTTL(key K, v V, ttl time.Duration) error {
+	return f.forward(Event[K, V]{Op: OpSet, Key: key, Value: v, TTL: ttl})
+}
+
+// Delete forwards a deletion to the primary.
+func (f *Follower[K, V]) Delete(key K) error {
+	return f.forward(Event[K, V]{Op: OpDelete, Key: key})
+}
+
+// Purge forwards a purge to the primary.
+func (f *Follower[K, V]) Purge() error {
+	return f.forward(Event[K, V]{Op: OpPurge})
+}
+
+// Stats returns the replication state.
+func (f *Follower[K, V]) Stats() FollowerStats {
+	f.mu.Lock()
+	connected := f.conn != nil
+	f.mu.Unlock()
+
+	applied := f.applied.Load()
+	primarySeq := max(f.primarySeq.Load(), applied)
+
+	stats := FollowerStats{
+		Connected:  connected,
+		AppliedSeq: applied,
+		PrimarySeq: primarySeq,
+		Lag:        primarySeq - applied,
+	}
+	if sessions := f.sessions.Load(); sessions > 1 {
+		stats.Reconnects = sessions - 1
+	}
+	if lastContact := f.lastContact.Load(); lastContact > 0 {
+		stats.LastContact = time.Unix(0, lastContact)
+	}
+
+	return stats
+}
+
+// Close disconnects from the primary. This method is safe to call multiple times.
+func (f *Follower[K, V]) Close() error {
+	f.closeOnce.Do(func() {
+		close(f.closed)
+
+		f.mu.Lock()
+		if f.conn != nil {
+			_ = f.conn.Close()
+		}
+		f.mu.Unlock()
+
+		f.wg.Wait()
+	})
+	return nil
+}
+
+// Describe implements the prometheus.Collector interface.
+func (f *Follower[K, V]) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range f.descs {
+		ch <- desc
+	}
+}
+
+// Collect implements the prometheus.Collector interface.
+func (f *Follower[K, V]) Collect(ch chan<- prometheus.Metric) {
+	stats := f.Stats()
+
+	connected := 0.0
+	if stats.Connected {
+		connected = 1
+	}
+	lastContact := 0.0
+	if !stats.LastContact.IsZero() {
+		lastContact = float64(stats.LastContact.UnixNano()) / float64(time.Second)
+	}
+
+	ch <- prometheus.MustNewConstMetric(f.descs["lag"], prometheus.GaugeValue, float64(stats.Lag))
+	ch <- prometheus.MustNewConstMetric(f.descs["applied"], prometheus.GaugeValue, float64(stats.AppliedSeq))
+	ch <- prometheus.MustNewConstMetric(f.descs["connected"], prometheus.GaugeValue, connected)
+	ch <- prometheus.MustNewConstMetric(f.descs["last_contact"], prometheus.GaugeValue, lastContact)
+	ch <- prometheus.MustNewConstMetric(f.descs["reconnects"], prometheus.CounterValue, float64(stats.Reconnects))
+}
+
+func (f *Follower[K, V]) forward(event Event[K, V]) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.enc == nil {
+		return ErrNotConnected
+	}
+
+	return f.enc.Encode(frame[K, V]{Type: frameWrite, Events: []Event[K, V]{event}})
+}
+
+// run keeps a session open with the primary, with exponential backoff between attempts.
+func (f *Follower[K, V]) run() {
+	defer f.wg.Done()
+
+	backoff := minBackoff
+	for {
+		if f.session() {
+			backoff = minBackoff
+		}
+
+		select {
+		case <-f.closed:
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// session connects to the primary and applies the stream until the connection fails.
+// Returns true if the connection was established.
+func (f *Follower[K, V]) session() bool {
+	conn, err := net.DialTimeout("tcp", f.addr, dialTimeout)
+	if err != nil {
+		return false
+	}
+
+	f.mu.Lock()
+	select {
+	case <-f.closed:
+		f.mu.Unlock()
+		_ = conn.Close()
+		return false
+	default:
+	}
+	f.conn = conn
+	f.enc = json.NewEncoder(conn)
+	err = f.enc.Encode(frame[K, V]{Type: frameHello, Seq: f.applied.Load(), Epoch: f.epoch})
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		f.conn = nil
+		f.enc = nil
+		f.mu.Unlock()
+		_ = conn.Close()
+	}()
+
+	if err != nil {
+		return false
+	}
+
+	f.sessions.Add(1)
+
+	dec := json.NewDecoder(bufio.NewReader(conn))
+	for {
+		var fr frame[K, V]
+		if err := dec.Decode(&fr); err != nil {
+			return true
+		}
+
+		f.lastContact.Store(time.Now().UnixNano())
+
+		switch fr.Type {
+		case frameSnapshot:
+			for _, event := range fr.Events {
+				Apply(f.cache, event)
+			}
+			f.epoch = fr.Epoch
+			f.applied.Store(fr.Seq)
+			f.primarySeq.Store(fr.Seq)
+		case frameEvents:
+			for _, event := range fr.Events {
+				Apply(f.cache, event)
+				f.applied.Store(event.Seq)
+			}
+			if n := len(fr.Events); n > 0 && fr.Events[n-1].Seq > f.primarySeq.Load() {
+				f.primarySeq.Store(fr.Events[n-1].Seq)
+			}
+		case frameHeartbeat:
+			f.primarySeq.Store(fr.Seq)
+		}
+	}
+}
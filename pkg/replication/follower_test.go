@@ -0,0 +1,223 @@
+package replication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrimaryFollower(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	log := NewLog[string, int](100)
+	primaryCache := newMapApplier()
+	apply := func(event Event[string, int]) {
+		Apply[string, int](primaryCache, event)
+		log.Append(event)
+	}
+	log.SetSnapshot(func() []Event[string, int] {
+		events := []Event[string, int]{}
+		for k, v := range primaryCache.snapshot() {
+			events = append(events, Event[string, int]{Op: OpSet, Key: k, Value: v})
+		}
+		return events
+	})
+
+	apply(Event[string, int]{Op: OpSet, Key: "a", Value: 1})
+
+	primary, err := NewPrimary("127.0.0.1:0", log, Applier[string, int](forwardingApplier{apply}))
+	is.NoError(err)
+	defer primary.Close()
+
+	followerCache := newMapApplier()
+	follower := NewFollower[string, int]("test", primary.Addr().String(), followerCache)
+	defer follower.Close()
+
+	// bootstrap snapshot
+	is.Eventually(func() bool {
+		return follower.Stats().Connected && len(followerCache.snapshot()) == 1
+	}, time.Second, 5*time.Millisecond)
+	is.Equal(1, primary.Followers())
+
+	// streamed events
+	apply(Event[string, int]{Op: OpSet, Key: "b", Value: 2})
+	apply(Event[string, int]{Op: OpDelete, Key: "a"})
+	is.Eventually(func() bool {
+		return follower.Stats().AppliedSeq == 3
+	}, time.Second, 5*time.Millisecond)
+	is.Equal(map[string]int{"b": 2}, followerCache.snapshot())
+
+	stats := follower.Stats()
+	is.Equal(uint64(0), stats.Lag)
+	is.Equal(uint64(3), stats.PrimarySeq)
+	is.False(stats.LastContact.IsZero())
+
+	// forwarded writes
+	is.NoError(follower.Set("c", 3))
+	is.NoError(follower.SetWithTTL("d", 4, time.Minute))
+	is.NoError(follower.Delete("b"))
+	is.Eventually(func() bool {
+		values := followerCache.snapshot()
+		return len(values) == 2 && values["c"] == 3 && values["d"] == 4
+	}, time.Second, 5*time.Millisecond)
+	is.Equal(map[string]int{"c": 3, "d": 4}, primaryCache.snapshot())
+
+	is.Equal(5, testutil.CollectAndCount(follower))
+
+	is.NoError(follower.Close())
+	is.NoError(follower.Close())
+	is.ErrorIs(follower.Purge(), ErrNotConnected)
+}
+
+func TestFollower_Metrics(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	first := NewFollower[string, int]("first", "127.0.0.1:1", newMapApplier())
+	defer first.Close()
+	second := NewFollower[string, int]("second", "127.0.0.1:1", newMapApplier())
+	defer second.Close()
+
+	// followers of several caches are registered side by side
+	registry := prometheus.NewRegistry()
+	is.NoError(registry.Register(first))
+	is.NoError(registry.Register(second))
+	is.Equal(10, testutil.CollectAndCount(registry))
+}
+
+func TestFollower_Resume(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	log := NewLog[string, int](100)
+	primaryCache := newMapApplier()
+	apply := func(event Event[string, int]) {
+		Apply[string, int](primaryCache, event)
+		log.Append(event)
+	}
+	log.SetSnapshot(func() []Event[string, int] {
+		events := []Event[string, int]{}
+		for k, v := range primaryCache.snapshot() {
+			events = append(events, Event[string, int]{Op: OpSet, Key: k, Value: v})
+		}
+		return events
+	})
+
+	primary, err := NewPrimary("127.0.0.1:0", log, Applier[string, int](primaryCache))
+	is.NoError(err)
+	addr := primary.Addr().String()
+
+	followerCache := newMapApplier()
+	follower := NewFollower[string, int]("test", addr, followerCache)
+	defer follower.Close()
+
+	apply(Event[string, int]{Op: OpSet, Key: "a", Value: 1})
+	is.Eventually(func() bool {
+		return follower.Stats().AppliedSeq == 1
+	}, time.Second, 5*time.Millisecond)
+
+	// the primary goes away, writes continue
+	is.NoError(primary.Close())
+	is.Eventually(func() bool {
+		return !follower.Stats().Connected
+	}, time.Second, 5*time.Millisecond)
+	apply(Event[string, int]{Op: OpSet, Key: "b", Value: 2})
+
+	// the stream resumes after the last event applied, without a snapshot
+	followerCache.SetWithTTL("local", 0, 0)
+	primary, err = NewPrimary(addr, log, Applier[string, int](primaryCache))
+	is.NoError(err)
+	defer primary.Close()
+
+	is.Eventually(func() bool {
+		return follower.Stats().AppliedSeq == 2
+	}, 2*time.Second, 5*time.Millisecond)
+	is.Equal(map[string]int{"a": 1, "b": 2, "local": 0}, followerCache.snapshot())
+	is.Equal(uint64(1), follower.Stats().Reconnects)
+}
+
+func TestFollower_EpochChange(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	newPrimary := func(addr string, events ...Event[string, int]) *Primary[string, int] {
+		log := NewLog[string, int](100)
+		cache := newMapApplier()
+		log.SetSnapshot(func() []Event[string, int] {
+			snapshot := []Event[string, int]{}
+			for k, v := range cache.snapshot() {
+				snapshot = append(snapshot, Event[string, int]{Op: OpSet, Key: k, Value: v})
+			}
+			return snapshot
+		})
+		for _, event := range events {
+			Apply[string, int](cache, event)
+			log.Append(event)
+		}
+
+		primary, err := NewPrimary(addr, log, Applier[string, int](cache))
+		is.NoError(err)
+		return primary
+	}
+
+	primary := newPrimary("127.0.0.1:0", Event[string, int]{Op: OpSet, Key: "a", Value: 1})
+	addr := primary.Addr().String()
+
+	followerCache := newMapApplier()
+	follower := NewFollower[string, int]("test", addr, followerCache)
+	defer follower.Close()
+
+	is.Eventually(func() bool {
+		return follower.Stats().AppliedSeq == 1
+	}, time.Second, 5*time.Millisecond)
+	is.NoError(primary.Close())
+	is.Eventually(func() bool {
+		return !follower.Stats().Connected
+	}, time.Second, 5*time.Millisecond)
+
+	// a restarted primary has a new log: its sequence numbers do not match the ones of the follower
+	primary = newPrimary(addr,
+		Event[string, int]{Op: OpSet, Key: "b", Value: 2},
+		Event[string, int]{Op: OpSet, Key: "c", Value: 3},
+	)
+	defer primary.Close()
+
+	is.Eventually(func() bool {
+		return follower.Stats().AppliedSeq == 2
+	}, 2*time.Second, 5*time.Millisecond)
+	is.Equal(map[string]int{"b": 2, "c": 3}, followerCache.snapshot())
+}
+
+// forwardingApplier applies forwarded writes like the primary cache would, including the log.
+type forwardingApplier struct {
+	apply func(Event[string, int])
+}
+
+func (a forwardingApplier) Set(key string, v int) {
+	a.SetWithTTL(key, v, DefaultTTL)
+}
+
+func (a forwardingApplier) SetMissing(key string) {
+	a.SetMissingWithTTL(key, DefaultTTL)
+}
+
+func (a forwardingApplier) SetWithTTL(key string, v int, ttl time.Duration) {
+	a.apply(Event[string, int]{Op: OpSet, Key: key, Value: v, TTL: ttl})
+}
+
+func (a forwardingApplier) SetMissingWithTTL(key string, ttl time.Duration) {
+	a.apply(Event[string, int]{Op: OpSetMissing, Key: key, TTL: ttl})
+}
+
+func (a forwardingApplier) Delete(key string) bool {
+	a.apply(Event[string, int]{Op: OpDelete, Key: key})
+	return true
+}
+
+func (a forwardingApplier) Purge() {
+	a.apply(Event[string, int]{Op: OpPurge})
+}
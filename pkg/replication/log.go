@@ -0,0 +1,148 @@
+package replication
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+)
+
+// NewLog creates a replication log keeping the last `size` events.
+// Followers lagging by more than `size` events are bootstrapped again from a snapshot.
+func NewLog[K comparable, V any](size int) *Log[K, V] {
+	if size <= 0 {
+		panic("replication log size must be greater than 0")
+	}
+
+	epoch := make([]byte, 8)
+	_, _ = rand.Read(epoch)
+
+	return &Log[K, V]{
+		epoch:   hex.EncodeToString(epoch),
+		events:  make([]Event[K, V], size),
+		changed: make(chan struct{}),
+	}
+}
+
+// Log is a bounded, in-memory log of the writes applied to the primary cache.
+// It is safe for concurrent access.
+type Log[K comparable, V any] struct {
+	epoch    string // Random identifier of the log: sequence numbers are only meaningful within an epoch
+	mu       sync.RWMutex
+	events   []Event[K, V] // Ring buffer, indexed by seq % len(events)
+	lastSeq  uint64
+	changed  chan struct{} // Closed and replaced on every append
+	snapshot func() []Event[K, V]
+}
+
+// Append assigns the next sequence number to the event and stores it.
+func (l *Log[K, V]) Append(event Event[K, V]) uint64 {
+	l.mu.Lock()
+	seq := l.append(event)
+	changed := l.changed
+	l.changed = make(chan struct{})
+	l.mu.Unlock()
+
+	close(changed)
+
+	return seq
+}
+
+// Record calls `write`, which applies a write to the primary cache and returns the events describing it,
+// and appends these events. Both happen under the log lock, so that concurrent writes are logged
+// in the order they were applied to the cache. `write` must not use the log.
+func (l *Log[K, V]) Record(write func() []Event[K, V]) {
+	l.mu.Lock()
+	events := write()
+	if len(events) == 0 {
+		l.mu.Unlock()
+		return
+	}
+
+	for _, event := range events {
+		l.append(event)
+	}
+	changed := l.changed
+	l.changed = make(chan struct{})
+	l.mu.Unlock()
+
+	close(changed)
+}
+
+// append assigns the next sequence number to the event and stores it.
+// The caller must hold the lock.
+func (l *Log[K, V]) append(event Event[K, V]) uint64 {
+	l.lastSeq++
+	event.Seq = l.lastSeq
+	l.events[event.Seq%uint64(len(l.events))] = event
+	return event.Seq
+}
+
+// Epoch returns the random identifier of the log. A follower resuming from a sequence number
+// of another epoch, such as the log of a restarted primary, is bootstrapped again from a snapshot.
+func (l *Log[K, V]) Epoch() string {
+	return l.epoch
+}
+
+// LastSeq returns the sequence number of the last event.
+func (l *Log[K, V]) LastSeq() uint64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.lastSeq
+}
+
+// Since returns the events following `seq`. It returns false when some of them
+// are no longer in the log: the follower must be bootstrapped from a snapshot.
+func (l *Log[K, V]) Since(seq uint64) ([]Event[K, V], bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if seq > l.lastSeq {
+		// The follower is ahead: it follows another epoch.
+		return nil, false
+	}
+	if l.lastSeq-seq > uint64(len(l.events)) {
+		return nil, false
+	}
+
+	events := make([]Event[K, V], 0, l.lastSeq-seq)
+	for s := seq + 1; s <= l.lastSeq; s++ {
+		events = append(events, l.events[s%uint64(len(l.events))])
+	}
+
+	return events, true
+}
+
+// Changed returns a channel closed on the next Append.
+func (l *Log[K, V]) Changed() <-chan struct{} {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.changed
+}
+
+// SetSnapshot registers the function listing the content of the primary cache,
+// as a sequence of events. It is registered by the cache itself.
+func (l *Log[K, V]) SetSnapshot(snapshot func() []Event[K, V]) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.snapshot = snapshot
+}
+
+// Snapshot returns the content of the primary cache, and the sequence number from which
+// the log must be replayed after applying it. Events replayed after the snapshot
+// might already be included in it: applying them twice is harmless.
+func (l *Log[K, V]) Snapshot() ([]Event[K, V], uint64) {
+	l.mu.RLock()
+	seq := l.lastSeq
+	snapshot := l.snapshot
+	l.mu.RUnlock()
+
+	events := []Event[K, V]{{Op: OpPurge}}
+	if snapshot != nil {
+		events = append(events, snapshot()...)
+	}
+
+	return events, seq
+}
@@ -0,0 +1,122 @@
+package replication
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	is.Panics(func() {
+		_ = NewLog[string, int](0)
+	})
+
+	log := NewLog[string, int](3)
+	is.Equal(uint64(0), log.LastSeq())
+
+	events, ok := log.Since(0)
+	is.True(ok)
+	is.Empty(events)
+
+	changed := log.Changed()
+	is.Equal(uint64(1), log.Append(Event[string, int]{Op: OpSet, Key: "a", Value: 1}))
+	select {
+	case <-changed:
+	default:
+		is.Fail("changed should be closed")
+	}
+
+	log.Append(Event[string, int]{Op: OpSet, Key: "b", Value: 2})
+	log.Append(Event[string, int]{Op: OpDelete, Key: "a"})
+	is.Equal(uint64(3), log.LastSeq())
+
+	events, ok = log.Since(1)
+	is.True(ok)
+	is.Equal([]Event[string, int]{
+		{Seq: 2, Op: OpSet, Key: "b", Value: 2},
+		{Seq: 3, Op: OpDelete, Key: "a"},
+	}, events)
+
+	log.Append(Event[string, int]{Op: OpPurge})
+
+	// truncated
+	_, ok = log.Since(0)
+	is.False(ok)
+	events, ok = log.Since(1)
+	is.True(ok)
+	is.Len(events, 3)
+
+	// ahead of the primary
+	_, ok = log.Since(42)
+	is.False(ok)
+}
+
+func TestLog_Snapshot(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	log := NewLog[string, int](10)
+	log.Append(Event[string, int]{Op: OpSet, Key: "a", Value: 1})
+
+	events, seq := log.Snapshot()
+	is.Equal(uint64(1), seq)
+	is.Equal([]Event[string, int]{{Op: OpPurge}}, events)
+
+	log.SetSnapshot(func() []Event[string, int] {
+		return []Event[string, int]{{Op: OpSet, Key: "a", Value: 1}}
+	})
+	events, seq = log.Snapshot()
+	is.Equal(uint64(1), seq)
+	is.Equal([]Event[string, int]{{Op: OpPurge}, {Op: OpSet, Key: "a", Value: 1}}, events)
+}
+
+func TestLog_Record(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	log := NewLog[string, int](10)
+	is.NotEmpty(log.Epoch())
+	is.NotEqual(log.Epoch(), NewLog[string, int](10).Epoch())
+
+	// nothing is appended
+	changed := log.Changed()
+	log.Record(func() []Event[string, int] { return nil })
+	is.Equal(uint64(0), log.LastSeq())
+	select {
+	case <-changed:
+		is.Fail("changed should not be closed")
+	default:
+	}
+
+	// the write runs under the log lock
+	var applied []int
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			log.Record(func() []Event[string, int] {
+				applied = append(applied, i)
+				return []Event[string, int]{{Op: OpSet, Key: "a", Value: i}, {Op: OpDelete, Key: "b"}}
+			})
+		}(i)
+	}
+	wg.Wait()
+	<-changed
+
+	events, ok := log.Since(0)
+	is.False(ok)
+	events, ok = log.Since(90)
+	is.True(ok)
+	is.Len(events, 10)
+	for j, event := range events {
+		is.Equal(uint64(91+j), event.Seq)
+		if event.Op == OpSet {
+			is.Equal(applied[45+j/2], event.Value)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package replication
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"sync"
+	"time"
+)
+
+// heartbeatInterval is the interval of the heartbeats sent to idle followers.
+// Followers compute their lag from them.
+const heartbeatInterval = time.Second
+
+// NewPrimary listens for followers on addr and streams the log to them.
+// Writes forwarded by followers are applied to `cache`, which must be the cache writing to the log.
+func NewPrimary[K comparable, V any](addr string, log *Log[K, V], cache Applier[K, V]) (*Primary[K, V], error) {
+	if log == nil {
+		panic("replication log is required")
+	}
+	if cache == nil {
+		panic("cache is required")
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Primary[K, V]{
+		log:      log,
+		cache:    cache,
+		listener: listener,
+		conns:    map[net.Conn]struct{}{},
+		closed:   make(chan struct{}),
+	}
+
+	p.wg.Add(1)
+	go p.accept()
+
+	return p, nil
+}
+
+// Primary owns the writes and streams them to the followers.
+type Primary[K comparable, V any] struct {
+	log      *Log[K, V]
+	cache    Applier[K, V]
+	listener net.Listener
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	wg        sync.WaitGroup
+}
+
+// Addr returns the address the primary listens on.
+func (p *Primary[K, V]) Addr() net.Addr {
+	return p.listener.Addr()
+}
+
+// Followers returns the number of connected followers.
+func (p *Primary[K, V]) Followers() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.conns)
+}
+
+// Close disconnects the followers and stops listening.
+// This method is safe to call multiple times.
+func (p *Primary[K, V]) Close() error {
+	var err error
+	p.closeOnce.Do(func() {
+		close(p.closed)
+		err = p.listener.Close()
+
+		p.mu.Lock()
+		for conn := range p.conns {
+			_ = conn.Close()
+		}
+		p.mu.Unlock()
+
+		p.wg.Wait()
+	})
+	return err
+}
+
+func (p *Primary[K, V]) accept() {
+	defer p.wg.Done()
+
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		p.mu.Lock()
+		select {
+		case <-p.closed:
+			p.mu.Unlock()
+			_ = conn.Close()
+			return
+		default:
+		}
+		p.conns[conn] = struct{}{}
+		p.mu.Unlock()
+
+		p.wg.Add(1)
+		go p.serve(conn)
+	}
+}
+
+// serve streams the log to a follower, and applies the writes it forwards.
+func (p *Primary[K, V]) serve(conn net.Conn) {
+	defer p.wg.Done()
+
+	done := make(chan struct{})
+	defer func() {
+		p.mu.Lock()
+		delete(p.conns, conn)
+		p.mu.Unlock()
+		_ = conn.Close()
+	}()
+
+	dec := json.NewDecoder(bufio.NewReader(conn))
+
+	var hello frame[K, V]
+	if err := dec.Decode(&hello); err != nil || hello.Type != frameHello {
+		return
+	}
+
+	// Forwarded writes are read in the background. The stream stops when the follower disconnects.
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer close(done)
+
+		for {
+			var f frame[K, V]
+			if err := dec.Decode(&f); err != nil {
+				_ = conn.Close()
+				return
+			}
+			if f.Type == frameWrite {
+				for _, event := range f.Events {
+					Apply(p.cache, event)
+				}
+			}
+		}
+	}()
+
+	p.stream(conn, hello.Epoch, hello.Seq, done)
+}
+
+func (p *Primary[K, V]) stream(conn net.Conn, epoch string, seq uint64, done <-chan struct{}) {
+	w := bufio.NewWriter(conn)
+	enc := json.NewEncoder(w)
+	send := func(f frame[K, V]) bool {
+		if err := enc.Encode(f); err != nil {
+			return false
+		}
+		return w.Flush() == nil
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	// New followers are always bootstrapped from a snapshot: replaying the log
+	// would not give the remaining time-to-live of the entries. Followers of another
+	// epoch are bootstrapped again: their sequence number does not refer to this log.
+	bootstrap := seq == 0 || epoch != p.log.Epoch()
+
+	for {
+		// Read Changed before Since, so that an append between both calls is not missed.
+		changed := p.log.Changed()
+
+		events, ok := p.log.Since(seq)
+		if !ok || bootstrap {
+			bootstrap = false
+			var snapshot []Event[K, V]
+			snapshot, seq = p.log.Snapshot()
+			if !send(frame[K, V]{Type: frameSnapshot, Seq: seq, Epoch: p.log.Epoch(), Events: snapshot}) {
+				return
+			}
+			continue
+		}
+
+		if len(events) > 0 {
+			if !send(frame[K, V]{Type: frameEvents, Events: events}) {
+				return
+			}
+			seq = events[len(events)-1].Seq
+			continue
+		}
+
+		select {
+		case <-changed:
+		case <-heartbeat.C:
+			if !send(frame[K, V]{Type: frameHeartbeat, Seq: p.log.LastSeq()}) {
+				return
+			}
+		case <-done:
+			return
+		case <-p.closed:
+			return
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package replication
+
+// Frames are JSON-encoded, one per line.
+//
+// Follower -> primary:
+//   - hello: first frame, Seq is the last event applied by the follower (0 for a new follower),
+//     in the log Epoch it was bootstrapped from;
+//   - write: writes forwarded by the follower, in Events.
+//
+// Primary -> follower:
+//   - snapshot: content of the primary, in Events, followed by the events after Seq of the log Epoch;
+//   - events: events of the log, in order;
+//   - heartbeat: sent when idle, Seq is the last event of the primary.
+const (
+	frameHello     = "hello"
+	frameWrite     = "write"
+	frameSnapshot  = "snapshot"
+	frameEvents    = "events"
+	frameHeartbeat = "heartbeat"
+)
+
+type frame[K comparable, V any] struct {
+	Type   string        `json:"type"`
+	Seq    uint64        `json:"seq,omitempty"`
+	Epoch  string        `json:"epoch,omitempty"`
+	Events []Event[K, V] `json:"events,omitempty"`
+}
@@ -45,6 +45,7 @@ const (
 
 // Ensure S3FIFOCache implements InMemoryCache interface.
 var _ base.InMemoryCache[string, int] = (*S3FIFOCache[string, int])(nil)
+var _ base.RetentionOrderedCache[string] = (*S3FIFOCache[string, int])(nil)
 
 // NewS3FIFOCache creates a new S3 FIFO cache with the specified capacity.
 func NewS3FIFOCache[K comparable, V any](capacity int) *S3FIFOCache[K, V] {
@@ -160,6 +161,23 @@ func (c *S3FIFOCache[K, V]) Keys() []K {
 	return all
 }
 
+// KeysByRetention returns up to n keys: keys of the main queue first, then keys of the small queue,
+// each queue ordered from the newest to the oldest. Ghost entries are not returned.
+// A non-positive n returns all keys.
+func (c *S3FIFOCache[K, V]) KeysByRetention(n int) []K {
+	if n <= 0 || n > len(c.cache) {
+		n = len(c.cache)
+	}
+
+	keys := make([]K, 0, n)
+	for _, l := range []*list.List[*entry[K, V]]{c.main, c.small} {
+		for e := l.Back(); e != nil && len(keys) < n; e = e.Prev() {
+			keys = append(keys, e.Value.key)
+		}
+	}
+	return keys
+}
+
 // Values returns all values currently in the cache.
 func (c *S3FIFOCache[K, V]) Values() []V {
 	all := make([]V, 0, len(c.cache))
@@ -613,3 +613,19 @@ func TestS3FIFOCache_EvictFromMain_FreqZeroEvictedImmediately(t *testing.T) {
 	assert.True(t, cache.Has("c"))
 	assert.True(t, cache.Has("d"))
 }
+
+func TestKeysByRetention(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewS3FIFOCache[string, int](10)
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+	cache.Get("a")
+	cache.Get("a")
+
+	// "a" was promoted to the main queue
+	is.Equal([]string{"a", "c", "b"}, cache.KeysByRetention(0))
+	is.Equal([]string{"a"}, cache.KeysByRetention(1))
+}
@@ -26,6 +26,8 @@ type SafeInMemoryCache[K comparable, V any] struct { //nolint:revive
 
 // Ensure SafeInMemoryCache implements InMemoryCache interface.
 var _ base.InMemoryCache[string, int] = (*SafeInMemoryCache[string, int])(nil)
+var _ base.RetentionOrderedCache[string] = (*SafeInMemoryCache[string, int])(nil)
+var _ base.AdaptiveWindowCache = (*SafeInMemoryCache[string, int])(nil)
 
 // Set stores a key-value pair in the cache with exclusive write lock.
 // This operation blocks other writers and readers until completion.
@@ -69,6 +71,30 @@ func (c *SafeInMemoryCache[K, V]) Keys() []K {
 	return c.InMemoryCache.Keys()
 }
 
+// KeysByRetention returns up to n keys in the retention order of the underlying cache, using a shared read lock.
+// Falls back to unordered keys when the underlying cache does not implement base.RetentionOrderedCache.
+func (c *SafeInMemoryCache[K, V]) KeysByRetention(n int) []K {
+	c.RLock()
+	defer c.RUnlock()
+	return base.KeysByRetention(c.InMemoryCache, n)
+}
+
+// WindowRatio returns the fraction of the capacity used by the admission window of the underlying cache,
+// using a shared read lock.
+func (c *SafeInMemoryCache[K, V]) WindowRatio() (float64, bool) {
+	c.RLock()
+	defer c.RUnlock()
+	return base.WindowRatio(c.InMemoryCache)
+}
+
+// PinWindowRatio pins the admission window of the underlying cache to a fraction of the capacity,
+// using an exclusive lock.
+func (c *SafeInMemoryCache[K, V]) PinWindowRatio(ratio float64) bool {
+	c.Lock()
+	defer c.Unlock()
+	return base.PinWindowRatio(c.InMemoryCache, ratio)
+}
+
 // Values returns all values currently in the cache using a shared read lock.
 // The returned slice is a snapshot and may not reflect concurrent modifications.
 func (c *SafeInMemoryCache[K, V]) Values() []V {
@@ -415,3 +415,18 @@ func TestSafeInMemoryCache_InterfaceCompliance(t *testing.T) {
 	is.True(ok)
 	is.Equal(42, value)
 }
+
+func TestSafeInMemoryCache_KeysByRetention(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewSafeInMemoryCache[string, int](lru.NewLRUCache[string, int](10))
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+
+	ordered, ok := cache.(base.RetentionOrderedCache[string])
+	is.True(ok)
+	is.Equal([]string{"c", "b", "a"}, ordered.KeysByRetention(0))
+	is.Equal([]string{"c"}, ordered.KeysByRetention(1))
+}
@@ -37,6 +37,9 @@ type ShardedInMemoryCache[K comparable, V any] struct {
 
 // Ensure ShardedInMemoryCache implements InMemoryCache interface.
 var _ base.InMemoryCache[string, int] = (*ShardedInMemoryCache[string, int])(nil)
+var _ base.RetentionOrderedCache[string] = (*ShardedInMemoryCache[string, int])(nil)
+var _ base.AdaptiveWindowCache = (*ShardedInMemoryCache[string, int])(nil)
+var _ base.TieredStorageCache[string, int] = (*ShardedInMemoryCache[string, int])(nil)
 
 // Set stores a key-value pair in the appropriate shard based on the key's hash.
 // The key is hashed to determine which shard to use, providing O(1) average case performance.
@@ -73,6 +76,55 @@ func (c *ShardedInMemoryCache[K, V]) Keys() []K {
 	return keys
 }
 
+// KeysByRetention returns up to n keys from all shards.
+// Shards are interleaved round-robin, so that the keys each shard would keep the longest come first.
+// A non-positive n returns all keys.
+func (c *ShardedInMemoryCache[K, V]) KeysByRetention(n int) []K {
+	perShard := make([][]K, len(c.caches))
+	total := 0
+	for i := range c.caches {
+		perShard[i] = base.KeysByRetention(c.caches[i], n)
+		total += len(perShard[i])
+	}
+
+	if n <= 0 || n > total {
+		n = total
+	}
+
+	keys := make([]K, 0, n)
+	for rank := 0; len(keys) < n; rank++ {
+		for i := range perShard {
+			if rank < len(perShard[i]) && len(keys) < n {
+				keys = append(keys, perShard[i][rank])
+			}
+		}
+	}
+	return keys
+}
+
+// WindowRatio returns the average fraction of the capacity used by the admission windows of the shards.
+// Each shard adapts its window independently.
+func (c *ShardedInMemoryCache[K, V]) WindowRatio() (float64, bool) {
+	sum := 0.0
+	for i := range c.caches {
+		ratio, ok := base.WindowRatio(c.caches[i])
+		if !ok {
+			return 0, false
+		}
+		sum += ratio
+	}
+	return sum / float64(len(c.caches)), true
+}
+
+// PinWindowRatio pins the admission window of every shard to a fraction of its capacity.
+func (c *ShardedInMemoryCache[K, V]) PinWindowRatio(ratio float64) bool {
+	ok := true
+	for i := range c.caches {
+		ok = base.PinWindowRatio(c.caches[i], ratio) && ok
+	}
+	return ok
+}
+
 // Values returns all values from all shards combined into a single slice.
 // The order of values in the returned slice is not guaranteed.
 // Time complexity: O(n) where n is the total number of values across all shards.
@@ -112,6 +164,21 @@ func (c *ShardedInMemoryCache[K, V]) Range(f func(K, V) bool) {
 	}
 }
 
+// RangeMemory iterates over the key-value pairs held in memory by all shards.
+// The iteration stops if the function returns false.
+func (c *ShardedInMemoryCache[K, V]) RangeMemory(f func(K, V) bool) {
+	ok := true
+	for i := range c.caches {
+		base.RangeMemory(c.caches[i], func(k K, v V) bool {
+			ok = f(k, v)
+			return ok
+		})
+		if !ok {
+			return
+		}
+	}
+}
+
 // Delete removes a key from the appropriate shard based on the key's hash.
 // Returns true if the key was found and removed, false otherwise.
 func (c *ShardedInMemoryCache[K, V]) Delete(key K) bool {
@@ -442,3 +442,24 @@ func TestShardedInMemoryCache_ManyShards(t *testing.T) {
 		is.Equal(i*10, value)
 	}
 }
+
+func TestShardedInMemoryCache_KeysByRetention(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewShardedInMemoryCache(2, func(shardIndex int) base.InMemoryCache[int, int] {
+		return lru.NewLRUCache[int, int](10)
+	}, func(key int) uint64 { return uint64(key) })
+
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+	cache.Set(3, 3)
+	cache.Set(4, 4)
+	cache.Set(6, 6)
+
+	ordered, ok := cache.(base.RetentionOrderedCache[int])
+	is.True(ok)
+	// shards are interleaved: [6 4 2] and [3 1]
+	is.Equal([]int{6, 3, 4, 1, 2}, ordered.KeysByRetention(0))
+	is.Equal([]int{6, 3, 4}, ordered.KeysByRetention(3))
+}
@@ -71,6 +71,7 @@ func NewSIEVECacheWithEvictionCallback[K comparable, V any](capacity int, onEvic
 
 // Ensure SIEVECache implements InMemoryCache interface.
 var _ base.InMemoryCache[string, int] = (*SIEVECache[string, int])(nil)
+var _ base.RetentionOrderedCache[string] = (*SIEVECache[string, int])(nil)
 
 // Set stores a key-value pair in the cache.
 // If the key already exists, its value is updated and visited bit is set.
@@ -129,6 +130,24 @@ func (c *SIEVECache[K, V]) Keys() []K {
 	return all
 }
 
+// KeysByRetention returns up to n keys: visited keys first, then unvisited keys,
+// each group ordered from the newest to the oldest. A non-positive n returns all keys.
+func (c *SIEVECache[K, V]) KeysByRetention(n int) []K {
+	if n <= 0 || n > c.ll.Len() {
+		n = c.ll.Len()
+	}
+
+	keys := make([]K, 0, n)
+	for _, visited := range []bool{true, false} {
+		for e := c.ll.Front(); e != nil && len(keys) < n; e = e.Next() {
+			if e.Value.visited == visited {
+				keys = append(keys, e.Value.key)
+			}
+		}
+	}
+	return keys
+}
+
 // Values returns all values currently in the cache.
 func (c *SIEVECache[K, V]) Values() []V {
 	all := make([]V, 0, c.ll.Len())
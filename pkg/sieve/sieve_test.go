@@ -643,3 +643,17 @@ func TestEvictionCallback(t *testing.T) {
 	is.Equal("b", evictedKeys[1])
 	is.Equal(2, evictedValues[1])
 }
+
+func TestKeysByRetention(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewSIEVECache[string, int](3)
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+	cache.Get("a")
+
+	is.Equal([]string{"a", "c", "b"}, cache.KeysByRetention(0))
+	is.Equal([]string{"a", "c"}, cache.KeysByRetention(2))
+}
@@ -26,9 +26,28 @@ func NewTinyLFUCache[K comparable, V any](capacity int) *TinyLFUCache[K, V] {
 // NewTinyLFUCacheWithEvictionCallback creates a new TinyLFU cache with the specified capacity and eviction callback.
 // The callback will be called whenever an item is evicted from the cache.
 func NewTinyLFUCacheWithEvictionCallback[K comparable, V any](capacity int, onEviction base.EvictionCallback[K, V]) *TinyLFUCache[K, V] {
+	return NewTinyLFUCacheWithSketchAndEvictionCallback(capacity, capacity, sketch.DefaultSampleSizeRatio*capacity, onEviction)
+}
+
+// NewTinyLFUCacheWithSketch creates a new TinyLFU cache with the specified capacity and frequency sketch.
+// The sketch has sketchWidth 4-bit counters per row (the capacity by default), and its counters are halved
+// every sampleSize increments (10 × the capacity by default).
+func NewTinyLFUCacheWithSketch[K comparable, V any](capacity int, sketchWidth int, sampleSize int) *TinyLFUCache[K, V] {
+	return NewTinyLFUCacheWithSketchAndEvictionCallback[K, V](capacity, sketchWidth, sampleSize, nil)
+}
+
+// NewTinyLFUCacheWithSketchAndEvictionCallback creates a new TinyLFU cache with the specified capacity,
+// frequency sketch and eviction callback.
+func NewTinyLFUCacheWithSketchAndEvictionCallback[K comparable, V any](capacity int, sketchWidth int, sampleSize int, onEviction base.EvictionCallback[K, V]) *TinyLFUCache[K, V] {
 	if capacity <= 0 {
 		panic("capacity must be greater than 0")
 	}
+	if sketchWidth <= 0 {
+		panic("sketch width must be greater than 0")
+	}
+	if sampleSize <= 0 {
+		panic("sample size must be greater than 0")
+	}
 
 	admissionCapacity := max(capacity/100, 1)
 	mainCapacity := max(capacity-admissionCapacity, 1)
@@ -39,7 +58,7 @@ func NewTinyLFUCacheWithEvictionCallback[K comparable, V any](capacity int, onEv
 	}
 
 	return &TinyLFUCache[K, V]{
-		sketch: sketch.NewCountMinSketch[K](capacity, cmsDepth),
+		sketch: sketch.NewCountMinSketch[K](sketchWidth, cmsDepth, sampleSize),
 
 		mainCapacity:      mainCapacity,
 		admissionCapacity: admissionCapacity,
@@ -73,6 +92,7 @@ type TinyLFUCache[K comparable, V any] struct {
 
 // Ensure TinyLFUCache implements InMemoryCache interface.
 var _ base.InMemoryCache[string, int] = (*TinyLFUCache[string, int])(nil)
+var _ base.RetentionOrderedCache[string] = (*TinyLFUCache[string, int])(nil)
 
 // Set stores a key-value pair in the cache.
 // If the key already exists, its value is updated and it becomes the most recently used item.
@@ -200,6 +220,23 @@ func (c *TinyLFUCache[K, V]) Keys() []K {
 	return all
 }
 
+// KeysByRetention returns up to n keys: keys of the main cache first, then keys of the admission window,
+// each segment ordered from the most recently used to the least recently used.
+// A non-positive n returns all keys.
+func (c *TinyLFUCache[K, V]) KeysByRetention(n int) []K {
+	if n <= 0 || n > c.Len() {
+		n = c.Len()
+	}
+
+	keys := make([]K, 0, n)
+	for _, l := range []*list.List[*entry[K, V]]{c.mainLl, c.admissionLl} {
+		for e := l.Front(); e != nil && len(keys) < n; e = e.Next() {
+			keys = append(keys, e.Value.key)
+		}
+	}
+	return keys
+}
+
 // Values returns all values currently in the cache.
 func (c *TinyLFUCache[K, V]) Values() []V {
 	all := make([]V, 0, c.mainLl.Len()+c.admissionLl.Len())
@@ -2,6 +2,7 @@ package tinylfu
 
 import (
 	"fmt"
+	"math/rand"
 	"testing"
 
 	"github.com/samber/hot/pkg/base"
@@ -1196,9 +1197,9 @@ func TestTinyLFUCacheCountMinSketchBehavior(t *testing.T) {
 		cache.Set(key, i) // This also increments the counter
 	}
 
-	// The sketch should have a high estimate for this key
+	// The sketch should have a high estimate for this key: 4-bit counters saturate at 15
 	estimate := cache.sketch.Estimate(key)
-	is.Greater(estimate, 50) // Should have high frequency
+	is.Equal(15, estimate) // Should have high frequency
 
 	// Test with different keys
 	for i := 0; i < 50; i++ {
@@ -1334,3 +1335,56 @@ func TestTinyLFUCacheSetGetUpdatePattern(t *testing.T) {
 		}
 	}
 }
+
+func TestKeysByRetention(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewTinyLFUCache[string, int](200)
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Get("a")
+
+	keys := cache.KeysByRetention(0)
+	is.Len(keys, cache.Len())
+	is.ElementsMatch([]string{"a", "b"}, keys)
+	is.Len(cache.KeysByRetention(1), 1)
+}
+
+// replayShift reads 1_000 Zipf-distributed keys, then 1_000 new ones, inserting the misses,
+// and returns the hit ratio of the second phase.
+func replayShift(cache *TinyLFUCache[int, int]) float64 {
+	r := rand.New(rand.NewSource(42))
+	hits := 0
+	for phase := 0; phase < 2; phase++ {
+		zipf := rand.NewZipf(r, 1.1, 1, 999)
+		hits = 0
+		for i := 0; i < 50_000; i++ {
+			key := phase*100_000 + int(zipf.Uint64())
+			if _, ok := cache.Get(key); ok {
+				hits++
+			} else {
+				cache.Set(key, key)
+			}
+		}
+	}
+	return float64(hits) / 50_000
+}
+
+func TestNewTinyLFUCacheWithSketch(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	// Without aging, the keys of the first phase keep saturated frequencies, and new keys are never admitted.
+	aging := replayShift(NewTinyLFUCacheWithSketch[int, int](100, 100, 1_000))
+	frozen := replayShift(NewTinyLFUCacheWithSketch[int, int](100, 100, 1<<60))
+	is.Greater(aging, 0.5)
+	is.Less(frozen, 0.1)
+
+	is.PanicsWithValue("sketch width must be greater than 0", func() {
+		_ = NewTinyLFUCacheWithSketch[int, int](100, 0, 1_000)
+	})
+	is.PanicsWithValue("sample size must be greater than 0", func() {
+		_ = NewTinyLFUCacheWithSketch[int, int](100, 100, 0)
+	})
+}
@@ -109,6 +109,7 @@ type TwoQueueCache[K comparable, V any] struct { //nolint:revive
 
 // Ensure TwoQueueCache implements InMemoryCache interface.
 var _ base.InMemoryCache[string, int] = (*TwoQueueCache[string, int])(nil)
+var _ base.RetentionOrderedCache[string] = (*TwoQueueCache[string, int])(nil)
 
 // Set stores a key-value pair in the cache using the 2Q algorithm.
 // The algorithm determines where to place the item based on its access history:
@@ -193,6 +194,21 @@ func (c *TwoQueueCache[K, V]) Keys() []K {
 	return append(k1, k2...)
 }
 
+// KeysByRetention returns up to n keys: keys of the frequent cache first (most recently used first),
+// then keys of the recent cache (newest first). Ghost entries are not returned.
+// A non-positive n returns all keys.
+func (c *TwoQueueCache[K, V]) KeysByRetention(n int) []K {
+	if n <= 0 || n > c.Len() {
+		n = c.Len()
+	}
+
+	keys := c.frequent.KeysByRetention(n)
+	for e := c.recent.ll.Back(); e != nil && len(keys) < n; e = e.Prev() {
+		keys = append(keys, e.Value.key)
+	}
+	return keys
+}
+
 // Values returns all values from both frequent and recent caches combined.
 // The order of values in the returned slice is not guaranteed.
 func (c *TwoQueueCache[K, V]) Values() []V {
@@ -583,3 +583,18 @@ func TestInternalState_Range(t *testing.T) {
 	is.Equal(1, visited["a"])
 	is.Equal(2, visited["b"])
 }
+
+func TestKeysByRetention(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := New2QCache[string, int](20)
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+	cache.Get("a")
+
+	// "a" was promoted to the frequent cache
+	is.Equal([]string{"a", "c", "b"}, cache.KeysByRetention(0))
+	is.Equal([]string{"a", "c"}, cache.KeysByRetention(2))
+}
@@ -1,6 +1,8 @@
 package wtinylfu
 
 import (
+	"math"
+
 	"github.com/DmitriyVTitov/size"
 	"github.com/samber/hot/internal"
 	"github.com/samber/hot/internal/container/list"
@@ -15,66 +17,132 @@ type entry[K comparable, V any] struct {
 	freq  int // The frequency of the key
 }
 
+// Hill climbing of the window size, as in Caffeine.
+const (
+	// initialWindowRatio is the fraction of the capacity used by the window cache, before any adaptation.
+	initialWindowRatio = 0.01
+	// maxWindowRatio bounds the window cache, so that frequently used keys always have room in the main cache.
+	maxWindowRatio = 0.8
+	// sampleSizeRatio is the number of reads of a sample of the hit ratio, relative to the capacity.
+	sampleSizeRatio = 10
+	// stepRatio is the first resize of the window, relative to the capacity.
+	stepRatio = 0.0625
+	// stepDecay shrinks the step after each sample, so that the window converges.
+	stepDecay = 0.98
+	// restartThreshold is the change of hit ratio that restarts the climb with a full step,
+	// as the workload has likely changed.
+	restartThreshold = 0.05
+)
+
 // NewWTinyLFUCache creates a new Windowed TinyLFU cache with the specified capacity.
-// The cache uses a window cache (1%) and SLRU main cache (99%) with frequency-based admission.
+// The cache uses a window cache (1% at first) and SLRU main cache with frequency-based admission.
+// The size of the window adapts to the workload: see WindowRatio.
 func NewWTinyLFUCache[K comparable, V any](capacity int) *WTinyLFUCache[K, V] {
 	return NewWTinyLFUCacheWithEvictionCallback[K, V](capacity, nil)
 }
 
 // NewWTinyLFUCacheWithEvictionCallback creates a new Windowed TinyLFU cache with the specified capacity and eviction callback.
 func NewWTinyLFUCacheWithEvictionCallback[K comparable, V any](capacity int, onEviction base.EvictionCallback[K, V]) *WTinyLFUCache[K, V] {
+	return NewWTinyLFUCacheWithSketchAndEvictionCallback(capacity, capacity, sketch.DefaultSampleSizeRatio*capacity, onEviction)
+}
+
+// NewWTinyLFUCacheWithSketch creates a new Windowed TinyLFU cache with the specified capacity and frequency sketch.
+// The sketch has sketchWidth 4-bit counters per row (the capacity by default). Every sampleSize increments
+// (10 × the capacity by default), its counters are halved and its doorkeeper is cleared.
+func NewWTinyLFUCacheWithSketch[K comparable, V any](capacity int, sketchWidth int, sampleSize int) *WTinyLFUCache[K, V] {
+	return NewWTinyLFUCacheWithSketchAndEvictionCallback[K, V](capacity, sketchWidth, sampleSize, nil)
+}
+
+// NewWTinyLFUCacheWithSketchAndEvictionCallback creates a new Windowed TinyLFU cache with the specified capacity,
+// frequency sketch and eviction callback.
+func NewWTinyLFUCacheWithSketchAndEvictionCallback[K comparable, V any](capacity int, sketchWidth int, sampleSize int, onEviction base.EvictionCallback[K, V]) *WTinyLFUCache[K, V] {
 	if capacity <= 0 {
 		panic("capacity must be greater than 0")
 	}
-
-	// W-TinyLFU uses 1% of capacity for the window cache
-	windowCapacity := max(capacity/100, 1)
-	mainCapacity := capacity - windowCapacity
-
-	// SLRU: 20% of main cache for probationary, 80% for protected
-	probationaryCapacity := max(mainCapacity/5, 1)
-	protectedCapacity := mainCapacity - probationaryCapacity
+	if sketchWidth <= 0 {
+		panic("sketch width must be greater than 0")
+	}
+	if sampleSize <= 0 {
+		panic("sample size must be greater than 0")
+	}
 
 	cmsDepth := 4
 	if capacity < 10_000 {
 		cmsDepth = 3
 	}
 
-	return &WTinyLFUCache[K, V]{
-		sketch: sketch.NewDoorkeeperCountMinSketch[K](capacity, cmsDepth),
+	c := &WTinyLFUCache[K, V]{
+		sketch: sketch.NewDoorkeeperCountMinSketch[K](sketchWidth, cmsDepth, sampleSize),
 
-		// Window cache (admission window) - 1% of total capacity
-		windowCapacity: windowCapacity,
-		windowLl:       list.New[*entry[K, V]](),
-		windowCache:    make(map[K]*list.Element[*entry[K, V]]),
+		capacity: capacity,
 
-		// Main cache with SLRU structure - 99% of total capacity
-		mainCapacity:         mainCapacity,
-		probationaryCapacity: probationaryCapacity,
-		protectedCapacity:    protectedCapacity,
+		windowLl:    list.New[*entry[K, V]](),
+		windowCache: make(map[K]*list.Element[*entry[K, V]]),
 
 		probationaryLl:  list.New[*entry[K, V]](),
 		probationaryMap: make(map[K]*list.Element[*entry[K, V]]),
 		protectedLl:     list.New[*entry[K, V]](),
 		protectedMap:    make(map[K]*list.Element[*entry[K, V]]),
 
+		minWindowCapacity: 1,
+		maxWindowCapacity: max(int(float64(capacity)*maxWindowRatio), 1),
+		sampleSize:        sampleSizeRatio * capacity,
+		step:              stepRatio * float64(capacity),
+
 		onEviction: onEviction,
 	}
+
+	c.windowTarget = float64(max(int(float64(capacity)*initialWindowRatio), 1))
+	c.resize(int(c.windowTarget))
+
+	return c
+}
+
+// NewWTinyLFUCacheWithWindowRatio creates a new Windowed TinyLFU cache whose window cache is pinned
+// to a fraction of the capacity, in (0, 1).
+func NewWTinyLFUCacheWithWindowRatio[K comparable, V any](capacity int, windowRatio float64) *WTinyLFUCache[K, V] {
+	return NewWTinyLFUCacheWithWindowRatioAndEvictionCallback[K, V](capacity, windowRatio, nil)
+}
+
+// NewWTinyLFUCacheWithWindowRatioAndEvictionCallback creates a new Windowed TinyLFU cache whose window cache
+// is pinned to a fraction of the capacity, in (0, 1), with an eviction callback.
+func NewWTinyLFUCacheWithWindowRatioAndEvictionCallback[K comparable, V any](capacity int, windowRatio float64, onEviction base.EvictionCallback[K, V]) *WTinyLFUCache[K, V] {
+	c := NewWTinyLFUCacheWithEvictionCallback[K, V](capacity, onEviction)
+	c.PinWindowRatio(windowRatio)
+	return c
 }
 
 // WTinyLFUCache is a Windowed TinyLFU cache implementation.
-// It uses a window cache (1%) and SLRU main cache (99%) with frequency-based admission policy.
+// It uses a window cache and SLRU main cache with frequency-based admission policy.
+//
+// The window cache starts at 1% of the capacity. Unless pinned, its size is tuned by hill climbing,
+// as in Caffeine: every 10 × capacity reads, the window grows or shrinks by a step, in the direction
+// that improved the hit ratio of the last sample. The step decays, and restarts when the hit ratio changes
+// abruptly. The window is bounded to [1, 80% of the capacity].
 type WTinyLFUCache[K comparable, V any] struct {
 	noCopy internal.NoCopy // Prevents accidental copying of the cache
 
 	sketch *sketch.DoorkeeperCountMinSketch[K]
 
-	// Window cache (admission window) - 1% of total capacity
+	capacity int // Window and main cache capacities sum up to capacity
+
+	// Window cache (admission window)
 	windowCapacity int
 	windowLl       *list.List[*entry[K, V]]
 	windowCache    map[K]*list.Element[*entry[K, V]]
 
-	// Main cache with SLRU structure - 99% of total capacity
+	// Hill climbing of the window capacity
+	pinned            bool
+	minWindowCapacity int
+	maxWindowCapacity int
+	windowTarget      float64 // Window capacity before rounding
+	sampleSize        int     // Reads per sample
+	sampleHits        int
+	sampleMisses      int
+	previousHitRatio  float64
+	step              float64 // Signed: positive grows the window
+
+	// Main cache with SLRU structure
 	mainCapacity         int
 	probationaryCapacity int
 	protectedCapacity    int
@@ -89,6 +157,8 @@ type WTinyLFUCache[K comparable, V any] struct {
 
 // Ensure WTinyLFUCache implements InMemoryCache interface.
 var _ base.InMemoryCache[string, int] = (*WTinyLFUCache[string, int])(nil)
+var _ base.RetentionOrderedCache[string] = (*WTinyLFUCache[string, int])(nil)
+var _ base.AdaptiveWindowCache = (*WTinyLFUCache[string, int])(nil)
 
 // Set stores a key-value pair in the cache.
 func (c *WTinyLFUCache[K, V]) Set(key K, value V) {
@@ -151,6 +221,21 @@ func (c *WTinyLFUCache[K, V]) Get(key K) (value V, ok bool) {
 	// Increment frequency exactly ONCE per cache access (TinyLFU paper spec)
 	c.sketch.Inc(key)
 
+	value, ok = c.get(key)
+
+	if ok {
+		c.sampleHits++
+	} else {
+		c.sampleMisses++
+	}
+	if c.sampleHits+c.sampleMisses >= c.sampleSize {
+		c.climb()
+	}
+
+	return value, ok
+}
+
+func (c *WTinyLFUCache[K, V]) get(key K) (value V, ok bool) {
 	// Check protected segment first
 	if e, hit := c.protectedMap[key]; hit {
 		c.protectedLl.MoveToFront(e)
@@ -212,6 +297,23 @@ func (c *WTinyLFUCache[K, V]) Keys() []K {
 	return all
 }
 
+// KeysByRetention returns up to n keys: protected keys first, then probationary keys, then window keys,
+// each segment ordered from the most recently used to the least recently used.
+// A non-positive n returns all keys.
+func (c *WTinyLFUCache[K, V]) KeysByRetention(n int) []K {
+	if n <= 0 || n > c.Len() {
+		n = c.Len()
+	}
+
+	keys := make([]K, 0, n)
+	for _, l := range []*list.List[*entry[K, V]]{c.protectedLl, c.probationaryLl, c.windowLl} {
+		for e := l.Front(); e != nil && len(keys) < n; e = e.Next() {
+			keys = append(keys, e.Value.key)
+		}
+	}
+	return keys
+}
+
 // Values returns all values currently in the cache.
 func (c *WTinyLFUCache[K, V]) Values() []V {
 	all := make([]V, 0, c.windowLl.Len()+c.probationaryLl.Len()+c.protectedLl.Len())
@@ -346,11 +448,91 @@ func (c *WTinyLFUCache[K, V]) Purge() {
 	c.probationaryMap = make(map[K]*list.Element[*entry[K, V]])
 	c.protectedMap = make(map[K]*list.Element[*entry[K, V]])
 	c.sketch.Reset()
+	c.sampleHits = 0
+	c.sampleMisses = 0
 }
 
 // Capacity returns the maximum number of items the cache can hold.
 func (c *WTinyLFUCache[K, V]) Capacity() int {
-	return c.windowCapacity + c.mainCapacity
+	return c.capacity
+}
+
+// WindowRatio returns the fraction of the capacity used by the window cache.
+func (c *WTinyLFUCache[K, V]) WindowRatio() (float64, bool) {
+	return float64(c.windowCapacity) / float64(c.capacity), true
+}
+
+// PinWindowRatio resizes the window cache to a fraction of the capacity, in (0, 1), and stops its adaptation.
+// The window keeps at least 1 key, and leaves at least 1 key to the main cache when the capacity allows it.
+func (c *WTinyLFUCache[K, V]) PinWindowRatio(ratio float64) bool {
+	if ratio <= 0 || ratio >= 1 {
+		panic("window ratio must be in (0, 1)")
+	}
+
+	c.pinned = true
+	c.windowTarget = float64(c.capacity) * ratio
+	c.resize(min(max(int(c.windowTarget), 1), max(c.capacity-1, 1)))
+	return true
+}
+
+// climb ends a sample: the window moves by a step in the direction that improved the hit ratio,
+// or in the opposite direction when the hit ratio decreased.
+func (c *WTinyLFUCache[K, V]) climb() {
+	hitRatio := float64(c.sampleHits) / float64(c.sampleHits+c.sampleMisses)
+	c.sampleHits = 0
+	c.sampleMisses = 0
+
+	if c.pinned || c.maxWindowCapacity <= c.minWindowCapacity {
+		return
+	}
+
+	change := hitRatio - c.previousHitRatio
+	c.previousHitRatio = hitRatio
+
+	amount := c.step
+	if change < 0 {
+		amount = -c.step
+	}
+
+	if math.Abs(change) >= restartThreshold {
+		c.step = math.Copysign(stepRatio*float64(c.capacity), amount)
+	} else {
+		c.step = stepDecay * amount
+	}
+
+	c.windowTarget = min(max(c.windowTarget+amount, float64(c.minWindowCapacity)), float64(c.maxWindowCapacity))
+	c.resize(int(math.Round(c.windowTarget)))
+}
+
+// resize splits the capacity between the window cache and the main cache, and moves the keys accordingly:
+// keys leaving a shrinking window go to the probationary segment, keys leaving a shrinking protected segment
+// are demoted to the probationary segment, and the probationary segment evicts its least recently used keys
+// until the main cache fits.
+func (c *WTinyLFUCache[K, V]) resize(windowCapacity int) {
+	c.windowCapacity = windowCapacity
+	c.mainCapacity = c.capacity - windowCapacity
+
+	// SLRU: 20% of main cache for probationary, 80% for protected
+	c.probationaryCapacity = max(c.mainCapacity/5, 1)
+	c.protectedCapacity = max(c.mainCapacity-c.probationaryCapacity, 0)
+
+	for c.windowLl.Len() > c.windowCapacity {
+		e := c.windowLl.Back()
+		c.windowLl.Remove(e)
+		delete(c.windowCache, e.Value.key)
+		c.probationaryMap[e.Value.key] = c.probationaryLl.PushFront(e.Value)
+	}
+
+	for c.protectedLl.Len() > c.protectedCapacity {
+		e := c.protectedLl.Back()
+		c.protectedLl.Remove(e)
+		delete(c.protectedMap, e.Value.key)
+		c.probationaryMap[e.Value.key] = c.probationaryLl.PushFront(e.Value)
+	}
+
+	for c.probationaryLl.Len()+c.protectedLl.Len() > c.mainCapacity && c.probationaryLl.Len() > 0 {
+		c.evictFromProbationary()
+	}
 }
 
 // Algorithm returns the name of the eviction algorithm used by the cache.
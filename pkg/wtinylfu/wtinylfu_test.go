@@ -3,6 +3,7 @@ package wtinylfu
 import (
 	"fmt"
 	"log"
+	"math/rand"
 	"os"
 	"testing"
 
@@ -592,3 +593,121 @@ func TestConcurrentAccessPattern(t *testing.T) {
 	// Cache should respect capacity
 	is.LessOrEqual(cache.Len(), 1000)
 }
+
+func TestKeysByRetention(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewWTinyLFUCache[string, int](200)
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.Set("c", 3)
+	cache.Set("d", 4)
+
+	keys := cache.KeysByRetention(0)
+	is.Len(keys, cache.Len())
+	is.ElementsMatch(cache.Keys(), keys)
+	is.Len(cache.KeysByRetention(2), 2)
+}
+
+// replay reads keys like a loader would, inserting the misses, and returns the hit ratio.
+func replay(is *assert.Assertions, cache *WTinyLFUCache[int, int], keys func(i int) int, requests int) float64 {
+	hits := 0
+	for i := 0; i < requests; i++ {
+		key := keys(i)
+		if _, ok := cache.Get(key); ok {
+			hits++
+		} else {
+			cache.Set(key, key)
+		}
+		is.LessOrEqual(cache.Len(), cache.Capacity())
+	}
+	return float64(hits) / float64(requests)
+}
+
+func TestAdaptiveWindow(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	// A working set of 50 keys sliding forward: new keys are hot at once, so the sketch rejects them
+	// from the main cache, and a larger window helps.
+	recency := func(r *rand.Rand) func(i int) int {
+		return func(i int) int { return i/20 + r.Intn(50) }
+	}
+
+	cache := NewWTinyLFUCache[int, int](100)
+	ratio, ok := cache.WindowRatio()
+	is.True(ok)
+	is.InDelta(0.01, ratio, 1e-9)
+
+	adaptive := replay(is, cache, recency(rand.New(rand.NewSource(42))), 100_000)
+	ratio, _ = cache.WindowRatio()
+	is.Greater(ratio, 0.2)
+	is.LessOrEqual(ratio, maxWindowRatio)
+
+	pinned := NewWTinyLFUCacheWithWindowRatio[int, int](100, 0.01)
+	fixed := replay(is, pinned, recency(rand.New(rand.NewSource(42))), 100_000)
+	ratio, _ = pinned.WindowRatio()
+	is.InDelta(0.01, ratio, 1e-9)
+	is.Greater(adaptive, fixed+0.5)
+}
+
+func TestPinWindowRatio(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	evicted := 0
+	cache := NewWTinyLFUCacheWithEvictionCallback[int, int](100, func(base.EvictionReason, int, int) { evicted++ })
+	for i := 0; i < 100; i++ {
+		cache.Set(i, i)
+		cache.Get(i)
+		cache.Get(i)
+	}
+	is.Equal(100, cache.Len()+evicted)
+
+	// Shrinking the main cache evicts keys, growing it again does not.
+	is.True(cache.PinWindowRatio(0.5))
+	ratio, _ := cache.WindowRatio()
+	is.InDelta(0.5, ratio, 1e-9)
+	is.LessOrEqual(cache.Len(), 100)
+	is.Equal(100, cache.Len()+evicted)
+
+	is.True(cache.PinWindowRatio(0.1))
+	is.Equal(100, cache.Len()+evicted)
+
+	// The window keeps at least 1 key.
+	cache = NewWTinyLFUCacheWithWindowRatio[int, int](10, 0.01)
+	ratio, _ = cache.WindowRatio()
+	is.InDelta(0.1, ratio, 1e-9)
+
+	is.PanicsWithValue("window ratio must be in (0, 1)", func() {
+		NewWTinyLFUCacheWithWindowRatio[int, int](100, 1)
+	})
+	is.PanicsWithValue("window ratio must be in (0, 1)", func() {
+		cache.PinWindowRatio(0)
+	})
+}
+
+func TestNewWTinyLFUCacheWithSketch(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewWTinyLFUCacheWithSketch[int, int](100, 100, 20)
+	for i := 0; i < 10; i++ {
+		cache.Set(1, 1)
+	}
+	is.Equal(10, cache.sketch.Estimate(1))
+
+	// The 20th increment halves the counters and clears the doorkeeper.
+	for i := 0; i < 10; i++ {
+		cache.Set(2, 2)
+	}
+	is.Equal(4, cache.sketch.Estimate(1))
+
+	is.PanicsWithValue("sketch width must be greater than 0", func() {
+		_ = NewWTinyLFUCacheWithSketch[int, int](100, 0, 1_000)
+	})
+	is.PanicsWithValue("sample size must be greater than 0", func() {
+		_ = NewWTinyLFUCacheWithSketch[int, int](100, 100, 0)
+	})
+}
@@ -0,0 +1,149 @@
+package hot
+
+import (
+	"time"
+
+	"github.com/samber/hot/internal"
+	"github.com/samber/hot/pkg/base"
+	"github.com/samber/hot/pkg/replication"
+)
+
+// Writes of a cache with a replication log are applied under the log lock, and appended
+// to the log in the same critical section: the order of the log is the order in which
+// the writes were applied, so that followers replaying it end up in the same state.
+
+// setReplicated stores a key-value pair, or a missing key, and appends it to the replication log.
+func (c *HotCache[K, V]) setReplicated(key K, hasValue bool, value V, ttlNano int64) {
+	c.replicationLog.Record(func() []replication.Event[K, V] {
+		c.storeUnsafe(key, hasValue, value, ttlNano)
+		return []replication.Event[K, V]{setEvent(key, hasValue, value, ttlNano)}
+	})
+}
+
+// setManyReplicated stores multiple key-value pairs and missing keys, and appends them to the replication log.
+func (c *HotCache[K, V]) setManyReplicated(items map[K]V, missing []K, ttlNano int64) {
+	c.replicationLog.Record(func() []replication.Event[K, V] {
+		c.storeManyUnsafe(items, missing, ttlNano)
+
+		events := make([]replication.Event[K, V], 0, len(items)+len(missing))
+		for k, v := range items {
+			events = append(events, setEvent(k, true, v, ttlNano))
+		}
+		for _, k := range missing {
+			events = append(events, setEvent(k, false, zero[V](), ttlNano))
+		}
+		return events
+	})
+}
+
+// deleteReplicated removes a key and appends the deletion to the replication log.
+func (c *HotCache[K, V]) deleteReplicated(key K) (ok bool) {
+	c.replicationLog.Record(func() []replication.Event[K, V] {
+		ok = c.deleteLocal(key)
+		return []replication.Event[K, V]{{Op: replication.OpDelete, Key: key}}
+	})
+	return ok
+}
+
+// deleteManyReplicated removes multiple keys and appends the deletions to the replication log.
+func (c *HotCache[K, V]) deleteManyReplicated(keys []K) (output map[K]bool) {
+	c.replicationLog.Record(func() []replication.Event[K, V] {
+		output = c.deleteManyLocal(keys)
+
+		events := make([]replication.Event[K, V], 0, len(keys))
+		for _, key := range keys {
+			events = append(events, replication.Event[K, V]{Op: replication.OpDelete, Key: key})
+		}
+		return events
+	})
+	return output
+}
+
+// purgeReplicated removes all keys and appends the purge to the replication log.
+func (c *HotCache[K, V]) purgeReplicated() {
+	c.replicationLog.Record(func() []replication.Event[K, V] {
+		c.purgeLocal()
+		return []replication.Event[K, V]{{Op: replication.OpPurge}}
+	})
+}
+
+// deleteExpired removes the keys found expired by the janitor from a cache and,
+// when a replication log is configured, appends the keys actually removed to it.
+// The keys removed are also expired from the shadow caches.
+func (c *HotCache[K, V]) deleteExpired(cache base.InMemoryCache[K, *item[V]], keys []K) (deleted map[K]bool) {
+	if c.replicationLog == nil {
+		deleted = cache.DeleteMany(keys)
+	} else {
+		c.replicationLog.Record(func() []replication.Event[K, V] {
+			deleted = cache.DeleteMany(keys)
+
+			events := make([]replication.Event[K, V], 0, len(deleted))
+			for k, ok := range deleted {
+				if ok {
+					events = append(events, replication.Event[K, V]{Op: replication.OpExpire, Key: k})
+				}
+			}
+			return events
+		})
+	}
+
+	if c.shadows != nil {
+		for k, ok := range deleted {
+			if ok {
+				c.shadows.delete(k)
+			}
+		}
+	}
+
+	return deleted
+}
+
+// setEvent describes a write as a replication event.
+func setEvent[K comparable, V any](key K, hasValue bool, value V, ttlNano int64) replication.Event[K, V] {
+	event := replication.Event[K, V]{Op: replication.OpSetMissing, Key: key, TTL: time.Duration(ttlNano)}
+	if hasValue {
+		event.Op = replication.OpSet
+		event.Value = value
+	}
+	return event
+}
+
+// MissingKeysEnabled reports whether the cache stores missing keys, in a dedicated or shared missing cache.
+// Followers without missing cache delete the missing keys replicated to them instead of storing them.
+func (c *HotCache[K, V]) MissingKeysEnabled() bool {
+	return c.missingCache != nil || c.missingSharedCache
+}
+
+// replicationSnapshot lists the content of the cache as replication events, with the remaining time-to-live.
+// Expired entries are skipped. When hot key tracking is enabled, the hottest keys come last.
+func (c *HotCache[K, V]) replicationSnapshot() []replication.Event[K, V] {
+	nowNano := internal.NowNano()
+	events := []replication.Event[K, V]{}
+
+	collect := func(k K, v *item[V]) bool {
+		var ttl time.Duration
+		if v.expiryNano > 0 {
+			if v.expiryNano <= nowNano {
+				return true
+			}
+			ttl = time.Duration(v.expiryNano - nowNano)
+		}
+
+		event := replication.Event[K, V]{Op: replication.OpSetMissing, Key: k, TTL: ttl}
+		if v.hasValue {
+			event.Op = replication.OpSet
+			event.Value = v.value
+		}
+		events = append(events, event)
+		return true
+	}
+
+	c.cache.Range(collect)
+	if c.missingCache != nil {
+		c.missingCache.Range(collect)
+	}
+
+	c.sortSnapshotByHotness(events)
+
+	return events
+}
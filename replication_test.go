@@ -0,0 +1,221 @@
+package hot
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samber/hot/pkg/replication"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithReplicationLog(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	log := replication.NewLog[string, int](100)
+	cache := NewHotCache[string, int](LRU, 10).
+		WithMissingCache(LRU, 10).
+		WithLoaders(func(keys []string) (map[string]int, error) {
+			return map[string]int{"loaded": 42}, nil
+		}).
+		WithReplicationLog(log).
+		Build()
+
+	cache.Set("a", 1)
+	cache.SetWithTTL("b", 2, time.Minute)
+	cache.SetMissing("c")
+	cache.SetMany(map[string]int{"d": 4})
+	_, _, _ = cache.GetMany([]string{"loaded", "unknown"})
+	cache.Delete("a")
+	cache.DeleteMany([]string{"b"})
+
+	events, ok := log.Since(0)
+	is.True(ok)
+	is.Len(events, 8)
+	is.Equal(replication.Event[string, int]{Seq: 1, Op: replication.OpSet, Key: "a", Value: 1}, events[0])
+	is.Equal(replication.Event[string, int]{Seq: 2, Op: replication.OpSet, Key: "b", Value: 2, TTL: time.Minute}, events[1])
+	is.Equal(replication.Event[string, int]{Seq: 3, Op: replication.OpSetMissing, Key: "c"}, events[2])
+	is.Equal(replication.Event[string, int]{Seq: 4, Op: replication.OpSet, Key: "d", Value: 4}, events[3])
+	is.ElementsMatch([]replication.Event[string, int]{
+		{Seq: 5, Op: replication.OpSet, Key: "loaded", Value: 42},
+		{Seq: 6, Op: replication.OpSetMissing, Key: "unknown"},
+	}, events[4:6])
+	is.Equal(replication.Event[string, int]{Seq: 7, Op: replication.OpDelete, Key: "a"}, events[6])
+	is.Equal(replication.Event[string, int]{Seq: 8, Op: replication.OpDelete, Key: "b"}, events[7])
+
+	snapshot, seq := log.Snapshot()
+	is.Equal(uint64(8), seq)
+	is.Equal(replication.Event[string, int]{Op: replication.OpPurge}, snapshot[0])
+	is.ElementsMatch([]replication.Event[string, int]{
+		{Op: replication.OpSet, Key: "d", Value: 4},
+		{Op: replication.OpSet, Key: "loaded", Value: 42},
+		{Op: replication.OpSetMissing, Key: "c"},
+		{Op: replication.OpSetMissing, Key: "unknown"},
+	}, snapshot[1:])
+
+	cache.Purge()
+	is.Equal(uint64(9), log.LastSeq())
+
+	is.Panics(func() {
+		_ = NewHotCache[string, int](LRU, 10).WithReplicationLog(nil)
+	})
+}
+
+func TestWithReplicationLog_Janitor(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+	testWithTimeout(t, 2*time.Second)
+
+	log := replication.NewLog[string, int](100)
+	cache := NewHotCache[string, int](LRU, 10).
+		WithTTL(10 * time.Millisecond).
+		WithJanitor().
+		WithReplicationLog(log).
+		Build()
+	defer cache.StopJanitor()
+
+	cache.Set("a", 1)
+
+	is.Eventually(func() bool {
+		events, _ := log.Since(1)
+		return len(events) == 1 && events[0].Op == replication.OpExpire && events[0].Key == "a"
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestWithReplicationLog_ConcurrentWrites(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	log := replication.NewLog[string, int](10_000)
+	cache := NewHotCache[string, int](LRU, 10).
+		WithReplicationLog(log).
+		Build()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				switch j % 4 {
+				case 0:
+					cache.Set("a", i*1000+j)
+				case 1:
+					cache.SetMany(map[string]int{"a": i*1000 + j, "b": j})
+				case 2:
+					cache.Delete("b")
+				default:
+					cache.DeleteMany([]string{"a"})
+					cache.Set("a", i*1000+j)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// replaying the log gives the state of the cache
+	replica := NewHotCache[string, int](LRU, 10).Build()
+	events, ok := log.Since(0)
+	is.True(ok)
+	for _, event := range events {
+		replication.Apply[string, int](replica, event)
+	}
+
+	for _, key := range []string{"a", "b"} {
+		expected, expectedOk := cache.Peek(key)
+		v, ok := replica.Peek(key)
+		is.Equal(expectedOk, ok, key)
+		is.Equal(expected, v, key)
+	}
+}
+
+func TestReplication_FollowerWithoutMissingCache(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewHotCache[string, int](LRU, 10).Build()
+	is.False(cache.MissingKeysEnabled())
+	is.True(NewHotCache[string, int](LRU, 10).WithMissingSharedCache().Build().MissingKeysEnabled())
+	is.True(NewHotCache[string, int](LRU, 10).WithMissingCache(LRU, 10).Build().MissingKeysEnabled())
+
+	// missing keys of the primary are deleted instead of stored
+	cache.Set("a", 1)
+	is.NotPanics(func() {
+		replication.Apply[string, int](cache, replication.Event[string, int]{Op: replication.OpSetMissing, Key: "a"})
+	})
+	is.False(cache.Has("a"))
+}
+
+func TestReplication_PrimaryFollower(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+	testWithTimeout(t, 5*time.Second)
+
+	log := replication.NewLog[string, int](100)
+	primaryCache := NewHotCache[string, int](LRU, 10).
+		WithReplicationLog(log).
+		Build()
+	primaryCache.SetWithTTL("a", 1, time.Hour)
+
+	primary, err := replication.NewPrimary[string, int]("127.0.0.1:0", log, primaryCache)
+	is.NoError(err)
+
+	followerCache := NewHotCache[string, int](LRU, 10).Build()
+	follower := replication.NewFollower[string, int]("test", primary.Addr().String(), followerCache)
+
+	is.Eventually(func() bool {
+		return followerCache.Has("a")
+	}, time.Second, 5*time.Millisecond)
+
+	is.NoError(follower.Set("b", 2))
+	is.Eventually(func() bool {
+		return followerCache.Has("b") && primaryCache.Has("b")
+	}, time.Second, 5*time.Millisecond)
+
+	primaryCache.Purge()
+	is.Eventually(func() bool {
+		return followerCache.Len() == 0
+	}, time.Second, 5*time.Millisecond)
+
+	is.NoError(follower.Close())
+	is.NoError(primary.Close())
+}
+
+func TestReplication_FollowerSetDefaultTTL(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+	testWithTimeout(t, 5*time.Second)
+
+	log := replication.NewLog[string, int](100)
+	primaryCache := NewHotCache[string, int](LRU, 10).
+		WithTTL(time.Minute).
+		WithReplicationLog(log).
+		Build()
+
+	primary, err := replication.NewPrimary[string, int]("127.0.0.1:0", log, primaryCache)
+	is.NoError(err)
+	defer primary.Close()
+
+	followerCache := NewHotCache[string, int](LRU, 10).Build()
+	follower := replication.NewFollower[string, int]("test", primary.Addr().String(), followerCache)
+	defer follower.Close()
+
+	is.Eventually(func() bool {
+		return follower.Stats().Connected
+	}, time.Second, 5*time.Millisecond)
+
+	// a write forwarded without TTL expires with the default TTL of the primary, on both sides
+	start := time.Now()
+	is.NoError(follower.Set("a", 1))
+	is.Eventually(func() bool {
+		return followerCache.Has("a")
+	}, time.Second, 5*time.Millisecond)
+
+	for _, cache := range []*HotCache[string, int]{primaryCache, followerCache} {
+		info, ok := cache.Inspect("a")
+		is.True(ok)
+		is.False(info.ExpiresAt.IsZero())
+		is.WithinDuration(start.Add(time.Minute), info.ExpiresAt, 5*time.Second)
+	}
+}
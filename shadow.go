@@ -0,0 +1,213 @@
+package hot
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/samber/hot/internal"
+	"github.com/samber/hot/internal/mrc"
+	"github.com/samber/hot/pkg/base"
+	"github.com/samber/hot/pkg/metrics"
+)
+
+// shadowMaxCapacity is the maximum capacity of a shadow cache. Larger caches are simulated
+// in miniature: keys are sampled, and the capacity of the shadow caches is scaled down by the same rate.
+const shadowMaxCapacity = 4096
+
+// ShadowStats reports the activity of a shadow cache, replaying the accesses of the cache with another eviction algorithm.
+// When keys are sampled, Lookups and Hits are extrapolated from the sampled keys: both only grow, like counters.
+type ShadowStats struct {
+	Algorithm EvictionAlgorithm `json:"algorithm"`
+	// Baseline is true for the shadow using the algorithm of the cache: the gap between its hit ratio
+	// and the real one is the error of the simulation.
+	Baseline bool `json:"baseline"`
+	// Capacity is the capacity of the shadow cache, scaled down by the sampling rate.
+	Capacity int `json:"capacity"`
+	// Lookups is the estimated number of keys read from the cache, and replayed in the shadow cache.
+	Lookups float64 `json:"lookups"`
+	// Hits is the estimated number of keys found in the shadow cache.
+	Hits float64 `json:"hits"`
+}
+
+// HitRatio returns the ratio of hits over lookups, or 0 when nothing was looked up.
+func (s ShadowStats) HitRatio() float64 {
+	if s.Lookups == 0 {
+		return 0
+	}
+	return s.Hits / s.Lookups
+}
+
+// shadowCaches replay a sample of the accesses in key-only caches using other eviction algorithms:
+// reads are looked up, writes (including missing keys and loaded values) are inserted with their TTL,
+// and deletions are removed. Keys that are not sampled cost a hash, without locking.
+type shadowCaches[K comparable] struct {
+	threshold uint64  // Keys are sampled when hash % mrc.Modulus < threshold
+	scale     float64 // Inverse of the sampling rate, extrapolating the sampled counts
+	capacity  int
+	staleNano int64
+
+	mu             sync.Mutex
+	algorithms     []EvictionAlgorithm // The algorithm of the cache comes first
+	caches         []base.InMemoryCache[K, *item[struct{}]]
+	sampledHits    []int64
+	sampledLookups int64
+
+	// Exporter of the shadow caches, nil when disabled.
+	prometheus *metrics.PrometheusShadowCollector
+}
+
+// newShadowCaches creates a shadow cache for the algorithm of the cache, as a baseline,
+// then one for each other algorithm.
+func newShadowCaches[K comparable](capacity int, staleNano int64, baseline EvictionAlgorithm, algorithms []EvictionAlgorithm) *shadowCaches[K] {
+	rate := 1.0
+	if capacity > shadowMaxCapacity {
+		rate = float64(shadowMaxCapacity) / float64(capacity)
+	}
+
+	all := []EvictionAlgorithm{baseline}
+	for _, algorithm := range algorithms {
+		if !slices.Contains(all, algorithm) {
+			all = append(all, algorithm)
+		}
+	}
+
+	threshold := max(uint64(rate*mrc.Modulus), 1)
+
+	s := &shadowCaches[K]{
+		threshold:   threshold,
+		scale:       float64(mrc.Modulus) / float64(threshold),
+		capacity:    max(int(float64(capacity)*rate), 1),
+		staleNano:   staleNano,
+		algorithms:  all,
+		caches:      make([]base.InMemoryCache[K, *item[struct{}]], 0, len(all)),
+		sampledHits: make([]int64, len(all)),
+	}
+
+	for _, algorithm := range all {
+		s.caches = append(s.caches, composeInternalCache[K, struct{}](false, algorithm, s.capacity, 0, -1, nil, nil, nil, nil, nil))
+	}
+
+	return s
+}
+
+func (s *shadowCaches[K]) sampled(key K) bool {
+	return mrc.Hash(key)%mrc.Modulus < s.threshold
+}
+
+// access replays the reads of the keys: keys found and not expired are counted as hits.
+// Misses are inserted by the write that follows, if any, like in the cache.
+// A nil shadowCaches replays nothing.
+func (s *shadowCaches[K]) access(keys ...K) {
+	if s == nil {
+		return
+	}
+
+	for _, key := range keys {
+		if !s.sampled(key) {
+			continue
+		}
+
+		nowNano := internal.NowNano()
+
+		s.mu.Lock()
+		s.sampledLookups++
+		for i, cache := range s.caches {
+			shadow, ok := cache.Get(key)
+			switch {
+			case ok && !shadow.isExpired(nowNano):
+				s.sampledHits[i]++
+			case ok:
+				cache.Delete(key)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// set replays the writes of the keys, values or missing keys, with the TTL of the cache entries.
+// A nil shadowCaches replays nothing.
+func (s *shadowCaches[K]) set(ttlNano int64, keys ...K) {
+	if s == nil {
+		return
+	}
+
+	for _, key := range keys {
+		if !s.sampled(key) {
+			continue
+		}
+
+		// The TTL is shared by the shadow caches: they only remember keys and expiry times.
+		shadow := newItemNoValue[struct{}](ttlNano, s.staleNano)
+
+		s.mu.Lock()
+		for _, cache := range s.caches {
+			cache.Set(key, shadow)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// delete replays the deletions of the keys, explicit or expired.
+// A nil shadowCaches replays nothing.
+func (s *shadowCaches[K]) delete(keys ...K) {
+	if s == nil {
+		return
+	}
+
+	for _, key := range keys {
+		if !s.sampled(key) {
+			continue
+		}
+
+		s.mu.Lock()
+		for _, cache := range s.caches {
+			cache.Delete(key)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// purge replays a purge of the cache.
+// A nil shadowCaches replays nothing.
+func (s *shadowCaches[K]) purge() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	for _, cache := range s.caches {
+		cache.Purge()
+	}
+	s.mu.Unlock()
+}
+
+func (s *shadowCaches[K]) stats() []ShadowStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Scaling the sampled counts keeps them monotonic, unlike scaling the sampled hit ratio.
+	stats := make([]ShadowStats, 0, len(s.algorithms))
+	for i, algorithm := range s.algorithms {
+		stats = append(stats, ShadowStats{
+			Algorithm: algorithm,
+			Baseline:  i == 0,
+			Capacity:  s.capacity,
+			Lookups:   float64(s.sampledLookups) * s.scale,
+			Hits:      float64(s.sampledHits[i]) * s.scale,
+		})
+	}
+
+	return stats
+}
+
+// prometheusShadows returns the shadow caches formatted for the Prometheus exporter.
+func (c *HotCache[K, V]) prometheusShadows() []metrics.ShadowResult {
+	stats := c.shadows.stats()
+
+	results := make([]metrics.ShadowResult, 0, len(stats))
+	for _, s := range stats {
+		results = append(results, metrics.ShadowResult{Algorithm: string(s.Algorithm), Lookups: s.Lookups, Hits: s.Hits})
+	}
+
+	return results
+}
@@ -0,0 +1,226 @@
+package hot
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHotCache_ShadowStats(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	is.Nil(NewHotCache[int, int](LRU, 10).Build().Stats().Shadows)
+
+	// 1_000 keys are replayed without sampling, 10_000 keys are sampled.
+	for _, capacity := range []int{1_000, 10_000} {
+		cache := NewHotCache[int, int](LRU, capacity).
+			WithStats().
+			WithShadowAlgorithms(LRU, FIFO, WTinyLFU).
+			WithLoaders(func(keys []int) (map[int]int, error) {
+				values := make(map[int]int, len(keys))
+				for _, key := range keys {
+					values[key] = key
+				}
+				return values, nil
+			}).
+			Build()
+
+		zipf := rand.NewZipf(rand.New(rand.NewSource(42)), 1.1, 1, uint64(capacity*100-1))
+		for i := 0; i < 200_000; i++ {
+			_, _, _ = cache.Get(int(zipf.Uint64()))
+		}
+
+		stats := cache.Stats()
+		is.Len(stats.Shadows, 3)
+		is.Equal(LRU, stats.Shadows[0].Algorithm)
+		is.True(stats.Shadows[0].Baseline)
+		is.False(stats.Shadows[1].Baseline)
+		is.Equal(FIFO, stats.Shadows[1].Algorithm)
+		is.Equal(WTinyLFU, stats.Shadows[2].Algorithm)
+		is.Equal(min(capacity, shadowMaxCapacity), stats.Shadows[0].Capacity)
+
+		// The cache is LRU: its shadow estimates its own hit ratio.
+		if capacity <= shadowMaxCapacity {
+			is.Equal(200_000.0, stats.Shadows[0].Lookups)
+		} else {
+			// Extrapolated from the sampled keys: few hot keys weigh a lot with a skewed workload.
+			is.InEpsilon(200_000.0, stats.Shadows[0].Lookups, 0.5)
+		}
+		is.InDelta(stats.HitRatio(), stats.Shadows[0].HitRatio(), 0.05)
+
+		// FIFO never promotes the keys it hits.
+		is.Less(stats.Shadows[1].HitRatio(), stats.Shadows[0].HitRatio())
+		is.Positive(stats.Shadows[2].Hits)
+	}
+}
+
+func TestWithShadowAlgorithms(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewHotCache[int, int](LRU, 100).
+		WithShadowAlgorithms(LRU, LFU).
+		WithPrometheusMetrics("test-cache").
+		Build()
+
+	// The first pass misses, without loaders to fill the shadow caches. The second pass hits the keys written since.
+	keys := make([]int, 50)
+	items := make(map[int]int, len(keys))
+	for i := range keys {
+		keys[i] = i
+		items[i] = i
+	}
+	_, _, _ = cache.GetMany(keys)
+	cache.SetMany(items)
+	_, _, _ = cache.GetMany(keys)
+
+	expected := `
+# HELP hot_shadow_hit_total Estimated number of keys found in a shadow cache using another eviction algorithm
+# TYPE hot_shadow_hit_total counter
+hot_shadow_hit_total{algorithm="lfu",name="test-cache"} 50
+hot_shadow_hit_total{algorithm="lru",name="test-cache"} 50
+# HELP hot_shadow_lookup_total Number of keys read from the cache and replayed in a shadow cache using another eviction algorithm
+# TYPE hot_shadow_lookup_total counter
+hot_shadow_lookup_total{algorithm="lfu",name="test-cache"} 100
+hot_shadow_lookup_total{algorithm="lru",name="test-cache"} 100
+`
+	is.NoError(testutil.CollectAndCompare(cache, strings.NewReader(expected), "hot_shadow_hit_total", "hot_shadow_lookup_total"))
+
+	is.Equal(0.5, cache.Stats().Shadows[0].HitRatio())
+	is.Equal(0.0, ShadowStats{}.HitRatio())
+
+	is.Panics(func() {
+		_ = NewHotCache[int, int](LRU, 100).WithShadowAlgorithms()
+	})
+}
+
+func TestWithShadowAlgorithms_Baseline(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewHotCache[int, int](LFU, 100).
+		WithShadowAlgorithms(LRU, LFU, LRU).
+		Build()
+
+	stats := cache.Stats().Shadows
+	is.Len(stats, 2)
+	is.Equal(LFU, stats[0].Algorithm)
+	is.True(stats[0].Baseline)
+	is.Equal(LRU, stats[1].Algorithm)
+	is.False(stats[1].Baseline)
+}
+
+func TestWithShadowAlgorithms_Writes(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewHotCache[int, int](LRU, 100).
+		WithMissingSharedCache().
+		WithShadowAlgorithms(FIFO).
+		Build()
+	hits := func() []float64 {
+		stats := cache.Stats().Shadows
+		return []float64{stats[0].Hits, stats[1].Hits}
+	}
+
+	// writes and missing keys are inserted
+	cache.Set(1, 1)
+	cache.SetMany(map[int]int{2: 2})
+	cache.SetMissing(3)
+	_, _, _ = cache.GetMany([]int{1, 2, 3})
+	is.Equal([]float64{3, 3}, hits())
+
+	// deletions are removed
+	cache.Delete(1)
+	cache.DeleteMany([]int{2})
+	_, _, _ = cache.GetMany([]int{1, 2, 3})
+	is.Equal([]float64{4, 4}, hits())
+
+	cache.Purge()
+	_, _, _ = cache.Get(3)
+	is.Equal([]float64{4, 4}, hits())
+
+	// expired keys are misses
+	cache.SetWithTTL(4, 4, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, found, _ := cache.Get(4)
+	is.False(found)
+	is.Equal([]float64{4, 4}, hits())
+
+	// reads alone do not insert
+	_, _, _ = cache.Get(5)
+	_, _, _ = cache.Get(5)
+	is.Equal([]float64{4, 4}, hits())
+}
+
+func TestWithShadowAlgorithms_Janitor(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+	testWithTimeout(t, 2*time.Second)
+
+	cache := NewHotCache[int, int](LRU, 100).
+		WithTTL(50 * time.Millisecond).
+		WithJanitor().
+		WithShadowAlgorithms(FIFO).
+		Build()
+	defer cache.StopJanitor()
+
+	shadowLen := func() int {
+		cache.shadows.mu.Lock()
+		defer cache.shadows.mu.Unlock()
+		return cache.shadows.caches[0].Len() + cache.shadows.caches[1].Len()
+	}
+
+	cache.Set(1, 1)
+	is.Equal(2, shadowLen())
+
+	// keys expired by the janitor leave the shadow caches
+	is.Eventually(func() bool { return shadowLen() == 0 }, time.Second, 5*time.Millisecond)
+}
+
+func TestWithShadowAlgorithms_SampledCounters(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	capacity := 10 * shadowMaxCapacity
+	cache := NewHotCache[int, int](LRU, capacity).
+		WithShadowAlgorithms(FIFO).
+		WithPrometheusMetrics("test-cache").
+		Build()
+
+	// keys are sampled: a hot working set, then a scan of sampled keys missing the cache
+	sampled := []int{}
+	for key := 0; len(sampled) < 1_000; key++ {
+		if cache.shadows.sampled(key) {
+			sampled = append(sampled, key)
+		}
+	}
+	for _, key := range sampled[:100] {
+		cache.Set(key, key)
+	}
+
+	previous := cache.Stats().Shadows
+	for i := 0; i < 10; i++ {
+		for j := 0; j < 1_000; j++ {
+			_, _, _ = cache.Get(j % 100) // mostly not sampled
+		}
+		_, _, _ = cache.GetMany(sampled[100:])
+
+		current := cache.Stats().Shadows
+		for k := range current {
+			// exported as counters: they never go down
+			is.GreaterOrEqual(current[k].Lookups, previous[k].Lookups)
+			is.GreaterOrEqual(current[k].Hits, previous[k].Hits)
+		}
+		previous = current
+	}
+
+	is.Positive(previous[0].Hits)
+	is.Less(previous[0].HitRatio(), 1.0)
+	is.Equal(2, testutil.CollectAndCount(cache, "hot_shadow_hit_total"))
+}
@@ -0,0 +1,62 @@
+package sqlloader
+
+import "strconv"
+
+// Dialect describes how a database driver expects bind parameters.
+type Dialect struct {
+	// Name of the dialect, for debugging.
+	Name string
+	// Placeholder returns the bind parameter at the given 1-based position.
+	Placeholder func(position int) string
+	// MaxParams is the maximum number of bind parameters in a single statement.
+	MaxParams int
+}
+
+var (
+	// Postgres uses numbered `$1, $2, ...` parameters, with up to 65535 of them per statement.
+	Postgres = Dialect{
+		Name:        "postgres",
+		Placeholder: func(position int) string { return "$" + strconv.Itoa(position) },
+		MaxParams:   65535,
+	}
+
+	// MySQL uses `?` parameters, with up to 65535 of them per statement.
+	MySQL = Dialect{
+		Name:        "mysql",
+		Placeholder: func(int) string { return "?" },
+		MaxParams:   65535,
+	}
+
+	// SQLite uses `?` parameters. The limit is the historical SQLITE_MAX_VARIABLE_NUMBER default.
+	SQLite = Dialect{
+		Name:        "sqlite",
+		Placeholder: func(int) string { return "?" },
+		MaxParams:   999,
+	}
+
+	// SQLServer uses named `@p1, @p2, ...` parameters, with up to 2100 of them per statement.
+	SQLServer = Dialect{
+		Name:        "sqlserver",
+		Placeholder: func(position int) string { return "@p" + strconv.Itoa(position) },
+		MaxParams:   2100,
+	}
+
+	// Oracle uses numbered `:1, :2, ...` parameters. The limit is the size of an `IN` list.
+	Oracle = Dialect{
+		Name:        "oracle",
+		Placeholder: func(position int) string { return ":" + strconv.Itoa(position) },
+		MaxParams:   1000,
+	}
+)
+
+// placeholders returns the comma separated list of `count` parameters, starting after `offset` parameters.
+func (d Dialect) placeholders(offset int, count int) string {
+	buf := make([]byte, 0, count*4)
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		buf = append(buf, d.Placeholder(offset+i+1)...)
+	}
+	return string(buf)
+}
@@ -0,0 +1,20 @@
+package sqlloader
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialect_placeholders(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	is.Equal("$1, $2, $3", Postgres.placeholders(0, 3))
+	is.Equal("$3, $4", Postgres.placeholders(2, 2))
+	is.Equal("?, ?", MySQL.placeholders(1, 2))
+	is.Equal("?", SQLite.placeholders(0, 1))
+	is.Equal("@p2, @p3", SQLServer.placeholders(1, 2))
+	is.Equal(":1, :2", Oracle.placeholders(0, 2))
+	is.Equal("", Postgres.placeholders(0, 0))
+}
@@ -0,0 +1,118 @@
+package sqlloader
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// memDriver is an in-memory database/sql driver. Every query returns the rows of the table whose
+// id matches one of the bind parameters. Static arguments are recognized by their string type.
+type memDriver struct {
+	mu      sync.Mutex
+	table   map[int64]string
+	queries []string
+	args    [][]driver.NamedValue
+	delay   time.Duration
+	fail    error
+}
+
+var driverCount atomic.Int64
+
+func newMemDB(table map[int64]string) (*sql.DB, *memDriver) {
+	d := &memDriver{table: table}
+	name := fmt.Sprintf("sqlloader-mem-%d", driverCount.Add(1))
+	sql.Register(name, d)
+
+	db, err := sql.Open(name, "")
+	if err != nil {
+		panic(err)
+	}
+	return db, d
+}
+
+func (d *memDriver) Open(string) (driver.Conn, error) {
+	return &memConn{driver: d}, nil
+}
+
+type memConn struct {
+	driver *memDriver
+}
+
+var (
+	_ driver.Conn           = (*memConn)(nil)
+	_ driver.QueryerContext = (*memConn)(nil)
+)
+
+func (c *memConn) Prepare(string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *memConn) Close() error {
+	return nil
+}
+
+func (c *memConn) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *memConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	d := c.driver
+
+	d.mu.Lock()
+	d.queries = append(d.queries, query)
+	d.args = append(d.args, args)
+	delay, fail := d.delay, d.fail
+	d.mu.Unlock()
+
+	if fail != nil {
+		return nil, fail
+	}
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	rows := &memRows{}
+	for _, arg := range args {
+		id, ok := arg.Value.(int64)
+		if !ok {
+			continue
+		}
+		if name, ok := d.table[id]; ok {
+			rows.values = append(rows.values, []driver.Value{id, name})
+		}
+	}
+
+	return rows, nil
+}
+
+type memRows struct {
+	values [][]driver.Value
+}
+
+func (r *memRows) Columns() []string {
+	return []string{"id", "name"}
+}
+
+func (r *memRows) Close() error {
+	return nil
+}
+
+func (r *memRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
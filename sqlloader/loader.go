@@ -0,0 +1,210 @@
+package sqlloader
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/samber/hot"
+)
+
+// KeysPlaceholder is the token of the query template replaced by the list of bind parameters.
+const KeysPlaceholder = "{keys}"
+
+// ScanFunc reads the current row.
+type ScanFunc[V any] func(rows *sql.Rows) (V, error)
+
+// KeyFunc returns the key of a loaded value.
+type KeyFunc[K comparable, V any] func(value V) K
+
+// New creates a loader fetching values with a `SELECT ... WHERE id IN ({keys})` query.
+// The query template must contain KeysPlaceholder, which is expanded into one bind parameter
+// per key. Keys are split into chunks respecting the parameter limit of the dialect, and
+// keys without a matching row are reported as not found.
+//
+// Example:
+//
+//	loader := sqlloader.New(db, "SELECT id, name FROM users WHERE id IN ({keys})", scanUser, func(u *User) int { return u.ID }).
+//		WithDialect(sqlloader.Postgres).
+//		WithTimeout(time.Second).
+//		Build()
+func New[K comparable, V any](db *sql.DB, query string, scan ScanFunc[V], key KeyFunc[K, V]) LoaderConfig[K, V] {
+	return LoaderConfig[K, V]{
+		db:      db,
+		query:   query,
+		scan:    scan,
+		key:     key,
+		dialect: Postgres,
+	}
+}
+
+// LoaderConfig holds the configuration of the loader.
+type LoaderConfig[K comparable, V any] struct {
+	db        *sql.DB
+	query     string
+	scan      ScanFunc[V]
+	key       KeyFunc[K, V]
+	dialect   Dialect
+	args      []any
+	chunkSize int
+	timeout   time.Duration
+	ctx       context.Context
+}
+
+// WithDialect sets the bind parameter syntax. Default is Postgres.
+func (cfg LoaderConfig[K, V]) WithDialect(dialect Dialect) LoaderConfig[K, V] {
+	cfg.dialect = dialect
+	return cfg
+}
+
+// WithArgs sets static arguments bound before the keys, such as a tenant id.
+// With positional dialects (`?`), they must appear before KeysPlaceholder in the query.
+func (cfg LoaderConfig[K, V]) WithArgs(args ...any) LoaderConfig[K, V] {
+	cfg.args = args
+	return cfg
+}
+
+// WithChunkSize sets the maximum number of keys per query.
+// Default is the parameter limit of the dialect, minus the static arguments.
+func (cfg LoaderConfig[K, V]) WithChunkSize(size int) LoaderConfig[K, V] {
+	cfg.chunkSize = size
+	return cfg
+}
+
+// WithTimeout sets the maximum duration of each query. Default is no timeout.
+func (cfg LoaderConfig[K, V]) WithTimeout(timeout time.Duration) LoaderConfig[K, V] {
+	cfg.timeout = timeout
+	return cfg
+}
+
+// WithContext sets the parent context of the queries run by the hot.Loader returned by Build,
+// such as an application-wide context cancelled on shutdown. Default is context.Background().
+func (cfg LoaderConfig[K, V]) WithContext(ctx context.Context) LoaderConfig[K, V] {
+	cfg.ctx = ctx
+	return cfg
+}
+
+// Build returns a hot.Loader, to be registered with WithLoaders.
+func (cfg LoaderConfig[K, V]) Build() hot.Loader[K, V] {
+	load := cfg.BuildContext()
+
+	ctx := cfg.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return func(keys []K) (map[K]V, error) {
+		return load(ctx, keys)
+	}
+}
+
+// BuildContext returns a loading function accepting a context, for callers having a request-scoped context.
+func (cfg LoaderConfig[K, V]) BuildContext() func(ctx context.Context, keys []K) (map[K]V, error) {
+	if cfg.db == nil {
+		panic("db is required")
+	}
+	if !strings.Contains(cfg.query, KeysPlaceholder) {
+		panic("query must contain " + KeysPlaceholder)
+	}
+	if cfg.scan == nil {
+		panic("scan function is required")
+	}
+	if cfg.key == nil {
+		panic("key function is required")
+	}
+	if cfg.dialect.Placeholder == nil {
+		panic("dialect placeholder is required")
+	}
+
+	chunkSize := cfg.chunkSize
+	if chunkSize == 0 && cfg.dialect.MaxParams > 0 {
+		chunkSize = cfg.dialect.MaxParams - len(cfg.args)
+	}
+	if chunkSize <= 0 {
+		panic("chunk size must be a positive value")
+	}
+	if cfg.dialect.MaxParams > 0 && chunkSize+len(cfg.args) > cfg.dialect.MaxParams {
+		panic(fmt.Sprintf("chunk size exceeds the %d parameters allowed by %s", cfg.dialect.MaxParams, cfg.dialect.Name))
+	}
+	if cfg.timeout < 0 {
+		panic("timeout must be a positive value")
+	}
+
+	l := &loader[K, V]{cfg: cfg, chunkSize: chunkSize}
+	return l.load
+}
+
+type loader[K comparable, V any] struct {
+	cfg       LoaderConfig[K, V]
+	chunkSize int
+}
+
+func (l *loader[K, V]) load(ctx context.Context, keys []K) (map[K]V, error) {
+	keys = uniqueKeys(keys)
+	results := make(map[K]V, len(keys))
+
+	for start := 0; start < len(keys); start += l.chunkSize {
+		end := min(start+l.chunkSize, len(keys))
+		if err := l.loadChunk(ctx, keys[start:end], results); err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
+
+func (l *loader[K, V]) loadChunk(ctx context.Context, keys []K, results map[K]V) error {
+	if l.cfg.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.cfg.timeout)
+		defer cancel()
+	}
+
+	query := strings.Replace(l.cfg.query, KeysPlaceholder, l.cfg.dialect.placeholders(len(l.cfg.args), len(keys)), 1)
+
+	args := make([]any, 0, len(l.cfg.args)+len(keys))
+	args = append(args, l.cfg.args...)
+	for _, key := range keys {
+		args = append(args, key)
+	}
+
+	rows, err := l.cfg.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	requested := make(map[K]struct{}, len(keys))
+	for _, key := range keys {
+		requested[key] = struct{}{}
+	}
+
+	for rows.Next() {
+		value, err := l.cfg.scan(rows)
+		if err != nil {
+			return err
+		}
+
+		// Rows not matching a requested key (eg: type conversion by the database) are ignored.
+		key := l.cfg.key(value)
+		if _, ok := requested[key]; ok {
+			results[key] = value
+		}
+	}
+
+	return rows.Err()
+}
+
+func uniqueKeys[K comparable](keys []K) []K {
+	seen := make(map[K]struct{}, len(keys))
+	unique := make([]K, 0, len(keys))
+	for _, key := range keys {
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			unique = append(unique, key)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,144 @@
+package sqlloader
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samber/hot"
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	ID   int
+	Name string
+}
+
+func scanUser(rows *sql.Rows) (user, error) {
+	var u user
+	err := rows.Scan(&u.ID, &u.Name)
+	return u, err
+}
+
+func userID(u user) int {
+	return u.ID
+}
+
+func TestLoader(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	db, d := newMemDB(map[int64]string{1: "alice", 2: "bob", 3: "carol", 4: "dave", 5: "eve"})
+	defer db.Close()
+
+	loader := New(db, "SELECT id, name FROM users WHERE tenant = $1 AND id IN ({keys})", scanUser, userID).
+		WithArgs("acme").
+		WithChunkSize(2).
+		Build()
+
+	found, err := loader([]int{1, 2, 3, 3, 5, 42})
+	is.NoError(err)
+	is.Equal(map[int]user{1: {1, "alice"}, 2: {2, "bob"}, 3: {3, "carol"}, 5: {5, "eve"}}, found)
+
+	is.Equal([]string{
+		"SELECT id, name FROM users WHERE tenant = $1 AND id IN ($2, $3)",
+		"SELECT id, name FROM users WHERE tenant = $1 AND id IN ($2, $3)",
+		"SELECT id, name FROM users WHERE tenant = $1 AND id IN ($2)",
+	}, d.queries)
+	is.Len(d.args[0], 3)
+	is.Equal("acme", d.args[0][0].Value)
+
+	found, err = loader(nil)
+	is.NoError(err)
+	is.Empty(found)
+	is.Len(d.queries, 3)
+}
+
+func TestLoader_withHotCache(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	db, d := newMemDB(map[int64]string{1: "alice", 2: "bob"})
+	defer db.Close()
+
+	cache := hot.NewHotCache[int, user](hot.LRU, 10).
+		WithMissingSharedCache().
+		WithLoaders(New(db, "SELECT id, name FROM users WHERE id IN ({keys})", scanUser, userID).WithDialect(MySQL).Build()).
+		Build()
+
+	found, missing, err := cache.GetMany([]int{1, 2, 3})
+	is.NoError(err)
+	is.Equal(map[int]user{1: {1, "alice"}, 2: {2, "bob"}}, found)
+	is.Equal([]int{3}, missing)
+
+	_, _, err = cache.GetMany([]int{1, 2, 3})
+	is.NoError(err)
+	is.Equal([]string{"SELECT id, name FROM users WHERE id IN (?, ?, ?)"}, d.queries)
+}
+
+func TestLoader_errors(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	db, d := newMemDB(map[int64]string{1: "alice"})
+	defer db.Close()
+
+	d.fail = errors.New("connection refused")
+	_, err := New(db, "SELECT id, name FROM users WHERE id IN ({keys})", scanUser, userID).Build()([]int{1})
+	is.EqualError(err, "connection refused")
+	d.fail = nil
+
+	scanErr := errors.New("bad row")
+	_, err = New(db, "SELECT id, name FROM users WHERE id IN ({keys})", func(*sql.Rows) (user, error) { return user{}, scanErr }, userID).Build()([]int{1})
+	is.ErrorIs(err, scanErr)
+}
+
+func TestLoader_context(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	db, d := newMemDB(map[int64]string{1: "alice"})
+	defer db.Close()
+	d.delay = 100 * time.Millisecond
+
+	cfg := New(db, "SELECT id, name FROM users WHERE id IN ({keys})", scanUser, userID)
+
+	_, err := cfg.WithTimeout(5 * time.Millisecond).Build()([]int{1})
+	is.ErrorIs(err, context.DeadlineExceeded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = cfg.WithContext(ctx).Build()([]int{1})
+	is.ErrorIs(err, context.Canceled)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	_, err = cfg.BuildContext()(ctx, []int{1})
+	is.ErrorIs(err, context.DeadlineExceeded)
+
+	found, err := cfg.Build()([]int{1})
+	is.NoError(err)
+	is.Equal(map[int]user{1: {1, "alice"}}, found)
+}
+
+func TestLoader_build(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	db, _ := newMemDB(nil)
+	defer db.Close()
+
+	query := "SELECT id, name FROM users WHERE id IN ({keys})"
+
+	is.Panics(func() { New[int, user](nil, query, scanUser, userID).Build() })
+	is.Panics(func() { New(db, "SELECT id, name FROM users", scanUser, userID).Build() })
+	is.Panics(func() { New(db, query, nil, userID).Build() })
+	is.Panics(func() { New[int, user](db, query, scanUser, nil).Build() })
+	is.Panics(func() { New(db, query, scanUser, userID).WithDialect(Dialect{}).Build() })
+	is.Panics(func() { New(db, query, scanUser, userID).WithChunkSize(-1).Build() })
+	is.Panics(func() { New(db, query, scanUser, userID).WithDialect(Oracle).WithChunkSize(1001).Build() })
+	is.Panics(func() { New(db, query, scanUser, userID).WithTimeout(-time.Second).Build() })
+	is.NotPanics(func() { New(db, query, scanUser, userID).WithDialect(Oracle).WithChunkSize(1000).Build() })
+}
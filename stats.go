@@ -0,0 +1,299 @@
+package hot
+
+import (
+	"expvar"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/samber/hot/internal"
+	"github.com/samber/hot/pkg/base"
+)
+
+const (
+	// statsRateWindow is the duration over which Stats computes rates.
+	statsRateWindow = time.Minute
+	// statsRateSamples is the number of snapshots kept to compute rates over the window.
+	statsRateSamples = 12
+)
+
+// Stats is a snapshot of the cache activity since Build.
+// Counters are cumulative and maintained with atomic operations, independently of Prometheus.
+// They are always 0 unless WithStats is enabled.
+type Stats struct {
+	// Hits is the number of keys found in the cache, including keys cached as missing and stale values.
+	Hits int64 `json:"hits"`
+	// MissingHits is the number of hits on keys cached as missing.
+	MissingHits int64 `json:"missing_hits"`
+	// StaleHits is the number of hits on stale values, served while being revalidated.
+	StaleHits int64 `json:"stale_hits"`
+	// Misses is the number of keys not found in the cache.
+	Misses int64 `json:"misses"`
+
+	// Loads is the number of calls to the loader chain, including revalidations.
+	Loads int64 `json:"loads"`
+	// LoadErrors is the number of calls to the loader chain that returned an error.
+	LoadErrors int64 `json:"load_errors"`
+	// LoadDuration is the cumulative time spent in the loader chain.
+	LoadDuration time.Duration `json:"load_duration"`
+
+	// Insertions is the number of entries written to the cache, including keys cached as missing.
+	Insertions int64 `json:"insertions"`
+	// Evictions is the number of entries evicted by the cache, by reason.
+	Evictions map[base.EvictionReason]int64 `json:"evictions"`
+	// PrematureEvictions is the number of misses on keys recently evicted for capacity.
+	// Always 0 unless WithEvictionRegretTracking is enabled.
+	PrematureEvictions int64 `json:"premature_evictions"`
+	// InvalidationPublishErrors is the number of invalidations not published to every other instance,
+	// such as dropped by a full queue. Always 0 unless WithInvalidator is enabled.
+	InvalidationPublishErrors int64 `json:"invalidation_publish_errors"`
+
+	// Length is the number of entries currently cached, including keys cached as missing.
+	Length int `json:"length"`
+	// Bytes is the estimated memory used by the cached entries.
+	Bytes int64 `json:"bytes"`
+
+	// Rates are computed over the last minute.
+	Rates StatsRates `json:"rates"`
+
+	// Shadows replay the reads with other eviction algorithms (WithShadowAlgorithms), to compare their hit ratio.
+	Shadows []ShadowStats `json:"shadows,omitempty"`
+}
+
+// StatsRates holds per-second rates over a sliding window.
+type StatsRates struct {
+	// Window is the duration actually covered by the rates. It is shorter than a minute
+	// until Stats has been called over a whole minute, and zero on the first call.
+	Window time.Duration `json:"window"`
+
+	Hits       float64 `json:"hits"`
+	Misses     float64 `json:"misses"`
+	Loads      float64 `json:"loads"`
+	LoadErrors float64 `json:"load_errors"`
+	Insertions float64 `json:"insertions"`
+	Evictions  float64 `json:"evictions"`
+	// HitRatio is the ratio of hits over lookups during the window.
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+// HitRatio returns the ratio of hits over lookups since Build, or 0 when nothing was looked up.
+func (s Stats) HitRatio() float64 {
+	return ratio(s.Hits, s.Hits+s.Misses)
+}
+
+// AvgLoadLatency returns the mean duration of a call to the loader chain.
+func (s Stats) AvgLoadLatency() time.Duration {
+	if s.Loads == 0 {
+		return 0
+	}
+	return s.LoadDuration / time.Duration(s.Loads)
+}
+
+// Stats returns a snapshot of the cache activity. Counters and rates are only maintained with WithStats,
+// while Length, Bytes and Shadows are always reported.
+// Computing Bytes iterates over the entries, so this method should not be called in a hot path.
+func (c *HotCache[K, V]) Stats() Stats {
+	stats := c.stats.snapshot()
+
+	stats.Length = c.Len()
+	stats.Bytes = c.cache.SizeBytes()
+	if c.missingCache != nil {
+		stats.Bytes += c.missingCache.SizeBytes()
+	}
+
+	stats.Rates = c.stats.rates(stats, internal.NowNano())
+
+	stats.InvalidationPublishErrors = c.invalidationPublishErrors()
+
+	if c.shadows != nil {
+		stats.Shadows = c.shadows.stats()
+	}
+
+	return stats
+}
+
+// PublishExpvar exposes Stats as an expvar variable with the given name, served by the
+// /debug/vars endpoint. Like expvar.Publish, it panics if the name is already registered.
+func (c *HotCache[K, V]) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return c.Stats()
+	}))
+}
+
+// statsCounters holds the counters behind Stats. A nil *statsCounters counts nothing.
+type statsCounters struct {
+	hits         atomic.Int64
+	missingHits  atomic.Int64
+	staleHits    atomic.Int64
+	misses       atomic.Int64
+	loads        atomic.Int64
+	loadErrors   atomic.Int64
+	loadDuration atomic.Int64
+	insertions   atomic.Int64
+	evictions    [4]atomic.Int64 // indexed like base.EvictionReasons
+
+	prematureEvictions atomic.Int64
+
+	mu      sync.Mutex
+	samples []statsSample // snapshots used to compute rates, oldest first
+}
+
+type statsSample struct {
+	nowNano int64
+	stats   Stats
+}
+
+func newStatsCounters() *statsCounters {
+	return &statsCounters{}
+}
+
+func (s *statsCounters) addHits(hits int64, missingHits int64, staleHits int64) {
+	if s == nil {
+		return
+	}
+	if hits > 0 {
+		s.hits.Add(hits)
+	}
+	if missingHits > 0 {
+		s.missingHits.Add(missingHits)
+	}
+	if staleHits > 0 {
+		s.staleHits.Add(staleHits)
+	}
+}
+
+func (s *statsCounters) addMisses(count int64) {
+	if s == nil || count == 0 {
+		return
+	}
+	s.misses.Add(count)
+}
+
+func (s *statsCounters) addLoad(duration time.Duration, err error) {
+	if s == nil {
+		return
+	}
+	s.loads.Add(1)
+	s.loadDuration.Add(int64(duration))
+	if err != nil {
+		s.loadErrors.Add(1)
+	}
+}
+
+func (s *statsCounters) addInsertions(count int64) {
+	if s == nil || count == 0 {
+		return
+	}
+	s.insertions.Add(count)
+}
+
+func (s *statsCounters) addEviction(reason base.EvictionReason) {
+	if s == nil {
+		return
+	}
+	for i, r := range base.EvictionReasons {
+		if r == reason {
+			s.evictions[i].Add(1)
+			return
+		}
+	}
+}
+
+func (s *statsCounters) addPrematureEvictions(count int64) {
+	if s == nil || count == 0 {
+		return
+	}
+	s.prematureEvictions.Add(count)
+}
+
+// evictionCallbackWithStats wraps the user eviction callback to count evictions.
+func evictionCallbackWithStats[K comparable, V any](s *statsCounters, onEviction base.EvictionCallback[K, V]) base.EvictionCallback[K, V] {
+	return func(reason base.EvictionReason, key K, value V) {
+		s.addEviction(reason)
+		if onEviction != nil {
+			onEviction(reason, key, value)
+		}
+	}
+}
+
+func (s *statsCounters) snapshot() Stats {
+	stats := Stats{Evictions: map[base.EvictionReason]int64{}}
+	if s == nil {
+		return stats
+	}
+
+	stats.Hits = s.hits.Load()
+	stats.MissingHits = s.missingHits.Load()
+	stats.StaleHits = s.staleHits.Load()
+	stats.Misses = s.misses.Load()
+	stats.Loads = s.loads.Load()
+	stats.LoadErrors = s.loadErrors.Load()
+	stats.LoadDuration = time.Duration(s.loadDuration.Load())
+	stats.Insertions = s.insertions.Load()
+	for i, reason := range base.EvictionReasons {
+		stats.Evictions[reason] = s.evictions[i].Load()
+	}
+	stats.PrematureEvictions = s.prematureEvictions.Load()
+
+	return stats
+}
+
+// rates compares the current snapshot to the oldest snapshot of the window.
+// A snapshot is recorded at most every window/statsRateSamples, so that memory is bounded.
+func (s *statsCounters) rates(current Stats, nowNano int64) StatsRates {
+	if s == nil {
+		return StatsRates{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	windowNano := statsRateWindow.Nanoseconds()
+
+	// drop the snapshots older than the window, but keep at least one
+	for len(s.samples) > 1 && nowNano-s.samples[1].nowNano >= windowNano {
+		s.samples = s.samples[1:]
+	}
+
+	if len(s.samples) == 0 || nowNano-s.samples[len(s.samples)-1].nowNano >= windowNano/statsRateSamples {
+		s.samples = append(s.samples, statsSample{nowNano: nowNano, stats: current})
+	}
+
+	oldest := s.samples[0]
+	elapsed := time.Duration(nowNano - oldest.nowNano)
+	if elapsed <= 0 {
+		return StatsRates{}
+	}
+
+	seconds := elapsed.Seconds()
+	perSecond := func(current int64, previous int64) float64 {
+		return float64(current-previous) / seconds
+	}
+
+	var evictions, previousEvictions int64
+	for _, reason := range base.EvictionReasons {
+		evictions += current.Evictions[reason]
+		previousEvictions += oldest.stats.Evictions[reason]
+	}
+
+	hits := current.Hits - oldest.stats.Hits
+	misses := current.Misses - oldest.stats.Misses
+
+	return StatsRates{
+		Window:     elapsed,
+		Hits:       perSecond(current.Hits, oldest.stats.Hits),
+		Misses:     perSecond(current.Misses, oldest.stats.Misses),
+		Loads:      perSecond(current.Loads, oldest.stats.Loads),
+		LoadErrors: perSecond(current.LoadErrors, oldest.stats.LoadErrors),
+		Insertions: perSecond(current.Insertions, oldest.stats.Insertions),
+		Evictions:  perSecond(evictions, previousEvictions),
+		HitRatio:   ratio(hits, hits+misses),
+	}
+}
+
+func ratio(a int64, b int64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
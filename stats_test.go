@@ -0,0 +1,200 @@
+package hot
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samber/hot/pkg/base"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHotCache_Stats(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var fail atomic.Bool
+	evicted := []string{}
+	cache := NewHotCache[string, int](LRU, 3).
+		WithStats().
+		WithMissingSharedCache().
+		WithEvictionCallback(func(reason base.EvictionReason, key string, value int) {
+			evicted = append(evicted, key)
+		}).
+		WithLoaders(func(keys []string) (map[string]int, error) {
+			if fail.Load() {
+				return nil, errors.New("boom")
+			}
+			return map[string]int{"a": 1, "b": 2}, nil
+		}).
+		Build()
+
+	stats := cache.Stats()
+	is.Zero(stats.Hits)
+	is.Zero(stats.Misses)
+	is.Zero(stats.HitRatio())
+	is.Zero(stats.AvgLoadLatency())
+	is.Len(stats.Evictions, len(base.EvictionReasons))
+
+	// 1 miss + 1 load + 2 insertions (the loader returns a and b)
+	_, _, _ = cache.Get("a")
+	// 1 hit
+	_, _, _ = cache.Get("a")
+	// 2 hits (a, b) + 1 miss (c) + 1 load + 3 insertions (a, b, c missing)
+	_, _, _ = cache.GetMany([]string{"a", "b", "c"})
+	// 1 missing hit
+	_, _, _ = cache.Get("c")
+	// 1 miss + 1 failed load
+	fail.Store(true)
+	_, _, err := cache.Get("d")
+	is.Error(err)
+	// 1 insertion + 1 eviction for capacity
+	cache.Set("e", 5)
+
+	stats = cache.Stats()
+	is.Equal(int64(4), stats.Hits)
+	is.Equal(int64(1), stats.MissingHits)
+	is.Equal(int64(0), stats.StaleHits)
+	is.Equal(int64(3), stats.Misses)
+	is.Equal(int64(3), stats.Loads)
+	is.Equal(int64(1), stats.LoadErrors)
+	is.Equal(int64(6), stats.Insertions)
+	is.Equal(int64(1), stats.Evictions[base.EvictionReasonCapacity])
+	is.Len(evicted, 1)
+	is.Equal(3, stats.Length)
+	is.Positive(stats.Bytes)
+	is.InDelta(4.0/7, stats.HitRatio(), 0.001)
+	is.Equal(stats.LoadDuration/3, stats.AvgLoadLatency())
+}
+
+func TestHotCache_StatsStaleAndTTL(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewHotCache[string, int](LRU, 10).
+		WithStats().
+		WithTTL(10*time.Millisecond).
+		WithRevalidation(20*time.Millisecond, func(keys []string) (map[string]int, error) {
+			return map[string]int{"a": 2}, nil
+		}).
+		Build()
+
+	cache.Set("a", 1)
+	cache.Set("b", 1)
+	time.Sleep(15 * time.Millisecond)
+
+	_, _, _ = cache.Get("a")
+	is.Eventually(func() bool {
+		return cache.Stats().Loads == 1
+	}, time.Second, time.Millisecond)
+
+	time.Sleep(30 * time.Millisecond)
+	_, found, _ := cache.Get("b")
+	is.False(found)
+
+	stats := cache.Stats()
+	is.Equal(int64(1), stats.StaleHits)
+	is.Equal(int64(1), stats.Hits)
+	is.Equal(int64(1), stats.Misses)
+	is.Equal(int64(1), stats.Evictions[base.EvictionReasonTTL])
+}
+
+func TestStatsCounters_rates(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	s := newStatsCounters()
+	second := time.Second.Nanoseconds()
+
+	// first call: no window yet
+	is.Equal(StatsRates{}, s.rates(s.snapshot(), 0))
+
+	s.addHits(30, 0, 0)
+	s.addMisses(10)
+	s.addInsertions(10)
+	s.addEviction(base.EvictionReasonCapacity)
+	s.addLoad(time.Millisecond, nil)
+	rates := s.rates(s.snapshot(), 10*second)
+	is.Equal(10*time.Second, rates.Window)
+	is.InDelta(3, rates.Hits, 0.001)
+	is.InDelta(1, rates.Misses, 0.001)
+	is.InDelta(1, rates.Insertions, 0.001)
+	is.InDelta(0.1, rates.Evictions, 0.001)
+	is.InDelta(0.1, rates.Loads, 0.001)
+	is.InDelta(0.75, rates.HitRatio, 0.001)
+
+	// the window slides: the first sample is dropped after a minute
+	s.addHits(60, 0, 0)
+	rates = s.rates(s.snapshot(), 70*second)
+	is.Equal(60*time.Second, rates.Window)
+	is.InDelta(1, rates.Hits, 0.001)
+	is.InDelta(1, rates.HitRatio, 0.001)
+
+	// samples are bounded
+	for i := int64(0); i < 1000; i++ {
+		s.rates(s.snapshot(), 70*second+i*second/10)
+	}
+	is.LessOrEqual(len(s.samples), statsRateSamples+1)
+
+	// nil counters
+	var empty *statsCounters
+	empty.addHits(1, 1, 1)
+	empty.addMisses(1)
+	empty.addLoad(time.Second, nil)
+	empty.addInsertions(1)
+	empty.addEviction(base.EvictionReasonTTL)
+	is.Zero(empty.snapshot().Hits)
+	is.Equal(StatsRates{}, empty.rates(Stats{}, second))
+}
+
+func TestHotCache_StatsDisabled(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewHotCache[string, int](LRU, 1).Build()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	_, _, _ = cache.Get("a")
+	_, _, _ = cache.Get("b")
+
+	stats := cache.Stats()
+	is.Zero(stats.Hits)
+	is.Zero(stats.Misses)
+	is.Zero(stats.Insertions)
+	is.Zero(stats.Evictions[base.EvictionReasonCapacity])
+	is.Equal(StatsRates{}, stats.Rates)
+	is.Equal(1, stats.Length)
+
+	// evictions are not observed: the disk tier does not need to decode expired values
+	is.Nil(cache.stats)
+	is.Nil(cache.onEviction)
+}
+
+var expvarCounter atomic.Int64
+
+func TestHotCache_PublishExpvar(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	// expvar names are global to the process, and tests might run several times
+	name := fmt.Sprintf("hot_test_publish_expvar_%d", expvarCounter.Add(1))
+
+	cache := NewHotCache[string, int](LRU, 10).WithStats().Build()
+	cache.PublishExpvar(name)
+	_, _, _ = cache.Get("a")
+
+	v := expvar.Get(name)
+	is.NotNil(v)
+
+	var stats Stats
+	is.NoError(json.Unmarshal([]byte(v.String()), &stats))
+	is.Equal(int64(1), stats.Misses)
+
+	is.Panics(func() {
+		cache.PublishExpvar(name)
+	})
+}
@@ -0,0 +1,158 @@
+package hot
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// tracerName is the instrumentation scope of the spans.
+const tracerName = "github.com/samber/hot"
+
+// Span names and attribute keys.
+const (
+	spanNameLoad       = "hot.load"
+	spanNameLoader     = "hot.loader"
+	spanNameRevalidate = "hot.revalidate"
+
+	eventNameHit  = "hot.hit"
+	eventNameMiss = "hot.miss"
+
+	attrCacheName        = attribute.Key("hot.cache.name")
+	attrKeysCount        = attribute.Key("hot.keys.count")
+	attrFoundCount       = attribute.Key("hot.keys.found")
+	attrMissingCount     = attribute.Key("hot.keys.missing")
+	attrSharedCount      = attribute.Key("hot.keys.shared")
+	attrStaleCount       = attribute.Key("hot.keys.stale")
+	attrMissingHitsCount = attribute.Key("hot.keys.missing_hits")
+	attrLoaderIndex      = attribute.Key("hot.loader.index")
+)
+
+// tracing holds the tracer of a cache. A nil tracing records nothing.
+type tracing struct {
+	tracer trace.Tracer
+	attrs  []attribute.KeyValue
+}
+
+// newTracing creates the tracer of a cache. The cache name is added to every span, when not empty.
+func newTracing(tracerProvider trace.TracerProvider, cacheName string) *tracing {
+	attrs := []attribute.KeyValue{}
+	if cacheName != "" {
+		attrs = append(attrs, attrCacheName.String(cacheName))
+	}
+
+	return &tracing{
+		tracer: tracerProvider.Tracer(tracerName),
+		attrs:  attrs,
+	}
+}
+
+// attributes returns the attributes of the cache, followed by the given ones.
+func (t *tracing) attributes(attrs ...attribute.KeyValue) []attribute.KeyValue {
+	return append(append(make([]attribute.KeyValue, 0, len(t.attrs)+len(attrs)), t.attrs...), attrs...)
+}
+
+// recordLookup adds hit and miss events to the span of the caller, if any.
+func (t *tracing) recordLookup(ctx context.Context, hits, missingHits, staleHits, misses int) {
+	if t == nil {
+		return
+	}
+
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	if hits > 0 {
+		span.AddEvent(eventNameHit, trace.WithAttributes(
+			t.attributes(
+				attrKeysCount.Int(hits),
+				attrMissingHitsCount.Int(missingHits),
+				attrStaleCount.Int(staleHits),
+			)...,
+		))
+	}
+	if misses > 0 {
+		span.AddEvent(eventNameMiss, trace.WithAttributes(t.attributes(attrKeysCount.Int(misses))...))
+	}
+}
+
+// startLoad starts the span of a load of keys, child of the span of the caller.
+func (t *tracing) startLoad(ctx context.Context, keys int) (context.Context, trace.Span) {
+	if t == nil {
+		return ctx, noop.Span{}
+	}
+
+	return t.tracer.Start(
+		ctx,
+		spanNameLoad,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(t.attributes(attrKeysCount.Int(keys))...),
+	)
+}
+
+// endLoad records the outcome of a load and ends its span.
+func (t *tracing) endLoad(span trace.Span, found, missing, shared int, err error) {
+	if t == nil {
+		return
+	}
+
+	span.SetAttributes(
+		attrFoundCount.Int(found),
+		attrMissingCount.Int(missing),
+		attrSharedCount.Int(shared),
+	)
+	endSpan(span, err)
+}
+
+// startRevalidation starts the root span of a background revalidation, linked to the span of the caller:
+// the revalidation outlives the request that triggered it.
+func (t *tracing) startRevalidation(ctx context.Context, keys int) (context.Context, trace.Span) {
+	if t == nil {
+		return context.Background(), noop.Span{}
+	}
+
+	opts := []trace.SpanStartOption{
+		trace.WithNewRoot(),
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(t.attributes(attrKeysCount.Int(keys))...),
+	}
+	if link := trace.LinkFromContext(ctx); link.SpanContext.IsValid() {
+		opts = append(opts, trace.WithLinks(link))
+	}
+
+	return t.tracer.Start(context.Background(), spanNameRevalidate, opts...)
+}
+
+// loaderObserverWithTracing returns an observer starting a span around each loader of a chain. Nil when tracing is disabled.
+func loaderObserverWithTracing[K comparable](ctx context.Context, t *tracing) loaderObserver[K] {
+	if t == nil {
+		return nil
+	}
+
+	return func(index int, keys []K) func(found int, err error) {
+		_, span := t.tracer.Start(
+			ctx,
+			spanNameLoader,
+			trace.WithSpanKind(trace.SpanKindInternal),
+			trace.WithAttributes(t.attributes(attrLoaderIndex.Int(index), attrKeysCount.Int(len(keys)))...),
+		)
+
+		return func(found int, err error) {
+			span.SetAttributes(attrFoundCount.Int(found))
+			endSpan(span, err)
+		}
+	}
+}
+
+// endSpan records the error, if any, and ends the span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
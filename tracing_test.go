@@ -0,0 +1,235 @@
+package hot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTestTracerProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	return provider, exporter
+}
+
+func spanAttribute(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, attr := range span.Attributes {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func findSpans(spans tracetest.SpanStubs, name string) []tracetest.SpanStub {
+	found := []tracetest.SpanStub{}
+	for _, span := range spans {
+		if span.Name == name {
+			found = append(found, span)
+		}
+	}
+	return found
+}
+
+func TestWithTracing(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	provider, _ := newTestTracerProvider()
+	opts := NewHotCache[string, int](LRU, 42).WithTracing(provider)
+	is.Equal(provider, opts.tracerProvider)
+
+	is.Panics(func() {
+		_ = opts.WithTracing(nil)
+	})
+
+	cache := NewHotCache[string, int](LRU, 42).Build()
+	is.Nil(cache.tracing)
+}
+
+func TestTracing_load(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	provider, exporter := newTestTracerProvider()
+	cache := NewHotCache[string, int](LRU, 42).
+		WithMissingSharedCache().
+		WithPrometheusMetrics("test-cache").
+		WithTracing(provider).
+		WithLoaders(
+			func(keys []string) (map[string]int, error) {
+				return map[string]int{"a": 1}, nil
+			},
+			func(keys []string) (map[string]int, error) {
+				return map[string]int{"b": 2}, nil
+			},
+		).
+		Build()
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	values, missing, err := cache.GetManyContext(ctx, []string{"a", "b", "c"})
+	is.NoError(err)
+	is.Equal(map[string]int{"a": 1, "b": 2}, values)
+	is.Equal([]string{"c"}, missing)
+
+	_, _, err = cache.GetManyContext(ctx, []string{"a", "c"})
+	is.NoError(err)
+	parent.End()
+
+	spans := exporter.GetSpans()
+
+	loads := findSpans(spans, spanNameLoad)
+	is.Len(loads, 1)
+	is.Equal(parent.SpanContext().SpanID(), loads[0].Parent.SpanID())
+	is.Equal("test-cache", spanAttribute(loads[0], attrCacheName).AsString())
+	is.Equal(int64(3), spanAttribute(loads[0], attrKeysCount).AsInt64())
+	is.Equal(int64(2), spanAttribute(loads[0], attrFoundCount).AsInt64())
+	is.Equal(int64(1), spanAttribute(loads[0], attrMissingCount).AsInt64())
+	is.Equal(int64(0), spanAttribute(loads[0], attrSharedCount).AsInt64())
+
+	loaders := findSpans(spans, spanNameLoader)
+	is.Len(loaders, 2)
+	for i, span := range loaders {
+		is.Equal(loads[0].SpanContext.SpanID(), span.Parent.SpanID())
+		is.Equal(int64(i), spanAttribute(span, attrLoaderIndex).AsInt64())
+		is.Equal(int64(1), spanAttribute(span, attrFoundCount).AsInt64())
+	}
+	is.Equal(int64(3), spanAttribute(loaders[0], attrKeysCount).AsInt64())
+	is.Equal(int64(2), spanAttribute(loaders[1], attrKeysCount).AsInt64())
+
+	parents := findSpans(spans, "parent")
+	is.Len(parents, 1)
+	events := parents[0].Events
+	is.Len(events, 2)
+	is.Equal(eventNameMiss, events[0].Name)
+	is.Contains(events[0].Attributes, attrKeysCount.Int(3))
+	is.Equal(eventNameHit, events[1].Name)
+	is.Contains(events[1].Attributes, attrKeysCount.Int(2))
+	is.Contains(events[1].Attributes, attrMissingHitsCount.Int(1))
+}
+
+func TestTracing_hit(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	provider, exporter := newTestTracerProvider()
+	cache := NewHotCache[string, int](LRU, 42).
+		WithMissingSharedCache().
+		WithTracing(provider).
+		Build()
+	cache.Set("a", 1)
+	cache.SetMissing("b")
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	v, ok, err := cache.GetContext(ctx, "a")
+	is.NoError(err)
+	is.True(ok)
+	is.Equal(1, v)
+	_, ok, err = cache.GetContext(ctx, "b")
+	is.NoError(err)
+	is.False(ok)
+	parent.End()
+
+	spans := exporter.GetSpans()
+	is.Empty(findSpans(spans, spanNameLoad))
+
+	parents := findSpans(spans, "parent")
+	is.Len(parents, 1)
+	is.Len(parents[0].Events, 2)
+	is.Equal(eventNameHit, parents[0].Events[0].Name)
+	is.Equal(eventNameHit, parents[0].Events[1].Name)
+	is.Contains(parents[0].Events[1].Attributes, attrMissingHitsCount.Int(1))
+}
+
+func TestTracing_error(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	provider, exporter := newTestTracerProvider()
+	cache := NewHotCache[string, int](LRU, 42).
+		WithTracing(provider).
+		WithLoaders(func(keys []string) (map[string]int, error) {
+			return nil, errors.New("boom")
+		}).
+		Build()
+
+	_, _, err := cache.Get("a")
+	is.EqualError(err, "boom")
+
+	spans := exporter.GetSpans()
+	for _, name := range []string{spanNameLoad, spanNameLoader} {
+		found := findSpans(spans, name)
+		is.Len(found, 1)
+		is.Equal(codes.Error, found[0].Status.Code)
+		is.Equal("boom", found[0].Status.Description)
+		is.Len(found[0].Events, 1)
+		is.Equal("exception", found[0].Events[0].Name)
+	}
+}
+
+func TestTracing_revalidate(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	provider, exporter := newTestTracerProvider()
+	cache := NewHotCache[string, int](LRU, 42).
+		WithTTL(time.Millisecond).
+		WithRevalidation(time.Hour).
+		WithTracing(provider).
+		WithLoaders(func(keys []string) (map[string]int, error) {
+			return map[string]int{"a": 1}, nil
+		}).
+		Build()
+	cache.Set("a", 0)
+	time.Sleep(5 * time.Millisecond)
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	v, ok, err := cache.GetContext(ctx, "a")
+	is.NoError(err)
+	is.True(ok)
+	is.Equal(0, v)
+	parent.End()
+
+	is.Eventually(func() bool {
+		return len(findSpans(exporter.GetSpans(), spanNameRevalidate)) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	spans := exporter.GetSpans()
+	revalidations := findSpans(spans, spanNameRevalidate)
+	is.False(revalidations[0].Parent.IsValid())
+	is.NotEqual(parent.SpanContext().TraceID(), revalidations[0].SpanContext.TraceID())
+	is.Len(revalidations[0].Links, 1)
+	is.Equal(parent.SpanContext().SpanID(), revalidations[0].Links[0].SpanContext.SpanID())
+
+	loads := findSpans(spans, spanNameLoad)
+	is.Len(loads, 1)
+	is.Equal(revalidations[0].SpanContext.SpanID(), loads[0].Parent.SpanID())
+
+	parents := findSpans(spans, "parent")
+	is.Len(parents, 1)
+	is.Len(parents[0].Events, 1)
+	is.Contains(parents[0].Events[0].Attributes, attrStaleCount.Int(1))
+}
+
+func TestTracing_disabled(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewHotCache[string, int](LRU, 42).
+		WithLoaders(func(keys []string) (map[string]int, error) {
+			return map[string]int{"a": 1}, nil
+		}).
+		Build()
+
+	v, ok, err := cache.GetContext(context.Background(), "a")
+	is.NoError(err)
+	is.True(ok)
+	is.Equal(1, v)
+}
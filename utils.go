@@ -22,3 +22,11 @@ func assertValue(ok bool, msg string) {
 		panic(msg)
 	}
 }
+
+// boolToInt64 returns 1 when b is true, 0 otherwise.
+func boolToInt64(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
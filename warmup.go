@@ -0,0 +1,334 @@
+package hot
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/samber/hot/internal"
+	"github.com/samber/hot/pkg/base"
+)
+
+// warmUpKeysChunkSize is the number of keys sent to the loader chain in a single call during WarmUpKeys.
+const warmUpKeysChunkSize = 100
+
+// WarmUpStatus reports the progress of the key-based warm-up started by Build.
+type WarmUpStatus struct {
+	// Total is the number of keys to warm up.
+	Total int
+	// Loaded is the number of keys already sent through the loader chain.
+	Loaded int
+	// Done is true once the warm-up has finished, successfully or not.
+	Done bool
+	// Err is the first error returned by the loaders or by the hot keys file reader.
+	Err error
+}
+
+// warmUpState tracks a background key-based warm-up.
+type warmUpState struct {
+	total  atomic.Int64
+	loaded atomic.Int64
+	err    error // written before closing done
+	done   chan struct{}
+	cancel context.CancelFunc // stops the warm-up on Close
+}
+
+// hotKeysPersistence holds the state of the periodic hot keys dump.
+type hotKeysPersistence struct {
+	path     string
+	limit    int
+	ticker   *time.Ticker // nil when keys are only written on Close
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+	err      error // first error of the periodic writes, written before closing done
+}
+
+// DumpHotKeys writes up to limit keys to w, in the order the eviction policy would keep them.
+// Missing and expired keys are not written. Keys are encoded as JSON, one key per line.
+// Values are never written, which makes the output safe to persist for PII-sensitive data.
+// A non-positive limit writes all keys.
+func (c *HotCache[K, V]) DumpHotKeys(w io.Writer, limit int) error {
+	// All keys are fetched, since missing and expired keys are skipped without counting towards the limit.
+	keys := base.KeysByRetention(c.cache, 0)
+	nowNano := internal.NowNano()
+
+	enc := json.NewEncoder(w)
+	written := 0
+	for _, key := range keys {
+		if limit > 0 && written >= limit {
+			break
+		}
+
+		v, ok := c.cache.Peek(key)
+		if !ok || !v.hasValue || v.isExpired(nowNano) {
+			continue
+		}
+
+		if err := enc.Encode(key); err != nil {
+			return err
+		}
+		written++
+	}
+
+	return nil
+}
+
+// LoadHotKeys reads keys written by DumpHotKeys.
+func LoadHotKeys[K comparable](r io.Reader) ([]K, error) {
+	keys := []K{}
+
+	dec := json.NewDecoder(bufio.NewReader(r))
+	for {
+		var key K
+		err := dec.Decode(&key)
+		if errors.Is(err, io.EOF) {
+			return keys, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+}
+
+// WarmUpKeys loads the provided keys through the default loader chain and stores them in the cache.
+// Keys are sent to the loaders in chunks, with up to `concurrency` chunks loaded in parallel.
+// It blocks until every chunk has been loaded and returns the first loader error.
+func (c *HotCache[K, V]) WarmUpKeys(keys []K, concurrency int) error {
+	return c.warmUpKeys(context.Background(), keys, concurrency, nil)
+}
+
+// WarmUpStatus returns the progress of the key-based warm-up started by Build.
+// When no such warm-up was configured, the returned status is already done.
+func (c *HotCache[K, V]) WarmUpStatus() WarmUpStatus {
+	if c.warmUp == nil {
+		return WarmUpStatus{Done: true}
+	}
+
+	status := WarmUpStatus{
+		Total:  int(c.warmUp.total.Load()),
+		Loaded: int(c.warmUp.loaded.Load()),
+	}
+
+	select {
+	case <-c.warmUp.done:
+		status.Done = true
+		status.Err = c.warmUp.err
+	default:
+	}
+
+	return status
+}
+
+// WarmUpDone returns a channel that is closed when the key-based warm-up started by Build has finished.
+// This is useful for readiness probes. When no such warm-up was configured, the channel is already closed.
+func (c *HotCache[K, V]) WarmUpDone() <-chan struct{} {
+	if c.warmUp == nil {
+		done := make(chan struct{})
+		close(done)
+		return done
+	}
+
+	return c.warmUp.done
+}
+
+// warmUpKeys loads keys chunk by chunk, using a pool of `concurrency` workers.
+// onProgress is called after each chunk with the number of keys of the chunk.
+// Once ctx is cancelled, no more chunks are loaded, and ctx.Err() is returned.
+func (c *HotCache[K, V]) warmUpKeys(ctx context.Context, keys []K, concurrency int, onProgress func(int)) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	chunks := make(chan []K)
+	go func() {
+		defer close(chunks)
+
+		for i := 0; i < len(keys); i += warmUpKeysChunkSize {
+			select {
+			case <-ctx.Done():
+				return
+			case chunks <- keys[i:min(i+warmUpKeysChunkSize, len(keys))]:
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for chunk := range chunks {
+				if ctx.Err() != nil {
+					continue
+				}
+
+				_, err := c.loadAndSetMany(ctx, chunk, c.loaderFns, c.loaderNames)
+				if err != nil {
+					errOnce.Do(func() { firstErr = err })
+				}
+
+				if onProgress != nil {
+					onProgress(len(chunk))
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+
+	return firstErr
+}
+
+// startWarmUpFromFile reads the hot keys file and loads the keys in the background.
+// A missing file is not an error: the warm-up is reported as done with zero keys.
+func (c *HotCache[K, V]) startWarmUpFromFile(path string, concurrency int) {
+	state := &warmUpState{done: make(chan struct{})}
+	c.warmUp = state
+
+	keys, err := readHotKeysFile[K](path)
+	if err != nil {
+		state.err = err
+		close(state.done)
+		return
+	}
+
+	state.total.Store(int64(len(keys)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	state.cancel = cancel
+
+	go func() {
+		state.err = c.warmUpKeys(ctx, keys, concurrency, func(n int) {
+			state.loaded.Add(int64(n))
+		})
+		close(state.done)
+	}()
+}
+
+// stopWarmUp cancels the background warm-up and waits for the chunks being loaded.
+// This method is safe to call multiple times.
+func (c *HotCache[K, V]) stopWarmUp() {
+	if c.warmUp == nil || c.warmUp.cancel == nil {
+		return
+	}
+
+	c.warmUp.cancel()
+	<-c.warmUp.done
+}
+
+// startHotKeysPersistence writes the hot keys file every interval, and on Close.
+// A zero interval only writes the file on Close. The first error of the periodic writes is returned by Close.
+func (c *HotCache[K, V]) startHotKeysPersistence(path string, limit int, interval time.Duration) {
+	p := &hotKeysPersistence{
+		path:  path,
+		limit: limit,
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	c.hotKeys = p
+
+	if interval <= 0 {
+		close(p.done)
+		return
+	}
+
+	p.ticker = time.NewTicker(interval)
+
+	go func() {
+		defer close(p.done)
+
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-p.ticker.C:
+				if err := c.writeHotKeysFile(p.path, p.limit); err != nil && p.err == nil {
+					p.err = err
+				}
+			}
+		}
+	}()
+}
+
+// stopHotKeysPersistence stops the periodic dump and writes the hot keys file a last time.
+// It returns the first error of the periodic writes, or else the error of the last write.
+// This method is safe to call multiple times.
+func (c *HotCache[K, V]) stopHotKeysPersistence() error {
+	p := c.hotKeys
+	if p == nil {
+		return nil
+	}
+
+	var err error
+	p.stopOnce.Do(func() {
+		close(p.stop)
+		<-p.done
+		if p.ticker != nil {
+			p.ticker.Stop()
+		}
+
+		err = p.err
+		if writeErr := c.writeHotKeysFile(p.path, p.limit); err == nil {
+			err = writeErr
+		}
+	})
+
+	return err
+}
+
+// writeHotKeysFile dumps the hot keys into a temporary file, then renames it,
+// so that a crash never leaves a truncated file behind.
+func (c *HotCache[K, V]) writeHotKeysFile(path string, limit int) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(tmp)
+	err = c.DumpHotKeys(w, limit)
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// readHotKeysFile reads a file written by writeHotKeysFile.
+// A missing file returns no keys and no error.
+func readHotKeysFile[K comparable](path string) ([]K, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []K{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return LoadHotKeys[K](f)
+}
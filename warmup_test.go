@@ -0,0 +1,287 @@
+package hot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHotCache_DumpHotKeys(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewHotCache[string, int](LRU, 10).
+		WithMissingSharedCache().
+		Build()
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	cache.SetMissing("missing")
+	cache.Set("c", 3)
+	cache.SetWithTTL("expired", 4, time.Millisecond)
+	cache.Get("a") //nolint:errcheck
+	time.Sleep(5 * time.Millisecond)
+
+	var buf bytes.Buffer
+	is.NoError(cache.DumpHotKeys(&buf, 0))
+	is.Equal("\"a\"\n\"c\"\n\"b\"\n", buf.String())
+
+	keys, err := LoadHotKeys[string](&buf)
+	is.NoError(err)
+	is.Equal([]string{"a", "c", "b"}, keys)
+
+	// limit
+	buf.Reset()
+	is.NoError(cache.DumpHotKeys(&buf, 2))
+	keys, err = LoadHotKeys[string](&buf)
+	is.NoError(err)
+	is.Equal([]string{"a", "c"}, keys)
+
+	// invalid input
+	_, err = LoadHotKeys[int](bytes.NewBufferString("\"a\"\n"))
+	is.Error(err)
+}
+
+func TestHotCache_WarmUpKeys(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	var mu sync.Mutex
+	chunks := [][]int{}
+
+	cache := NewHotCache[int, int](LRU, 1000).
+		WithLoaders(func(keys []int) (map[int]int, error) {
+			mu.Lock()
+			chunks = append(chunks, keys)
+			mu.Unlock()
+
+			results := map[int]int{}
+			for _, k := range keys {
+				if k%2 == 0 {
+					results[k] = k * 10
+				}
+			}
+			return results, nil
+		}).
+		Build()
+
+	keys := make([]int, 250)
+	for i := range keys {
+		keys[i] = i
+	}
+
+	is.NoError(cache.WarmUpKeys(keys, 4))
+	is.Len(chunks, 3)
+	sizes := []int{len(chunks[0]), len(chunks[1]), len(chunks[2])}
+	sort.Ints(sizes)
+	is.Equal([]int{50, 100, 100}, sizes)
+	is.Equal(125, cache.Len())
+
+	v, ok := cache.Peek(42)
+	is.True(ok)
+	is.Equal(420, v)
+
+	// loader error
+	cache = NewHotCache[int, int](LRU, 1000).
+		WithLoaders(func(keys []int) (map[int]int, error) {
+			return nil, errors.New("boom")
+		}).
+		Build()
+	is.EqualError(cache.WarmUpKeys(keys, 2), "boom")
+}
+
+func TestHotCache_WarmUpStatus_Disabled(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	cache := NewHotCache[int, int](LRU, 10).Build()
+	is.Equal(WarmUpStatus{Done: true}, cache.WarmUpStatus())
+
+	select {
+	case <-cache.WarmUpDone():
+	default:
+		is.Fail("warm-up should be done")
+	}
+
+	is.NoError(cache.Close())
+}
+
+func TestWithHotKeysPersistence(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "hot-keys.jsonl")
+
+	var loaded int64
+	loader := func(keys []string) (map[string]int, error) {
+		atomic.AddInt64(&loaded, int64(len(keys)))
+		results := map[string]int{}
+		for _, k := range keys {
+			results[k] = len(k)
+		}
+		return results, nil
+	}
+
+	// first run: no file yet
+	cache := NewHotCache[string, int](LRU, 10).
+		WithLoaders(loader).
+		WithHotKeysPersistence(path, 2, 0, 2).
+		Build()
+	<-cache.WarmUpDone()
+	is.Equal(WarmUpStatus{Done: true}, cache.WarmUpStatus())
+
+	cache.Set("a", 1)
+	cache.Set("bb", 2)
+	cache.Set("ccc", 3)
+	is.NoError(cache.Close())
+	is.NoError(cache.Close()) // idempotent
+
+	content, err := os.ReadFile(path)
+	is.NoError(err)
+	is.Equal("\"ccc\"\n\"bb\"\n", string(content))
+
+	// second run: keys are loaded back through the loaders
+	cache = NewHotCache[string, int](LRU, 10).
+		WithLoaders(loader).
+		WithHotKeysPersistence(path, 2, 0, 2).
+		Build()
+	<-cache.WarmUpDone()
+	is.Equal(WarmUpStatus{Total: 2, Loaded: 2, Done: true}, cache.WarmUpStatus())
+	is.Equal(int64(2), atomic.LoadInt64(&loaded))
+
+	v, ok := cache.Peek("ccc")
+	is.True(ok)
+	is.Equal(3, v)
+	is.NoError(cache.Close())
+}
+
+func TestWithHotKeysPersistence_CloseStopsWarmUp(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+	testWithTimeout(t, 2*time.Second)
+
+	path := filepath.Join(t.TempDir(), "hot-keys.jsonl")
+	content := ""
+	for i := 0; i < 5*warmUpKeysChunkSize; i++ {
+		content += fmt.Sprintf("\"key-%d\"\n", i)
+	}
+	is.NoError(os.WriteFile(path, []byte(content), 0o600))
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var calls int64
+	loader := func(keys []string) (map[string]int, error) {
+		atomic.AddInt64(&calls, 1)
+		started <- struct{}{}
+		<-release
+		return map[string]int{}, nil
+	}
+
+	cache := NewHotCache[string, int](LRU, 10).
+		WithLoaders(loader).
+		WithHotKeysPersistence(path, 0, 0, 1).
+		Build()
+	<-started
+
+	closed := make(chan struct{})
+	go func() {
+		_ = cache.Close()
+		close(closed)
+	}()
+
+	// Close waits for the chunk being loaded, and no other chunk is loaded.
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	<-closed
+
+	status := cache.WarmUpStatus()
+	is.True(status.Done)
+	is.ErrorIs(status.Err, context.Canceled)
+	is.Equal(int64(1), atomic.LoadInt64(&calls))
+}
+
+func TestWithHotKeysPersistence_Interval(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+	testWithTimeout(t, 2*time.Second)
+
+	path := filepath.Join(t.TempDir(), "hot-keys.jsonl")
+
+	cache := NewHotCache[string, int](LRU, 10).
+		WithHotKeysPersistence(path, 0, 10*time.Millisecond, 1).
+		Build()
+	cache.Set("a", 1)
+
+	is.Eventually(func() bool {
+		content, err := os.ReadFile(path)
+		return err == nil && string(content) == "\"a\"\n"
+	}, time.Second, 5*time.Millisecond)
+
+	is.NoError(cache.Close())
+}
+
+func TestWithHotKeysPersistence_IntervalError(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+	testWithTimeout(t, 2*time.Second)
+
+	dir := filepath.Join(t.TempDir(), "hot-keys")
+	is.NoError(os.Mkdir(dir, 0o700))
+	path := filepath.Join(dir, "hot-keys.jsonl")
+
+	cache := NewHotCache[string, int](LRU, 10).
+		WithHotKeysPersistence(path, 0, 10*time.Millisecond, 1).
+		Build()
+	cache.Set("a", 1)
+
+	// The periodic writes fail, then the last write succeeds: the error is still reported.
+	is.NoError(os.RemoveAll(dir))
+	time.Sleep(50 * time.Millisecond)
+	is.NoError(os.Mkdir(dir, 0o700))
+
+	is.Error(cache.Close())
+
+	content, err := os.ReadFile(path)
+	is.NoError(err)
+	is.Equal("\"a\"\n", string(content))
+}
+
+func TestWithHotKeysPersistence_InvalidFile(t *testing.T) {
+	is := assert.New(t)
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "hot-keys.jsonl")
+	is.NoError(os.WriteFile(path, []byte("not json"), 0o600))
+
+	cache := NewHotCache[string, int](LRU, 10).
+		WithHotKeysPersistence(path, 0, 0, 1).
+		Build()
+	<-cache.WarmUpDone()
+
+	status := cache.WarmUpStatus()
+	is.True(status.Done)
+	is.Error(status.Err)
+	is.NoError(cache.Close())
+
+	is.Panics(func() {
+		_ = NewHotCache[string, int](LRU, 10).
+			WithoutLocking().
+			WithHotKeysPersistence(path, 0, 0, 1).
+			Build()
+	})
+	is.Panics(func() {
+		_ = NewHotCache[string, int](LRU, 10).WithHotKeysPersistence("", 0, 0, 1)
+	})
+	is.Panics(func() {
+		_ = NewHotCache[string, int](LRU, 10).WithHotKeysPersistence(path, 0, 0, 0)
+	})
+}